@@ -4,10 +4,16 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/robfig/cron/v3"
 	"webook/internal/events"
+	"webook/internal/job"
+	"webook/internal/service"
+	"webook/pkg/logger"
 )
 
 type App struct {
 	server    *gin.Engine
 	consumers []events.Consumer
 	cron      *cron.Cron
+	scheduler *job.Scheduler
+	searchSvc service.SearchService
+	l         logger.LoggerV1
 }
@@ -0,0 +1,37 @@
+package domain
+
+import "time"
+
+type CommentStatus uint8
+
+const (
+	CommentStatusNormal CommentStatus = iota
+	// CommentStatusDeleted 评论的人自己删除
+	CommentStatusDeleted
+	// CommentStatusRemoved biz 的所有者删除，比如文章作者删除文章下面的评论
+	CommentStatusRemoved
+)
+
+func (s CommentStatus) Deleted() bool {
+	return s != CommentStatusNormal
+}
+
+type Comment struct {
+	Id    int64
+	Uid   int64
+	Biz   string
+	BizId int64
+	// Content 已经删除的评论是空的
+	Content string
+	// RootId 为 0 说明是根评论
+	RootId     int64
+	ParentId   int64
+	ReplyToUid int64
+	Status     CommentStatus
+	// ReplyCnt 只有根评论有
+	ReplyCnt int64
+	// Replies 只有在列表里面的根评论有，是最早的几条回复
+	Replies []Comment
+	Ctime   time.Time
+	Utime   time.Time
+}
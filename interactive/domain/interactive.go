@@ -1,11 +1,14 @@
 package domain
 
+import "time"
+
 type Interactive struct {
 	Biz        string
 	BizId      int64
 	ReadCnt    int64
 	LikeCnt    int64
 	CollectCnt int64
+	CommentCnt int64
 	Liked      bool
 	Collected  bool
 }
@@ -16,3 +19,19 @@ type InteractiveArticle struct {
 	LikeCnt    int64
 	CollectCnt int64
 }
+
+// DailyStat 某一天的访问量，UV 按照用户或者设备去重，PV 是阅读次数
+type DailyStat struct {
+	Biz   string
+	BizId int64
+	// Day 当天的 0 点
+	Day time.Time
+	UV  int64
+	PV  int64
+}
+
+// CoLiked 同时点赞了两个资源的人数
+type CoLiked struct {
+	BizId int64
+	Cnt   int64
+}
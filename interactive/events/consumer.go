@@ -2,7 +2,9 @@ package events
 
 import (
 	"context"
+	"fmt"
 	"github.com/IBM/sarama"
+	"strconv"
 	"time"
 	"webook/interactive/repository"
 	"webook/pkg/logger"
@@ -14,6 +16,21 @@ const TopicReadEvent = "article_read"
 type ReadEvent struct {
 	Aid int64
 	Uid int64
+	// Device 没有登录的时候用设备 ID 统计 UV
+	Device string
+	// Ctime 阅读的时间，毫秒数，老的消息没有这个字段
+	Ctime int64
+}
+
+// visitor 登录用户按照 uid 去重，没有登录的按照设备去重
+func (e ReadEvent) visitor() string {
+	if e.Uid > 0 {
+		return "u:" + strconv.FormatInt(e.Uid, 10)
+	}
+	if e.Device != "" {
+		return "d:" + e.Device
+	}
+	return ""
 }
 
 type InteractiveReadEventConsumer struct {
@@ -66,8 +83,25 @@ func (i *InteractiveReadEventConsumer) BatchConsume(msgs []*sarama.ConsumerMessa
 	return i.repo.BatchIncrReadCnt(ctx, bizs, bizIds)
 }
 
+// Consume 提交之前重启或者再均衡的时候消息会重复投递，按照分区和偏移量去重，
+// 不然阅读数和 PV 都会多算。处理失败也不会重试，所以先标记再处理
 func (i *InteractiveReadEventConsumer) Consume(msg *sarama.ConsumerMessage, event ReadEvent) error {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 	defer cancel()
-	return i.repo.IncrReadCnt(ctx, "article", event.Aid)
+	ok, err := i.repo.MarkConsumed(ctx, fmt.Sprintf("%s:%d:%d", msg.Topic, msg.Partition, msg.Offset))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return nil
+	}
+	err = i.repo.IncrReadCnt(ctx, "article", event.Aid)
+	if err != nil {
+		return err
+	}
+	t := time.Now()
+	if event.Ctime > 0 {
+		t = time.UnixMilli(event.Ctime)
+	}
+	return i.repo.AddVisit(ctx, "article", event.Aid, event.visitor(), t)
 }
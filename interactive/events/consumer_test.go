@@ -0,0 +1,64 @@
+package events
+
+import (
+	"context"
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+	"webook/interactive/repository"
+	repomocks "webook/interactive/repository/mocks"
+	"webook/pkg/logger"
+)
+
+func TestInteractiveReadEventConsumer_Consume(t *testing.T) {
+	msg := &sarama.ConsumerMessage{Topic: TopicReadEvent, Partition: 1, Offset: 10}
+	ctime := time.Date(2024, 3, 1, 10, 0, 0, 0, time.Local)
+	testCases := []struct {
+		name    string
+		mock    func(ctrl *gomock.Controller) repository.InteractiveRepository
+		event   ReadEvent
+		wantErr error
+	}{
+		{
+			name: "没有登录按照设备统计",
+			mock: func(ctrl *gomock.Controller) repository.InteractiveRepository {
+				repo := repomocks.NewMockInteractiveRepository(ctrl)
+				repo.EXPECT().MarkConsumed(gomock.Any(), "article_read:1:10").Return(true, nil)
+				repo.EXPECT().IncrReadCnt(gomock.Any(), "article", int64(2)).Return(nil)
+				repo.EXPECT().AddVisit(gomock.Any(), "article", int64(2), "d:abc", ctime).Return(nil)
+				return repo
+			},
+			event: ReadEvent{Aid: 2, Device: "abc", Ctime: ctime.UnixMilli()},
+		},
+		{
+			name: "重复投递",
+			mock: func(ctrl *gomock.Controller) repository.InteractiveRepository {
+				repo := repomocks.NewMockInteractiveRepository(ctrl)
+				repo.EXPECT().MarkConsumed(gomock.Any(), "article_read:1:10").Return(false, nil)
+				return repo
+			},
+			event: ReadEvent{Aid: 2, Uid: 3, Ctime: ctime.UnixMilli()},
+		},
+		{
+			name: "标记失败",
+			mock: func(ctrl *gomock.Controller) repository.InteractiveRepository {
+				repo := repomocks.NewMockInteractiveRepository(ctrl)
+				repo.EXPECT().MarkConsumed(gomock.Any(), "article_read:1:10").Return(false, context.DeadlineExceeded)
+				return repo
+			},
+			event:   ReadEvent{Aid: 2, Uid: 3, Ctime: ctime.UnixMilli()},
+			wantErr: context.DeadlineExceeded,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			c := NewInteractiveReadEventConsumer(tc.mock(ctrl), nil, logger.NewNopLogger())
+			err := c.Consume(msg, tc.event)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package grpc
+
+import (
+	"context"
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"strings"
+)
+
+// TokenParser 校验 token 的签名和过期时间，一般是 jwtx.RemoteKeySet
+type TokenParser interface {
+	Parse(tokenStr string, claims jwt.Claims) error
+}
+
+// userClaims webook 签发的 access token 里面这里用得到的部分
+type userClaims struct {
+	jwt.RegisteredClaims
+	Uid int64
+}
+
+// uidRequest 代表某个用户发起的请求
+type uidRequest interface {
+	GetUid() int64
+}
+
+// NewAuthInterceptor 带了 uid 的请求必须带上这个用户的 access token，防止别的服务冒充用户点赞、评论。
+// 不带 uid 的是定时任务之类的内部调用，不需要 token
+func NewAuthInterceptor(parser TokenParser) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ur, ok := req.(uidRequest)
+		if !ok || ur.GetUid() == 0 {
+			return handler(ctx, req)
+		}
+		tokenStr := extractToken(ctx)
+		if tokenStr == "" {
+			return nil, status.Error(codes.Unauthenticated, "缺少 access token")
+		}
+		var uc userClaims
+		err := parser.Parse(tokenStr, &uc)
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, "access token 无效")
+		}
+		if uc.Uid != ur.GetUid() {
+			return nil, status.Error(codes.PermissionDenied, "uid 和 access token 不一致")
+		}
+		return handler(ctx, req)
+	}
+}
+
+// extractToken 和 webook 的约定一样，Bearer 后面是 token
+func extractToken(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	vals := md.Get("authorization")
+	if len(vals) == 0 {
+		return ""
+	}
+	segs := strings.SplitN(vals[0], " ", 2)
+	if len(segs) != 2 {
+		return ""
+	}
+	return segs[1]
+}
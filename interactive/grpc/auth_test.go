@@ -0,0 +1,95 @@
+package grpc
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"testing"
+	"time"
+	intrv1 "webook/api/proto/gen/intr/v1"
+	"webook/pkg/jwtx"
+)
+
+func TestAuthInterceptor(t *testing.T) {
+	ks := newTestKeySet(t, "access")
+	sign := func(uid int64) string {
+		token, err := ks.Sign(userClaims{
+			RegisteredClaims: jwt.RegisteredClaims{
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+			},
+			Uid: uid,
+		})
+		require.NoError(t, err)
+		return token
+	}
+	// 别人的密钥签发的
+	forged, err := newTestKeySet(t, "access").Sign(userClaims{Uid: 1})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		req      any
+		token    string
+		wantCode codes.Code
+	}{
+		{
+			name:     "内部调用不带 uid",
+			req:      &intrv1.GetByIdsRequest{Biz: "article", Ids: []int64{1}},
+			wantCode: codes.OK,
+		},
+		{
+			name:     "用户的 token",
+			req:      &intrv1.LikeRequest{Biz: "article", BizId: 2, Uid: 1},
+			token:    sign(1),
+			wantCode: codes.OK,
+		},
+		{
+			name:     "没有 token",
+			req:      &intrv1.LikeRequest{Biz: "article", BizId: 2, Uid: 1},
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:     "伪造的 token",
+			req:      &intrv1.LikeRequest{Biz: "article", BizId: 2, Uid: 1},
+			token:    forged,
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:     "冒充别的用户",
+			req:      &intrv1.CollectRequest{Biz: "article", BizId: 2, Cid: 3, Uid: 2},
+			token:    sign(1),
+			wantCode: codes.PermissionDenied,
+		},
+	}
+	interceptor := NewAuthInterceptor(ks)
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tc.token != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "Bearer "+tc.token))
+			}
+			_, err := interceptor(ctx, tc.req, &grpc.UnaryServerInfo{},
+				func(ctx context.Context, req any) (any, error) {
+					return nil, nil
+				})
+			assert.Equal(t, tc.wantCode, status.Code(err))
+		})
+	}
+}
+
+func newTestKeySet(t *testing.T, kid string) *jwtx.KeySet {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	key, err := jwtx.NewKey(kid, private)
+	require.NoError(t, err)
+	ks, err := jwtx.NewKeySet(kid, key)
+	require.NoError(t, err)
+	return ks
+}
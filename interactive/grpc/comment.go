@@ -0,0 +1,101 @@
+package grpc
+
+import (
+	"context"
+	"github.com/gotomicro/ekit/slice"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	commentv1 "webook/api/proto/gen/comment/v1"
+	"webook/interactive/domain"
+	"webook/interactive/service"
+)
+
+type CommentServiceServer struct {
+	commentv1.UnimplementedCommentServiceServer
+	svc service.CommentService
+}
+
+func NewCommentServiceServer(svc service.CommentService) *CommentServiceServer {
+	return &CommentServiceServer{svc: svc}
+}
+
+func (c *CommentServiceServer) Register(s *grpc.Server) {
+	commentv1.RegisterCommentServiceServer(s, c)
+}
+
+func (c *CommentServiceServer) CreateComment(ctx context.Context, request *commentv1.CreateCommentRequest) (*commentv1.CreateCommentResponse, error) {
+	res, err := c.svc.Create(ctx, domain.Comment{
+		Uid:      request.GetUid(),
+		Biz:      request.GetBiz(),
+		BizId:    request.GetBizId(),
+		ParentId: request.GetParentId(),
+		Content:  request.GetContent(),
+	})
+	if err != nil {
+		return nil, ToStatusError(err)
+	}
+	return &commentv1.CreateCommentResponse{Comment: ToCommentDTO(res)}, nil
+}
+
+func (c *CommentServiceServer) DeleteComment(ctx context.Context, request *commentv1.DeleteCommentRequest) (*commentv1.DeleteCommentResponse, error) {
+	err := c.svc.Delete(ctx, request.GetId(), request.GetUid(), request.GetBiz(), request.GetBizId(), request.GetBizOwner())
+	if err != nil {
+		return nil, ToStatusError(err)
+	}
+	return &commentv1.DeleteCommentResponse{}, nil
+}
+
+func (c *CommentServiceServer) GetCommentList(ctx context.Context, request *commentv1.CommentListRequest) (*commentv1.CommentListResponse, error) {
+	res, err := c.svc.List(ctx, request.GetBiz(), request.GetBizId(), request.GetMaxId(), int(request.GetLimit()))
+	if err != nil {
+		return nil, err
+	}
+	return &commentv1.CommentListResponse{Comments: ToCommentDTOs(res)}, nil
+}
+
+func (c *CommentServiceServer) GetMoreReplies(ctx context.Context, request *commentv1.GetMoreRepliesRequest) (*commentv1.GetMoreRepliesResponse, error) {
+	res, err := c.svc.Replies(ctx, request.GetRootId(), request.GetMinId(), int(request.GetLimit()))
+	if err != nil {
+		return nil, err
+	}
+	return &commentv1.GetMoreRepliesResponse{Replies: ToCommentDTOs(res)}, nil
+}
+
+// ToStatusError 业务错误转成 grpc 的错误码，调用方按照错误码区分
+func ToStatusError(err error) error {
+	switch err {
+	case service.ErrInvalidComment:
+		return status.Error(codes.InvalidArgument, err.Error())
+	case service.ErrCommentNotFound:
+		return status.Error(codes.NotFound, err.Error())
+	case service.ErrCommentPermissionDenied:
+		return status.Error(codes.PermissionDenied, err.Error())
+	default:
+		return err
+	}
+}
+
+func ToCommentDTOs(cs []domain.Comment) []*commentv1.Comment {
+	return slice.Map(cs, func(idx int, src domain.Comment) *commentv1.Comment {
+		return ToCommentDTO(src)
+	})
+}
+
+func ToCommentDTO(c domain.Comment) *commentv1.Comment {
+	return &commentv1.Comment{
+		Id:         c.Id,
+		Uid:        c.Uid,
+		Biz:        c.Biz,
+		BizId:      c.BizId,
+		Content:    c.Content,
+		RootId:     c.RootId,
+		ParentId:   c.ParentId,
+		ReplyToUid: c.ReplyToUid,
+		Status:     int32(c.Status),
+		ReplyCnt:   c.ReplyCnt,
+		Replies:    ToCommentDTOs(c.Replies),
+		Ctime:      c.Ctime.UnixMilli(),
+		Utime:      c.Utime.UnixMilli(),
+	}
+}
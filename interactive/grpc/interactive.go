@@ -2,7 +2,11 @@ package grpc
 
 import (
 	"context"
+	"github.com/gotomicro/ekit/slice"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"time"
 	"webook/api/proto/gen/intr/v1"
 	"webook/interactive/domain"
 	"webook/interactive/service"
@@ -65,6 +69,49 @@ func (i *InteractiveServiceServer) GetByIds(ctx context.Context, request *intrv1
 	}, nil
 }
 
+func (i *InteractiveServiceServer) GetDailyStats(ctx context.Context, request *intrv1.GetDailyStatsRequest) (*intrv1.GetDailyStatsResponse, error) {
+	stats, err := i.svc.GetDailyStats(ctx, request.GetBiz(), request.GetBizId(),
+		time.UnixMilli(request.GetStart()), time.UnixMilli(request.GetEnd()))
+	if err == service.ErrInvalidStatsRange {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &intrv1.GetDailyStatsResponse{
+		Stats: ToDailyStatDTOs(stats),
+	}, nil
+}
+
+func ToDailyStatDTOs(stats []domain.DailyStat) []*intrv1.DailyStat {
+	return slice.Map(stats, func(idx int, src domain.DailyStat) *intrv1.DailyStat {
+		return &intrv1.DailyStat{
+			Day: src.Day.UnixMilli(),
+			Uv:  src.UV,
+			Pv:  src.PV,
+		}
+	})
+}
+
+func (i *InteractiveServiceServer) GetCoLiked(ctx context.Context, request *intrv1.GetCoLikedRequest) (*intrv1.GetCoLikedResponse, error) {
+	items, err := i.svc.GetCoLiked(ctx, request.GetBiz(), request.GetBizId(), int(request.GetLimit()))
+	if err != nil {
+		return nil, err
+	}
+	return &intrv1.GetCoLikedResponse{
+		Items: ToCoLikedDTOs(items),
+	}, nil
+}
+
+func ToCoLikedDTOs(items []domain.CoLiked) []*intrv1.CoLiked {
+	return slice.Map(items, func(idx int, src domain.CoLiked) *intrv1.CoLiked {
+		return &intrv1.CoLiked{
+			BizId: src.BizId,
+			Cnt:   src.Cnt,
+		}
+	})
+}
+
 func (i *InteractiveServiceServer) toDTO(intr domain.Interactive) *intrv1.Interactive {
 	return &intrv1.Interactive{
 		Biz:        intr.Biz,
@@ -74,5 +121,6 @@ func (i *InteractiveServiceServer) toDTO(intr domain.Interactive) *intrv1.Intera
 		CollectCnt: intr.CollectCnt,
 		Liked:      intr.Liked,
 		LikeCnt:    intr.LikeCnt,
+		CommentCnt: intr.CommentCnt,
 	}
 }
@@ -5,10 +5,12 @@ import (
 	"google.golang.org/grpc"
 	grpc2 "webook/interactive/grpc"
 	"webook/pkg/grpcx"
+	"webook/pkg/jwtx"
 	"webook/pkg/logger"
 )
 
-func NewGrpcxServer(intrSvc *grpc2.InteractiveServiceServer, l logger.LoggerV1) *grpcx.Server {
+func NewGrpcxServer(intrSvc *grpc2.InteractiveServiceServer, commentSvc *grpc2.CommentServiceServer,
+	jwks *jwtx.RemoteKeySet, l logger.LoggerV1) *grpcx.Server {
 	type Config struct {
 		EtcdAddr string `yaml:"etcdAddr"`
 		Port     int    `yaml:"port"`
@@ -19,8 +21,9 @@ func NewGrpcxServer(intrSvc *grpc2.InteractiveServiceServer, l logger.LoggerV1)
 	if err != nil {
 		panic(err)
 	}
-	server := grpc.NewServer()
+	server := grpc.NewServer(grpc.UnaryInterceptor(grpc2.NewAuthInterceptor(jwks)))
 	intrSvc.Register(server)
+	commentSvc.Register(server)
 	return &grpcx.Server{
 		Server:   server,
 		EtcdAddr: cfg.EtcdAddr,
@@ -0,0 +1,24 @@
+package ioc
+
+import (
+	"github.com/spf13/viper"
+	"net/http"
+	"time"
+	"webook/pkg/jwtx"
+)
+
+// InitJWKS 从 webook 的 /.well-known/jwks.json 拉取验证 access token 的公钥
+func InitJWKS() *jwtx.RemoteKeySet {
+	type Config struct {
+		JWKSURL string `yaml:"jwksURL"`
+	}
+	var cfg Config
+	err := viper.UnmarshalKey("jwt", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	if cfg.JWKSURL == "" {
+		panic("没有配置 jwt.jwksURL")
+	}
+	return jwtx.NewRemoteKeySet(cfg.JWKSURL, &http.Client{Timeout: 3 * time.Second})
+}
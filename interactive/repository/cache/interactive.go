@@ -17,16 +17,25 @@ var luaCnt string
 const fieldReadCnt = "read_cnt"
 const fieldLikeCnt = "like_cnt"
 const fieldCollectCnt = "collect_cnt"
+const fieldCommentCnt = "comment_cnt"
 
 type InteractiveCache interface {
 	IncrReadCntIfPresent(ctx context.Context, biz string, bizId int64) error
 	IncrLikeCntIfPresent(ctx context.Context, biz string, id int64) error
 	DecrLikeCntIfPresent(ctx context.Context, biz string, id int64) error
 	IncrCollectCntIfPresent(ctx context.Context, biz string, id int64) error
+	IncrCommentCntIfPresent(ctx context.Context, biz string, id int64) error
+	DecrCommentCntIfPresent(ctx context.Context, biz string, id int64) error
 	Get(ctx context.Context, biz string, id int64) (domain.Interactive, error)
 	Set(ctx context.Context, biz string, id int64, res domain.Interactive) error
 	GetLikeTopN(ctx context.Context, biz string, num int64) ([]domain.InteractiveArticle, error)
 	SetLikeTopN(ctx context.Context, biz string, num int64, data []domain.InteractiveArticle) error
+	// AddVisit 记录 t 这一天的一次访问，visitor 为空只算 PV
+	AddVisit(ctx context.Context, biz string, bizId int64, visitor string, t time.Time) error
+	GetVisits(ctx context.Context, biz string, bizId int64, days []time.Time) ([]domain.DailyStat, error)
+	ScanVisits(ctx context.Context, day time.Time, cursor uint64, count int64) ([]domain.DailyStat, uint64, error)
+	// MarkConsumed 标记消息已经处理过，返回 false 说明之前已经标记过了
+	MarkConsumed(ctx context.Context, msgKey string) (bool, error)
 }
 
 type InteractiveRedisCache struct {
@@ -78,7 +87,8 @@ func (i *InteractiveRedisCache) Set(ctx context.Context, biz string, id int64, r
 	err := i.client.HSet(ctx, key,
 		fieldReadCnt, res.ReadCnt,
 		fieldLikeCnt, res.LikeCnt,
-		fieldCollectCnt, res.CollectCnt).Err()
+		fieldCollectCnt, res.CollectCnt,
+		fieldCommentCnt, res.CommentCnt).Err()
 	if err != nil {
 		return err
 	}
@@ -98,6 +108,7 @@ func (i *InteractiveRedisCache) Get(ctx context.Context, biz string, id int64) (
 	intr.CollectCnt, _ = strconv.ParseInt(res[fieldCollectCnt], 10, 64)
 	intr.LikeCnt, _ = strconv.ParseInt(res[fieldLikeCnt], 10, 64)
 	intr.ReadCnt, _ = strconv.ParseInt(res[fieldReadCnt], 10, 64)
+	intr.CommentCnt, _ = strconv.ParseInt(res[fieldCommentCnt], 10, 64)
 	return intr, nil
 }
 
@@ -106,6 +117,16 @@ func (i *InteractiveRedisCache) IncrCollectCntIfPresent(ctx context.Context, biz
 	return i.client.Eval(ctx, luaCnt, []string{key}, fieldCollectCnt, 1).Err()
 }
 
+func (i *InteractiveRedisCache) IncrCommentCntIfPresent(ctx context.Context, biz string, id int64) error {
+	key := i.key(biz, id)
+	return i.client.Eval(ctx, luaCnt, []string{key}, fieldCommentCnt, 1).Err()
+}
+
+func (i *InteractiveRedisCache) DecrCommentCntIfPresent(ctx context.Context, biz string, id int64) error {
+	key := i.key(biz, id)
+	return i.client.Eval(ctx, luaCnt, []string{key}, fieldCommentCnt, -1).Err()
+}
+
 func (i *InteractiveRedisCache) IncrReadCntIfPresent(ctx context.Context, biz string, bizId int64) error {
 	key := i.key(biz, bizId)
 	err := i.client.Eval(ctx, luaCnt, []string{key}, fieldReadCnt, 1).Err()
@@ -0,0 +1,124 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"strconv"
+	"strings"
+	"time"
+	"webook/interactive/domain"
+)
+
+// visitRetention 每天的访问数据在 Redis 里面保留的时间，
+// 要比汇总任务的间隔长，汇总失败的时候第二天还能补上
+const visitRetention = 3 * 24 * time.Hour
+
+const visitDayLayout = "20060102"
+
+// consumedExpiration 重复投递的都是还没有提交的消息，不需要记很久
+const consumedExpiration = 10 * time.Minute
+
+func (i *InteractiveRedisCache) AddVisit(ctx context.Context, biz string, bizId int64, visitor string, t time.Time) error {
+	day := t.Format(visitDayLayout)
+	uvKey, pvKey := i.uvKey(biz, bizId, day), i.pvKey(biz, bizId, day)
+	activeKey := i.activeKey(day)
+	_, err := i.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		// 没有用户也没有设备的只算 PV
+		if visitor != "" {
+			pipe.PFAdd(ctx, uvKey, visitor)
+			pipe.Expire(ctx, uvKey, visitRetention)
+		}
+		pipe.Incr(ctx, pvKey)
+		pipe.Expire(ctx, pvKey, visitRetention)
+		pipe.SAdd(ctx, activeKey, fmt.Sprintf("%s:%d", biz, bizId))
+		pipe.Expire(ctx, activeKey, visitRetention)
+		return nil
+	})
+	return err
+}
+
+func (i *InteractiveRedisCache) GetVisits(ctx context.Context, biz string, bizId int64, days []time.Time) ([]domain.DailyStat, error) {
+	keys := make([]visitKey, len(days))
+	for idx, day := range days {
+		keys[idx] = visitKey{Biz: biz, BizId: bizId, Day: day}
+	}
+	return i.getVisits(ctx, keys)
+}
+
+// ScanVisits 分批返回 day 这一天有访问的业务和访问量，cursor 为 0 说明遍历完了
+func (i *InteractiveRedisCache) ScanVisits(ctx context.Context, day time.Time, cursor uint64, count int64) ([]domain.DailyStat, uint64, error) {
+	members, next, err := i.client.SScan(ctx, i.activeKey(day.Format(visitDayLayout)), cursor, "", count).Result()
+	if err != nil {
+		return nil, 0, err
+	}
+	keys := make([]visitKey, 0, len(members))
+	for _, m := range members {
+		idx := strings.LastIndexByte(m, ':')
+		if idx < 0 {
+			continue
+		}
+		bizId, er := strconv.ParseInt(m[idx+1:], 10, 64)
+		if er != nil {
+			continue
+		}
+		keys = append(keys, visitKey{Biz: m[:idx], BizId: bizId, Day: day})
+	}
+	res, err := i.getVisits(ctx, keys)
+	return res, next, err
+}
+
+type visitKey struct {
+	Biz   string
+	BizId int64
+	Day   time.Time
+}
+
+func (i *InteractiveRedisCache) getVisits(ctx context.Context, keys []visitKey) ([]domain.DailyStat, error) {
+	uvs := make([]*redis.IntCmd, len(keys))
+	pvs := make([]*redis.StringCmd, len(keys))
+	_, err := i.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for idx, k := range keys {
+			day := k.Day.Format(visitDayLayout)
+			uvs[idx] = pipe.PFCount(ctx, i.uvKey(k.Biz, k.BizId, day))
+			pvs[idx] = pipe.Get(ctx, i.pvKey(k.Biz, k.BizId, day))
+		}
+		return nil
+	})
+	// 没有访问的那天 PV 的 key 不存在
+	if err != nil && err != redis.Nil {
+		return nil, err
+	}
+	res := make([]domain.DailyStat, len(keys))
+	for idx, k := range keys {
+		pv, er := pvs[idx].Int64()
+		if er != nil && er != redis.Nil {
+			return nil, er
+		}
+		res[idx] = domain.DailyStat{
+			Biz:   k.Biz,
+			BizId: k.BizId,
+			Day:   k.Day,
+			UV:    uvs[idx].Val(),
+			PV:    pv,
+		}
+	}
+	return res, nil
+}
+
+func (i *InteractiveRedisCache) MarkConsumed(ctx context.Context, msgKey string) (bool, error) {
+	return i.client.SetNX(ctx, "interactive:consumed:"+msgKey, 1, consumedExpiration).Result()
+}
+
+func (i *InteractiveRedisCache) uvKey(biz string, bizId int64, day string) string {
+	return fmt.Sprintf("interactive:uv:%s:%d:%s", biz, bizId, day)
+}
+
+func (i *InteractiveRedisCache) pvKey(biz string, bizId int64, day string) string {
+	return fmt.Sprintf("interactive:pv:%s:%d:%s", biz, bizId, day)
+}
+
+// activeKey 当天有访问的业务，汇总的时候只需要遍历这个集合
+func (i *InteractiveRedisCache) activeKey(day string) string {
+	return fmt.Sprintf("interactive:visit:active:%s", day)
+}
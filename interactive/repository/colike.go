@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"github.com/gotomicro/ekit/slice"
+	"webook/interactive/domain"
+	"webook/interactive/repository/dao"
+)
+
+func (c *CachedInteractiveRepository) GetCoLiked(ctx context.Context, biz string, bizId int64, limit int) ([]domain.CoLiked, error) {
+	items, err := c.dao.GetCoLiked(ctx, biz, bizId, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(items, func(idx int, src dao.CoLiked) domain.CoLiked {
+		return domain.CoLiked{BizId: src.BizId, Cnt: src.Cnt}
+	}), nil
+}
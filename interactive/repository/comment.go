@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"github.com/gotomicro/ekit/slice"
+	"time"
+	"webook/interactive/domain"
+	"webook/interactive/repository/cache"
+	"webook/interactive/repository/dao"
+	"webook/pkg/logger"
+)
+
+//go:generate mockgen -source=./comment.go -package=repomocks -destination=./mocks/comment.mock.go CommentRepository
+type CommentRepository interface {
+	Create(ctx context.Context, c domain.Comment) (domain.Comment, error)
+	FindById(ctx context.Context, id int64) (domain.Comment, error)
+	Delete(ctx context.Context, c domain.Comment, status domain.CommentStatus) error
+	FindRoots(ctx context.Context, biz string, bizId int64, maxId int64, limit int) ([]domain.Comment, error)
+	FindReplies(ctx context.Context, rootId int64, minId int64, limit int) ([]domain.Comment, error)
+}
+
+type CachedCommentRepository struct {
+	dao dao.CommentDAO
+	// cache 评论数和阅读数、点赞数缓存在一起
+	cache cache.InteractiveCache
+	l     logger.LoggerV1
+}
+
+func NewCachedCommentRepository(dao dao.CommentDAO, cache cache.InteractiveCache, l logger.LoggerV1) CommentRepository {
+	return &CachedCommentRepository{
+		dao:   dao,
+		cache: cache,
+		l:     l,
+	}
+}
+
+func (c *CachedCommentRepository) Create(ctx context.Context, cmt domain.Comment) (domain.Comment, error) {
+	res, err := c.dao.Insert(ctx, c.toEntity(cmt))
+	if err != nil {
+		return domain.Comment{}, err
+	}
+	er := c.cache.IncrCommentCntIfPresent(ctx, cmt.Biz, cmt.BizId)
+	if er != nil {
+		c.l.Error("评论数增加写缓存失败", logger.String("biz", cmt.Biz),
+			logger.Int64("bizId", cmt.BizId), logger.Error(er))
+	}
+	return c.toDomain(res), nil
+}
+
+func (c *CachedCommentRepository) FindById(ctx context.Context, id int64) (domain.Comment, error) {
+	res, err := c.dao.FindById(ctx, id)
+	if err != nil {
+		return domain.Comment{}, err
+	}
+	return c.toDomain(res), nil
+}
+
+func (c *CachedCommentRepository) Delete(ctx context.Context, cmt domain.Comment, status domain.CommentStatus) error {
+	err := c.dao.Delete(ctx, c.toEntity(cmt), uint8(status))
+	if err != nil {
+		return err
+	}
+	er := c.cache.DecrCommentCntIfPresent(ctx, cmt.Biz, cmt.BizId)
+	if er != nil {
+		c.l.Error("评论数减少写缓存失败", logger.String("biz", cmt.Biz),
+			logger.Int64("bizId", cmt.BizId), logger.Error(er))
+	}
+	return nil
+}
+
+func (c *CachedCommentRepository) FindRoots(ctx context.Context, biz string, bizId int64, maxId int64, limit int) ([]domain.Comment, error) {
+	res, err := c.dao.FindRoots(ctx, biz, bizId, maxId, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(res, func(idx int, src dao.Comment) domain.Comment {
+		return c.toDomain(src)
+	}), nil
+}
+
+func (c *CachedCommentRepository) FindReplies(ctx context.Context, rootId int64, minId int64, limit int) ([]domain.Comment, error) {
+	res, err := c.dao.FindReplies(ctx, rootId, minId, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(res, func(idx int, src dao.Comment) domain.Comment {
+		return c.toDomain(src)
+	}), nil
+}
+
+func (c *CachedCommentRepository) toEntity(cmt domain.Comment) dao.Comment {
+	return dao.Comment{
+		Id:         cmt.Id,
+		Uid:        cmt.Uid,
+		Biz:        cmt.Biz,
+		BizId:      cmt.BizId,
+		RootId:     cmt.RootId,
+		ParentId:   cmt.ParentId,
+		ReplyToUid: cmt.ReplyToUid,
+		Content:    cmt.Content,
+		Status:     uint8(cmt.Status),
+	}
+}
+
+func (c *CachedCommentRepository) toDomain(cmt dao.Comment) domain.Comment {
+	return domain.Comment{
+		Id:         cmt.Id,
+		Uid:        cmt.Uid,
+		Biz:        cmt.Biz,
+		BizId:      cmt.BizId,
+		RootId:     cmt.RootId,
+		ParentId:   cmt.ParentId,
+		ReplyToUid: cmt.ReplyToUid,
+		Content:    cmt.Content,
+		Status:     domain.CommentStatus(cmt.Status),
+		ReplyCnt:   cmt.ReplyCnt,
+		Ctime:      time.UnixMilli(cmt.Ctime),
+		Utime:      time.UnixMilli(cmt.Utime),
+	}
+}
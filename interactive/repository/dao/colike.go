@@ -0,0 +1,22 @@
+package dao
+
+import "context"
+
+type CoLiked struct {
+	BizId int64
+	Cnt   int64
+}
+
+func (g *GORMInteractiveDAO) GetCoLiked(ctx context.Context, biz string, bizId int64, limit int) ([]CoLiked, error) {
+	var res []CoLiked
+	// 先按照 biz_type_id 找到点赞的用户，再按照 uid_biz_type_id 找到这些用户的其它点赞
+	err := g.db.WithContext(ctx).Table("user_like_bizs AS a").
+		Select("b.biz_id AS biz_id, COUNT(*) AS cnt").
+		Joins("JOIN user_like_bizs AS b ON b.uid = a.uid AND b.biz = a.biz AND b.biz_id != a.biz_id AND b.status = 1").
+		Where("a.biz = ? AND a.biz_id = ? AND a.status = 1", biz, bizId).
+		Group("b.biz_id").
+		Order("cnt DESC, biz_id DESC").
+		Limit(limit).
+		Scan(&res).Error
+	return res, err
+}
@@ -0,0 +1,140 @@
+package dao
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type CommentDAO interface {
+	// Insert 同时增加 biz 的评论数，回复的话还要增加根评论的回复数
+	Insert(ctx context.Context, c Comment) (Comment, error)
+	FindById(ctx context.Context, id int64) (Comment, error)
+	// Delete 软删除，已经删除的评论不会重复减少评论数
+	Delete(ctx context.Context, c Comment, status uint8) error
+	// FindRoots 从新到旧，没有回复的已删除评论不返回
+	FindRoots(ctx context.Context, biz string, bizId int64, maxId int64, limit int) ([]Comment, error)
+	// FindReplies 从旧到新，不返回已删除的回复
+	FindReplies(ctx context.Context, rootId int64, minId int64, limit int) ([]Comment, error)
+}
+
+type GORMCommentDAO struct {
+	db *gorm.DB
+}
+
+func NewGORMCommentDAO(db *gorm.DB) CommentDAO {
+	return &GORMCommentDAO{db: db}
+}
+
+func (g *GORMCommentDAO) Insert(ctx context.Context, c Comment) (Comment, error) {
+	now := time.Now().UnixMilli()
+	c.Ctime = now
+	c.Utime = now
+	err := g.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Create(&c).Error
+		if err != nil {
+			return err
+		}
+		if c.RootId > 0 {
+			err = tx.Model(&Comment{}).Where("id=?", c.RootId).
+				Updates(map[string]any{
+					"reply_cnt": gorm.Expr("reply_cnt + 1"),
+				}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "biz_id"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"comment_cnt": gorm.Expr("comment_cnt + 1"),
+				"utime":       now,
+			}),
+		}).Create(&Interactive{
+			BizId:      c.BizId,
+			Biz:        c.Biz,
+			CommentCnt: 1,
+			Ctime:      now,
+			Utime:      now,
+		}).Error
+	})
+	return c, err
+}
+
+func (g *GORMCommentDAO) FindById(ctx context.Context, id int64) (Comment, error) {
+	var res Comment
+	err := g.db.WithContext(ctx).Where("id=?", id).First(&res).Error
+	return res, err
+}
+
+func (g *GORMCommentDAO) Delete(ctx context.Context, c Comment, status uint8) error {
+	now := time.Now().UnixMilli()
+	return g.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&Comment{}).Where("id=? AND status=?", c.Id, 0).
+			Updates(map[string]any{
+				"status": status,
+				"utime":  now,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			// 已经删除过了
+			return nil
+		}
+		if c.RootId > 0 {
+			err := tx.Model(&Comment{}).Where("id=?", c.RootId).
+				Updates(map[string]any{
+					"reply_cnt": gorm.Expr("reply_cnt - 1"),
+				}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return tx.Model(&Interactive{}).
+			Where("biz_id=? AND biz=?", c.BizId, c.Biz).
+			Updates(map[string]any{
+				"comment_cnt": gorm.Expr("comment_cnt - 1"),
+				"utime":       now,
+			}).Error
+	})
+}
+
+func (g *GORMCommentDAO) FindRoots(ctx context.Context, biz string, bizId int64, maxId int64, limit int) ([]Comment, error) {
+	var res []Comment
+	db := g.db.WithContext(ctx).
+		Where("biz=? AND biz_id=? AND root_id=?", biz, bizId, 0).
+		Where("status=? OR reply_cnt > 0", 0)
+	if maxId > 0 {
+		db = db.Where("id < ?", maxId)
+	}
+	err := db.Order("id DESC").Limit(limit).Find(&res).Error
+	return res, err
+}
+
+func (g *GORMCommentDAO) FindReplies(ctx context.Context, rootId int64, minId int64, limit int) ([]Comment, error) {
+	var res []Comment
+	err := g.db.WithContext(ctx).
+		Where("root_id=? AND id > ? AND status=?", rootId, minId, 0).
+		Order("id ASC").Limit(limit).Find(&res).Error
+	return res, err
+}
+
+type Comment struct {
+	Id    int64  `gorm:"primaryKey,autoIncrement"`
+	Uid   int64  `gorm:"index"`
+	Biz   string `gorm:"type:varchar(128);index:biz_root,priority:1"`
+	BizId int64  `gorm:"index:biz_root,priority:2"`
+	// RootId 为 0 说明是根评论，按照 biz 列出根评论和按照根评论列出回复都要用到
+	RootId     int64 `gorm:"index:biz_root,priority:3;index"`
+	ParentId   int64
+	ReplyToUid int64
+	Content    string `gorm:"type:varchar(1024)"`
+	// Status 0 正常，1 自己删除，2 被 biz 的所有者删除
+	Status uint8
+	// ReplyCnt 根评论下面没有删除的回复数量
+	ReplyCnt int64
+	Ctime    int64
+	Utime    int64
+}
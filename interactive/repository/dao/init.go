@@ -7,5 +7,7 @@ func InitTables(db *gorm.DB) error {
 		&Interactive{},
 		&UserLikeBiz{},
 		&UserCollectionBiz{},
+		&Comment{},
+		&InteractiveDaily{},
 	)
 }
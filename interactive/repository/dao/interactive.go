@@ -18,6 +18,11 @@ type InteractiveDAO interface {
 	BatchIncrReadCnt(ctx context.Context, bizs []string, ids []int64) error
 	GetLikeTopN(ctx context.Context, biz string, num int) ([]Interactive, error)
 	GetByIds(ctx context.Context, biz string, ids []int64) ([]Interactive, error)
+	UpsertDaily(ctx context.Context, dailies []InteractiveDaily) error
+	// GetDaily start 和 end 都是 20060102 格式，包含两端
+	GetDaily(ctx context.Context, biz string, bizId int64, start string, end string) ([]InteractiveDaily, error)
+	// GetCoLiked 点赞了 bizId 的用户还点赞了哪些资源
+	GetCoLiked(ctx context.Context, biz string, bizId int64, limit int) ([]CoLiked, error)
 }
 
 type GORMInteractiveDAO struct {
@@ -174,15 +179,17 @@ type Interactive struct {
 	ReadCnt    int64
 	CollectCnt int64
 	LikeCnt    int64
+	CommentCnt int64
 	Ctime      int64
 	Utime      int64
 }
 
+// UserLikeBiz biz_type_id 用来查询点赞了某个资源的用户
 type UserLikeBiz struct {
 	Id     int64  `gorm:"primaryKey,autoIncrement"`
 	Uid    int64  `gorm:"uniqueIndex:uid_biz_type_id"`
-	BizId  int64  `gorm:"uniqueIndex:uid_biz_type_id"`
-	Biz    string `gorm:"type:varchar(128);uniqueIndex:uid_biz_type_id"`
+	BizId  int64  `gorm:"uniqueIndex:uid_biz_type_id;index:biz_type_id"`
+	Biz    string `gorm:"type:varchar(128);uniqueIndex:uid_biz_type_id;index:biz_type_id"`
 	Status int
 	Utime  int64
 	Ctime  int64
@@ -0,0 +1,44 @@
+package dao
+
+import (
+	"context"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+func (g *GORMInteractiveDAO) UpsertDaily(ctx context.Context, dailies []InteractiveDaily) error {
+	if len(dailies) == 0 {
+		return nil
+	}
+	now := time.Now().UnixMilli()
+	for i := range dailies {
+		dailies[i].Ctime = now
+		dailies[i].Utime = now
+	}
+	// 汇总任务可能重复执行，直接用 Redis 里面的数据覆盖
+	return g.db.WithContext(ctx).Clauses(clause.OnConflict{
+		DoUpdates: clause.AssignmentColumns([]string{"uv", "pv", "utime"}),
+	}).Create(&dailies).Error
+}
+
+func (g *GORMInteractiveDAO) GetDaily(ctx context.Context, biz string, bizId int64, start string, end string) ([]InteractiveDaily, error) {
+	var res []InteractiveDaily
+	err := g.db.WithContext(ctx).
+		Where("biz_id = ? AND biz = ? AND day BETWEEN ? AND ?", bizId, biz, start, end).
+		Order("day ASC").
+		Find(&res).Error
+	return res, err
+}
+
+// InteractiveDaily 每天的 UV 和 PV，由定时任务从 Redis 汇总过来
+type InteractiveDaily struct {
+	Id    int64  `gorm:"primaryKey,autoIncrement"`
+	BizId int64  `gorm:"uniqueIndex:biz_type_id_day"`
+	Biz   string `gorm:"type:varchar(128);uniqueIndex:biz_type_id_day"`
+	// Day 20060102 格式
+	Day   string `gorm:"type:char(8);uniqueIndex:biz_type_id_day"`
+	Uv    int64
+	Pv    int64
+	Ctime int64
+	Utime int64
+}
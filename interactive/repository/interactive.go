@@ -28,6 +28,16 @@ type InteractiveRepository interface {
 	LikeTopN(ctx context.Context, biz string, num int64) ([]domain.InteractiveArticle, error)
 	CronUpdateCacheLikeTopN(ctx context.Context, biz string, num int64)
 	GetByIds(ctx context.Context, biz string, ids []int64) ([]domain.Interactive, error)
+	// AddVisit 记录一次访问，visitor 是用户或者设备，用来统计 UV
+	AddVisit(ctx context.Context, biz string, bizId int64, visitor string, t time.Time) error
+	// MarkConsumed 消息重复投递的时候返回 false，msgKey 要能唯一确定一条消息
+	MarkConsumed(ctx context.Context, msgKey string) (bool, error)
+	// GetDailyStats days 是按照顺序排列的每天 0 点，返回的结果和 days 一一对应
+	GetDailyStats(ctx context.Context, biz string, bizId int64, days []time.Time) ([]domain.DailyStat, error)
+	// RollupDaily 把 day 这一天的访问量从 Redis 汇总到数据库，返回汇总的业务数量
+	RollupDaily(ctx context.Context, day time.Time, batchSize int) (int, error)
+	// GetCoLiked 点赞了 bizId 的用户还点赞了哪些资源，按照人数从多到少排列
+	GetCoLiked(ctx context.Context, biz string, bizId int64, limit int) ([]domain.CoLiked, error)
 }
 
 type CachedInteractiveRepository struct {
@@ -202,6 +212,7 @@ func (c *CachedInteractiveRepository) toDomain(ie dao.Interactive) domain.Intera
 		ReadCnt:    ie.ReadCnt,
 		LikeCnt:    ie.LikeCnt,
 		CollectCnt: ie.CollectCnt,
+		CommentCnt: ie.CommentCnt,
 	}
 }
 
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"github.com/gotomicro/ekit/slice"
+	"time"
+	"webook/interactive/domain"
+	"webook/interactive/repository/dao"
+)
+
+const visitDayLayout = "20060102"
+
+func (c *CachedInteractiveRepository) AddVisit(ctx context.Context, biz string, bizId int64, visitor string, t time.Time) error {
+	return c.cache.AddVisit(ctx, biz, bizId, visitor, t)
+}
+
+func (c *CachedInteractiveRepository) MarkConsumed(ctx context.Context, msgKey string) (bool, error) {
+	return c.cache.MarkConsumed(ctx, msgKey)
+}
+
+// GetDailyStats 汇总过的日子从数据库里面拿，还没有汇总的比如今天从 Redis 里面拿
+func (c *CachedInteractiveRepository) GetDailyStats(ctx context.Context, biz string, bizId int64, days []time.Time) ([]domain.DailyStat, error) {
+	if len(days) == 0 {
+		return nil, nil
+	}
+	dailies, err := c.dao.GetDaily(ctx, biz, bizId,
+		days[0].Format(visitDayLayout), days[len(days)-1].Format(visitDayLayout))
+	if err != nil {
+		return nil, err
+	}
+	stored := make(map[string]dao.InteractiveDaily, len(dailies))
+	for _, d := range dailies {
+		stored[d.Day] = d
+	}
+	res := make([]domain.DailyStat, len(days))
+	var missing []int
+	for i, day := range days {
+		d, ok := stored[day.Format(visitDayLayout)]
+		if !ok {
+			missing = append(missing, i)
+			continue
+		}
+		res[i] = domain.DailyStat{Biz: biz, BizId: bizId, Day: day, UV: d.Uv, PV: d.Pv}
+	}
+	if len(missing) == 0 {
+		return res, nil
+	}
+	visits, err := c.cache.GetVisits(ctx, biz, bizId, slice.Map(missing, func(idx int, src int) time.Time {
+		return days[src]
+	}))
+	if err != nil {
+		return nil, err
+	}
+	for i, idx := range missing {
+		res[idx] = visits[i]
+	}
+	return res, nil
+}
+
+func (c *CachedInteractiveRepository) RollupDaily(ctx context.Context, day time.Time, batchSize int) (int, error) {
+	var (
+		cursor uint64
+		cnt    int
+	)
+	for {
+		visits, next, err := c.cache.ScanVisits(ctx, day, cursor, int64(batchSize))
+		if err != nil {
+			return cnt, err
+		}
+		err = c.dao.UpsertDaily(ctx, slice.Map(visits, func(idx int, src domain.DailyStat) dao.InteractiveDaily {
+			return dao.InteractiveDaily{
+				Biz:   src.Biz,
+				BizId: src.BizId,
+				Day:   src.Day.Format(visitDayLayout),
+				Uv:    src.UV,
+				Pv:    src.PV,
+			}
+		}))
+		if err != nil {
+			return cnt, err
+		}
+		cnt += len(visits)
+		if next == 0 {
+			return cnt, nil
+		}
+		cursor = next
+	}
+}
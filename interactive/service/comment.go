@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode/utf8"
+	"webook/interactive/domain"
+	"webook/interactive/repository"
+)
+
+const (
+	maxCommentLen = 1000
+	// previewReplyCnt 列表里面每条根评论带上的回复数量，更多的回复单独分页
+	previewReplyCnt = 3
+	maxCommentLimit = 50
+)
+
+var (
+	ErrInvalidComment          = errors.New("评论内容不能为空，最多 1000 个字")
+	ErrCommentNotFound         = errors.New("评论不存在")
+	ErrCommentPermissionDenied = errors.New("不能删除别人的评论")
+)
+
+//go:generate mockgen -source=./comment.go -package=svcmocks -destination=./mocks/comment.mock.go CommentService
+type CommentService interface {
+	// Create parentId 为 0 表示根评论，否则是回复，不能回复已经删除的评论
+	Create(ctx context.Context, c domain.Comment) (domain.Comment, error)
+	// Delete 评论的人可以删除自己的评论，bizOwner 为 true 说明 uid 是 biz 的所有者，可以删除任何评论
+	Delete(ctx context.Context, id int64, uid int64, biz string, bizId int64, bizOwner bool) error
+	// List 按照根评论分页，每条根评论带上最早的几条回复
+	List(ctx context.Context, biz string, bizId int64, maxId int64, limit int) ([]domain.Comment, error)
+	Replies(ctx context.Context, rootId int64, minId int64, limit int) ([]domain.Comment, error)
+}
+
+type commentService struct {
+	repo repository.CommentRepository
+}
+
+func NewCommentService(repo repository.CommentRepository) CommentService {
+	return &commentService{repo: repo}
+}
+
+func (s *commentService) Create(ctx context.Context, c domain.Comment) (domain.Comment, error) {
+	c.Content = strings.TrimSpace(c.Content)
+	if c.Content == "" || utf8.RuneCountInString(c.Content) > maxCommentLen {
+		return domain.Comment{}, ErrInvalidComment
+	}
+	c.Status = domain.CommentStatusNormal
+	c.RootId = 0
+	c.ReplyToUid = 0
+	if c.ParentId > 0 {
+		parent, err := s.repo.FindById(ctx, c.ParentId)
+		if err == repository.ErrRecordNotFound {
+			return domain.Comment{}, ErrCommentNotFound
+		}
+		if err != nil {
+			return domain.Comment{}, err
+		}
+		if parent.Status.Deleted() || parent.Biz != c.Biz || parent.BizId != c.BizId {
+			return domain.Comment{}, ErrCommentNotFound
+		}
+		// 只有两层，回复的回复也挂在根评论下面
+		c.RootId = parent.RootId
+		if c.RootId == 0 {
+			c.RootId = parent.Id
+		}
+		c.ReplyToUid = parent.Uid
+	}
+	return s.repo.Create(ctx, c)
+}
+
+func (s *commentService) Delete(ctx context.Context, id int64, uid int64, biz string, bizId int64, bizOwner bool) error {
+	c, err := s.repo.FindById(ctx, id)
+	if err == repository.ErrRecordNotFound {
+		return ErrCommentNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if c.Status.Deleted() {
+		return nil
+	}
+	status := domain.CommentStatusDeleted
+	if c.Uid != uid {
+		if !bizOwner || c.Biz != biz || c.BizId != bizId {
+			return ErrCommentPermissionDenied
+		}
+		status = domain.CommentStatusRemoved
+	}
+	return s.repo.Delete(ctx, c, status)
+}
+
+func (s *commentService) List(ctx context.Context, biz string, bizId int64, maxId int64, limit int) ([]domain.Comment, error) {
+	roots, err := s.repo.FindRoots(ctx, biz, bizId, maxId, s.limit(limit))
+	if err != nil {
+		return nil, err
+	}
+	for i := range roots {
+		if roots[i].ReplyCnt == 0 {
+			continue
+		}
+		roots[i].Replies, err = s.repo.FindReplies(ctx, roots[i].Id, 0, previewReplyCnt)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return hideDeleted(roots), nil
+}
+
+func (s *commentService) Replies(ctx context.Context, rootId int64, minId int64, limit int) ([]domain.Comment, error) {
+	res, err := s.repo.FindReplies(ctx, rootId, minId, s.limit(limit))
+	if err != nil {
+		return nil, err
+	}
+	return hideDeleted(res), nil
+}
+
+func (s *commentService) limit(limit int) int {
+	if limit <= 0 || limit > maxCommentLimit {
+		return maxCommentLimit
+	}
+	return limit
+}
+
+// hideDeleted 去掉已经删除的评论的内容，有回复的已删除评论还要占一个位置
+func hideDeleted(cs []domain.Comment) []domain.Comment {
+	for i := range cs {
+		if cs[i].Status.Deleted() {
+			cs[i].Content = ""
+		}
+		cs[i].Replies = hideDeleted(cs[i].Replies)
+	}
+	return cs
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"webook/interactive/domain"
+	"webook/interactive/repository"
+	repomocks "webook/interactive/repository/mocks"
+)
+
+func TestCommentService_Create(t *testing.T) {
+	testCases := []struct {
+		name    string
+		mock    func(ctrl *gomock.Controller) repository.CommentRepository
+		cmt     domain.Comment
+		wantErr error
+	}{
+		{
+			name: "根评论",
+			mock: func(ctrl *gomock.Controller) repository.CommentRepository {
+				repo := repomocks.NewMockCommentRepository(ctrl)
+				repo.EXPECT().Create(gomock.Any(), domain.Comment{
+					Uid: 1, Biz: "article", BizId: 2, Content: "好文章",
+				}).Return(domain.Comment{Id: 10}, nil)
+				return repo
+			},
+			cmt: domain.Comment{Uid: 1, Biz: "article", BizId: 2, Content: " 好文章 "},
+		},
+		{
+			name: "回复的回复挂在根评论下面",
+			mock: func(ctrl *gomock.Controller) repository.CommentRepository {
+				repo := repomocks.NewMockCommentRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(11)).Return(domain.Comment{
+					Id: 11, Uid: 3, Biz: "article", BizId: 2, RootId: 10, ParentId: 10,
+				}, nil)
+				repo.EXPECT().Create(gomock.Any(), domain.Comment{
+					Uid: 1, Biz: "article", BizId: 2, Content: "同意",
+					RootId: 10, ParentId: 11, ReplyToUid: 3,
+				}).Return(domain.Comment{Id: 12}, nil)
+				return repo
+			},
+			cmt: domain.Comment{Uid: 1, Biz: "article", BizId: 2, ParentId: 11, Content: "同意"},
+		},
+		{
+			name: "回复已经删除的评论",
+			mock: func(ctrl *gomock.Controller) repository.CommentRepository {
+				repo := repomocks.NewMockCommentRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(10)).Return(domain.Comment{
+					Id: 10, Uid: 3, Biz: "article", BizId: 2, Status: domain.CommentStatusDeleted,
+				}, nil)
+				return repo
+			},
+			cmt:     domain.Comment{Uid: 1, Biz: "article", BizId: 2, ParentId: 10, Content: "同意"},
+			wantErr: ErrCommentNotFound,
+		},
+		{
+			name: "回复别的文章的评论",
+			mock: func(ctrl *gomock.Controller) repository.CommentRepository {
+				repo := repomocks.NewMockCommentRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(10)).Return(domain.Comment{
+					Id: 10, Uid: 3, Biz: "article", BizId: 5,
+				}, nil)
+				return repo
+			},
+			cmt:     domain.Comment{Uid: 1, Biz: "article", BizId: 2, ParentId: 10, Content: "同意"},
+			wantErr: ErrCommentNotFound,
+		},
+		{
+			name: "内容为空",
+			mock: func(ctrl *gomock.Controller) repository.CommentRepository {
+				return repomocks.NewMockCommentRepository(ctrl)
+			},
+			cmt:     domain.Comment{Uid: 1, Biz: "article", BizId: 2, Content: "  "},
+			wantErr: ErrInvalidComment,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewCommentService(tc.mock(ctrl))
+			_, err := svc.Create(context.Background(), tc.cmt)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func TestCommentService_Delete(t *testing.T) {
+	cmt := domain.Comment{Id: 10, Uid: 3, Biz: "article", BizId: 2}
+	testCases := []struct {
+		name     string
+		mock     func(ctrl *gomock.Controller) repository.CommentRepository
+		uid      int64
+		bizId    int64
+		bizOwner bool
+		wantErr  error
+	}{
+		{
+			name: "删除自己的评论",
+			mock: func(ctrl *gomock.Controller) repository.CommentRepository {
+				repo := repomocks.NewMockCommentRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(10)).Return(cmt, nil)
+				repo.EXPECT().Delete(gomock.Any(), cmt, domain.CommentStatusDeleted).Return(nil)
+				return repo
+			},
+			uid:   3,
+			bizId: 2,
+		},
+		{
+			name: "作者删除别人的评论",
+			mock: func(ctrl *gomock.Controller) repository.CommentRepository {
+				repo := repomocks.NewMockCommentRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(10)).Return(cmt, nil)
+				repo.EXPECT().Delete(gomock.Any(), cmt, domain.CommentStatusRemoved).Return(nil)
+				return repo
+			},
+			uid:      1,
+			bizId:    2,
+			bizOwner: true,
+		},
+		{
+			name: "别的文章的作者",
+			mock: func(ctrl *gomock.Controller) repository.CommentRepository {
+				repo := repomocks.NewMockCommentRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(10)).Return(cmt, nil)
+				return repo
+			},
+			uid:      1,
+			bizId:    5,
+			bizOwner: true,
+			wantErr:  ErrCommentPermissionDenied,
+		},
+		{
+			name: "删除别人的评论",
+			mock: func(ctrl *gomock.Controller) repository.CommentRepository {
+				repo := repomocks.NewMockCommentRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(10)).Return(cmt, nil)
+				return repo
+			},
+			uid:     1,
+			bizId:   2,
+			wantErr: ErrCommentPermissionDenied,
+		},
+		{
+			name: "已经删除",
+			mock: func(ctrl *gomock.Controller) repository.CommentRepository {
+				repo := repomocks.NewMockCommentRepository(ctrl)
+				deleted := cmt
+				deleted.Status = domain.CommentStatusDeleted
+				repo.EXPECT().FindById(gomock.Any(), int64(10)).Return(deleted, nil)
+				return repo
+			},
+			uid:   3,
+			bizId: 2,
+		},
+		{
+			name: "评论不存在",
+			mock: func(ctrl *gomock.Controller) repository.CommentRepository {
+				repo := repomocks.NewMockCommentRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(10)).
+					Return(domain.Comment{}, repository.ErrRecordNotFound)
+				return repo
+			},
+			uid:     3,
+			bizId:   2,
+			wantErr: ErrCommentNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewCommentService(tc.mock(ctrl))
+			err := svc.Delete(context.Background(), 10, tc.uid, "article", tc.bizId, tc.bizOwner)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
@@ -2,7 +2,9 @@ package service
 
 import (
 	"context"
+	"errors"
 	"golang.org/x/sync/errgroup"
+	"time"
 	"webook/interactive/domain"
 	"webook/interactive/repository"
 )
@@ -17,8 +19,21 @@ type InteractiveService interface {
 	LikeTopN(ctx context.Context, biz string, num int64) ([]domain.InteractiveArticle, error)
 	CronUpdateCacheLikeTopN(ctx context.Context, biz string, num int64)
 	GetByIds(ctx context.Context, biz string, ids []int64) (map[int64]domain.Interactive, error)
+	// GetDailyStats 从 start 到 end 每天的 UV 和 PV，包含两端，最多 MaxStatsDays 天
+	GetDailyStats(ctx context.Context, biz string, bizId int64, start time.Time, end time.Time) ([]domain.DailyStat, error)
+	// RollupDaily 把 day 这一天的访问量汇总到数据库，可以重复执行
+	RollupDaily(ctx context.Context, day time.Time, batchSize int) (int, error)
+	// GetCoLiked 点赞了 bizId 的用户还点赞了哪些资源，最多 MaxCoLikedLimit 个
+	GetCoLiked(ctx context.Context, biz string, bizId int64, limit int) ([]domain.CoLiked, error)
 }
 
+const (
+	MaxStatsDays    = 90
+	MaxCoLikedLimit = 100
+)
+
+var ErrInvalidStatsRange = errors.New("统计的时间范围不对")
+
 type interactiveService struct {
 	repo repository.InteractiveRepository
 }
@@ -81,3 +96,31 @@ func (i *interactiveService) CancelLike(ctx context.Context, biz string, id int6
 func (i *interactiveService) IncrReadCnt(ctx context.Context, biz string, bizId int64) error {
 	return i.repo.IncrReadCnt(ctx, biz, bizId)
 }
+
+func (i *interactiveService) GetDailyStats(ctx context.Context, biz string, bizId int64, start time.Time, end time.Time) ([]domain.DailyStat, error) {
+	start, end = startOfDay(start), startOfDay(end)
+	if end.Before(start) || end.Sub(start) >= MaxStatsDays*24*time.Hour {
+		return nil, ErrInvalidStatsRange
+	}
+	var days []time.Time
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		days = append(days, day)
+	}
+	return i.repo.GetDailyStats(ctx, biz, bizId, days)
+}
+
+func (i *interactiveService) GetCoLiked(ctx context.Context, biz string, bizId int64, limit int) ([]domain.CoLiked, error) {
+	if limit <= 0 || limit > MaxCoLikedLimit {
+		limit = MaxCoLikedLimit
+	}
+	return i.repo.GetCoLiked(ctx, biz, bizId, limit)
+}
+
+func (i *interactiveService) RollupDaily(ctx context.Context, day time.Time, batchSize int) (int, error) {
+	return i.repo.RollupDaily(ctx, startOfDay(day), batchSize)
+}
+
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
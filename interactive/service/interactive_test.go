@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+	"webook/interactive/domain"
+	"webook/interactive/repository"
+	repomocks "webook/interactive/repository/mocks"
+)
+
+func TestInteractiveService_GetDailyStats(t *testing.T) {
+	day := func(d int) time.Time {
+		return time.Date(2024, 3, d, 0, 0, 0, 0, time.Local)
+	}
+	testCases := []struct {
+		name    string
+		mock    func(ctrl *gomock.Controller) repository.InteractiveRepository
+		start   time.Time
+		end     time.Time
+		want    []domain.DailyStat
+		wantErr error
+	}{
+		{
+			name: "按天查询",
+			mock: func(ctrl *gomock.Controller) repository.InteractiveRepository {
+				repo := repomocks.NewMockInteractiveRepository(ctrl)
+				repo.EXPECT().GetDailyStats(gomock.Any(), "article", int64(1),
+					[]time.Time{day(1), day(2), day(3)}).
+					Return([]domain.DailyStat{{Day: day(1), UV: 1, PV: 2}, {Day: day(2)}, {Day: day(3), UV: 3, PV: 3}}, nil)
+				return repo
+			},
+			// 时间会被截断到当天 0 点
+			start: day(1).Add(10 * time.Hour),
+			end:   day(3).Add(23 * time.Hour),
+			want:  []domain.DailyStat{{Day: day(1), UV: 1, PV: 2}, {Day: day(2)}, {Day: day(3), UV: 3, PV: 3}},
+		},
+		{
+			name: "结束早于开始",
+			mock: func(ctrl *gomock.Controller) repository.InteractiveRepository {
+				return repomocks.NewMockInteractiveRepository(ctrl)
+			},
+			start:   day(3),
+			end:     day(1),
+			wantErr: ErrInvalidStatsRange,
+		},
+		{
+			name: "超过最大天数",
+			mock: func(ctrl *gomock.Controller) repository.InteractiveRepository {
+				return repomocks.NewMockInteractiveRepository(ctrl)
+			},
+			start:   day(1),
+			end:     day(1).AddDate(0, 0, MaxStatsDays),
+			wantErr: ErrInvalidStatsRange,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewInteractiveService(tc.mock(ctrl))
+			res, err := svc.GetDailyStats(context.Background(), "article", 1, tc.start, tc.end)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.want, res)
+		})
+	}
+}
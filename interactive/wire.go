@@ -18,6 +18,7 @@ var thirdPartySet = wire.NewSet(
 	ioc.InitLogger,
 	ioc.InitRedis,
 	ioc.InitSaramaClient,
+	ioc.InitJWKS,
 )
 
 var interactiveSvcSet = wire.NewSet(
@@ -27,13 +28,21 @@ var interactiveSvcSet = wire.NewSet(
 	dao2.NewGORMInteractiveDAO,
 )
 
+var commentSvcSet = wire.NewSet(
+	service2.NewCommentService,
+	repository2.NewCachedCommentRepository,
+	dao2.NewGORMCommentDAO,
+)
+
 func InitApp() *App {
 	wire.Build(
 		thirdPartySet,
 		interactiveSvcSet,
+		commentSvcSet,
 		ioc.InitConsumers,
 		events.NewInteractiveReadEventConsumer,
 		grpc.NewInteractiveServiceServer,
+		grpc.NewCommentServiceServer,
 		ioc.NewGrpcxServer,
 		wire.Struct(new(App), "*"),
 	)
@@ -50,6 +50,14 @@ func (i *InteractiveClient) GetByIds(ctx context.Context, in *intrv1.GetByIdsReq
 	return i.selectClient().GetByIds(ctx, in, opts...)
 }
 
+func (i *InteractiveClient) GetCoLiked(ctx context.Context, in *intrv1.GetCoLikedRequest, opts ...grpc.CallOption) (*intrv1.GetCoLikedResponse, error) {
+	return i.selectClient().GetCoLiked(ctx, in, opts...)
+}
+
+func (i *InteractiveClient) GetDailyStats(ctx context.Context, in *intrv1.GetDailyStatsRequest, opts ...grpc.CallOption) (*intrv1.GetDailyStatsResponse, error) {
+	return i.selectClient().GetDailyStats(ctx, in, opts...)
+}
+
 func NewInteractiveClient(remote intrv1.InteractiveServiceClient, local intrv1.InteractiveServiceClient) *InteractiveClient {
 	return &InteractiveClient{remote: remote, local: local, threshold: atomicx.NewValue[int32]()}
 }
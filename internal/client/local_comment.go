@@ -0,0 +1,57 @@
+package client
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	commentv1 "webook/api/proto/gen/comment/v1"
+	"webook/interactive/domain"
+	grpc2 "webook/interactive/grpc"
+	"webook/interactive/service"
+)
+
+// LocalCommentServiceAdapter 不走 grpc，直接调用本地的 CommentService，错误码和 grpc 一致
+type LocalCommentServiceAdapter struct {
+	svc service.CommentService
+}
+
+func NewLocalCommentServiceAdapter(svc service.CommentService) *LocalCommentServiceAdapter {
+	return &LocalCommentServiceAdapter{svc: svc}
+}
+
+func (l *LocalCommentServiceAdapter) CreateComment(ctx context.Context, in *commentv1.CreateCommentRequest, opts ...grpc.CallOption) (*commentv1.CreateCommentResponse, error) {
+	res, err := l.svc.Create(ctx, domain.Comment{
+		Uid:      in.GetUid(),
+		Biz:      in.GetBiz(),
+		BizId:    in.GetBizId(),
+		ParentId: in.GetParentId(),
+		Content:  in.GetContent(),
+	})
+	if err != nil {
+		return nil, grpc2.ToStatusError(err)
+	}
+	return &commentv1.CreateCommentResponse{Comment: grpc2.ToCommentDTO(res)}, nil
+}
+
+func (l *LocalCommentServiceAdapter) DeleteComment(ctx context.Context, in *commentv1.DeleteCommentRequest, opts ...grpc.CallOption) (*commentv1.DeleteCommentResponse, error) {
+	err := l.svc.Delete(ctx, in.GetId(), in.GetUid(), in.GetBiz(), in.GetBizId(), in.GetBizOwner())
+	if err != nil {
+		return nil, grpc2.ToStatusError(err)
+	}
+	return &commentv1.DeleteCommentResponse{}, nil
+}
+
+func (l *LocalCommentServiceAdapter) GetCommentList(ctx context.Context, in *commentv1.CommentListRequest, opts ...grpc.CallOption) (*commentv1.CommentListResponse, error) {
+	res, err := l.svc.List(ctx, in.GetBiz(), in.GetBizId(), in.GetMaxId(), int(in.GetLimit()))
+	if err != nil {
+		return nil, err
+	}
+	return &commentv1.CommentListResponse{Comments: grpc2.ToCommentDTOs(res)}, nil
+}
+
+func (l *LocalCommentServiceAdapter) GetMoreReplies(ctx context.Context, in *commentv1.GetMoreRepliesRequest, opts ...grpc.CallOption) (*commentv1.GetMoreRepliesResponse, error) {
+	res, err := l.svc.Replies(ctx, in.GetRootId(), in.GetMinId(), int(in.GetLimit()))
+	if err != nil {
+		return nil, err
+	}
+	return &commentv1.GetMoreRepliesResponse{Replies: grpc2.ToCommentDTOs(res)}, nil
+}
@@ -3,8 +3,10 @@ package client
 import (
 	"context"
 	"google.golang.org/grpc"
+	"time"
 	intrv1 "webook/api/proto/gen/intr/v1"
 	"webook/interactive/domain"
+	grpc2 "webook/interactive/grpc"
 	"webook/interactive/service"
 )
 
@@ -40,6 +42,27 @@ func (l *LocalInteractiveServiceAdapter) Get(ctx context.Context, in *intrv1.Get
 	return &intrv1.GetResponse{Intr: l.toDTO(intr)}, err
 }
 
+func (l *LocalInteractiveServiceAdapter) GetDailyStats(ctx context.Context, in *intrv1.GetDailyStatsRequest, opts ...grpc.CallOption) (*intrv1.GetDailyStatsResponse, error) {
+	stats, err := l.svc.GetDailyStats(ctx, in.GetBiz(), in.GetBizId(),
+		time.UnixMilli(in.GetStart()), time.UnixMilli(in.GetEnd()))
+	if err != nil {
+		return nil, err
+	}
+	return &intrv1.GetDailyStatsResponse{
+		Stats: grpc2.ToDailyStatDTOs(stats),
+	}, nil
+}
+
+func (l *LocalInteractiveServiceAdapter) GetCoLiked(ctx context.Context, in *intrv1.GetCoLikedRequest, opts ...grpc.CallOption) (*intrv1.GetCoLikedResponse, error) {
+	items, err := l.svc.GetCoLiked(ctx, in.GetBiz(), in.GetBizId(), int(in.GetLimit()))
+	if err != nil {
+		return nil, err
+	}
+	return &intrv1.GetCoLikedResponse{
+		Items: grpc2.ToCoLikedDTOs(items),
+	}, nil
+}
+
 func (l *LocalInteractiveServiceAdapter) GetByIds(ctx context.Context, in *intrv1.GetByIdsRequest, opts ...grpc.CallOption) (*intrv1.GetByIdsResponse, error) {
 	res, err := l.svc.GetByIds(ctx, in.GetBiz(), in.GetIds())
 	if err != nil {
@@ -67,5 +90,6 @@ func (l *LocalInteractiveServiceAdapter) toDTO(intr domain.Interactive) *intrv1.
 		CollectCnt: intr.CollectCnt,
 		Liked:      intr.Liked,
 		LikeCnt:    intr.LikeCnt,
+		CommentCnt: intr.CommentCnt,
 	}
 }
@@ -0,0 +1,22 @@
+package client
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// ForwardTokenInterceptor 把用户的 access token 转给 interactive，它用 JWKS 里面的公钥验证。
+// handler 直接把 *gin.Context 当 context 传进来，定时任务之类的没有用户的调用不带 token
+func ForwardTokenInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any,
+		cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if gc, ok := ctx.(*gin.Context); ok {
+			if auth := gc.GetHeader("Authorization"); auth != "" {
+				ctx = metadata.AppendToOutgoingContext(ctx, "authorization", auth)
+			}
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
@@ -0,0 +1,46 @@
+package domain
+
+import "time"
+
+// ArchiveTask 导出或者导入文章压缩包的异步任务，由 internal/job 里面的调度器执行
+type ArchiveTask struct {
+	Id     int64
+	Uid    int64
+	Type   ArchiveTaskType
+	Status ArchiveTaskStatus
+	// BlobKey 导出任务是生成的压缩包，导入任务是上传的压缩包，清理之后为空
+	BlobKey string
+	// Cnt 导出或者导入成功的文章数量
+	Cnt int
+	// Errors 导入的时候每个文件的错误
+	Errors []ArchiveFileError
+	// Reason 整个任务失败的原因
+	Reason string
+	// Token 下载导出结果的签名凭证，只有完成的导出任务才有，不落库
+	Token string
+	Ctime time.Time
+	Utime time.Time
+}
+
+type ArchiveFileError struct {
+	File string `json:"file"`
+	Err  string `json:"err"`
+}
+
+type ArchiveTaskType uint8
+
+const (
+	ArchiveTaskTypeUnknown ArchiveTaskType = iota
+	ArchiveTaskTypeExport
+	ArchiveTaskTypeImport
+)
+
+type ArchiveTaskStatus uint8
+
+const (
+	ArchiveTaskStatusUnknown ArchiveTaskStatus = iota
+	ArchiveTaskStatusPending
+	ArchiveTaskStatusRunning
+	ArchiveTaskStatusDone
+	ArchiveTaskStatusFailed
+)
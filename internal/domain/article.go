@@ -6,10 +6,21 @@ type Article struct {
 	Id      int64
 	Title   string
 	Content string
+	// HTML 和 Summary 是发表的时候从 Content 渲染出来的，草稿没有
+	HTML    string
+	Summary string
 	Status  ArticleStatus
 	Author  Author
-	Ctime   time.Time
-	Utime   time.Time
+	// Tags 为 nil 的时候表示不修改文章的标签
+	Tags []string
+	// PublishAt 定时发表的时间，只有 ArticleStatusScheduled 才有意义
+	PublishAt time.Time
+	Ctime     time.Time
+	Utime     time.Time
+	// DeletedAt 移入回收站的时间，零值表示没有删除
+	DeletedAt time.Time
+	// Collaborators 除了作者之外的合作者，只有详情页会查询
+	Collaborators []Collaborator
 }
 
 type ArticleStatus uint8
@@ -19,6 +30,9 @@ func (s ArticleStatus) ToUint8() uint8 {
 }
 
 func (s Article) Abstract() string {
+	if s.Summary != "" {
+		return s.Summary
+	}
 	str := []rune(s.Content)
 	if len(str) > 128 {
 		str = str[:128]
@@ -31,8 +45,35 @@ const (
 	ArticleStatusUnpublished
 	ArticleStatusPublished
 	ArticleStatusPrivate
+	// ArticleStatusScheduled 等待定时发表
+	ArticleStatusScheduled
+	// ArticleStatusPendingReview 命中了敏感词，等待人工审核，线上库还是上一次发表的版本
+	ArticleStatusPendingReview
 )
 
+// ArticleCursor 按照 (Utime, Id) 倒序翻页的游标，零值表示第一页
+type ArticleCursor struct {
+	Utime time.Time
+	Id    int64
+}
+
+func (c ArticleCursor) IsZero() bool {
+	return c.Utime.IsZero()
+}
+
+// NextArticleCursor 根据这一页的数据计算下一页的游标，
+// 不满一页说明已经没有数据了，返回零值
+func NextArticleCursor(arts []Article, limit int) ArticleCursor {
+	if len(arts) == 0 || len(arts) < limit {
+		return ArticleCursor{}
+	}
+	last := arts[len(arts)-1]
+	return ArticleCursor{
+		Utime: last.Utime,
+		Id:    last.Id,
+	}
+}
+
 type Author struct {
 	Id   int64
 	Name string
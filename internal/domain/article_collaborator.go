@@ -0,0 +1,71 @@
+package domain
+
+import "time"
+
+// ArticleRole 用户在文章上的角色，数值越大权限越多
+type ArticleRole uint8
+
+const (
+	ArticleRoleUnknown ArticleRole = iota
+	// ArticleRoleViewer 可以看草稿和历史版本
+	ArticleRoleViewer
+	// ArticleRoleEditor 还可以修改、发表和撤回
+	ArticleRoleEditor
+	// ArticleRoleOwner 文章的作者，还可以删除文章和管理合作者
+	ArticleRoleOwner
+)
+
+func (r ArticleRole) ToUint8() uint8 {
+	return uint8(r)
+}
+
+func (r ArticleRole) String() string {
+	switch r {
+	case ArticleRoleViewer:
+		return "viewer"
+	case ArticleRoleEditor:
+		return "editor"
+	case ArticleRoleOwner:
+		return "owner"
+	default:
+		return "unknown"
+	}
+}
+
+// Collaborator 文章的合作者，作者本人不算
+type Collaborator struct {
+	Uid  int64
+	Name string
+	Role ArticleRole
+	// Inviter 谁邀请的
+	Inviter int64
+	Ctime   time.Time
+}
+
+const (
+	ArticleAuditCreate          = "create"
+	ArticleAuditEdit            = "edit"
+	ArticleAuditPublish         = "publish"
+	ArticleAuditWithdraw        = "withdraw"
+	ArticleAuditSchedule        = "schedule"
+	ArticleAuditCancelSchedule  = "cancel_schedule"
+	ArticleAuditRestoreRevision = "restore_revision"
+	ArticleAuditDelete          = "delete"
+	ArticleAuditRestore         = "restore"
+	ArticleAuditAddCollaborator = "add_collaborator"
+	ArticleAuditDelCollaborator = "del_collaborator"
+	ArticleAuditSubmitReview    = "submit_review"
+	ArticleAuditApprove         = "approve"
+	ArticleAuditReject          = "reject"
+)
+
+// ArticleAudit 谁在什么时候对文章做了什么
+type ArticleAudit struct {
+	Id        int64
+	ArticleId int64
+	Uid       int64
+	Action    string
+	// Detail 补充说明，比如恢复的版本号、合作者的角色
+	Detail string
+	Ctime  time.Time
+}
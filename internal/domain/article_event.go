@@ -0,0 +1,12 @@
+package domain
+
+import "time"
+
+// ArticleEvent 发件箱里面等待投递的文章生命周期事件
+type ArticleEvent struct {
+	Id      int64
+	Type    string
+	Article Article
+	Retries int
+	Ctime   time.Time
+}
@@ -0,0 +1,7 @@
+package domain
+
+// RelatedArticle 离线计算好的相关文章，Score 越大越相关
+type RelatedArticle struct {
+	Id    int64   `json:"id"`
+	Score float64 `json:"score"`
+}
@@ -0,0 +1,52 @@
+package domain
+
+import "time"
+
+type ReviewStatus uint8
+
+const (
+	ReviewStatusUnknown ReviewStatus = iota
+	ReviewStatusPending
+	ReviewStatusApproved
+	ReviewStatusRejected
+	// ReviewStatusCancelled 作者重新提交或者修改了文章，这次审核作废
+	ReviewStatusCancelled
+)
+
+func (s ReviewStatus) ToUint8() uint8 {
+	return uint8(s)
+}
+
+func (s ReviewStatus) String() string {
+	switch s {
+	case ReviewStatusPending:
+		return "pending"
+	case ReviewStatusApproved:
+		return "approved"
+	case ReviewStatusRejected:
+		return "rejected"
+	case ReviewStatusCancelled:
+		return "cancelled"
+	default:
+		return "unknown"
+	}
+}
+
+// ArticleReview 发表的时候没有通过自动检查的文章，等待管理员审核
+type ArticleReview struct {
+	Id        int64
+	ArticleId int64
+	// Title 提交审核时候的标题，方便管理员在列表里面看
+	Title  string
+	Author int64
+	// Submitter 作者或者合作者
+	Submitter int64
+	// Hits 命中的敏感词
+	Hits     []string
+	Status   ReviewStatus
+	Reviewer int64
+	// Reason 驳回的原因
+	Reason string
+	Ctime  time.Time
+	Utime  time.Time
+}
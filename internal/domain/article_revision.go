@@ -0,0 +1,15 @@
+package domain
+
+import "time"
+
+// ArticleRevision 文章的一个历史版本，一旦写入就不再修改
+type ArticleRevision struct {
+	Id        int64
+	ArticleId int64
+	Version   int64
+	Title     string
+	Content   string
+	Status    ArticleStatus
+	Author    Author
+	Ctime     time.Time
+}
@@ -0,0 +1,19 @@
+package domain
+
+import "time"
+
+// Blob 上传的图片和附件，按照内容的 sha256 寻址
+type Blob struct {
+	Id       int64
+	Hash     string
+	Size     int64
+	MimeType string
+	Uploader int64
+	Ctime    time.Time
+	Utime    time.Time
+}
+
+// Path 文章里面引用 blob 用的地址
+func (b Blob) Path() string {
+	return "/blobs/" + b.Hash
+}
@@ -0,0 +1,12 @@
+package domain
+
+import "time"
+
+// Feed 生成好的订阅或者 sitemap 文档
+type Feed struct {
+	Content []byte
+	// ETag 带引号，可以直接放到响应头里面
+	ETag string
+	// Mtime 文档里面最新的文章修改时间
+	Mtime time.Time
+}
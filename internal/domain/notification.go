@@ -0,0 +1,20 @@
+package domain
+
+import "time"
+
+const (
+	NotificationArticleApproved = "article_approved"
+	NotificationArticleRejected = "article_rejected"
+)
+
+// Notification 站内通知
+type Notification struct {
+	Id  int64
+	Uid int64
+	// Type 前端按照类型决定怎么展示，BizId 是对应的业务 ID，比如文章 ID
+	Type    string
+	BizId   int64
+	Content string
+	Read    bool
+	Ctime   time.Time
+}
@@ -0,0 +1,17 @@
+package domain
+
+type SearchResult struct {
+	// Total 命中的文章总数
+	Total    int
+	Articles []SearchArticle
+}
+
+// SearchArticle 搜索结果里面的文章，Title 和 Snippet 已经高亮处理过
+type SearchArticle struct {
+	Id      int64
+	Title   string
+	Snippet string
+	Author  Author
+	Tags    []string
+	Score   float64
+}
@@ -0,0 +1,38 @@
+package domain
+
+import "time"
+
+// Series 系列，把一篇长教程拆成多篇文章按顺序组织起来
+type Series struct {
+	Id          int64
+	Title       string
+	Description string
+	Author      Author
+	Status      SeriesStatus
+	// Articles 按照作者设置的顺序排列
+	Articles []Article
+	Ctime    time.Time
+	Utime    time.Time
+}
+
+type SeriesStatus uint8
+
+func (s SeriesStatus) ToUint8() uint8 {
+	return uint8(s)
+}
+
+const (
+	SeriesStatusUnknown SeriesStatus = iota
+	SeriesStatusUnpublished
+	SeriesStatusPublished
+)
+
+// SeriesNav 文章在系列里面的位置，Prev 和 Next 的 Id 为 0 表示没有上一篇或者下一篇
+type SeriesNav struct {
+	Series Series
+	// Index 从 0 开始，只算已经发表的文章
+	Index int
+	Total int
+	Prev  Article
+	Next  Article
+}
@@ -0,0 +1,24 @@
+package domain
+
+import "time"
+
+// ShareLink 文章的分享链接，拿到链接的人不需要登录就可以看到草稿或者仅自己可见的文章
+type ShareLink struct {
+	Id        int64
+	ArticleId int64
+	AuthorId  int64
+	Nonce     string
+	// Token 带签名的完整凭证，由 Nonce 和 ExpireAt 计算出来
+	Token    string
+	ExpireAt time.Time
+	// MaxViews 为 0 表示不限制访问次数
+	MaxViews int64
+	Views    int64
+	Revoked  bool
+	Ctime    time.Time
+}
+
+// Valid 没有撤销、没有过期、访问次数没有用完
+func (l ShareLink) Valid(now time.Time) bool {
+	return !l.Revoked && now.Before(l.ExpireAt) && (l.MaxViews == 0 || l.Views < l.MaxViews)
+}
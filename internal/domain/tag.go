@@ -0,0 +1,9 @@
+package domain
+
+import "time"
+
+type Tag struct {
+	Id    int64
+	Name  string
+	Ctime time.Time
+}
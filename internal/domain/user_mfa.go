@@ -0,0 +1,23 @@
+package domain
+
+type MFAStatus uint8
+
+const (
+	MFAStatusUnknown MFAStatus = iota
+	// MFAStatusPending 已经生成了密钥，还没有用第一个验证码确认
+	MFAStatusPending
+	MFAStatusEnabled
+)
+
+func (s MFAStatus) ToUint8() uint8 {
+	return uint8(s)
+}
+
+// UserMFA 用户的两步验证，目前只有 TOTP
+type UserMFA struct {
+	Uid    int64
+	Secret string
+	Status MFAStatus
+	// LastStep 最后一次使用的验证码所在的时间片
+	LastStep int64
+}
@@ -0,0 +1,35 @@
+package article
+
+const TopicLifecycleEvent = "article_lifecycle"
+
+// LifecycleSchemaVersion 修改 LifecycleEvent 的字段含义的时候要加一，
+// 只增加字段的时候不需要
+const LifecycleSchemaVersion = 1
+
+type LifecycleEventType string
+
+const (
+	LifecycleCreated   LifecycleEventType = "created"
+	LifecycleUpdated   LifecycleEventType = "updated"
+	LifecyclePublished LifecycleEventType = "published"
+	LifecycleWithdrawn LifecycleEventType = "withdrawn"
+	// LifecycleDeleted 移入回收站
+	LifecycleDeleted  LifecycleEventType = "deleted"
+	LifecycleRestored LifecycleEventType = "restored"
+	// LifecyclePurged 从回收站里面彻底删除，不能再恢复
+	LifecyclePurged LifecycleEventType = "purged"
+)
+
+// LifecycleEvent 文章的生命周期事件，同一篇文章的事件按照 Aid 分区，保证顺序。
+// 投递是至少一次的，消费者需要按照 EventId 去重
+type LifecycleEvent struct {
+	SchemaVersion int
+	EventId       int64
+	Type          LifecycleEventType
+	Aid           int64
+	Uid           int64
+	// Status 事件发生之后文章的状态
+	Status uint8
+	// Ctime 事件发生的时间，毫秒数
+	Ctime int64
+}
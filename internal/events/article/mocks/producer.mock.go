@@ -0,0 +1,95 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./producer.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./producer.go -package=evtmocks -destination=./mocks/producer.mock.go Producer
+//
+// Package evtmocks is a generated GoMock package.
+package evtmocks
+
+import (
+	reflect "reflect"
+	article "webook/internal/events/article"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockProducer is a mock of Producer interface.
+type MockProducer struct {
+	ctrl     *gomock.Controller
+	recorder *MockProducerMockRecorder
+}
+
+// MockProducerMockRecorder is the mock recorder for MockProducer.
+type MockProducerMockRecorder struct {
+	mock *MockProducer
+}
+
+// NewMockProducer creates a new mock instance.
+func NewMockProducer(ctrl *gomock.Controller) *MockProducer {
+	mock := &MockProducer{ctrl: ctrl}
+	mock.recorder = &MockProducerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProducer) EXPECT() *MockProducerMockRecorder {
+	return m.recorder
+}
+
+// ProduceLifecycleEvent mocks base method.
+func (m *MockProducer) ProduceLifecycleEvent(event article.LifecycleEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProduceLifecycleEvent", event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ProduceLifecycleEvent indicates an expected call of ProduceLifecycleEvent.
+func (mr *MockProducerMockRecorder) ProduceLifecycleEvent(event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProduceLifecycleEvent", reflect.TypeOf((*MockProducer)(nil).ProduceLifecycleEvent), event)
+}
+
+// ProducePublishEvent mocks base method.
+func (m *MockProducer) ProducePublishEvent(event article.PublishEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProducePublishEvent", event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ProducePublishEvent indicates an expected call of ProducePublishEvent.
+func (mr *MockProducerMockRecorder) ProducePublishEvent(event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProducePublishEvent", reflect.TypeOf((*MockProducer)(nil).ProducePublishEvent), event)
+}
+
+// ProduceReadEvent mocks base method.
+func (m *MockProducer) ProduceReadEvent(event article.ReadEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProduceReadEvent", event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ProduceReadEvent indicates an expected call of ProduceReadEvent.
+func (mr *MockProducerMockRecorder) ProduceReadEvent(event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProduceReadEvent", reflect.TypeOf((*MockProducer)(nil).ProduceReadEvent), event)
+}
+
+// ProduceWithdrawEvent mocks base method.
+func (m *MockProducer) ProduceWithdrawEvent(event article.WithdrawEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProduceWithdrawEvent", event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ProduceWithdrawEvent indicates an expected call of ProduceWithdrawEvent.
+func (mr *MockProducerMockRecorder) ProduceWithdrawEvent(event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProduceWithdrawEvent", reflect.TypeOf((*MockProducer)(nil).ProduceWithdrawEvent), event)
+}
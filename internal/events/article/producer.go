@@ -3,17 +3,44 @@ package article
 import (
 	"encoding/json"
 	"github.com/IBM/sarama"
+	"strconv"
 )
 
-const TopicReadEvent = "article_read"
+const (
+	TopicReadEvent     = "article_read"
+	TopicPublishEvent  = "article_publish"
+	TopicWithdrawEvent = "article_withdraw"
+)
 
+//go:generate mockgen -source=./producer.go -package=evtmocks -destination=./mocks/producer.mock.go Producer
 type Producer interface {
 	ProduceReadEvent(event ReadEvent) error
+	// ProducePublishEvent 和 ProduceWithdrawEvent 是生命周期事件之前的 topic，
+	// 还有消费者在订阅，和生命周期事件一起投递
+	ProducePublishEvent(event PublishEvent) error
+	ProduceWithdrawEvent(event WithdrawEvent) error
+	ProduceLifecycleEvent(event LifecycleEvent) error
 }
 
 type ReadEvent struct {
 	Aid int64
 	Uid int64
+	// Device 没有登录的时候用设备 ID 统计 UV
+	Device string
+	// Ctime 阅读的时间，毫秒数
+	Ctime int64
+}
+
+// PublishEvent 文章发表或者重新发表
+type PublishEvent struct {
+	Aid int64
+	Uid int64
+}
+
+// WithdrawEvent 文章撤回，仅自己可见
+type WithdrawEvent struct {
+	Aid int64
+	Uid int64
 }
 
 type SaramaSyncProducer struct {
@@ -27,12 +54,30 @@ func NewSaramaSyncProducer(producer sarama.SyncProducer) Producer {
 }
 
 func (s *SaramaSyncProducer) ProduceReadEvent(event ReadEvent) error {
+	return s.produce(TopicReadEvent, nil, event)
+}
+
+func (s *SaramaSyncProducer) ProducePublishEvent(event PublishEvent) error {
+	return s.produce(TopicPublishEvent, nil, event)
+}
+
+func (s *SaramaSyncProducer) ProduceWithdrawEvent(event WithdrawEvent) error {
+	return s.produce(TopicWithdrawEvent, nil, event)
+}
+
+func (s *SaramaSyncProducer) ProduceLifecycleEvent(event LifecycleEvent) error {
+	// 按照文章 ID 分区，同一篇文章的事件是有序的
+	return s.produce(TopicLifecycleEvent, sarama.StringEncoder(strconv.FormatInt(event.Aid, 10)), event)
+}
+
+func (s *SaramaSyncProducer) produce(topic string, key sarama.Encoder, event any) error {
 	val, err := json.Marshal(event)
 	if err != nil {
 		return err
 	}
 	_, _, err = s.producer.SendMessage(&sarama.ProducerMessage{
-		Topic: TopicReadEvent,
+		Topic: topic,
+		Key:   key,
 		Value: sarama.StringEncoder(val),
 	})
 	return err
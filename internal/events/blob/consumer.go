@@ -0,0 +1,57 @@
+package blob
+
+import (
+	"context"
+	"github.com/IBM/sarama"
+	"time"
+	"webook/internal/events/article"
+	"webook/internal/service"
+	"webook/pkg/logger"
+	"webook/pkg/saramax"
+)
+
+// ArticleEventConsumer 文章内容变化之后重新计算文章引用了哪些文件，
+// 没有被引用的文件由清理任务删除
+type ArticleEventConsumer struct {
+	svc    service.BlobService
+	client sarama.Client
+	l      logger.LoggerV1
+}
+
+func NewArticleEventConsumer(svc service.BlobService, client sarama.Client, l logger.LoggerV1) *ArticleEventConsumer {
+	return &ArticleEventConsumer{
+		svc:    svc,
+		client: client,
+		l:      l,
+	}
+}
+
+func (c *ArticleEventConsumer) Start() error {
+	cg, err := sarama.NewConsumerGroupFromClient("blob", c.client)
+	if err != nil {
+		return err
+	}
+	go func() {
+		er := cg.Consume(context.Background(),
+			[]string{article.TopicLifecycleEvent},
+			saramax.NewHandler[article.LifecycleEvent](c.l, c.Consume))
+		if er != nil {
+			c.l.Error("退出消费", logger.Error(er))
+		}
+	}()
+	return err
+}
+
+// Consume 每次都按照文章当前的内容重新计算，重复消费和乱序都没关系
+func (c *ArticleEventConsumer) Consume(msg *sarama.ConsumerMessage, evt article.LifecycleEvent) error {
+	switch evt.Type {
+	case article.LifecycleCreated, article.LifecycleUpdated, article.LifecyclePublished,
+		article.LifecycleRestored, article.LifecyclePurged:
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		return c.svc.SyncArticle(ctx, evt.Aid)
+	default:
+		// 撤回和移入回收站的文章还可能恢复，引用保留
+		return nil
+	}
+}
@@ -0,0 +1,55 @@
+package feed
+
+import (
+	"context"
+	"github.com/IBM/sarama"
+	"time"
+	"webook/internal/events/article"
+	"webook/internal/service"
+	"webook/pkg/logger"
+	"webook/pkg/saramax"
+)
+
+// ArticleEventConsumer 文章发表、撤回之后清理订阅和 sitemap 的缓存。
+// 缓存在 Redis 里面，所有实例共用一个消费者组
+type ArticleEventConsumer struct {
+	svc    service.FeedService
+	client sarama.Client
+	l      logger.LoggerV1
+}
+
+func NewArticleEventConsumer(svc service.FeedService, client sarama.Client, l logger.LoggerV1) *ArticleEventConsumer {
+	return &ArticleEventConsumer{
+		svc:    svc,
+		client: client,
+		l:      l,
+	}
+}
+
+func (c *ArticleEventConsumer) Start() error {
+	cg, err := sarama.NewConsumerGroupFromClient("feed", c.client)
+	if err != nil {
+		return err
+	}
+	go func() {
+		er := cg.Consume(context.Background(),
+			[]string{article.TopicLifecycleEvent},
+			saramax.NewHandler[article.LifecycleEvent](c.l, c.Consume))
+		if er != nil {
+			c.l.Error("退出消费", logger.Error(er))
+		}
+	}()
+	return err
+}
+
+func (c *ArticleEventConsumer) Consume(msg *sarama.ConsumerMessage, evt article.LifecycleEvent) error {
+	switch evt.Type {
+	case article.LifecyclePublished, article.LifecycleWithdrawn,
+		article.LifecycleDeleted, article.LifecycleRestored, article.LifecyclePurged:
+	default:
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	return c.svc.Invalidate(ctx, evt.Uid)
+}
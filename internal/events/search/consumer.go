@@ -0,0 +1,66 @@
+package search
+
+import (
+	"context"
+	"github.com/IBM/sarama"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/events/article"
+	"webook/internal/service"
+	"webook/pkg/logger"
+	"webook/pkg/saramax"
+)
+
+// ArticleEventConsumer 把文章的发表和撤回同步到搜索索引。
+// 索引在进程内，所以每个实例都要消费全部消息，每个实例用一个固定的消费者组，
+// 重启之后从提交的位置继续消费
+type ArticleEventConsumer struct {
+	svc    service.SearchService
+	client sarama.Client
+	l      logger.LoggerV1
+	group  string
+}
+
+func NewArticleEventConsumer(svc service.SearchService, client sarama.Client, group string, l logger.LoggerV1) *ArticleEventConsumer {
+	return &ArticleEventConsumer{
+		svc:    svc,
+		client: client,
+		l:      l,
+		group:  group,
+	}
+}
+
+func (c *ArticleEventConsumer) Start() error {
+	cg, err := sarama.NewConsumerGroupFromClient(c.group, c.client)
+	if err != nil {
+		return err
+	}
+	go func() {
+		er := cg.Consume(context.Background(),
+			[]string{article.TopicLifecycleEvent},
+			saramax.NewHandler[article.LifecycleEvent](c.l, c.Consume))
+		if er != nil {
+			c.l.Error("退出消费", logger.Error(er))
+		}
+	}()
+	return err
+}
+
+// Consume 重复消费也没关系，索引和删除都是幂等的
+func (c *ArticleEventConsumer) Consume(msg *sarama.ConsumerMessage, evt article.LifecycleEvent) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	switch evt.Type {
+	case article.LifecyclePublished:
+		return c.svc.IndexArticle(ctx, evt.Aid)
+	case article.LifecycleRestored:
+		if evt.Status != domain.ArticleStatusPublished {
+			return nil
+		}
+		return c.svc.IndexArticle(ctx, evt.Aid)
+	case article.LifecycleWithdrawn, article.LifecycleDeleted:
+		return c.svc.RemoveArticle(ctx, evt.Aid)
+	default:
+		return nil
+	}
+}
@@ -49,6 +49,8 @@ func (s *ArticleHandlerSuite) SetupSuite() {
 
 func (s *ArticleHandlerSuite) TearDownTest() {
 	s.db.Exec("truncate table `articles`")
+	s.db.Exec("truncate table `article_events`")
+	s.db.Exec("truncate table `article_audits`")
 }
 
 func (s *ArticleHandlerSuite) TestEdit() {
@@ -164,7 +166,8 @@ func (s *ArticleHandlerSuite) TestEdit() {
 			},
 			wantCode: http.StatusOK,
 			wantResult: Result[int64]{
-				Data: 3,
+				Code: 4,
+				Msg:  "没有权限",
 			},
 		},
 	}
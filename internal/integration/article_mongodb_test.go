@@ -24,16 +24,22 @@ type ArticleMongoDBHandlerSuite struct {
 	db      *mongo.Database
 	col     *mongo.Collection
 	liveCol *mongo.Collection
+	revCol  *mongo.Collection
+	evtCol  *mongo.Collection
 	server  *gin.Engine
 }
 
 func (s *ArticleMongoDBHandlerSuite) SetupSuite() {
 	s.db = startup.InitMongoDB()
+	err := dao.InitCollections(s.db)
+	assert.NoError(s.T(), err)
 	node, err := snowflake.NewNode(1)
 	assert.NoError(s.T(), err)
-	col := s.db.Collection("articles")
-	liveCol := s.db.Collection("published_articles")
-	hdl := startup.InitArticleHandler(dao.NewMongoDBArticleDAO(node, col, liveCol))
+	s.col = s.db.Collection("articles")
+	s.liveCol = s.db.Collection("published_articles")
+	s.revCol = s.db.Collection("article_revisions")
+	s.evtCol = s.db.Collection("article_events")
+	hdl := startup.InitArticleHandler(dao.NewMongoDBArticleDAO(node, s.col, s.liveCol, s.revCol, s.evtCol, dao.NewGORMTagDAO(startup.InitDB())))
 
 	server := gin.Default()
 	server.Use(func(ctx *gin.Context) {
@@ -52,6 +58,10 @@ func (s *ArticleMongoDBHandlerSuite) TearDownTest() {
 	assert.NoError(s.T(), err)
 	_, err = s.liveCol.DeleteMany(ctx, bson.D{})
 	assert.NoError(s.T(), err)
+	_, err = s.revCol.DeleteMany(ctx, bson.D{})
+	assert.NoError(s.T(), err)
+	_, err = s.evtCol.DeleteMany(ctx, bson.D{})
+	assert.NoError(s.T(), err)
 }
 
 func (s *ArticleMongoDBHandlerSuite) TestEdit() {
@@ -77,13 +87,13 @@ func (s *ArticleMongoDBHandlerSuite) TestEdit() {
 
 				assert.True(t, art.Ctime > 0)
 				assert.True(t, art.Utime > 0)
+				// id 是 snowflake 生成的
 				assert.True(t, art.Id > 0)
 
 				art.Ctime = 0
 				art.Utime = 0
 				art.Id = 0
 				assert.Equal(t, dao.Article{
-					Id:       1,
 					Title:    "这是标题",
 					Content:  "这是内容",
 					AuthorId: 1,
@@ -99,85 +109,94 @@ func (s *ArticleMongoDBHandlerSuite) TestEdit() {
 				Data: 1,
 			},
 		},
-		//{
-		//	name: "修改帖子",
-		//	before: func(t *testing.T) {
-		//		err := s.db.Create(dao.Article{
-		//			Id:       2,
-		//			Title:    "我的标题",
-		//			Content:  "我的内容",
-		//			Status:   2,
-		//			AuthorId: 1,
-		//			Ctime:    456,
-		//			Utime:    789,
-		//		}).Error
-		//		assert.NoError(t, err)
-		//	},
-		//	after: func(t *testing.T) {
-		//		//验证保存到了数据库中
-		//		var art = dao.Article{}
-		//		err := s.db.Where("id=?", 2).First(&art).Error
-		//		assert.NoError(t, err)
-		//		assert.True(t, art.Utime > 789)
-		//		art.Utime = 0
-		//		assert.Equal(t, dao.Article{
-		//			Id:       2,
-		//			Title:    "新的标题",
-		//			Content:  "新的内容",
-		//			Status:   1,
-		//			AuthorId: 1,
-		//			Ctime:    456,
-		//		}, art)
-		//	},
-		//	art: Article{
-		//		Id:      2,
-		//		Title:   "新的标题",
-		//		Content: "新的内容",
-		//	},
-		//	wantCode: http.StatusOK,
-		//	wantResult: Result[int64]{
-		//		Data: 2,
-		//	},
-		//},
-		//{
-		//	name: "修改帖子-别人的帖子",
-		//	before: func(t *testing.T) {
-		//		err := s.db.Create(dao.Article{
-		//			Id:       3,
-		//			Title:    "我的标题",
-		//			Content:  "我的内容",
-		//			Status:   1,
-		//			AuthorId: 2,
-		//			Ctime:    456,
-		//			Utime:    789,
-		//		}).Error
-		//		assert.NoError(t, err)
-		//	},
-		//	after: func(t *testing.T) {
-		//		//验证保存到了数据库中
-		//		var art = dao.Article{}
-		//		err := s.db.Where("id=?", 3).First(&art).Error
-		//		assert.NoError(t, err)
-		//		assert.Equal(t, dao.Article{
-		//			Id:       3,
-		//			Title:    "我的标题",
-		//			Content:  "我的内容",
-		//			Status:   1,
-		//			AuthorId: 2,
-		//			Ctime:    456,
-		//			Utime:    789,
-		//		}, art)
-		//	},
-		//	art: Article{
-		//		Id:      3,
-		//		Title:   "新的标题",
-		//		Content: "新的内容",
-		//	},
-		//	wantCode: http.StatusOK,
-		//	wantResult: Result[int64]{
-		//		Data: 3,
-		//	},
-		//},
+		{
+			name: "修改帖子",
+			before: func(t *testing.T) {
+				ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+				defer cancel()
+				_, err := s.col.InsertOne(ctx, dao.Article{
+					Id:       2,
+					Title:    "我的标题",
+					Content:  "我的内容",
+					Status:   2,
+					AuthorId: 1,
+					Ctime:    456,
+					Utime:    789,
+				})
+				assert.NoError(t, err)
+			},
+			after: func(t *testing.T) {
+				ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+				defer cancel()
+				//验证保存到了数据库中
+				var art = dao.Article{}
+				err := s.col.FindOne(ctx, bson.D{bson.E{Key: "id", Value: 2}}).Decode(&art)
+				assert.NoError(t, err)
+				assert.True(t, art.Utime > 789)
+				art.Utime = 0
+				assert.Equal(t, dao.Article{
+					Id:       2,
+					Title:    "新的标题",
+					Content:  "新的内容",
+					Status:   1,
+					AuthorId: 1,
+					Ctime:    456,
+				}, art)
+			},
+			art: Article{
+				Id:      2,
+				Title:   "新的标题",
+				Content: "新的内容",
+			},
+			wantCode: http.StatusOK,
+			wantResult: Result[int64]{
+				Data: 2,
+			},
+		},
+		{
+			name: "修改帖子-别人的帖子",
+			before: func(t *testing.T) {
+				ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+				defer cancel()
+				_, err := s.col.InsertOne(ctx, dao.Article{
+					Id:       3,
+					Title:    "我的标题",
+					Content:  "我的内容",
+					Status:   1,
+					AuthorId: 2,
+					Ctime:    456,
+					Utime:    789,
+				})
+				assert.NoError(t, err)
+			},
+			after: func(t *testing.T) {
+				ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+				defer cancel()
+				//验证没有被修改
+				var art = dao.Article{}
+				err := s.col.FindOne(ctx, bson.D{bson.E{Key: "id", Value: 3}}).Decode(&art)
+				assert.NoError(t, err)
+				assert.Equal(t, dao.Article{
+					Id:       3,
+					Title:    "我的标题",
+					Content:  "我的内容",
+					Status:   1,
+					AuthorId: 2,
+					Ctime:    456,
+					Utime:    789,
+				}, art)
+			},
+			art: Article{
+				Id:      3,
+				Title:   "新的标题",
+				Content: "新的内容",
+			},
+			wantCode: http.StatusOK,
+			wantResult: Result[int64]{
+				Code: 4,
+				Msg:  "没有权限",
+			},
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -205,7 +224,9 @@ func (s *ArticleMongoDBHandlerSuite) TestEdit() {
 			assert.NoError(t, err)
 			if tc.wantResult.Data > 0 {
 				assert.True(t, res.Data > 0)
+				return
 			}
+			assert.Equal(t, tc.wantResult, res)
 		})
 	}
 
@@ -0,0 +1,185 @@
+package integration
+
+import (
+	"context"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/integration/startup"
+	"webook/internal/repository"
+	"webook/internal/repository/cache"
+	"webook/pkg/cachex"
+)
+
+type ArticlePubCacheTestSuite struct {
+	suite.Suite
+	rdb redis.UniversalClient
+}
+
+func (s *ArticlePubCacheTestSuite) SetupSuite() {
+	s.rdb = startup.InitRedis().(redis.UniversalClient)
+}
+
+func (s *ArticlePubCacheTestSuite) TearDownTest() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+	defer cancel()
+	keys, err := s.rdb.Keys(ctx, "test:article:pub:*").Result()
+	require.NoError(s.T(), err)
+	if len(keys) > 0 {
+		require.NoError(s.T(), s.rdb.Del(ctx, keys...).Err())
+	}
+}
+
+func (s *ArticlePubCacheTestSuite) newCache() *cachex.MultiLevelCache[domain.Article] {
+	return cachex.NewMultiLevelCache[domain.Article](s.rdb, cachex.Config{
+		Prefix:      "test:article:pub:detail:",
+		Channel:     "test:article:pub:invalidate",
+		LocalSize:   100,
+		LocalTTL:    time.Minute,
+		TTL:         time.Minute,
+		NotFoundTTL: time.Second * 10,
+		ErrNotFound: repository.ErrArticleNotFound,
+	})
+}
+
+func (s *ArticlePubCacheTestSuite) TestSingleflight() {
+	t := s.T()
+	c := s.newCache()
+	var cnt atomic.Int64
+	load := func(ctx context.Context) (domain.Article, error) {
+		cnt.Add(1)
+		time.Sleep(time.Millisecond * 100)
+		return domain.Article{Id: 1, Title: "标题"}, nil
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			art, err := c.Get(context.Background(), "1", load)
+			assert.NoError(t, err)
+			assert.Equal(t, "标题", art.Title)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int64(1), cnt.Load())
+}
+
+func (s *ArticlePubCacheTestSuite) TestNotFound() {
+	t := s.T()
+	ctx := context.Background()
+	c := s.newCache()
+	var cnt atomic.Int64
+	load := func(ctx context.Context) (domain.Article, error) {
+		cnt.Add(1)
+		return domain.Article{}, repository.ErrArticleNotFound
+	}
+	_, err := c.Get(ctx, "2", load)
+	assert.Equal(t, repository.ErrArticleNotFound, err)
+	val, err := s.rdb.Get(ctx, "test:article:pub:detail:2").Result()
+	require.NoError(t, err)
+	assert.Equal(t, "", val)
+
+	// 别的实例命中 Redis 里面的空值，也不回源
+	_, err = s.newCache().Get(ctx, "2", load)
+	assert.Equal(t, repository.ErrArticleNotFound, err)
+	assert.Equal(t, int64(1), cnt.Load())
+}
+
+func (s *ArticlePubCacheTestSuite) TestBloom() {
+	t := s.T()
+	ctx := context.Background()
+	bloom := cachex.NewRedisBloomFilter(s.rdb, "{test:article:pub:bloom}", 1000, 0.01)
+	c := cache.NewArticlePubCache(s.newCache(), bloom)
+
+	// 还没有重建的时候都当成可能存在
+	ok, err := c.MightExist(ctx, 100)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	err = c.RebuildIds(ctx, func(add func(ids ...int64) error) error {
+		return add(1, 2, 3)
+	})
+	require.NoError(t, err)
+	ok, err = c.MightExist(ctx, 2)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = c.MightExist(ctx, 100)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	// 重建之后新发表的
+	require.NoError(t, c.AddIds(ctx, 100))
+	ok, err = c.MightExist(ctx, 100)
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func (s *ArticlePubCacheTestSuite) TestInvalidate() {
+	t := s.T()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c1, c2 := s.newCache(), s.newCache()
+	go func() {
+		_ = c2.Subscribe(ctx)
+	}()
+	// 等订阅生效
+	require.Eventually(t, func() bool {
+		res, err := s.rdb.PubSubNumSub(ctx, "test:article:pub:invalidate").Result()
+		return err == nil && res["test:article:pub:invalidate"] > 0
+	}, time.Second*3, time.Millisecond*10)
+
+	title := "旧标题"
+	load := func(ctx context.Context) (domain.Article, error) {
+		return domain.Article{Id: 3, Title: title}, nil
+	}
+	art, err := c2.Get(ctx, "3", load)
+	require.NoError(t, err)
+	assert.Equal(t, "旧标题", art.Title)
+
+	title = "新标题"
+	require.NoError(t, c1.Delete(ctx, "3"))
+	assert.Eventually(t, func() bool {
+		art, err := c2.Get(ctx, "3", load)
+		return err == nil && art.Title == "新标题"
+	}, time.Second*3, time.Millisecond*10)
+}
+
+// TestDeleteWhileLoading 回源期间数据被修改了，旧的值不能写回缓存
+func (s *ArticlePubCacheTestSuite) TestDeleteWhileLoading() {
+	t := s.T()
+	ctx := context.Background()
+	c := s.newCache()
+	loading, deleted := make(chan struct{}), make(chan struct{})
+	go func() {
+		<-loading
+		assert.NoError(t, c.Delete(ctx, "4"))
+		close(deleted)
+	}()
+	art, err := c.Get(ctx, "4", func(ctx context.Context) (domain.Article, error) {
+		close(loading)
+		<-deleted
+		return domain.Article{Id: 4, Title: "旧标题"}, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "旧标题", art.Title)
+
+	cnt, err := s.rdb.Exists(ctx, "test:article:pub:detail:4").Result()
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), cnt)
+	art, err = c.Get(ctx, "4", func(ctx context.Context) (domain.Article, error) {
+		return domain.Article{Id: 4, Title: "新标题"}, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "新标题", art.Title)
+}
+
+func TestArticlePubCache(t *testing.T) {
+	suite.Run(t, &ArticlePubCacheTestSuite{})
+}
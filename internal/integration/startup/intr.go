@@ -0,0 +1,17 @@
+package startup
+
+import (
+	commentv1 "webook/api/proto/gen/comment/v1"
+	intrv1 "webook/api/proto/gen/intr/v1"
+	"webook/interactive/service"
+	"webook/internal/client"
+)
+
+// InitIntrClient 测试里面直接调用本地的 InteractiveService，不走 grpc
+func InitIntrClient(svc service.InteractiveService) intrv1.InteractiveServiceClient {
+	return client.NewLocalInteractiveServiceAdapter(svc)
+}
+
+func InitCommentClient(svc service.CommentService) commentv1.CommentServiceClient {
+	return client.NewLocalCommentServiceAdapter(svc)
+}
@@ -0,0 +1,30 @@
+package startup
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"github.com/redis/go-redis/v9"
+	ijwt "webook/internal/web/jwt"
+	"webook/pkg/jwtx"
+)
+
+// InitJWTHandler 测试里面每次启动都生成新的密钥
+func InitJWTHandler(client redis.Cmdable) ijwt.Handler {
+	return ijwt.NewRedisJWTHandler(client, newJWTKeySet("access"), newJWTKeySet("refresh"))
+}
+
+func newJWTKeySet(kid string) *jwtx.KeySet {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	key, err := jwtx.NewKey(kid, private)
+	if err != nil {
+		panic(err)
+	}
+	ks, err := jwtx.NewKeySet(kid, key)
+	if err != nil {
+		panic(err)
+	}
+	return ks
+}
@@ -0,0 +1,66 @@
+package startup
+
+import (
+	"github.com/redis/go-redis/v9"
+	"os"
+	"path/filepath"
+	"time"
+	"webook/internal/repository"
+	"webook/internal/service"
+	"webook/internal/web"
+	"webook/pkg/blobx"
+	"webook/pkg/limiter"
+	"webook/pkg/logger"
+	"webook/pkg/sensitive"
+)
+
+func InitSearchService(repo repository.ArticleRepository, l logger.LoggerV1) service.SearchService {
+	return service.NewLocalSearchService(repo, filepath.Join("testdata", "articles.snapshot"), l)
+}
+
+func InitShareService(repo repository.ShareLinkRepository, artRepo repository.ArticleRepository, l logger.LoggerV1) service.ShareService {
+	return service.NewShareService(repo, artRepo, []byte("test-share-key-0123456789abcdefgh"), l)
+}
+
+func InitBlobHandler(repo repository.BlobRepository, artRepo repository.ArticleRepository, l logger.LoggerV1) *web.BlobHandler {
+	dir, err := os.MkdirTemp("", "webook-blobs-*")
+	if err != nil {
+		panic(err)
+	}
+	store, err := blobx.NewLocalStore(dir)
+	if err != nil {
+		panic(err)
+	}
+	const maxSize = 1 << 20
+	return web.NewBlobHandler(service.NewBlobService(repo, artRepo, store, maxSize, l), maxSize, l)
+}
+
+func InitSensitiveMatcher() sensitive.Matcher {
+	return sensitive.NewTrie([]string{"赌博", "代开发票"})
+}
+
+func InitReviewHandler(svc service.ArticleReviewService, l logger.LoggerV1) *web.ReviewHandler {
+	return web.NewReviewHandler(svc, []int64{1}, l)
+}
+
+func InitFeedService(artRepo repository.ArticleRepository, userRepo repository.UserRepository,
+	repo repository.FeedRepository, l logger.LoggerV1) service.FeedService {
+	return service.NewFeedService(artRepo, userRepo, repo, "http://localhost:8080", "webook", l)
+}
+
+func InitArchiveHandler(repo repository.ArchiveTaskRepository, artRepo repository.ArticleRepository,
+	artSvc service.ArticleService, client redis.Cmdable, l logger.LoggerV1) *web.ArchiveHandler {
+	dir, err := os.MkdirTemp("", "webook-archives-*")
+	if err != nil {
+		panic(err)
+	}
+	store, err := blobx.NewLocalStore(dir)
+	if err != nil {
+		panic(err)
+	}
+	const maxSize = 10 << 20
+	svc := service.NewArchiveService(repo, artRepo, artSvc, store,
+		[]byte("test-archive-key-0123456789abcdef"), maxSize,
+		limiter.NewRedisSlidingWindowLimiter(client, time.Hour, 100), l)
+	return web.NewArchiveHandler(svc, maxSize, l)
+}
@@ -9,18 +9,19 @@ import (
 	cache2 "webook/interactive/repository/cache"
 	dao2 "webook/interactive/repository/dao"
 	service2 "webook/interactive/service"
+	"webook/internal/events/article"
 	"webook/internal/repository"
 	"webook/internal/repository/cache"
 	"webook/internal/repository/dao"
 	"webook/internal/service"
 	"webook/internal/web"
-	"webook/internal/web/jwt"
 	"webook/ioc"
 )
 
 var thirdPartySet = wire.NewSet(
 	InitLogger,
-	InitDB, InitRedis, ioc.InitLocalMem)
+	InitDB, InitRedis, ioc.InitLocalMem,
+	InitSaramaClient, ioc.InitSyncProducer)
 
 var userSvcProvider = wire.NewSet(
 	dao.NewGORMUserDAO,
@@ -33,21 +34,82 @@ var interactiveSvcSet = wire.NewSet(
 	cache2.NewInteractiveRedisCache,
 	repository2.NewCachedInteractiveRepository,
 	service2.NewInteractiveService,
+	InitIntrClient,
 )
 
+var commentSvcSet = wire.NewSet(
+	dao2.NewGORMCommentDAO,
+	repository2.NewCachedCommentRepository,
+	service2.NewCommentService,
+	InitCommentClient,
+)
+
+var articleSvcProvider = wire.NewSet(
+	dao.NewGORMTagDAO,
+	dao.NewGORMArticleCollaboratorDAO,
+	cache.NewArticleRedisCache,
+	repository.NewCacheArticleRepository,
+	ioc.InitArticlePubCache,
+	repository.NewArticleCollaboratorRepository,
+	ioc.InitRankingCache,
+	repository.NewCachedRankingRepository,
+	article.NewSaramaSyncProducer,
+	InitSensitiveMatcher,
+	dao.NewGORMArticleReviewDAO,
+	repository.NewArticleReviewRepository,
+	service.NewArticleService)
+
+var seriesSvcSet = wire.NewSet(
+	dao.NewGORMSeriesDAO,
+	repository.NewSeriesRepository,
+	service.NewSeriesService)
+
 func InitWebServer() *gin.Engine {
 	wire.Build(
 		thirdPartySet,
 		interactiveSvcSet,
+		articleSvcProvider,
+		seriesSvcSet,
 		dao.NewArticleGORMDAO,
-		dao.NewGORMUserDAO, cache.NewRedisUserCache, cache.NewLocalCodeCache, cache.NewArticleRedisCache,
-		repository.NewCacheArticleRepository,
+		dao.NewGORMUserDAO, cache.NewRedisUserCache, cache.NewLocalCodeCache,
 		repository.NewCacheUserRepository, repository.NewCacheCodeRepository,
 		ioc.InitSMSService,
+		ioc.InitEmailService,
 		ioc.InitWechatService,
-		service.NewArticleService,
 		service.NewCacheUserService, service.NewCacheCodeService,
-		jwt.NewRedisJWTHandler,
+		dao.NewGORMUserMFADAO, cache.NewUserMFARedisCache,
+		repository.NewCachedUserMFARepository, service.NewUserMFAService,
+		InitJWTHandler,
+		web.NewJWKSHandler,
+		InitSearchService,
+		web.NewSearchHandler,
+		dao.NewGORMShareLinkDAO,
+		repository.NewShareLinkRepository,
+		InitShareService,
+		web.NewShareHandler,
+		web.NewSeriesHandler,
+		dao.NewGORMBlobDAO,
+		repository.NewBlobRepository,
+		InitBlobHandler,
+		commentSvcSet,
+		web.NewCommentHandler,
+		dao.NewGORMNotificationDAO,
+		repository.NewNotificationRepository,
+		service.NewNotificationService,
+		service.NewArticleReviewService,
+		InitReviewHandler,
+		web.NewNotificationHandler,
+		cache.NewFeedRedisCache,
+		repository.NewCachedFeedRepository,
+		InitFeedService,
+		web.NewFeedHandler,
+		dao.NewGORMArchiveTaskDAO,
+		repository.NewArchiveTaskRepository,
+		InitArchiveHandler,
+		cache.NewArticleRelatedRedisCache,
+		repository.NewCachedArticleRelatedRepository,
+		service.NewArticleRelatedService,
+		web.NewArticleRelatedHandler,
 		web.NewArticleHandler,
 		web.NewUserHandler,
 		web.NewOAuth2WechatHandler,
@@ -61,9 +123,8 @@ func InitArticleHandler(dao dao.ArticleDAO) *web.ArticleHandler {
 	wire.Build(thirdPartySet,
 		interactiveSvcSet,
 		userSvcProvider,
-		cache.NewArticleRedisCache,
-		repository.NewCacheArticleRepository,
-		service.NewArticleService,
+		articleSvcProvider,
+		seriesSvcSet,
 		web.NewArticleHandler)
 	return &web.ArticleHandler{}
 }
@@ -13,12 +13,12 @@ import (
 	cache2 "webook/interactive/repository/cache"
 	dao2 "webook/interactive/repository/dao"
 	service2 "webook/interactive/service"
+	"webook/internal/events/article"
 	"webook/internal/repository"
 	"webook/internal/repository/cache"
 	"webook/internal/repository/dao"
 	"webook/internal/service"
 	"webook/internal/web"
-	"webook/internal/web/jwt"
 	"webook/ioc"
 )
 
@@ -26,7 +26,7 @@ import (
 
 func InitWebServer() *gin.Engine {
 	cmdable := InitRedis()
-	handler := jwt.NewRedisJWTHandler(cmdable)
+	handler := InitJWTHandler(cmdable)
 	loggerV1 := InitLogger()
 	v := ioc.InitGinMiddleWares(cmdable, handler, loggerV1)
 	db := InitDB()
@@ -38,38 +38,108 @@ func InitWebServer() *gin.Engine {
 	codeCache := cache.NewLocalCodeCache(freecacheCache)
 	codeRepository := repository.NewCacheCodeRepository(codeCache)
 	smsService := ioc.InitSMSService()
-	codeService := service.NewCacheCodeService(codeRepository, smsService)
-	userHandler := web.NewUserHandler(userService, codeService, handler)
+	emailService := ioc.InitEmailService()
+	codeService := service.NewCacheCodeService(codeRepository, smsService, emailService)
+	userMFADAO := dao.NewGORMUserMFADAO(db)
+	userMFACache := cache.NewUserMFARedisCache(cmdable)
+	userMFARepository := repository.NewCachedUserMFARepository(userMFADAO, userMFACache)
+	userMFAService := service.NewUserMFAService(userMFARepository, userRepository)
+	userHandler := web.NewUserHandler(userService, codeService, userMFAService, handler, loggerV1)
 	wechatService := ioc.InitWechatService()
 	oAuth2WechatHandler := web.NewOAuth2WechatHandler(wechatService, userService, handler)
 	articleDAO := dao.NewArticleGORMDAO(db)
+	tagDAO := dao.NewGORMTagDAO(db)
 	articleCache := cache.NewArticleRedisCache(cmdable)
-	articleRepository := repository.NewCacheArticleRepository(articleDAO, userRepository, articleCache)
-	articleService := service.NewArticleService(articleRepository)
+	articlePubCache := ioc.InitArticlePubCache(cmdable, loggerV1)
+	articleRepository := repository.NewCacheArticleRepository(articleDAO, tagDAO, userRepository, articleCache, articlePubCache)
+	client := InitSaramaClient()
+	syncProducer := ioc.InitSyncProducer(client)
+	producer := article.NewSaramaSyncProducer(syncProducer)
+	rankingCache := ioc.InitRankingCache(cmdable)
+	rankingRepository := repository.NewCachedRankingRepository(rankingCache)
+	articleCollaboratorDAO := dao.NewGORMArticleCollaboratorDAO(db)
+	articleCollaboratorRepository := repository.NewArticleCollaboratorRepository(articleCollaboratorDAO, articleDAO, userRepository)
+	matcher := InitSensitiveMatcher()
+	articleReviewDAO := dao.NewGORMArticleReviewDAO(db)
+	articleReviewRepository := repository.NewArticleReviewRepository(articleReviewDAO)
+	articleService := service.NewArticleService(articleRepository, articleCollaboratorRepository, rankingRepository, producer, matcher, articleReviewRepository, loggerV1)
 	interactiveDAO := dao2.NewGORMInteractiveDAO(db)
 	interactiveCache := cache2.NewInteractiveRedisCache(cmdable)
 	interactiveRepository := repository2.NewCachedInteractiveRepository(interactiveDAO, interactiveCache)
 	interactiveService := service2.NewInteractiveService(interactiveRepository)
-	articleHandler := web.NewArticleHandler(articleService, interactiveService, loggerV1)
-	engine := ioc.InitWebServer(v, userHandler, oAuth2WechatHandler, articleHandler)
+	interactiveServiceClient := InitIntrClient(interactiveService)
+	seriesDAO := dao.NewGORMSeriesDAO(db)
+	seriesRepository := repository.NewSeriesRepository(seriesDAO, articleDAO)
+	seriesService := service.NewSeriesService(seriesRepository)
+	articleHandler := web.NewArticleHandler(articleService, seriesService, interactiveServiceClient, loggerV1)
+	searchService := InitSearchService(articleRepository, loggerV1)
+	searchHandler := web.NewSearchHandler(searchService, loggerV1)
+	shareLinkDAO := dao.NewGORMShareLinkDAO(db)
+	shareLinkRepository := repository.NewShareLinkRepository(shareLinkDAO)
+	shareService := InitShareService(shareLinkRepository, articleRepository, loggerV1)
+	shareHandler := web.NewShareHandler(shareService, loggerV1)
+	seriesHandler := web.NewSeriesHandler(seriesService, interactiveServiceClient, loggerV1)
+	blobDAO := dao.NewGORMBlobDAO(db)
+	blobRepository := repository.NewBlobRepository(blobDAO)
+	blobHandler := InitBlobHandler(blobRepository, articleRepository, loggerV1)
+	commentDAO := dao2.NewGORMCommentDAO(db)
+	commentRepository := repository2.NewCachedCommentRepository(commentDAO, interactiveCache, loggerV1)
+	commentService := service2.NewCommentService(commentRepository)
+	commentServiceClient := InitCommentClient(commentService)
+	commentHandler := web.NewCommentHandler(commentServiceClient, articleService, loggerV1)
+	notificationDAO := dao.NewGORMNotificationDAO(db)
+	notificationRepository := repository.NewNotificationRepository(notificationDAO)
+	notificationService := service.NewNotificationService(notificationRepository)
+	articleReviewService := service.NewArticleReviewService(articleReviewRepository, articleRepository, articleCollaboratorRepository, notificationService, loggerV1)
+	reviewHandler := InitReviewHandler(articleReviewService, loggerV1)
+	notificationHandler := web.NewNotificationHandler(notificationService, loggerV1)
+	feedCache := cache.NewFeedRedisCache(cmdable)
+	feedRepository := repository.NewCachedFeedRepository(feedCache)
+	feedService := InitFeedService(articleRepository, userRepository, feedRepository, loggerV1)
+	feedHandler := web.NewFeedHandler(feedService, loggerV1)
+	archiveTaskDAO := dao.NewGORMArchiveTaskDAO(db)
+	archiveTaskRepository := repository.NewArchiveTaskRepository(archiveTaskDAO)
+	archiveHandler := InitArchiveHandler(archiveTaskRepository, articleRepository, articleService, cmdable, loggerV1)
+	articleRelatedCache := cache.NewArticleRelatedRedisCache(cmdable)
+	articleRelatedRepository := repository.NewCachedArticleRelatedRepository(articleRelatedCache)
+	articleRelatedService := service.NewArticleRelatedService(articleRelatedRepository, articleRepository, interactiveServiceClient, loggerV1)
+	articleRelatedHandler := web.NewArticleRelatedHandler(articleRelatedService, interactiveServiceClient, loggerV1)
+	jwksHandler := web.NewJWKSHandler(handler)
+	engine := ioc.InitWebServer(v, userHandler, oAuth2WechatHandler, articleHandler, searchHandler, shareHandler, seriesHandler, blobHandler, commentHandler, reviewHandler, notificationHandler, feedHandler, archiveHandler, articleRelatedHandler, jwksHandler)
 	return engine
 }
 
-func InitArticleHandler(dao2 dao.ArticleDAO) *web.ArticleHandler {
+func InitArticleHandler(dao3 dao.ArticleDAO) *web.ArticleHandler {
 	db := InitDB()
+	tagDAO := dao.NewGORMTagDAO(db)
 	userDAO := dao.NewGORMUserDAO(db)
 	cmdable := InitRedis()
 	userCache := cache.NewRedisUserCache(cmdable)
 	userRepository := repository.NewCacheUserRepository(userDAO, userCache)
 	articleCache := cache.NewArticleRedisCache(cmdable)
-	articleRepository := repository.NewCacheArticleRepository(dao2, userRepository, articleCache)
-	articleService := service.NewArticleService(articleRepository)
+	loggerV1 := InitLogger()
+	articlePubCache := ioc.InitArticlePubCache(cmdable, loggerV1)
+	articleRepository := repository.NewCacheArticleRepository(dao3, tagDAO, userRepository, articleCache, articlePubCache)
+	client := InitSaramaClient()
+	syncProducer := ioc.InitSyncProducer(client)
+	producer := article.NewSaramaSyncProducer(syncProducer)
+	rankingCache := ioc.InitRankingCache(cmdable)
+	rankingRepository := repository.NewCachedRankingRepository(rankingCache)
+	articleCollaboratorDAO := dao.NewGORMArticleCollaboratorDAO(db)
+	articleCollaboratorRepository := repository.NewArticleCollaboratorRepository(articleCollaboratorDAO, dao3, userRepository)
+	matcher := InitSensitiveMatcher()
+	articleReviewDAO := dao.NewGORMArticleReviewDAO(db)
+	articleReviewRepository := repository.NewArticleReviewRepository(articleReviewDAO)
+	articleService := service.NewArticleService(articleRepository, articleCollaboratorRepository, rankingRepository, producer, matcher, articleReviewRepository, loggerV1)
 	interactiveDAO := dao2.NewGORMInteractiveDAO(db)
 	interactiveCache := cache2.NewInteractiveRedisCache(cmdable)
 	interactiveRepository := repository2.NewCachedInteractiveRepository(interactiveDAO, interactiveCache)
 	interactiveService := service2.NewInteractiveService(interactiveRepository)
-	loggerV1 := InitLogger()
-	articleHandler := web.NewArticleHandler(articleService, interactiveService, loggerV1)
+	interactiveServiceClient := InitIntrClient(interactiveService)
+	seriesDAO := dao.NewGORMSeriesDAO(db)
+	seriesRepository := repository.NewSeriesRepository(seriesDAO, dao3)
+	seriesService := service.NewSeriesService(seriesRepository)
+	articleHandler := web.NewArticleHandler(articleService, seriesService, interactiveServiceClient, loggerV1)
 	return articleHandler
 }
 
@@ -87,8 +157,15 @@ func InitInteractiveService() service2.InteractiveService {
 
 var thirdPartySet = wire.NewSet(
 	InitLogger,
-	InitDB, InitRedis, ioc.InitLocalMem)
+	InitDB, InitRedis, ioc.InitLocalMem,
+	InitSaramaClient, ioc.InitSyncProducer)
 
 var userSvcProvider = wire.NewSet(dao.NewGORMUserDAO, cache.NewRedisUserCache, repository.NewCacheUserRepository, service.NewCacheUserService)
 
-var interactiveSvcSet = wire.NewSet(dao2.NewGORMInteractiveDAO, cache2.NewInteractiveRedisCache, repository2.NewCachedInteractiveRepository, service2.NewInteractiveService)
+var interactiveSvcSet = wire.NewSet(dao2.NewGORMInteractiveDAO, cache2.NewInteractiveRedisCache, repository2.NewCachedInteractiveRepository, service2.NewInteractiveService,
+	InitIntrClient,
+)
+
+var articleSvcProvider = wire.NewSet(dao.NewGORMTagDAO, dao.NewGORMArticleCollaboratorDAO, cache.NewArticleRedisCache, repository.NewCacheArticleRepository, ioc.InitArticlePubCache, repository.NewArticleCollaboratorRepository, ioc.InitRankingCache, repository.NewCachedRankingRepository, article.NewSaramaSyncProducer, InitSensitiveMatcher, dao.NewGORMArticleReviewDAO, repository.NewArticleReviewRepository, service.NewArticleService)
+
+var seriesSvcSet = wire.NewSet(dao.NewGORMSeriesDAO, repository.NewSeriesRepository, service.NewSeriesService)
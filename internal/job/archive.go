@@ -0,0 +1,38 @@
+package job
+
+import (
+	"context"
+	"webook/internal/domain"
+	"webook/internal/service"
+	"webook/pkg/logger"
+)
+
+const ArchiveJobName = "article_archive"
+
+// NewArchiveFunc 返回注册到 LocalExecutor 上的方法，
+// 每次执行把等待中的导出导入任务一个一个执行完，最多执行 batchSize 个，然后清理过期的导出结果
+func NewArchiveFunc(svc service.ArchiveService, l logger.LoggerV1, batchSize int) func(ctx context.Context, job domain.Job) error {
+	return func(ctx context.Context, job domain.Job) error {
+		for i := 0; i < batchSize; i++ {
+			ok, err := svc.RunNext(ctx)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				break
+			}
+		}
+		for {
+			cnt, err := svc.Cleanup(ctx, batchSize)
+			if err != nil {
+				return err
+			}
+			if cnt > 0 {
+				l.Info("清理过期的导出压缩包", logger.Int64("jid", job.Id), logger.Int("cnt", cnt))
+			}
+			if cnt < batchSize {
+				return nil
+			}
+		}
+	}
+}
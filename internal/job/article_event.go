@@ -0,0 +1,36 @@
+package job
+
+import (
+	"context"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/service"
+	"webook/pkg/logger"
+)
+
+const ArticleEventRelayJobName = "article_event_relay"
+
+// NewArticleEventRelayFunc 返回注册到 LocalExecutor 上的方法，
+// 每次执行把发件箱里面的事件全部投递出去，顺便清理 retention 之前已经投递的事件
+func NewArticleEventRelayFunc(svc service.ArticleEventService, l logger.LoggerV1,
+	batchSize int, retention time.Duration) func(ctx context.Context, job domain.Job) error {
+	return func(ctx context.Context, job domain.Job) error {
+		for {
+			cnt, err := svc.Relay(ctx, batchSize)
+			if err != nil {
+				return err
+			}
+			if cnt < batchSize {
+				break
+			}
+		}
+		cnt, err := svc.Clean(ctx, time.Now().Add(-retention))
+		if err != nil {
+			return err
+		}
+		if cnt > 0 {
+			l.Info("清理已经投递的文章事件", logger.Int64("jid", job.Id), logger.Int64("cnt", cnt))
+		}
+		return nil
+	}
+}
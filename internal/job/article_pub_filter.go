@@ -0,0 +1,26 @@
+package job
+
+import (
+	"context"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/service"
+	"webook/pkg/logger"
+)
+
+const ArticlePubFilterJobName = "article_pub_filter_rebuild"
+
+// NewArticlePubFilterFunc 返回注册到 LocalExecutor 上的方法，
+// 布隆过滤器不能删除元素，定期按照线上库重建一次
+func NewArticlePubFilterFunc(svc service.ArticleService, l logger.LoggerV1, batchSize int) func(ctx context.Context, job domain.Job) error {
+	return func(ctx context.Context, job domain.Job) error {
+		start := time.Now()
+		err := svc.RebuildPubFilter(ctx, batchSize)
+		if err != nil {
+			return err
+		}
+		l.Info("重建文章布隆过滤器", logger.Int64("jid", job.Id),
+			logger.String("duration", time.Since(start).String()))
+		return nil
+	}
+}
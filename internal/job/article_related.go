@@ -0,0 +1,26 @@
+package job
+
+import (
+	"context"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/service"
+	"webook/pkg/logger"
+)
+
+const ArticleRelatedJobName = "article_related"
+
+// NewArticleRelatedFunc 返回注册到 LocalExecutor 上的方法，
+// 定期重新计算文章的相关推荐，计算的结果放在缓存里面
+func NewArticleRelatedFunc(svc service.ArticleRelatedService, l logger.LoggerV1) func(ctx context.Context, job domain.Job) error {
+	return func(ctx context.Context, job domain.Job) error {
+		start := time.Now()
+		cnt, err := svc.Recompute(ctx)
+		if err != nil {
+			return err
+		}
+		l.Info("计算相关文章", logger.Int64("jid", job.Id), logger.Int("cnt", cnt),
+			logger.String("duration", time.Since(start).String()))
+		return nil
+	}
+}
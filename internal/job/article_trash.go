@@ -0,0 +1,31 @@
+package job
+
+import (
+	"context"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/service"
+	"webook/pkg/logger"
+)
+
+const ArticleTrashPurgeJobName = "article_trash_purge"
+
+// NewArticleTrashPurgeFunc 返回注册到 LocalExecutor 上的方法，
+// 每次执行把回收站里面过期的文章分批彻底删除
+func NewArticleTrashPurgeFunc(svc service.ArticleService, l logger.LoggerV1, batchSize int) func(ctx context.Context, job domain.Job) error {
+	return func(ctx context.Context, job domain.Job) error {
+		now := time.Now()
+		for {
+			cnt, err := svc.PurgeExpired(ctx, now, batchSize)
+			if err != nil {
+				return err
+			}
+			if cnt > 0 {
+				l.Info("清理回收站", logger.Int64("jid", job.Id), logger.Int("cnt", cnt))
+			}
+			if cnt < batchSize {
+				return nil
+			}
+		}
+	}
+}
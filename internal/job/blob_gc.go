@@ -0,0 +1,31 @@
+package job
+
+import (
+	"context"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/service"
+	"webook/pkg/logger"
+)
+
+const BlobGCJobName = "blob_gc"
+
+// NewBlobGCFunc 返回注册到 LocalExecutor 上的方法，
+// 每次执行把没有被任何文章引用的文件分批删除，刚上传的文件留一段时间
+func NewBlobGCFunc(svc service.BlobService, l logger.LoggerV1, batchSize int) func(ctx context.Context, job domain.Job) error {
+	return func(ctx context.Context, job domain.Job) error {
+		before := time.Now().Add(-service.BlobGCGracePeriod)
+		for {
+			cnt, err := svc.GC(ctx, before, batchSize)
+			if err != nil {
+				return err
+			}
+			if cnt > 0 {
+				l.Info("清理没有引用的文件", logger.Int64("jid", job.Id), logger.Int("cnt", cnt))
+			}
+			if cnt < batchSize {
+				return nil
+			}
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package job
+
+import (
+	"context"
+	"time"
+	intrSvc "webook/interactive/service"
+	"webook/internal/domain"
+	"webook/pkg/logger"
+)
+
+const InteractiveRollupJobName = "interactive_daily_rollup"
+
+// NewInteractiveRollupFunc 返回注册到 LocalExecutor 上的方法，
+// 每天凌晨把前两天的 UV 和 PV 从 Redis 汇总到数据库。
+// 多汇总一天是为了补上前一次执行失败的数据，重复汇总会直接覆盖
+func NewInteractiveRollupFunc(svc intrSvc.InteractiveService, l logger.LoggerV1, batchSize int) func(ctx context.Context, job domain.Job) error {
+	return func(ctx context.Context, job domain.Job) error {
+		now := time.Now()
+		for _, day := range []time.Time{now.AddDate(0, 0, -2), now.AddDate(0, 0, -1)} {
+			cnt, err := svc.RollupDaily(ctx, day, batchSize)
+			if err != nil {
+				return err
+			}
+			l.Info("汇总每天的访问量",
+				logger.Int64("jid", job.Id),
+				logger.String("day", day.Format(time.DateOnly)),
+				logger.Int("cnt", cnt))
+		}
+		return nil
+	}
+}
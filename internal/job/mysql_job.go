@@ -19,6 +19,12 @@ type LocalExecutor struct {
 	funcs map[string]func(ctx context.Context, job domain.Job) error
 }
 
+func NewLocalExecutor() *LocalExecutor {
+	return &LocalExecutor{
+		funcs: make(map[string]func(ctx context.Context, job domain.Job) error),
+	}
+}
+
 func (l *LocalExecutor) RegisterFunc(name string, fn func(ctx context.Context, job domain.Job) error) {
 	l.funcs[name] = fn
 }
@@ -37,10 +43,23 @@ func (l *LocalExecutor) Exec(ctx context.Context, job domain.Job) error {
 
 type Scheduler struct {
 	dbTimeout time.Duration
-	svc       service.CronJobService
-	executors map[string]Executor
-	l         logger.LoggerV1
-	limiter   semaphore.Weighted
+	// execTimeout 一次执行最长的时间，任务自己要分批做
+	execTimeout time.Duration
+	svc         service.CronJobService
+	executors   map[string]Executor
+	l           logger.LoggerV1
+	limiter     *semaphore.Weighted
+}
+
+func NewScheduler(svc service.CronJobService, l logger.LoggerV1) *Scheduler {
+	return &Scheduler{
+		dbTimeout:   time.Second,
+		execTimeout: time.Minute * 10,
+		svc:         svc,
+		executors:   make(map[string]Executor),
+		l:           l,
+		limiter:     semaphore.NewWeighted(100),
+	}
 }
 
 func (s *Scheduler) RegisterExecutor(exec Executor) {
@@ -60,11 +79,16 @@ func (s *Scheduler) Schedule(ctx context.Context) error {
 		job, err := s.svc.Preempt(dbCtx)
 		cancel()
 		if err != nil {
+			// 没有抢到任务，歇一会再抢
+			s.limiter.Release(1)
+			time.Sleep(time.Second)
 			continue
 		}
 		executor, ok := s.executors[job.Executor]
 		if !ok {
 			s.l.Warn("找不到执行器", logger.Int64("jid", job.Id), logger.String("executor", job.Executor))
+			s.limiter.Release(1)
+			job.CancelFunc()
 			continue
 		}
 		go func() {
@@ -72,12 +96,16 @@ func (s *Scheduler) Schedule(ctx context.Context) error {
 				s.limiter.Release(1)
 				job.CancelFunc()
 			}()
-			er := executor.Exec(ctx, job)
+			execCtx, execCancel := context.WithTimeout(ctx, s.execTimeout)
+			er := executor.Exec(execCtx, job)
+			execCancel()
 			if er != nil {
 				s.l.Warn("执行任务失败", logger.Error(er), logger.Int64("jid", job.Id))
-				return
 			}
-			er = s.svc.ResetNextTime(ctx, job)
+			// 失败了也要等到下一次的时间再执行，不然释放之后马上又被抢到，一直失败一直重试
+			dbCtx, cancel := context.WithTimeout(ctx, s.dbTimeout)
+			defer cancel()
+			er = s.svc.ResetNextTime(dbCtx, job)
 			if er != nil {
 				s.l.Warn("刷新下一次执行时间失败", logger.Error(er), logger.Int64("jid", job.Id))
 			}
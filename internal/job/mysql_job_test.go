@@ -0,0 +1,45 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+	"webook/internal/domain"
+	svcmocks "webook/internal/service/mocks"
+	"webook/pkg/logger"
+)
+
+// TestScheduler_ExecFailed 执行失败也要更新下一次执行的时间，不然马上又被抢到
+func TestScheduler_ExecFailed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	svc := svcmocks.NewMockCronJobService(ctrl)
+	released := make(chan struct{})
+	job := domain.Job{Id: 1, Name: "test", Executor: "local_executor", Expression: "@every 1m",
+		CancelFunc: func() { close(released) }}
+	svc.EXPECT().Preempt(gomock.Any()).Return(job, nil)
+	svc.EXPECT().Preempt(gomock.Any()).Return(domain.Job{}, errors.New("没有任务")).AnyTimes()
+	svc.EXPECT().ResetNextTime(gomock.Any(), gomock.Any()).Return(nil)
+
+	exec := NewLocalExecutor()
+	exec.RegisterFunc("test", func(ctx context.Context, job domain.Job) error {
+		_, ok := ctx.Deadline()
+		assert.True(t, ok)
+		return errors.New("执行失败")
+	})
+	s := NewScheduler(svc, logger.NewNopLogger())
+	s.RegisterExecutor(exec)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_ = s.Schedule(ctx)
+	}()
+	select {
+	case <-released:
+	case <-time.After(time.Second * 3):
+		t.Fatal("任务没有释放")
+	}
+}
@@ -0,0 +1,31 @@
+package job
+
+import (
+	"context"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/service"
+	"webook/pkg/logger"
+)
+
+const ScheduledPublishJobName = "scheduled_publish"
+
+// NewScheduledPublishFunc 返回注册到 LocalExecutor 上的方法，
+// 每次执行把所有到期的定时文章分批发表
+func NewScheduledPublishFunc(svc service.ArticleService, l logger.LoggerV1, batchSize int) func(ctx context.Context, job domain.Job) error {
+	return func(ctx context.Context, job domain.Job) error {
+		now := time.Now()
+		for {
+			cnt, err := svc.PublishDue(ctx, now, batchSize)
+			if err != nil {
+				return err
+			}
+			if cnt > 0 {
+				l.Info("定时发表文章", logger.Int64("jid", job.Id), logger.Int("cnt", cnt))
+			}
+			if cnt < batchSize {
+				return nil
+			}
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package middleware
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+	ijwt "webook/internal/web/jwt"
+	"webook/pkg/jwtx"
+)
+
+func TestLoginJWTMiddlewareBuilder_CheckLogin(t *testing.T) {
+	testCases := []struct {
+		name string
+		// before 准备 Redis 里面的会话
+		before func(t *testing.T, mr *miniredis.Miniredis)
+		after  func(t *testing.T, mr *miniredis.Miniredis)
+		// token 用 access token 还是 refresh token
+		refresh bool
+		// legacy 升级之前签发的 access token，没有 iat
+		legacy   bool
+		path     string
+		wantCode int
+	}{
+		{
+			name:     "refresh token 可以刷新",
+			refresh:  true,
+			path:     "/users/refresh_token",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "refresh token 不能当 access token 用",
+			refresh:  true,
+			path:     "/users/profile",
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name: "access token",
+			before: func(t *testing.T, mr *miniredis.Miniredis) {
+				mr.HSet("users:session:a", "uid", "1", "last_seen", "0")
+			},
+			after: func(t *testing.T, mr *miniredis.Miniredis) {
+				// 顺便更新了最后活跃时间
+				assert.NotEqual(t, "0", mr.HGet("users:session:a", "last_seen"))
+			},
+			path:     "/users/profile",
+			wantCode: http.StatusOK,
+		},
+		{
+			name: "升级之前签发的 token 补登记会话",
+			after: func(t *testing.T, mr *miniredis.Miniredis) {
+				assert.Equal(t, "1", mr.HGet("users:session:a", "uid"))
+				ok, err := mr.SIsMember("users:sessions:1", "a")
+				require.NoError(t, err)
+				assert.True(t, ok)
+				assert.Equal(t, time.Hour*24*7, mr.TTL("users:session:a"))
+			},
+			legacy:   true,
+			path:     "/users/profile",
+			wantCode: http.StatusOK,
+		},
+		{
+			name: "会话已经注销",
+			before: func(t *testing.T, mr *miniredis.Miniredis) {
+				mr.HSet("users:session:a", "uid", "1", "last_seen", "0")
+				require.NoError(t, mr.Set("users:ssid:a", ""))
+			},
+			path:     "/users/profile",
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name: "会话不属于这个用户",
+			before: func(t *testing.T, mr *miniredis.Miniredis) {
+				mr.HSet("users:session:a", "uid", "2", "last_seen", "0")
+			},
+			path:     "/users/profile",
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name: "重置密码之前签发的",
+			before: func(t *testing.T, mr *miniredis.Miniredis) {
+				mr.HSet("users:session:a", "uid", "1", "last_seen", "0")
+				require.NoError(t, mr.Set("users:token_valid_after:1",
+					strconv.FormatInt(time.Now().Add(time.Minute).Unix(), 10)))
+			},
+			path:     "/users/profile",
+			wantCode: http.StatusUnauthorized,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mr := miniredis.RunT(t)
+			if tc.before != nil {
+				tc.before(t, mr)
+			}
+			accessKeys := newKeySet(t, "access")
+			client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+			hdl := ijwt.NewRedisJWTHandler(client, accessKeys, newKeySet(t, "refresh"))
+			token := issueToken(t, hdl.(*ijwt.RedisJWTHandler), tc.refresh)
+			if tc.legacy {
+				var err error
+				token, err = accessKeys.Sign(ijwt.UserClaims{
+					RegisteredClaims: jwt.RegisteredClaims{
+						ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+					},
+					Uid:  1,
+					Ssid: "a",
+				})
+				require.NoError(t, err)
+			}
+
+			server := gin.New()
+			server.Use(NewLoginJWTMiddlewareBuilder(hdl).CheckLogin())
+			server.POST("/users/refresh_token", func(ctx *gin.Context) {
+				ctx.Status(http.StatusOK)
+			})
+			server.GET("/users/profile", func(ctx *gin.Context) {
+				ctx.Status(http.StatusOK)
+			})
+			method := http.MethodGet
+			if tc.path == "/users/refresh_token" {
+				method = http.MethodPost
+			}
+			req := httptest.NewRequest(method, tc.path, nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			recorder := httptest.NewRecorder()
+			server.ServeHTTP(recorder, req)
+			assert.Equal(t, tc.wantCode, recorder.Code)
+			if tc.after != nil {
+				tc.after(t, mr)
+			}
+		})
+	}
+}
+
+func newKeySet(t *testing.T, kid string) *jwtx.KeySet {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	key, err := jwtx.NewKey(kid, private)
+	require.NoError(t, err)
+	ks, err := jwtx.NewKeySet(kid, key)
+	require.NoError(t, err)
+	return ks
+}
+
+func issueToken(t *testing.T, hdl *ijwt.RedisJWTHandler, refresh bool) string {
+	recorder := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(recorder)
+	ctx.Request = httptest.NewRequest(http.MethodPost, "/users/login", nil)
+	if refresh {
+		require.NoError(t, hdl.SetRefreshToken(ctx, 1, "a", "jti", time.Now().Add(time.Hour)))
+		return recorder.Header().Get("x-refresh-token")
+	}
+	require.NoError(t, hdl.SetJWTToken(ctx, 1, "a"))
+	return recorder.Header().Get("x-jwt-token")
+}
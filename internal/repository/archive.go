@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/gotomicro/ekit/slice"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository/dao"
+)
+
+var ErrArchiveTaskNotFound = dao.ErrRecordNotFound
+
+type ArchiveTaskRepository interface {
+	Create(ctx context.Context, t domain.ArchiveTask) (int64, error)
+	FindById(ctx context.Context, id int64) (domain.ArchiveTask, error)
+	ListByUid(ctx context.Context, uid int64, offset int, limit int) ([]domain.ArchiveTask, error)
+	// Preempt 没有可以执行的任务返回 ErrArchiveTaskNotFound
+	Preempt(ctx context.Context, timeout time.Duration) (domain.ArchiveTask, error)
+	Finish(ctx context.Context, t domain.ArchiveTask) error
+	ListExpired(ctx context.Context, before time.Time, limit int) ([]domain.ArchiveTask, error)
+	ClearBlob(ctx context.Context, id int64) error
+}
+
+type archiveTaskRepository struct {
+	dao dao.ArchiveTaskDAO
+}
+
+func NewArchiveTaskRepository(dao dao.ArchiveTaskDAO) ArchiveTaskRepository {
+	return &archiveTaskRepository{
+		dao: dao,
+	}
+}
+
+func (r *archiveTaskRepository) Create(ctx context.Context, t domain.ArchiveTask) (int64, error) {
+	entity, err := r.toEntity(t)
+	if err != nil {
+		return 0, err
+	}
+	return r.dao.Insert(ctx, entity)
+}
+
+func (r *archiveTaskRepository) FindById(ctx context.Context, id int64) (domain.ArchiveTask, error) {
+	t, err := r.dao.GetById(ctx, id)
+	if err != nil {
+		return domain.ArchiveTask{}, err
+	}
+	return r.toDomain(t), nil
+}
+
+func (r *archiveTaskRepository) ListByUid(ctx context.Context, uid int64, offset int, limit int) ([]domain.ArchiveTask, error) {
+	ts, err := r.dao.ListByUid(ctx, uid, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(ts, func(idx int, src dao.ArchiveTask) domain.ArchiveTask {
+		return r.toDomain(src)
+	}), nil
+}
+
+func (r *archiveTaskRepository) Preempt(ctx context.Context, timeout time.Duration) (domain.ArchiveTask, error) {
+	t, err := r.dao.Preempt(ctx, timeout)
+	if err != nil {
+		return domain.ArchiveTask{}, err
+	}
+	return r.toDomain(t), nil
+}
+
+func (r *archiveTaskRepository) Finish(ctx context.Context, t domain.ArchiveTask) error {
+	entity, err := r.toEntity(t)
+	if err != nil {
+		return err
+	}
+	return r.dao.Finish(ctx, entity)
+}
+
+func (r *archiveTaskRepository) ListExpired(ctx context.Context, before time.Time, limit int) ([]domain.ArchiveTask, error) {
+	ts, err := r.dao.ListExpired(ctx, before.UnixMilli(), limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(ts, func(idx int, src dao.ArchiveTask) domain.ArchiveTask {
+		return r.toDomain(src)
+	}), nil
+}
+
+func (r *archiveTaskRepository) ClearBlob(ctx context.Context, id int64) error {
+	return r.dao.ClearBlob(ctx, id)
+}
+
+func (r *archiveTaskRepository) toEntity(t domain.ArchiveTask) (dao.ArchiveTask, error) {
+	var errs string
+	if len(t.Errors) > 0 {
+		val, err := json.Marshal(t.Errors)
+		if err != nil {
+			return dao.ArchiveTask{}, err
+		}
+		errs = string(val)
+	}
+	return dao.ArchiveTask{
+		Id:      t.Id,
+		Uid:     t.Uid,
+		Type:    uint8(t.Type),
+		Status:  uint8(t.Status),
+		BlobKey: t.BlobKey,
+		Cnt:     t.Cnt,
+		Errors:  errs,
+		Reason:  t.Reason,
+	}, nil
+}
+
+func (r *archiveTaskRepository) toDomain(t dao.ArchiveTask) domain.ArchiveTask {
+	var errs []domain.ArchiveFileError
+	if t.Errors != "" {
+		// 数据是自己写进去的，解析失败的话只是看不到错误详情
+		_ = json.Unmarshal([]byte(t.Errors), &errs)
+	}
+	return domain.ArchiveTask{
+		Id:      t.Id,
+		Uid:     t.Uid,
+		Type:    domain.ArchiveTaskType(t.Type),
+		Status:  domain.ArchiveTaskStatus(t.Status),
+		BlobKey: t.BlobKey,
+		Cnt:     t.Cnt,
+		Errors:  errs,
+		Reason:  t.Reason,
+		Ctime:   time.UnixMilli(t.Ctime),
+		Utime:   time.UnixMilli(t.Utime),
+	}
+}
@@ -10,6 +10,12 @@ import (
 	"webook/internal/repository/dao"
 )
 
+var (
+	ErrArticleNotFound         = dao.ErrRecordNotFound
+	ErrArticleNotPendingReview = dao.ErrArticleNotPendingReview
+	ErrArticleNotScheduled     = dao.ErrArticleNotScheduled
+)
+
 type ArticleRepository interface {
 	Create(ctx context.Context, art domain.Article) (int64, error)
 	Update(ctx context.Context, art domain.Article) error
@@ -18,13 +24,36 @@ type ArticleRepository interface {
 	GetByAuthor(ctx context.Context, uid int64, offset int, limit int) ([]domain.Article, error)
 	GetById(ctx context.Context, id int64) (domain.Article, error)
 	GetPubById(ctx context.Context, id int64) (domain.Article, error)
+	RebuildPubFilter(ctx context.Context, batchSize int) error
 	ListPub(ctx context.Context, start time.Time, offset, limit int) ([]domain.Article, error)
+	ListPubByAuthor(ctx context.Context, uid int64, offset int, limit int) ([]domain.Article, error)
+	GetByAuthorCursor(ctx context.Context, uid int64, cursor domain.ArticleCursor, limit int) ([]domain.Article, error)
+	ListPubCursor(ctx context.Context, cursor domain.ArticleCursor, limit int) ([]domain.Article, error)
+	ListRevisions(ctx context.Context, artId int64, offset int, limit int) ([]domain.ArticleRevision, error)
+	GetRevision(ctx context.Context, artId int64, version int64) (domain.ArticleRevision, error)
+	ListScheduled(ctx context.Context, before time.Time, limit int) ([]domain.Article, error)
+	// PublishScheduled art 必须是 ListScheduled 查出来的，查出来之后被取消或者修改过的时候
+	// 返回 ErrArticleNotScheduled
+	PublishScheduled(ctx context.Context, art domain.Article) error
+	UpdateSchedule(ctx context.Context, id int64, uid int64, status domain.ArticleStatus, publishAt time.Time) error
+	// PublishReviewed art 必须是审核的时候查出来的，之后被修改过的时候返回 ErrArticleNotPendingReview
+	PublishReviewed(ctx context.Context, art domain.Article) error
+	ResolveReview(ctx context.Context, id int64, uid int64, status domain.ArticleStatus) error
+	ListTags(ctx context.Context, offset int, limit int) ([]domain.Tag, error)
+	ListPubByTag(ctx context.Context, tag string, offset int, limit int) ([]domain.Article, error)
+	Delete(ctx context.Context, id int64, uid int64) error
+	Restore(ctx context.Context, id int64, uid int64, deletedAfter time.Time) error
+	ListTrash(ctx context.Context, uid int64, offset int, limit int) ([]domain.Article, error)
+	ListExpiredTrash(ctx context.Context, before time.Time, limit int) ([]domain.Article, error)
+	Purge(ctx context.Context, id int64, before time.Time) error
 }
 
 type CacheArticleRepository struct {
 	userRepo  UserRepository
 	dao       dao.ArticleDAO
+	tagDao    dao.TagDAO
 	cache     cache.ArticleCache
+	pubCache  cache.ArticlePubCache
 	readerDao dao.ArticleReaderDAO
 	authorDao dao.ArticleAuthorDAO
 	db        *gorm.DB
@@ -40,32 +69,134 @@ func (c *CacheArticleRepository) ListPub(ctx context.Context, start time.Time, o
 	}), nil
 }
 
-func (c *CacheArticleRepository) GetPubById(ctx context.Context, id int64) (domain.Article, error) {
-	res, err := c.cache.GetPub(ctx, id)
-	if err == nil {
-		return res, nil
+func (c *CacheArticleRepository) ListPubByAuthor(ctx context.Context, uid int64, offset int, limit int) ([]domain.Article, error) {
+	arts, err := c.dao.ListPubByAuthor(ctx, uid, offset, limit)
+	if err != nil {
+		return nil, err
 	}
-	art, err := c.dao.GetPubById(ctx, id)
+	return slice.Map(arts, func(idx int, src dao.PublishedArticle) domain.Article {
+		return c.toDomain(dao.Article(src))
+	}), nil
+}
+
+func (c *CacheArticleRepository) GetByAuthorCursor(ctx context.Context, uid int64, cursor domain.ArticleCursor, limit int) ([]domain.Article, error) {
+	// 第一页也要按照 (utime, id) 排序，否则 utime 相同的文章翻页的时候会重复或者漏掉
+	utime, id := c.cursorToEntity(cursor)
+	arts, err := c.dao.GetByAuthorCursor(ctx, uid, utime, id, limit)
 	if err != nil {
-		return domain.Article{}, err
+		return nil, err
 	}
-	res = c.toDomain(dao.Article(art))
-	author, err := c.userRepo.FindById(ctx, res.Author.Id)
+	return slice.Map(arts, func(idx int, src dao.Article) domain.Article {
+		return c.toDomain(src)
+	}), nil
+}
+
+func (c *CacheArticleRepository) ListPubCursor(ctx context.Context, cursor domain.ArticleCursor, limit int) ([]domain.Article, error) {
+	utime, id := c.cursorToEntity(cursor)
+	arts, err := c.dao.ListPubCursor(ctx, utime, id, limit)
 	if err != nil {
-		return domain.Article{}, err
+		return nil, err
 	}
-	res.Author.Name = author.Nickname
+	return slice.Map(arts, func(idx int, src dao.PublishedArticle) domain.Article {
+		return c.toDomain(dao.Article(src))
+	}), nil
+}
 
-	go func() {
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-		defer cancel()
-		err := c.cache.SetPub(ctx, res)
+func (c *CacheArticleRepository) cursorToEntity(cursor domain.ArticleCursor) (int64, int64) {
+	if cursor.IsZero() {
+		return 0, 0
+	}
+	return cursor.Utime.UnixMilli(), cursor.Id
+}
+
+func (c *CacheArticleRepository) GetPubById(ctx context.Context, id int64) (domain.Article, error) {
+	// 布隆过滤器出错的时候直接放行，最多多查一次数据库
+	ok, err := c.pubCache.MightExist(ctx, id)
+	if err == nil && !ok {
+		return domain.Article{}, ErrArticleNotFound
+	}
+	res, err := c.pubCache.Get(ctx, id, func(ctx context.Context) (domain.Article, error) {
+		art, err := c.dao.GetPubById(ctx, id)
+		if err != nil {
+			return domain.Article{}, err
+		}
+		res := c.toDomain(dao.Article(art))
+		author, err := c.userRepo.FindById(ctx, res.Author.Id)
+		if err != nil {
+			return domain.Article{}, err
+		}
+		res.Author.Name = author.Nickname
+		// 线上库的标签只在 Sync 的时候修改，和文章一起缓存
+		tags, err := c.tagDao.GetPubArticleTags(ctx, id)
 		if err != nil {
+			return domain.Article{}, err
+		}
+		res.Tags = slice.Map(tags, func(idx int, src dao.Tag) string {
+			return src.Name
+		})
+		return res, nil
+	})
+	return res, err
+}
 
+// RebuildPubFilter 按照线上库重建布隆过滤器，清理掉已经彻底删除的文章
+func (c *CacheArticleRepository) RebuildPubFilter(ctx context.Context, batchSize int) error {
+	return c.pubCache.RebuildIds(ctx, func(add func(ids ...int64) error) error {
+		var afterId int64
+		for {
+			ids, err := c.dao.ListPubIds(ctx, afterId, batchSize)
+			if err != nil {
+				return err
+			}
+			if len(ids) == 0 {
+				return nil
+			}
+			err = add(ids...)
+			if err != nil {
+				return err
+			}
+			if len(ids) < batchSize {
+				return nil
+			}
+			afterId = ids[len(ids)-1]
 		}
-	}()
+	})
+}
 
-	return res, nil
+func (c *CacheArticleRepository) ListScheduled(ctx context.Context, before time.Time, limit int) ([]domain.Article, error) {
+	arts, err := c.dao.ListScheduled(ctx, before, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(arts, func(idx int, src dao.Article) domain.Article {
+		return c.toDomain(src)
+	}), nil
+}
+
+func (c *CacheArticleRepository) ResolveReview(ctx context.Context, id int64, uid int64, status domain.ArticleStatus) error {
+	err := c.dao.ResolveReview(ctx, id, status.ToUint8())
+	if err == nil {
+		er := c.cache.DelFirstPage(ctx, uid)
+		if er != nil {
+			//记录日志
+		}
+	}
+	return err
+}
+
+func (c *CacheArticleRepository) UpdateSchedule(ctx context.Context, id int64, uid int64, status domain.ArticleStatus, publishAt time.Time) error {
+	var at int64
+	if !publishAt.IsZero() {
+		at = publishAt.UnixMilli()
+	}
+	err := c.dao.UpdateSchedule(ctx, id, uid, status.ToUint8(), at)
+	if err == nil {
+		er := c.cache.DelFirstPage(ctx, uid)
+		if er != nil {
+			//记录日志
+		}
+	}
+	return err
 }
 
 func (c *CacheArticleRepository) preCache(ctx context.Context, arts []domain.Article) {
@@ -81,6 +212,7 @@ func (c *CacheArticleRepository) preCache(ctx context.Context, arts []domain.Art
 func (c *CacheArticleRepository) GetById(ctx context.Context, id int64) (domain.Article, error) {
 	res, err := c.cache.Get(ctx, id)
 	if err == nil {
+		res.Tags, err = c.getTags(ctx, id)
 		return res, err
 	}
 	art, err := c.dao.GetById(ctx, id)
@@ -88,6 +220,10 @@ func (c *CacheArticleRepository) GetById(ctx context.Context, id int64) (domain.
 		return domain.Article{}, err
 	}
 	res = c.toDomain(art)
+	res.Tags, err = c.getTags(ctx, id)
+	if err != nil {
+		return domain.Article{}, err
+	}
 	go func() {
 		c.cache.Set(ctx, res)
 	}()
@@ -132,6 +268,14 @@ func (c *CacheArticleRepository) SyncStatus(ctx context.Context, id int64, uid i
 		if err != nil {
 			//记录日志
 		}
+		er := c.cache.DelTags(ctx, id)
+		if er != nil {
+			//记录日志
+		}
+		er = c.pubCache.Del(ctx, id)
+		if er != nil {
+			//记录日志
+		}
 	}
 	return err
 }
@@ -144,31 +288,59 @@ func NewCacheArticleRepositoryV2(readerDao dao.ArticleReaderDAO, authorDao dao.A
 }
 
 func (c *CacheArticleRepository) Sync(ctx context.Context, art domain.Article) (int64, error) {
-	id, err := c.dao.Sync(ctx, c.toEntity(art))
-	if err == nil {
-		er := c.cache.DelFirstPage(ctx, art.Author.Id)
+	id, err := c.dao.Sync(ctx, c.toEntity(art), art.Tags)
+	if err != nil {
+		return 0, err
+	}
+	if art.Tags != nil {
+		er := c.cache.DelTags(ctx, id)
 		if er != nil {
 			//记录日志
 		}
 	}
+	err = c.afterSync(ctx, id, art.Author.Id)
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
 
-	go func() {
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-		defer cancel()
-		author, er := c.userRepo.FindById(ctx, art.Author.Id)
-		if er != nil {
-			return
-		}
-		art.Author = domain.Author{
-			Id:   author.Id,
-			Name: author.Nickname,
-		}
-		er = c.cache.SetPub(ctx, art)
-		if er != nil {
-			//记录日志
-		}
-	}()
-	return id, err
+func (c *CacheArticleRepository) PublishScheduled(ctx context.Context, art domain.Article) error {
+	entity := c.toEntity(art)
+	entity.Utime = art.Utime.UnixMilli()
+	err := c.dao.PublishScheduled(ctx, entity)
+	if err != nil {
+		return err
+	}
+	return c.afterSync(ctx, art.Id, art.Author.Id)
+}
+
+func (c *CacheArticleRepository) PublishReviewed(ctx context.Context, art domain.Article) error {
+	entity := c.toEntity(art)
+	entity.Utime = art.Utime.UnixMilli()
+	err := c.dao.PublishReviewed(ctx, entity)
+	if err != nil {
+		return err
+	}
+	return c.afterSync(ctx, art.Id, art.Author.Id)
+}
+
+// afterSync 发表之后更新缓存
+func (c *CacheArticleRepository) afterSync(ctx context.Context, id int64, uid int64) error {
+	// 过滤器里面没有的话文章会一直查不到，返回错误让用户重新发表
+	err := c.pubCache.AddIds(ctx, id)
+	if err != nil {
+		return err
+	}
+	er := c.cache.DelFirstPage(ctx, uid)
+	if er != nil {
+		//记录日志
+	}
+	er = c.pubCache.Del(ctx, id)
+	if er != nil {
+		//记录日志
+	}
+	return nil
 }
 
 func (c *CacheArticleRepository) SyncV2(ctx context.Context, art domain.Article) (int64, error) {
@@ -220,6 +392,9 @@ func (c *CacheArticleRepository) SyncV1(ctx context.Context, art domain.Article)
 
 func (c *CacheArticleRepository) Update(ctx context.Context, art domain.Article) error {
 	err := c.dao.UpdateById(ctx, c.toEntity(art))
+	if err == nil {
+		err = c.saveTags(ctx, art.Id, art.Tags)
+	}
 	if err == nil {
 		c.cache.DelFirstPage(ctx, art.Author.Id)
 		if err != nil {
@@ -230,16 +405,23 @@ func (c *CacheArticleRepository) Update(ctx context.Context, art domain.Article)
 }
 
 func NewCacheArticleRepository(dao dao.ArticleDAO,
+	tagDao dao.TagDAO,
 	userRepo UserRepository,
-	cache cache.ArticleCache) ArticleRepository {
+	cache cache.ArticleCache,
+	pubCache cache.ArticlePubCache) ArticleRepository {
 	return &CacheArticleRepository{
 		dao:      dao,
+		tagDao:   tagDao,
 		userRepo: userRepo,
 		cache:    cache,
+		pubCache: pubCache,
 	}
 }
 func (c *CacheArticleRepository) Create(ctx context.Context, art domain.Article) (int64, error) {
 	id, err := c.dao.Insert(ctx, c.toEntity(art))
+	if err == nil {
+		err = c.saveTags(ctx, id, art.Tags)
+	}
 	if err == nil {
 		c.cache.DelFirstPage(ctx, art.Author.Id)
 		if err != nil {
@@ -250,23 +432,43 @@ func (c *CacheArticleRepository) Create(ctx context.Context, art domain.Article)
 }
 
 func (c *CacheArticleRepository) toEntity(art domain.Article) dao.Article {
+	var publishAt int64
+	if !art.PublishAt.IsZero() {
+		publishAt = art.PublishAt.UnixMilli()
+	}
 	return dao.Article{
-		Id:       art.Id,
-		Title:    art.Title,
-		Content:  art.Content,
-		AuthorId: art.Author.Id,
-		Status:   art.Status.ToUint8(),
+		Id:        art.Id,
+		Title:     art.Title,
+		Content:   art.Content,
+		HTML:      art.HTML,
+		Summary:   art.Summary,
+		AuthorId:  art.Author.Id,
+		Status:    art.Status.ToUint8(),
+		PublishAt: publishAt,
 	}
 }
 
 func (c *CacheArticleRepository) toDomain(art dao.Article) domain.Article {
+	var publishAt, deletedAt time.Time
+	if art.PublishAt > 0 {
+		publishAt = time.UnixMilli(art.PublishAt)
+	}
+	if art.DeletedAt > 0 {
+		deletedAt = time.UnixMilli(art.DeletedAt)
+	}
 	return domain.Article{
 		Id:      art.Id,
 		Title:   art.Title,
 		Content: art.Content,
+		HTML:    art.HTML,
+		Summary: art.Summary,
 		Author: domain.Author{
 			Id: art.AuthorId,
 		},
-		Status: domain.ArticleStatus(art.Status),
+		Status:    domain.ArticleStatus(art.Status),
+		PublishAt: publishAt,
+		Ctime:     time.UnixMilli(art.Ctime),
+		Utime:     time.UnixMilli(art.Utime),
+		DeletedAt: deletedAt,
 	}
 }
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"github.com/gotomicro/ekit/slice"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository/dao"
+)
+
+type ArticleCollaboratorRepository interface {
+	Save(ctx context.Context, artId int64, c domain.Collaborator) error
+	Delete(ctx context.Context, artId int64, uid int64) error
+	// GetRole 不是合作者返回 ArticleRoleUnknown，作者本人也是 ArticleRoleUnknown
+	GetRole(ctx context.Context, artId int64, uid int64) (domain.ArticleRole, error)
+	// List 合作者带上昵称
+	List(ctx context.Context, artId int64) ([]domain.Collaborator, error)
+	ListArticles(ctx context.Context, uid int64, offset int, limit int) ([]domain.Article, error)
+	AddAudit(ctx context.Context, a domain.ArticleAudit) error
+	ListAudits(ctx context.Context, artId int64, offset int, limit int) ([]domain.ArticleAudit, error)
+}
+
+type articleCollaboratorRepository struct {
+	dao dao.ArticleCollaboratorDAO
+	// artDao 文章可能存在 MongoDB 里面，不能和合作者的表 JOIN
+	artDao   dao.ArticleDAO
+	userRepo UserRepository
+}
+
+func NewArticleCollaboratorRepository(dao dao.ArticleCollaboratorDAO, artDao dao.ArticleDAO,
+	userRepo UserRepository) ArticleCollaboratorRepository {
+	return &articleCollaboratorRepository{
+		dao:      dao,
+		artDao:   artDao,
+		userRepo: userRepo,
+	}
+}
+
+func (r *articleCollaboratorRepository) Save(ctx context.Context, artId int64, c domain.Collaborator) error {
+	return r.dao.Upsert(ctx, dao.ArticleCollaborator{
+		ArtId:   artId,
+		Uid:     c.Uid,
+		Role:    c.Role.ToUint8(),
+		Inviter: c.Inviter,
+	})
+}
+
+func (r *articleCollaboratorRepository) Delete(ctx context.Context, artId int64, uid int64) error {
+	return r.dao.Delete(ctx, artId, uid)
+}
+
+func (r *articleCollaboratorRepository) GetRole(ctx context.Context, artId int64, uid int64) (domain.ArticleRole, error) {
+	c, err := r.dao.Get(ctx, artId, uid)
+	if err == dao.ErrRecordNotFound {
+		return domain.ArticleRoleUnknown, nil
+	}
+	if err != nil {
+		return domain.ArticleRoleUnknown, err
+	}
+	return domain.ArticleRole(c.Role), nil
+}
+
+func (r *articleCollaboratorRepository) List(ctx context.Context, artId int64) ([]domain.Collaborator, error) {
+	cs, err := r.dao.List(ctx, artId)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]domain.Collaborator, 0, len(cs))
+	for _, c := range cs {
+		collaborator := domain.Collaborator{
+			Uid:     c.Uid,
+			Role:    domain.ArticleRole(c.Role),
+			Inviter: c.Inviter,
+			Ctime:   time.UnixMilli(c.Ctime),
+		}
+		u, er := r.userRepo.FindById(ctx, c.Uid)
+		if er == nil {
+			collaborator.Name = u.Nickname
+		}
+		res = append(res, collaborator)
+	}
+	return res, nil
+}
+
+func (r *articleCollaboratorRepository) ListArticles(ctx context.Context, uid int64, offset int, limit int) ([]domain.Article, error) {
+	ids, err := r.dao.ListArticleIds(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	arts, err := r.artDao.ListByIds(ctx, ids, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(arts, func(idx int, src dao.Article) domain.Article {
+		return domain.Article{
+			Id:      src.Id,
+			Title:   src.Title,
+			Summary: src.Summary,
+			Author: domain.Author{
+				Id: src.AuthorId,
+			},
+			Status: domain.ArticleStatus(src.Status),
+			Ctime:  time.UnixMilli(src.Ctime),
+			Utime:  time.UnixMilli(src.Utime),
+		}
+	}), nil
+}
+
+func (r *articleCollaboratorRepository) AddAudit(ctx context.Context, a domain.ArticleAudit) error {
+	return r.dao.InsertAudit(ctx, dao.ArticleAudit{
+		ArtId:  a.ArticleId,
+		Uid:    a.Uid,
+		Action: a.Action,
+		Detail: a.Detail,
+	})
+}
+
+func (r *articleCollaboratorRepository) ListAudits(ctx context.Context, artId int64, offset int, limit int) ([]domain.ArticleAudit, error) {
+	res, err := r.dao.ListAudits(ctx, artId, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(res, func(idx int, src dao.ArticleAudit) domain.ArticleAudit {
+		return domain.ArticleAudit{
+			Id:        src.Id,
+			ArticleId: src.ArtId,
+			Uid:       src.Uid,
+			Action:    src.Action,
+			Detail:    src.Detail,
+			Ctime:     time.UnixMilli(src.Ctime),
+		}
+	}), nil
+}
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"webook/internal/repository/dao"
+	daomocks "webook/internal/repository/dao/mocks"
+)
+
+func TestArticleCollaboratorRepository_ListArticles(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	collabDao := daomocks.NewMockArticleCollaboratorDAO(ctrl)
+	artDao := daomocks.NewMockArticleDAO(ctrl)
+	collabDao.EXPECT().ListArticleIds(gomock.Any(), int64(2)).Return([]int64{3, 4}, nil)
+	// 不管文章存在哪里，都按照 ID 查文章
+	artDao.EXPECT().ListByIds(gomock.Any(), []int64{3, 4}, 0, 10).
+		Return([]dao.Article{{Id: 4, Title: "标题", AuthorId: 1, Status: 1}}, nil)
+	arts, err := NewArticleCollaboratorRepository(collabDao, artDao, nil).
+		ListArticles(context.Background(), 2, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, arts, 1)
+	assert.Equal(t, int64(4), arts[0].Id)
+	assert.Equal(t, int64(1), arts[0].Author.Id)
+}
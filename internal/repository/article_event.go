@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"github.com/gotomicro/ekit/slice"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository/dao"
+)
+
+// ArticleEventRepository 事件是在 ArticleDAO 写文章的时候一起写入的，这里只负责投递的状态
+type ArticleEventRepository interface {
+	ListPending(ctx context.Context, limit int) ([]domain.ArticleEvent, error)
+	MarkSent(ctx context.Context, id int64) error
+	MarkFailed(ctx context.Context, id int64) error
+	DeleteSent(ctx context.Context, before time.Time) (int64, error)
+}
+
+type articleEventRepository struct {
+	dao dao.ArticleEventDAO
+}
+
+func NewArticleEventRepository(dao dao.ArticleEventDAO) ArticleEventRepository {
+	return &articleEventRepository{
+		dao: dao,
+	}
+}
+
+func (a *articleEventRepository) ListPending(ctx context.Context, limit int) ([]domain.ArticleEvent, error) {
+	evts, err := a.dao.ListPending(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(evts, func(idx int, src dao.ArticleEvent) domain.ArticleEvent {
+		return domain.ArticleEvent{
+			Id:   src.Id,
+			Type: src.Type,
+			Article: domain.Article{
+				Id:     src.ArtId,
+				Author: domain.Author{Id: src.AuthorId},
+				Status: domain.ArticleStatus(src.ArtStatus),
+			},
+			Retries: src.Retries,
+			Ctime:   time.UnixMilli(src.Ctime),
+		}
+	}), nil
+}
+
+func (a *articleEventRepository) MarkSent(ctx context.Context, id int64) error {
+	return a.dao.MarkSent(ctx, id)
+}
+
+func (a *articleEventRepository) MarkFailed(ctx context.Context, id int64) error {
+	return a.dao.MarkFailed(ctx, id)
+}
+
+func (a *articleEventRepository) DeleteSent(ctx context.Context, before time.Time) (int64, error) {
+	return a.dao.DeleteSent(ctx, before.UnixMilli())
+}
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+	"webook/internal/domain"
+	"webook/internal/repository/cache"
+)
+
+type ArticleRelatedRepository interface {
+	// Get 还没有计算过或者已经过期的时候返回 error
+	Get(ctx context.Context, id int64) ([]domain.RelatedArticle, error)
+	SetBatch(ctx context.Context, items map[int64][]domain.RelatedArticle) error
+}
+
+type CachedArticleRelatedRepository struct {
+	cache cache.ArticleRelatedCache
+}
+
+func NewCachedArticleRelatedRepository(cache cache.ArticleRelatedCache) ArticleRelatedRepository {
+	return &CachedArticleRelatedRepository{cache: cache}
+}
+
+func (c *CachedArticleRelatedRepository) Get(ctx context.Context, id int64) ([]domain.RelatedArticle, error) {
+	return c.cache.Get(ctx, id)
+}
+
+func (c *CachedArticleRelatedRepository) SetBatch(ctx context.Context, items map[int64][]domain.RelatedArticle) error {
+	return c.cache.SetBatch(ctx, items)
+}
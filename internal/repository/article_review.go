@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"github.com/gotomicro/ekit/slice"
+	"strings"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository/dao"
+)
+
+var (
+	ErrReviewNotFound   = dao.ErrRecordNotFound
+	ErrReviewNotPending = dao.ErrReviewNotPending
+)
+
+type ArticleReviewRepository interface {
+	Create(ctx context.Context, r domain.ArticleReview) (int64, error)
+	FindById(ctx context.Context, id int64) (domain.ArticleReview, error)
+	ListByStatus(ctx context.Context, status domain.ReviewStatus, offset int, limit int) ([]domain.ArticleReview, error)
+	ListByArticle(ctx context.Context, artId int64, offset int, limit int) ([]domain.ArticleReview, error)
+	Resolve(ctx context.Context, id int64, status domain.ReviewStatus, reviewer int64, reason string) error
+}
+
+type articleReviewRepository struct {
+	dao dao.ArticleReviewDAO
+}
+
+func NewArticleReviewRepository(dao dao.ArticleReviewDAO) ArticleReviewRepository {
+	return &articleReviewRepository{
+		dao: dao,
+	}
+}
+
+func (r *articleReviewRepository) Create(ctx context.Context, review domain.ArticleReview) (int64, error) {
+	return r.dao.Insert(ctx, dao.ArticleReview{
+		ArtId:     review.ArticleId,
+		Title:     review.Title,
+		AuthorId:  review.Author,
+		Submitter: review.Submitter,
+		Hits:      strings.Join(review.Hits, ","),
+		Status:    review.Status.ToUint8(),
+	})
+}
+
+func (r *articleReviewRepository) FindById(ctx context.Context, id int64) (domain.ArticleReview, error) {
+	res, err := r.dao.GetById(ctx, id)
+	if err != nil {
+		return domain.ArticleReview{}, err
+	}
+	return r.toDomain(res), nil
+}
+
+func (r *articleReviewRepository) ListByStatus(ctx context.Context, status domain.ReviewStatus, offset int, limit int) ([]domain.ArticleReview, error) {
+	res, err := r.dao.ListByStatus(ctx, status.ToUint8(), offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(res, func(idx int, src dao.ArticleReview) domain.ArticleReview {
+		return r.toDomain(src)
+	}), nil
+}
+
+func (r *articleReviewRepository) ListByArticle(ctx context.Context, artId int64, offset int, limit int) ([]domain.ArticleReview, error) {
+	res, err := r.dao.ListByArticle(ctx, artId, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(res, func(idx int, src dao.ArticleReview) domain.ArticleReview {
+		return r.toDomain(src)
+	}), nil
+}
+
+func (r *articleReviewRepository) Resolve(ctx context.Context, id int64, status domain.ReviewStatus, reviewer int64, reason string) error {
+	return r.dao.Resolve(ctx, id, status.ToUint8(), reviewer, reason)
+}
+
+func (r *articleReviewRepository) toDomain(review dao.ArticleReview) domain.ArticleReview {
+	var hits []string
+	if review.Hits != "" {
+		hits = strings.Split(review.Hits, ",")
+	}
+	return domain.ArticleReview{
+		Id:        review.Id,
+		ArticleId: review.ArtId,
+		Title:     review.Title,
+		Author:    review.AuthorId,
+		Submitter: review.Submitter,
+		Hits:      hits,
+		Status:    domain.ReviewStatus(review.Status),
+		Reviewer:  review.Reviewer,
+		Reason:    review.Reason,
+		Ctime:     time.UnixMilli(review.Ctime),
+		Utime:     time.UnixMilli(review.Utime),
+	}
+}
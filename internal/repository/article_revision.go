@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"github.com/gotomicro/ekit/slice"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository/dao"
+)
+
+func (c *CacheArticleRepository) ListRevisions(ctx context.Context, artId int64, offset int, limit int) ([]domain.ArticleRevision, error) {
+	revs, err := c.dao.ListRevisions(ctx, artId, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(revs, func(idx int, src dao.ArticleRevision) domain.ArticleRevision {
+		return c.toRevisionDomain(src)
+	}), nil
+}
+
+func (c *CacheArticleRepository) GetRevision(ctx context.Context, artId int64, version int64) (domain.ArticleRevision, error) {
+	rev, err := c.dao.GetRevision(ctx, artId, version)
+	if err != nil {
+		return domain.ArticleRevision{}, err
+	}
+	return c.toRevisionDomain(rev), nil
+}
+
+func (c *CacheArticleRepository) toRevisionDomain(rev dao.ArticleRevision) domain.ArticleRevision {
+	return domain.ArticleRevision{
+		Id:        rev.Id,
+		ArticleId: rev.ArtId,
+		Version:   rev.Version,
+		Title:     rev.Title,
+		Content:   rev.Content,
+		Status:    domain.ArticleStatus(rev.Status),
+		Author: domain.Author{
+			Id: rev.AuthorId,
+		},
+		Ctime: time.UnixMilli(rev.Ctime),
+	}
+}
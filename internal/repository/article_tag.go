@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"github.com/gotomicro/ekit/slice"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository/dao"
+)
+
+func (c *CacheArticleRepository) ListTags(ctx context.Context, offset int, limit int) ([]domain.Tag, error) {
+	tags, err := c.tagDao.ListTags(ctx, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(tags, func(idx int, src dao.Tag) domain.Tag {
+		return domain.Tag{
+			Id:    src.Id,
+			Name:  src.Name,
+			Ctime: time.UnixMilli(src.Ctime),
+		}
+	}), nil
+}
+
+// maxPubByTagIds 按照标签翻页的时候只看最近发表的这么多篇文章
+const maxPubByTagIds = 10000
+
+func (c *CacheArticleRepository) ListPubByTag(ctx context.Context, tag string, offset int, limit int) ([]domain.Article, error) {
+	ids, err := c.tagDao.ListPubArticleIds(ctx, tag, maxPubByTagIds)
+	if err != nil {
+		return nil, err
+	}
+	arts, err := c.dao.ListPubByIds(ctx, ids, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(arts, func(idx int, src dao.PublishedArticle) domain.Article {
+		return c.toDomain(dao.Article(src))
+	}), nil
+}
+
+func (c *CacheArticleRepository) getTags(ctx context.Context, id int64) ([]string, error) {
+	res, err := c.cache.GetTags(ctx, id)
+	if err == nil {
+		return res, nil
+	}
+	tags, err := c.tagDao.GetArticleTags(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	res = slice.Map(tags, func(idx int, src dao.Tag) string {
+		return src.Name
+	})
+	err = c.cache.SetTags(ctx, id, res)
+	if err != nil {
+		//记录日志
+	}
+	return res, nil
+}
+
+// saveTags tags 为 nil 说明这一次没有修改标签
+func (c *CacheArticleRepository) saveTags(ctx context.Context, id int64, tags []string) error {
+	if tags == nil {
+		return nil
+	}
+	err := c.tagDao.SetArticleTags(ctx, id, tags)
+	if err != nil {
+		return err
+	}
+	err = c.cache.DelTags(ctx, id)
+	if err != nil {
+		//记录日志
+	}
+	return nil
+}
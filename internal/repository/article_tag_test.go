@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"webook/internal/domain"
+	cachemocks "webook/internal/repository/cache/mocks"
+	"webook/internal/repository/dao"
+	daomocks "webook/internal/repository/dao/mocks"
+)
+
+func TestCacheArticleRepository_SyncTags(t *testing.T) {
+	testCases := []struct {
+		name    string
+		mock    func(artDao *daomocks.MockArticleDAO, c *cachemocks.MockArticleCache, pubCache *cachemocks.MockArticlePubCache)
+		art     domain.Article
+		wantId  int64
+		wantErr error
+	}{
+		{
+			name: "修改了标签",
+			mock: func(artDao *daomocks.MockArticleDAO, c *cachemocks.MockArticleCache, pubCache *cachemocks.MockArticlePubCache) {
+				artDao.EXPECT().Sync(gomock.Any(), dao.Article{Id: 3, Title: "标题", AuthorId: 1},
+					[]string{"go"}).Return(int64(3), nil)
+				c.EXPECT().DelTags(gomock.Any(), int64(3)).Return(nil)
+				pubCache.EXPECT().AddIds(gomock.Any(), int64(3)).Return(nil)
+				c.EXPECT().DelFirstPage(gomock.Any(), int64(1)).Return(nil)
+				pubCache.EXPECT().Del(gomock.Any(), int64(3)).Return(nil)
+			},
+			art:    domain.Article{Id: 3, Title: "标题", Author: domain.Author{Id: 1}, Tags: []string{"go"}},
+			wantId: 3,
+		},
+		{
+			name: "没有修改标签，不删除草稿标签的缓存",
+			mock: func(artDao *daomocks.MockArticleDAO, c *cachemocks.MockArticleCache, pubCache *cachemocks.MockArticlePubCache) {
+				artDao.EXPECT().Sync(gomock.Any(), dao.Article{Id: 3, Title: "标题", AuthorId: 1},
+					[]string(nil)).Return(int64(3), nil)
+				pubCache.EXPECT().AddIds(gomock.Any(), int64(3)).Return(nil)
+				c.EXPECT().DelFirstPage(gomock.Any(), int64(1)).Return(nil)
+				pubCache.EXPECT().Del(gomock.Any(), int64(3)).Return(nil)
+			},
+			art:    domain.Article{Id: 3, Title: "标题", Author: domain.Author{Id: 1}},
+			wantId: 3,
+		},
+		{
+			name: "标签和文章一起回滚",
+			mock: func(artDao *daomocks.MockArticleDAO, c *cachemocks.MockArticleCache, pubCache *cachemocks.MockArticlePubCache) {
+				artDao.EXPECT().Sync(gomock.Any(), gomock.Any(), []string{"go"}).
+					Return(int64(0), errors.New("mock db error"))
+			},
+			art:     domain.Article{Id: 3, Title: "标题", Author: domain.Author{Id: 1}, Tags: []string{"go"}},
+			wantErr: errors.New("mock db error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			artDao := daomocks.NewMockArticleDAO(ctrl)
+			c := cachemocks.NewMockArticleCache(ctrl)
+			pubCache := cachemocks.NewMockArticlePubCache(ctrl)
+			tc.mock(artDao, c, pubCache)
+			repo := NewCacheArticleRepository(artDao, daomocks.NewMockTagDAO(ctrl), nil, c, pubCache)
+			id, err := repo.Sync(context.Background(), tc.art)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantId, id)
+		})
+	}
+}
+
+// TestCacheArticleRepository_TagsSplit 读者看到的是发表时候的标签，作者看到的是草稿的标签
+func TestCacheArticleRepository_TagsSplit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	artDao := daomocks.NewMockArticleDAO(ctrl)
+	tagDao := daomocks.NewMockTagDAO(ctrl)
+	userRepo := stubUserRepository{user: domain.User{Id: 1, Nickname: "大明"}}
+	c := cachemocks.NewMockArticleCache(ctrl)
+	pubCache := cachemocks.NewMockArticlePubCache(ctrl)
+	repo := NewCacheArticleRepository(artDao, tagDao, userRepo, c, pubCache)
+
+	pubCache.EXPECT().MightExist(gomock.Any(), int64(3)).Return(true, nil)
+	pubCache.EXPECT().Get(gomock.Any(), int64(3), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, id int64,
+			load func(ctx context.Context) (domain.Article, error)) (domain.Article, error) {
+			return load(ctx)
+		})
+	artDao.EXPECT().GetPubById(gomock.Any(), int64(3)).
+		Return(dao.PublishedArticle{Id: 3, Title: "标题", AuthorId: 1, Status: 2}, nil)
+	tagDao.EXPECT().GetPubArticleTags(gomock.Any(), int64(3)).Return([]dao.Tag{{Id: 1, Name: "go"}}, nil)
+	pub, err := repo.GetPubById(context.Background(), 3)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"go"}, pub.Tags)
+	assert.Equal(t, "大明", pub.Author.Name)
+
+	// 草稿改了标签但是还没有发表
+	c.EXPECT().Get(gomock.Any(), int64(3)).Return(domain.Article{Id: 3, Title: "标题"}, nil)
+	c.EXPECT().GetTags(gomock.Any(), int64(3)).Return(nil, errors.New("cache miss"))
+	tagDao.EXPECT().GetArticleTags(gomock.Any(), int64(3)).
+		Return([]dao.Tag{{Id: 1, Name: "go"}, {Id: 2, Name: "redis"}}, nil)
+	c.EXPECT().SetTags(gomock.Any(), int64(3), []string{"go", "redis"}).Return(nil)
+	draft, err := repo.GetById(context.Background(), 3)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"go", "redis"}, draft.Tags)
+}
+
+func TestCacheArticleRepository_ListPubByTag(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	tagDao := daomocks.NewMockTagDAO(ctrl)
+	artDao := daomocks.NewMockArticleDAO(ctrl)
+	tagDao.EXPECT().ListPubArticleIds(gomock.Any(), "go", maxPubByTagIds).Return([]int64{3, 4}, nil)
+	// 4 已经撤回了
+	artDao.EXPECT().ListPubByIds(gomock.Any(), []int64{3, 4}, 0, 10).
+		Return([]dao.PublishedArticle{{Id: 3, Title: "标题", AuthorId: 1, Status: 2}}, nil)
+	repo := NewCacheArticleRepository(artDao, tagDao, nil, nil, nil)
+	arts, err := repo.ListPubByTag(context.Background(), "go", 0, 10)
+	require.NoError(t, err)
+	require.Len(t, arts, 1)
+	assert.Equal(t, int64(3), arts[0].Id)
+	assert.Equal(t, int64(1), arts[0].Author.Id)
+	assert.Equal(t, domain.ArticleStatus(domain.ArticleStatusPublished), arts[0].Status)
+}
+
+// stubUserRepository UserRepository 有私有方法，mockgen 生成的实现不了
+type stubUserRepository struct {
+	UserRepository
+	user domain.User
+}
+
+func (s stubUserRepository) FindById(ctx context.Context, id int64) (domain.User, error) {
+	return s.user, nil
+}
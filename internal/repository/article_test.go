@@ -87,3 +87,23 @@ func TestCacheArticleRepository_SyncV1(t *testing.T) {
 		})
 	}
 }
+
+// TestCacheArticleRepository_GetByAuthorCursor 第一页也走游标查询，不用 offset 翻页的缓存
+func TestCacheArticleRepository_GetByAuthorCursor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	artDao := daomocks.NewMockArticleDAO(ctrl)
+	artDao.EXPECT().GetByAuthorCursor(gomock.Any(), int64(1), int64(0), int64(0), 2).
+		Return([]dao.Article{{Id: 4, Utime: 100}, {Id: 3, Utime: 100}}, nil)
+	artDao.EXPECT().GetByAuthorCursor(gomock.Any(), int64(1), int64(100), int64(3), 2).
+		Return([]dao.Article{{Id: 2, Utime: 100}}, nil)
+	repo := NewCacheArticleRepository(artDao, nil, nil, nil, nil)
+
+	arts, err := repo.GetByAuthorCursor(context.Background(), 1, domain.ArticleCursor{}, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{4, 3}, []int64{arts[0].Id, arts[1].Id})
+	arts, err = repo.GetByAuthorCursor(context.Background(), 1, domain.NextArticleCursor(arts, 2), 2)
+	assert.NoError(t, err)
+	assert.Len(t, arts, 1)
+	assert.Equal(t, int64(2), arts[0].Id)
+}
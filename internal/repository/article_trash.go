@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"github.com/gotomicro/ekit/slice"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository/dao"
+)
+
+var ErrArticleNotInTrash = dao.ErrArticleNotInTrash
+
+func (c *CacheArticleRepository) Delete(ctx context.Context, id int64, uid int64) error {
+	err := c.dao.Delete(ctx, id, uid)
+	if err != nil {
+		return err
+	}
+	c.delCache(ctx, id, uid)
+	return nil
+}
+
+func (c *CacheArticleRepository) Restore(ctx context.Context, id int64, uid int64, deletedAfter time.Time) error {
+	err := c.dao.Restore(ctx, id, uid, deletedAfter.UnixMilli())
+	if err != nil {
+		return err
+	}
+	// 恢复之后第一页就不对了，线上库的缓存可能还是不存在
+	er := c.cache.DelFirstPage(ctx, uid)
+	if er != nil {
+		//记录日志
+	}
+	er = c.pubCache.Del(ctx, id)
+	if er != nil {
+		//记录日志
+	}
+	return nil
+}
+
+func (c *CacheArticleRepository) ListTrash(ctx context.Context, uid int64, offset int, limit int) ([]domain.Article, error) {
+	arts, err := c.dao.ListTrash(ctx, uid, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(arts, func(idx int, src dao.Article) domain.Article {
+		return c.toDomain(src)
+	}), nil
+}
+
+func (c *CacheArticleRepository) ListExpiredTrash(ctx context.Context, before time.Time, limit int) ([]domain.Article, error) {
+	arts, err := c.dao.ListExpiredTrash(ctx, before.UnixMilli(), limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(arts, func(idx int, src dao.Article) domain.Article {
+		return c.toDomain(src)
+	}), nil
+}
+
+func (c *CacheArticleRepository) Purge(ctx context.Context, id int64, before time.Time) error {
+	err := c.dao.Purge(ctx, id, before.UnixMilli())
+	if err != nil {
+		return err
+	}
+	err = c.tagDao.SetArticleTags(ctx, id, nil)
+	if err != nil {
+		return err
+	}
+	er := c.cache.DelTags(ctx, id)
+	if er != nil {
+		//记录日志
+	}
+	er = c.pubCache.Del(ctx, id)
+	if er != nil {
+		//记录日志
+	}
+	return nil
+}
+
+// delCache 删除之后文章的缓存全部失效，缓存的错误不影响删除的结果
+func (c *CacheArticleRepository) delCache(ctx context.Context, id int64, uid int64) {
+	er := c.cache.DelFirstPage(ctx, uid)
+	if er != nil {
+		//记录日志
+	}
+	er = c.cache.Del(ctx, id)
+	if er != nil {
+		//记录日志
+	}
+	er = c.pubCache.Del(ctx, id)
+	if er != nil {
+		//记录日志
+	}
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"github.com/gotomicro/ekit/slice"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository/dao"
+)
+
+var (
+	ErrBlobNotFound = dao.ErrRecordNotFound
+	ErrBlobInUse    = dao.ErrBlobInUse
+)
+
+type BlobRepository interface {
+	// Save 内容已经存在的时候返回已有的 blob
+	Save(ctx context.Context, b domain.Blob) (domain.Blob, error)
+	FindByHash(ctx context.Context, hash string) (domain.Blob, error)
+	SetArticleBlobs(ctx context.Context, artId int64, hashes []string) error
+	ListUnreferenced(ctx context.Context, before time.Time, limit int) ([]domain.Blob, error)
+	// Delete fn 在删除记录之后、提交之前执行，返回错误的时候回滚
+	Delete(ctx context.Context, id int64, before time.Time, fn func() error) error
+}
+
+type blobRepository struct {
+	dao dao.BlobDAO
+}
+
+func NewBlobRepository(dao dao.BlobDAO) BlobRepository {
+	return &blobRepository{
+		dao: dao,
+	}
+}
+
+func (r *blobRepository) Save(ctx context.Context, b domain.Blob) (domain.Blob, error) {
+	res, err := r.dao.Upsert(ctx, dao.Blob{
+		Hash:     b.Hash,
+		Size:     b.Size,
+		MimeType: b.MimeType,
+		Uploader: b.Uploader,
+	})
+	if err != nil {
+		return domain.Blob{}, err
+	}
+	return r.toDomain(res), nil
+}
+
+func (r *blobRepository) FindByHash(ctx context.Context, hash string) (domain.Blob, error) {
+	res, err := r.dao.GetByHash(ctx, hash)
+	if err != nil {
+		return domain.Blob{}, err
+	}
+	return r.toDomain(res), nil
+}
+
+func (r *blobRepository) SetArticleBlobs(ctx context.Context, artId int64, hashes []string) error {
+	return r.dao.SetArticleBlobs(ctx, artId, hashes)
+}
+
+func (r *blobRepository) ListUnreferenced(ctx context.Context, before time.Time, limit int) ([]domain.Blob, error) {
+	res, err := r.dao.ListUnreferenced(ctx, before.UnixMilli(), limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(res, func(idx int, src dao.Blob) domain.Blob {
+		return r.toDomain(src)
+	}), nil
+}
+
+func (r *blobRepository) Delete(ctx context.Context, id int64, before time.Time, fn func() error) error {
+	return r.dao.Delete(ctx, id, before.UnixMilli(), fn)
+}
+
+func (r *blobRepository) toDomain(b dao.Blob) domain.Blob {
+	return domain.Blob{
+		Id:       b.Id,
+		Hash:     b.Hash,
+		Size:     b.Size,
+		MimeType: b.MimeType,
+		Uploader: b.Uploader,
+		Ctime:    time.UnixMilli(b.Ctime),
+		Utime:    time.UnixMilli(b.Utime),
+	}
+}
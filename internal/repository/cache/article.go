@@ -15,8 +15,10 @@ type ArticleCache interface {
 	DelFirstPage(ctx context.Context, uid int64) error
 	Get(ctx context.Context, id int64) (domain.Article, error)
 	Set(ctx context.Context, res domain.Article) error
-	GetPub(ctx context.Context, id int64) (domain.Article, error)
-	SetPub(ctx context.Context, art domain.Article) error
+	Del(ctx context.Context, id int64) error
+	GetTags(ctx context.Context, id int64) ([]string, error)
+	SetTags(ctx context.Context, id int64, tags []string) error
+	DelTags(ctx context.Context, id int64) error
 }
 
 type ArticleRedisCache struct {
@@ -29,24 +31,6 @@ func NewArticleRedisCache(client redis.Cmdable) ArticleCache {
 	}
 }
 
-func (a *ArticleRedisCache) GetPub(ctx context.Context, id int64) (domain.Article, error) {
-	val, err := a.client.Get(ctx, a.pubKey(id)).Bytes()
-	if err != nil {
-		return domain.Article{}, err
-	}
-	var art domain.Article
-	err = json.Unmarshal(val, &art)
-	return art, err
-}
-
-func (a *ArticleRedisCache) SetPub(ctx context.Context, res domain.Article) error {
-	art, err := json.Marshal(res)
-	if err != nil {
-		return err
-	}
-	return a.client.Set(ctx, a.pubKey(res.Id), art, 10*time.Minute).Err()
-}
-
 func (a *ArticleRedisCache) Set(ctx context.Context, res domain.Article) error {
 	art, err := json.Marshal(res)
 	if err != nil {
@@ -65,9 +49,14 @@ func (a *ArticleRedisCache) Get(ctx context.Context, id int64) (domain.Article,
 	return art, err
 }
 
+func (a *ArticleRedisCache) Del(ctx context.Context, id int64) error {
+	return a.client.Del(ctx, a.key(id)).Err()
+}
+
 func (a *ArticleRedisCache) SetFirstPage(ctx context.Context, uid int64, arts []domain.Article) error {
 	for i := 0; i < len(arts); i++ {
 		arts[i].Content = arts[i].Abstract()
+		arts[i].HTML = ""
 	}
 	key := a.firstKey(uid)
 	value, err := json.Marshal(arts)
@@ -94,6 +83,32 @@ func (a *ArticleRedisCache) DelFirstPage(ctx context.Context, uid int64) error {
 	return err
 }
 
+func (a *ArticleRedisCache) GetTags(ctx context.Context, id int64) ([]string, error) {
+	val, err := a.client.Get(ctx, a.tagsKey(id)).Bytes()
+	if err != nil {
+		return nil, err
+	}
+	var res []string
+	err = json.Unmarshal(val, &res)
+	return res, err
+}
+
+func (a *ArticleRedisCache) SetTags(ctx context.Context, id int64, tags []string) error {
+	if tags == nil {
+		// 没有标签也要缓存下来，避免一直回查数据库
+		tags = []string{}
+	}
+	val, err := json.Marshal(tags)
+	if err != nil {
+		return err
+	}
+	return a.client.Set(ctx, a.tagsKey(id), val, 10*time.Minute).Err()
+}
+
+func (a *ArticleRedisCache) DelTags(ctx context.Context, id int64) error {
+	return a.client.Del(ctx, a.tagsKey(id)).Err()
+}
+
 func (a *ArticleRedisCache) firstKey(uid int64) string {
 	return fmt.Sprintf("article:first_page:%d", uid)
 }
@@ -102,6 +117,6 @@ func (a *ArticleRedisCache) key(id int64) string {
 	return fmt.Sprintf("article:detail:%d", id)
 }
 
-func (a *ArticleRedisCache) pubKey(id int64) string {
-	return fmt.Sprintf("article:pub:detail:%d", id)
+func (a *ArticleRedisCache) tagsKey(id int64) string {
+	return fmt.Sprintf("article:tags:%d", id)
 }
@@ -0,0 +1,71 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+	"webook/internal/domain"
+	"webook/pkg/cachex"
+)
+
+// ArticlePubCache 线上库文章详情的多级缓存，布隆过滤器挡住不存在的 ID
+type ArticlePubCache interface {
+	// Get 缓存没有命中的时候用 load 回源，同一个实例上并发的请求只回源一次。
+	// load 返回的不存在的错误也会被缓存一段时间
+	Get(ctx context.Context, id int64, load func(ctx context.Context) (domain.Article, error)) (domain.Article, error)
+	// Del 同时删除其它实例的本地缓存
+	Del(ctx context.Context, id int64) error
+	// MightExist 返回 false 的时候文章一定不存在，过滤器还没有建好的时候总是返回 true
+	MightExist(ctx context.Context, id int64) (bool, error)
+	AddIds(ctx context.Context, ids ...int64) error
+	// RebuildIds fill 通过 add 写入线上库全部文章的 ID
+	RebuildIds(ctx context.Context, fill func(add func(ids ...int64) error) error) error
+}
+
+type ArticlePubMultiLevelCache struct {
+	cache *cachex.MultiLevelCache[domain.Article]
+	bloom *cachex.RedisBloomFilter
+}
+
+func NewArticlePubCache(cache *cachex.MultiLevelCache[domain.Article], bloom *cachex.RedisBloomFilter) ArticlePubCache {
+	return &ArticlePubMultiLevelCache{
+		cache: cache,
+		bloom: bloom,
+	}
+}
+
+func (a *ArticlePubMultiLevelCache) Get(ctx context.Context, id int64,
+	load func(ctx context.Context) (domain.Article, error)) (domain.Article, error) {
+	return a.cache.Get(ctx, a.key(id), load)
+}
+
+func (a *ArticlePubMultiLevelCache) Del(ctx context.Context, id int64) error {
+	return a.cache.Delete(ctx, a.key(id))
+}
+
+func (a *ArticlePubMultiLevelCache) MightExist(ctx context.Context, id int64) (bool, error) {
+	return a.bloom.MightContain(ctx, a.key(id))
+}
+
+func (a *ArticlePubMultiLevelCache) AddIds(ctx context.Context, ids ...int64) error {
+	return a.bloom.Add(ctx, a.keys(ids)...)
+}
+
+func (a *ArticlePubMultiLevelCache) RebuildIds(ctx context.Context, fill func(add func(ids ...int64) error) error) error {
+	return a.bloom.Rebuild(ctx, func(add func(items ...string) error) error {
+		return fill(func(ids ...int64) error {
+			return add(a.keys(ids)...)
+		})
+	})
+}
+
+func (a *ArticlePubMultiLevelCache) key(id int64) string {
+	return strconv.FormatInt(id, 10)
+}
+
+func (a *ArticlePubMultiLevelCache) keys(ids []int64) []string {
+	res := make([]string, 0, len(ids))
+	for _, id := range ids {
+		res = append(res, a.key(id))
+	}
+	return res
+}
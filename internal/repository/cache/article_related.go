@@ -0,0 +1,59 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/redis/go-redis/v9"
+	"strconv"
+	"time"
+	"webook/internal/domain"
+)
+
+type ArticleRelatedCache interface {
+	Get(ctx context.Context, id int64) ([]domain.RelatedArticle, error)
+	// SetBatch 一次写入多篇文章的相关推荐
+	SetBatch(ctx context.Context, items map[int64][]domain.RelatedArticle) error
+}
+
+type ArticleRelatedRedisCache struct {
+	client redis.Cmdable
+	// expiration 要比计算任务的间隔长，任务失败几次也还有数据
+	expiration time.Duration
+}
+
+func NewArticleRelatedRedisCache(client redis.Cmdable) ArticleRelatedCache {
+	return &ArticleRelatedRedisCache{
+		client:     client,
+		expiration: time.Hour * 48,
+	}
+}
+
+func (a *ArticleRelatedRedisCache) Get(ctx context.Context, id int64) ([]domain.RelatedArticle, error) {
+	val, err := a.client.Get(ctx, a.key(id)).Bytes()
+	if err != nil {
+		return nil, err
+	}
+	var res []domain.RelatedArticle
+	err = json.Unmarshal(val, &res)
+	return res, err
+}
+
+func (a *ArticleRelatedRedisCache) SetBatch(ctx context.Context, items map[int64][]domain.RelatedArticle) error {
+	if len(items) == 0 {
+		return nil
+	}
+	pipe := a.client.Pipeline()
+	for id, related := range items {
+		val, err := json.Marshal(related)
+		if err != nil {
+			return err
+		}
+		pipe.Set(ctx, a.key(id), val, a.expiration)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (a *ArticleRelatedRedisCache) key(id int64) string {
+	return "article:related:" + strconv.FormatInt(id, 10)
+}
@@ -0,0 +1,58 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/redis/go-redis/v9"
+	"time"
+	"webook/internal/domain"
+)
+
+type FeedCache interface {
+	Get(ctx context.Context, key string) (domain.Feed, error)
+	Set(ctx context.Context, key string, feed domain.Feed) error
+	Del(ctx context.Context, keys ...string) error
+}
+
+type FeedRedisCache struct {
+	client redis.Cmdable
+	// expiration 正常情况下文章变更的时候会删除缓存，过期时间只是兜底
+	expiration time.Duration
+}
+
+func NewFeedRedisCache(client redis.Cmdable) FeedCache {
+	return &FeedRedisCache{
+		client:     client,
+		expiration: time.Hour,
+	}
+}
+
+func (f *FeedRedisCache) Get(ctx context.Context, key string) (domain.Feed, error) {
+	val, err := f.client.Get(ctx, f.key(key)).Bytes()
+	if err != nil {
+		return domain.Feed{}, err
+	}
+	var res domain.Feed
+	err = json.Unmarshal(val, &res)
+	return res, err
+}
+
+func (f *FeedRedisCache) Set(ctx context.Context, key string, feed domain.Feed) error {
+	val, err := json.Marshal(feed)
+	if err != nil {
+		return err
+	}
+	return f.client.Set(ctx, f.key(key), val, f.expiration).Err()
+}
+
+func (f *FeedRedisCache) Del(ctx context.Context, keys ...string) error {
+	redisKeys := make([]string, len(keys))
+	for i, k := range keys {
+		redisKeys[i] = f.key(k)
+	}
+	return f.client.Del(ctx, redisKeys...).Err()
+}
+
+func (f *FeedRedisCache) key(key string) string {
+	return "feed:" + key
+}
@@ -0,0 +1,13 @@
+-- 每次校验都先增加尝试次数，超过上限直接删除，防止暴力破解验证码
+local key = KEYS[1]
+local maxAttempts = tonumber(ARGV[1])
+local uid = redis.call("HGET", key, "uid")
+if not uid then
+    return -1
+end
+local attempts = redis.call("HINCRBY", key, "attempts", 1)
+if attempts > maxAttempts then
+    redis.call("DEL", key)
+    return -2
+end
+return tonumber(uid)
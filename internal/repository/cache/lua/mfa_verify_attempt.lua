@@ -0,0 +1,12 @@
+-- 先计数再校验验证码，并发的请求也绕不过上限。校验通过之后调用方会删掉这个 key
+local key = KEYS[1]
+local maxFailures = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local cnt = redis.call("INCR", key)
+if cnt == 1 then
+    redis.call("PEXPIRE", key, window)
+end
+if cnt > maxFailures then
+    return -1
+end
+return cnt
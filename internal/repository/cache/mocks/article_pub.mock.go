@@ -0,0 +1,117 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/repository/cache/article_pub.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/repository/cache/article_pub.go -package=cachemocks -destination=./internal/repository/cache/mocks/article_pub.mock.go
+//
+// Package cachemocks is a generated GoMock package.
+package cachemocks
+
+import (
+	context "context"
+	reflect "reflect"
+	domain "webook/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockArticlePubCache is a mock of ArticlePubCache interface.
+type MockArticlePubCache struct {
+	ctrl     *gomock.Controller
+	recorder *MockArticlePubCacheMockRecorder
+}
+
+// MockArticlePubCacheMockRecorder is the mock recorder for MockArticlePubCache.
+type MockArticlePubCacheMockRecorder struct {
+	mock *MockArticlePubCache
+}
+
+// NewMockArticlePubCache creates a new mock instance.
+func NewMockArticlePubCache(ctrl *gomock.Controller) *MockArticlePubCache {
+	mock := &MockArticlePubCache{ctrl: ctrl}
+	mock.recorder = &MockArticlePubCacheMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockArticlePubCache) EXPECT() *MockArticlePubCacheMockRecorder {
+	return m.recorder
+}
+
+// AddIds mocks base method.
+func (m *MockArticlePubCache) AddIds(ctx context.Context, ids ...int64) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range ids {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "AddIds", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddIds indicates an expected call of AddIds.
+func (mr *MockArticlePubCacheMockRecorder) AddIds(ctx any, ids ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, ids...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddIds", reflect.TypeOf((*MockArticlePubCache)(nil).AddIds), varargs...)
+}
+
+// Del mocks base method.
+func (m *MockArticlePubCache) Del(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Del", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Del indicates an expected call of Del.
+func (mr *MockArticlePubCacheMockRecorder) Del(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Del", reflect.TypeOf((*MockArticlePubCache)(nil).Del), ctx, id)
+}
+
+// Get mocks base method.
+func (m *MockArticlePubCache) Get(ctx context.Context, id int64, load func(context.Context) (domain.Article, error)) (domain.Article, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id, load)
+	ret0, _ := ret[0].(domain.Article)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockArticlePubCacheMockRecorder) Get(ctx, id, load any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockArticlePubCache)(nil).Get), ctx, id, load)
+}
+
+// MightExist mocks base method.
+func (m *MockArticlePubCache) MightExist(ctx context.Context, id int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MightExist", ctx, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MightExist indicates an expected call of MightExist.
+func (mr *MockArticlePubCacheMockRecorder) MightExist(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MightExist", reflect.TypeOf((*MockArticlePubCache)(nil).MightExist), ctx, id)
+}
+
+// RebuildIds mocks base method.
+func (m *MockArticlePubCache) RebuildIds(ctx context.Context, fill func(func(...int64) error) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RebuildIds", ctx, fill)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RebuildIds indicates an expected call of RebuildIds.
+func (mr *MockArticlePubCacheMockRecorder) RebuildIds(ctx, fill any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RebuildIds", reflect.TypeOf((*MockArticlePubCache)(nil).RebuildIds), ctx, fill)
+}
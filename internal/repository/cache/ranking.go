@@ -26,6 +26,7 @@ func NewRankingRedisCache(client redis.Cmdable, key string, expiration time.Dura
 func (r *RankingRedisCache) Set(ctx context.Context, arts []domain.Article) error {
 	for i, _ := range arts {
 		arts[i].Content = arts[i].Abstract()
+		arts[i].HTML = ""
 	}
 	val, err := json.Marshal(arts)
 	if err != nil {
@@ -15,6 +15,7 @@ type UserCache interface {
 	key(id int64) string
 	Get(ctx context.Context, id int64) (domain.User, error)
 	Set(ctx context.Context, u domain.User) error
+	Del(ctx context.Context, id int64) error
 }
 
 type RedisUserCache struct {
@@ -47,6 +48,10 @@ func (c *RedisUserCache) Set(ctx context.Context, u domain.User) error {
 	return err
 }
 
+func (c *RedisUserCache) Del(ctx context.Context, id int64) error {
+	return c.cmd.Del(ctx, c.key(id)).Err()
+}
+
 func NewRedisUserCache(cmd redis.Cmdable) UserCache {
 	return &RedisUserCache{
 		cmd:        cmd,
@@ -0,0 +1,105 @@
+package cache
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"github.com/redis/go-redis/v9"
+	"strconv"
+	"time"
+)
+
+var (
+	//go:embed lua/mfa_pending_attempt.lua
+	luaMFAPendingAttempt string
+	//go:embed lua/mfa_verify_attempt.lua
+	luaMFAVerifyAttempt string
+)
+
+var (
+	ErrMFATooManyAttempts = errors.New("两步验证尝试次数太多")
+	ErrMFALocked          = errors.New("两步验证失败次数太多，暂时锁定")
+)
+
+// UserMFACache 保存密码已经校验通过、等待两步验证的登录
+type UserMFACache interface {
+	SetPending(ctx context.Context, token string, uid int64) error
+	// AttemptPending 每调用一次算一次尝试，超过次数之后这个 token 就失效了。
+	// token 不存在或者已经过期的时候返回 ErrKeyNotExist
+	AttemptPending(ctx context.Context, token string) (int64, error)
+	DelPending(ctx context.Context, token string) error
+	// AttemptVerify 每次校验验证码之前调用，先按照失败算，校验通过之后再 ResetVerify。
+	// 重新输入密码换一个 token 也绕不过去，窗口之内失败太多次返回 ErrMFALocked
+	AttemptVerify(ctx context.Context, uid int64) error
+	ResetVerify(ctx context.Context, uid int64) error
+}
+
+type UserMFARedisCache struct {
+	client      redis.Cmdable
+	expiration  time.Duration
+	maxAttempts int
+	// lockWindow 内最多失败 maxFailures 次，超过之后锁定到窗口结束
+	lockWindow  time.Duration
+	maxFailures int
+}
+
+func NewUserMFARedisCache(client redis.Cmdable) UserMFACache {
+	return &UserMFARedisCache{
+		client:      client,
+		expiration:  time.Minute * 5,
+		maxAttempts: 5,
+		lockWindow:  time.Minute * 15,
+		maxFailures: 10,
+	}
+}
+
+func (c *UserMFARedisCache) SetPending(ctx context.Context, token string, uid int64) error {
+	pipe := c.client.TxPipeline()
+	pipe.HSet(ctx, c.key(token), "uid", uid, "attempts", 0)
+	pipe.Expire(ctx, c.key(token), c.expiration)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (c *UserMFARedisCache) AttemptPending(ctx context.Context, token string) (int64, error) {
+	res, err := c.client.Eval(ctx, luaMFAPendingAttempt, []string{c.key(token)}, c.maxAttempts).Int64()
+	if err != nil {
+		return 0, err
+	}
+	switch res {
+	case -1:
+		return 0, ErrKeyNotExist
+	case -2:
+		return 0, ErrMFATooManyAttempts
+	default:
+		return res, nil
+	}
+}
+
+func (c *UserMFARedisCache) DelPending(ctx context.Context, token string) error {
+	return c.client.Del(ctx, c.key(token)).Err()
+}
+
+func (c *UserMFARedisCache) AttemptVerify(ctx context.Context, uid int64) error {
+	res, err := c.client.Eval(ctx, luaMFAVerifyAttempt, []string{c.failureKey(uid)},
+		c.maxFailures, c.lockWindow.Milliseconds()).Int64()
+	if err != nil {
+		return err
+	}
+	if res < 0 {
+		return ErrMFALocked
+	}
+	return nil
+}
+
+func (c *UserMFARedisCache) ResetVerify(ctx context.Context, uid int64) error {
+	return c.client.Del(ctx, c.failureKey(uid)).Err()
+}
+
+func (c *UserMFARedisCache) failureKey(uid int64) string {
+	return "users:mfa:failures:" + strconv.FormatInt(uid, 10)
+}
+
+func (c *UserMFARedisCache) key(token string) string {
+	return "users:mfa:pending:" + token
+}
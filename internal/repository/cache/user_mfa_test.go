@@ -0,0 +1,57 @@
+package cache
+
+import (
+	"context"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestUserMFARedisCache_AttemptPending(t *testing.T) {
+	mr := miniredis.RunT(t)
+	c := NewUserMFARedisCache(redis.NewClient(&redis.Options{Addr: mr.Addr()}))
+	ctx := context.Background()
+
+	_, err := c.AttemptPending(ctx, "token")
+	assert.Equal(t, ErrKeyNotExist, err)
+
+	require.NoError(t, c.SetPending(ctx, "token", 1))
+	for i := 0; i < 5; i++ {
+		uid, err := c.AttemptPending(ctx, "token")
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), uid)
+	}
+	_, err = c.AttemptPending(ctx, "token")
+	assert.Equal(t, ErrMFATooManyAttempts, err)
+	// 超过次数之后 token 直接作废
+	_, err = c.AttemptPending(ctx, "token")
+	assert.Equal(t, ErrKeyNotExist, err)
+}
+
+func TestUserMFARedisCache_AttemptVerify(t *testing.T) {
+	mr := miniredis.RunT(t)
+	c := NewUserMFARedisCache(redis.NewClient(&redis.Options{Addr: mr.Addr()}))
+	ctx := context.Background()
+
+	// 校验通过之后重新计数
+	for i := 0; i < 9; i++ {
+		require.NoError(t, c.AttemptVerify(ctx, 1))
+	}
+	require.NoError(t, c.ResetVerify(ctx, 1))
+
+	for i := 0; i < 10; i++ {
+		require.NoError(t, c.AttemptVerify(ctx, 1))
+	}
+	assert.Equal(t, ErrMFALocked, c.AttemptVerify(ctx, 1))
+	// 不影响别的用户
+	assert.NoError(t, c.AttemptVerify(ctx, 2))
+
+	// 锁定期间一直尝试也不会延长锁定的时间
+	mr.FastForward(time.Minute * 10)
+	assert.Equal(t, ErrMFALocked, c.AttemptVerify(ctx, 1))
+	mr.FastForward(time.Minute * 5)
+	assert.NoError(t, c.AttemptVerify(ctx, 1))
+}
@@ -0,0 +1,137 @@
+package dao
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"time"
+)
+
+// ArchiveTask 导出和导入文章压缩包的任务
+type ArchiveTask struct {
+	Id     int64 `gorm:"primaryKey,autoIncrement"`
+	Uid    int64 `gorm:"index"`
+	Type   uint8
+	Status uint8 `gorm:"index:status_utime"`
+	// BlobKey 压缩包在 BlobStore 里面的 key
+	BlobKey string `gorm:"type:varchar(128)"`
+	Cnt     int
+	// Errors 每个文件的错误，JSON 格式
+	Errors string `gorm:"type:text"`
+	Reason string `gorm:"type:varchar(1024)"`
+	Ctime  int64
+	Utime  int64 `gorm:"index:status_utime"`
+}
+
+const (
+	archiveTaskStatusPending = 1
+	archiveTaskStatusRunning = 2
+	archiveTaskStatusDone    = 3
+)
+
+type ArchiveTaskDAO interface {
+	Insert(ctx context.Context, t ArchiveTask) (int64, error)
+	GetById(ctx context.Context, id int64) (ArchiveTask, error)
+	ListByUid(ctx context.Context, uid int64, offset int, limit int) ([]ArchiveTask, error)
+	// Preempt 抢占最早的等待中的任务，执行超时的任务也可以被重新抢占，没有任务返回 ErrRecordNotFound
+	Preempt(ctx context.Context, timeout time.Duration) (ArchiveTask, error)
+	// Finish 更新任务的最终状态
+	Finish(ctx context.Context, t ArchiveTask) error
+	// ListExpired before 之前完成并且还有压缩包的任务
+	ListExpired(ctx context.Context, before int64, limit int) ([]ArchiveTask, error)
+	ClearBlob(ctx context.Context, id int64) error
+}
+
+type GORMArchiveTaskDAO struct {
+	db *gorm.DB
+}
+
+func NewGORMArchiveTaskDAO(db *gorm.DB) ArchiveTaskDAO {
+	return &GORMArchiveTaskDAO{
+		db: db,
+	}
+}
+
+func (g *GORMArchiveTaskDAO) Insert(ctx context.Context, t ArchiveTask) (int64, error) {
+	now := time.Now().UnixMilli()
+	t.Ctime = now
+	t.Utime = now
+	err := g.db.WithContext(ctx).Create(&t).Error
+	return t.Id, err
+}
+
+func (g *GORMArchiveTaskDAO) GetById(ctx context.Context, id int64) (ArchiveTask, error) {
+	var res ArchiveTask
+	err := g.db.WithContext(ctx).Where("id = ?", id).First(&res).Error
+	return res, err
+}
+
+func (g *GORMArchiveTaskDAO) ListByUid(ctx context.Context, uid int64, offset int, limit int) ([]ArchiveTask, error) {
+	var res []ArchiveTask
+	err := g.db.WithContext(ctx).Where("uid = ?", uid).
+		Order("id DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&res).Error
+	return res, err
+}
+
+func (g *GORMArchiveTaskDAO) Preempt(ctx context.Context, timeout time.Duration) (ArchiveTask, error) {
+	db := g.db.WithContext(ctx)
+	for {
+		var t ArchiveTask
+		now := time.Now().UnixMilli()
+		err := db.Where("status = ? OR (status = ? AND utime < ?)",
+			archiveTaskStatusPending, archiveTaskStatusRunning, now-timeout.Milliseconds()).
+			Order("id ASC").
+			First(&t).Error
+		if err != nil {
+			return ArchiveTask{}, err
+		}
+		// 用状态和更新时间做乐观锁，别的实例先抢到了就换一个
+		res := db.Model(&ArchiveTask{}).
+			Where("id = ? AND status = ? AND utime = ?", t.Id, t.Status, t.Utime).
+			Updates(map[string]any{
+				"status": archiveTaskStatusRunning,
+				"utime":  now,
+			})
+		if res.Error != nil {
+			return ArchiveTask{}, res.Error
+		}
+		if res.RowsAffected == 0 {
+			continue
+		}
+		t.Status = archiveTaskStatusRunning
+		t.Utime = now
+		return t, nil
+	}
+}
+
+func (g *GORMArchiveTaskDAO) Finish(ctx context.Context, t ArchiveTask) error {
+	return g.db.WithContext(ctx).Model(&ArchiveTask{}).Where("id = ?", t.Id).
+		Updates(map[string]any{
+			"status":   t.Status,
+			"blob_key": t.BlobKey,
+			"cnt":      t.Cnt,
+			"errors":   t.Errors,
+			"reason":   t.Reason,
+			"utime":    time.Now().UnixMilli(),
+		}).Error
+}
+
+func (g *GORMArchiveTaskDAO) ListExpired(ctx context.Context, before int64, limit int) ([]ArchiveTask, error) {
+	var res []ArchiveTask
+	err := g.db.WithContext(ctx).
+		Where("status = ? AND utime < ? AND blob_key != ''", archiveTaskStatusDone, before).
+		Order("utime ASC").
+		Limit(limit).
+		Find(&res).Error
+	return res, err
+}
+
+func (g *GORMArchiveTaskDAO) ClearBlob(ctx context.Context, id int64) error {
+	return g.db.WithContext(ctx).Model(&ArchiveTask{}).Where("id = ?", id).
+		Updates(map[string]any{
+			"blob_key": "",
+			"utime":    time.Now().UnixMilli(),
+		}).Error
+}
@@ -12,23 +12,69 @@ type Article struct {
 	Id      int64  `gorm:"primaryKey,autoIncrement" bson:"id"`
 	Title   string `gorm:"type=varchar(4096)" bson:"title"`
 	Content string `gorm:"type=BLOB" bson:"content"`
-	// 我要根据创作者ID来查询
-	AuthorId int64 `gorm:"index" bson:"author_id"`
-	Status   uint8 `bson:"status"`
-	Ctime    int64 `bson:"ctime"`
-	// 更新时间
-	Utime int64 `bson:"utime"`
+	// HTML 发表的时候从 Content 渲染出来的，已经清洗过了
+	HTML string `gorm:"type:mediumtext" bson:"html"`
+	// Summary 从 HTML 里面提取的纯文本摘要
+	Summary string `gorm:"type:varchar(512)" bson:"summary"`
+	// 我要根据创作者ID来查询，按照 (utime, id) 翻页
+	AuthorId int64 `gorm:"index:author_id_utime" bson:"author_id"`
+	Status   uint8 `gorm:"index:status_publish_at" bson:"status"`
+	// 定时发表的时间，定时任务按照 status 和 publish_at 查询到期的文章
+	PublishAt int64 `gorm:"index:status_publish_at" bson:"publish_at"`
+	Ctime     int64 `bson:"ctime"`
+	// 更新时间，二级索引里面自带主键，所以 (utime, id) 的游标也能走索引
+	Utime int64 `gorm:"index:author_id_utime;index" bson:"utime"`
+	// DeletedAt 移入回收站的时间，0 表示没有删除。清理任务按照它查询过期的文章
+	DeletedAt int64 `gorm:"index" bson:"deleted_at"`
 }
 
 type ArticleDAO interface {
 	Insert(ctx context.Context, art Article) (int64, error)
+	// UpdateById 不校验作者，合作者的权限由 service 校验。art.AuthorId 必须是文章的作者
 	UpdateById(ctx context.Context, art Article) error
-	Sync(ctx context.Context, art Article) (int64, error)
+	// Sync 同时把标签复制到线上库，tags 为 nil 说明这一次没有修改标签
+	Sync(ctx context.Context, art Article, tags []string) (int64, error)
+	// SyncStatus 同样不校验作者，uid 是文章的作者
 	SyncStatus(ctx context.Context, id int64, uid int64, status uint8) error
 	GetByAuthor(ctx context.Context, uid int64, offset int, limit int) ([]Article, error)
 	GetById(ctx context.Context, id int64) (Article, error)
 	GetPubById(ctx context.Context, id int64) (PublishedArticle, error)
+	// ListPubIds 线上库 ID 大于 afterId 的文章 ID，包括回收站里面的，从小到大
+	ListPubIds(ctx context.Context, afterId int64, limit int) ([]int64, error)
 	ListPub(ctx context.Context, start time.Time, offset int, limit int) ([]PublishedArticle, error)
+	// ListPubByAuthor 作者已经发表的文章，最近修改的在前面
+	ListPubByAuthor(ctx context.Context, uid int64, offset int, limit int) ([]PublishedArticle, error)
+	// GetByAuthorCursor 返回 (utime, id) 小于游标的文章，utime 为 0 表示从第一页开始
+	GetByAuthorCursor(ctx context.Context, uid int64, utime int64, id int64, limit int) ([]Article, error)
+	ListPubCursor(ctx context.Context, utime int64, id int64, limit int) ([]PublishedArticle, error)
+	// ListByIds ids 里面没有删除的文章，不返回正文，最近修改的在前面。
+	// 标签、系列这些关系存在 MySQL 里面，文章可能在 MongoDB 里面，不能 JOIN，只能先查 ID
+	ListByIds(ctx context.Context, ids []int64, offset int, limit int) ([]Article, error)
+	// ListPubByIds ids 里面已经发表的文章，不返回 HTML，最近修改的在前面
+	ListPubByIds(ctx context.Context, ids []int64, offset int, limit int) ([]PublishedArticle, error)
+	ListRevisions(ctx context.Context, artId int64, offset int, limit int) ([]ArticleRevision, error)
+	GetRevision(ctx context.Context, artId int64, version int64) (ArticleRevision, error)
+	ListScheduled(ctx context.Context, before time.Time, limit int) ([]Article, error)
+	// PublishScheduled 发表 ListScheduled 查出来的文章，art.Utime 是查出来的时候的 utime。
+	// 文章已经不是定时发表状态或者之后被修改过的时候返回 ErrArticleNotScheduled
+	PublishScheduled(ctx context.Context, art Article) error
+	UpdateSchedule(ctx context.Context, id int64, uid int64, status uint8, publishAt int64) error
+	// PublishReviewed 发表审核通过的文章，art.Utime 是审核的时候查出来的 utime。
+	// 文章已经不在审核状态或者之后被修改过的时候返回 ErrArticleNotPendingReview
+	PublishReviewed(ctx context.Context, art Article) error
+	// ResolveReview 修改还在等待审核的文章的状态，只修改制作库。
+	// 文章已经不在审核状态的时候返回 ErrArticleNotPendingReview
+	ResolveReview(ctx context.Context, id int64, status uint8) error
+	// Delete 把文章连同线上库的文章一起移入回收站
+	Delete(ctx context.Context, id int64, uid int64) error
+	// Restore 只能恢复 deletedAfter 之后删除的文章
+	Restore(ctx context.Context, id int64, uid int64, deletedAfter int64) error
+	ListTrash(ctx context.Context, uid int64, offset int, limit int) ([]Article, error)
+	// ListExpiredTrash 返回 before 之前删除的文章
+	ListExpiredTrash(ctx context.Context, before int64, limit int) ([]Article, error)
+	// Purge 彻底删除 before 之前移入回收站的文章，包括线上库和历史版本。
+	// 文章已经被恢复的时候返回 ErrArticleNotInTrash
+	Purge(ctx context.Context, id int64, before int64) error
 }
 
 type ArticleGORMDAO struct {
@@ -38,17 +84,84 @@ type ArticleGORMDAO struct {
 func (a *ArticleGORMDAO) ListPub(ctx context.Context, start time.Time, offset int, limit int) ([]PublishedArticle, error) {
 	var res []PublishedArticle
 	const ArticleStatusPublished = 2
-	err := a.db.WithContext(ctx).Where("utime < ? AND status = ?", start.UnixMilli(), ArticleStatusPublished).
-		Offset(offset).Limit(limit).First(&res).Error
+	err := a.db.WithContext(ctx).Where("utime < ? AND status = ? AND deleted_at = 0", start.UnixMilli(), ArticleStatusPublished).
+		Order("utime DESC").
+		Offset(offset).Limit(limit).Find(&res).Error
+	return res, err
+}
+
+func (a *ArticleGORMDAO) ListByIds(ctx context.Context, ids []int64, offset int, limit int) ([]Article, error) {
+	var res []Article
+	if len(ids) == 0 {
+		return res, nil
+	}
+	err := a.db.WithContext(ctx).Omit("content", "html").
+		Where("id IN ? AND deleted_at = 0", ids).
+		Order("utime DESC").
+		Offset(offset).Limit(limit).Find(&res).Error
+	return res, err
+}
+
+func (a *ArticleGORMDAO) ListPubByIds(ctx context.Context, ids []int64, offset int, limit int) ([]PublishedArticle, error) {
+	var res []PublishedArticle
+	if len(ids) == 0 {
+		return res, nil
+	}
+	const ArticleStatusPublished = 2
+	err := a.db.WithContext(ctx).Omit("html").
+		Where("id IN ? AND status = ? AND deleted_at = 0", ids, ArticleStatusPublished).
+		Order("utime DESC").
+		Offset(offset).Limit(limit).Find(&res).Error
+	return res, err
+}
+
+func (a *ArticleGORMDAO) ListPubByAuthor(ctx context.Context, uid int64, offset int, limit int) ([]PublishedArticle, error) {
+	var res []PublishedArticle
+	const ArticleStatusPublished = 2
+	err := a.db.WithContext(ctx).Where("author_id = ? AND status = ? AND deleted_at = 0", uid, ArticleStatusPublished).
+		Order("utime DESC").
+		Offset(offset).Limit(limit).Find(&res).Error
+	return res, err
+}
+
+func (a *ArticleGORMDAO) GetByAuthorCursor(ctx context.Context, uid int64, utime int64, id int64, limit int) ([]Article, error) {
+	var arts []Article
+	query := a.db.WithContext(ctx).Model(&Article{}).Where("author_id=? AND deleted_at = 0", uid)
+	if utime > 0 {
+		query = query.Where("(utime < ? OR (utime = ? AND id < ?))", utime, utime, id)
+	}
+	err := query.Order("utime DESC, id DESC").
+		Limit(limit).
+		Find(&arts).Error
+	return arts, err
+}
+
+func (a *ArticleGORMDAO) ListPubCursor(ctx context.Context, utime int64, id int64, limit int) ([]PublishedArticle, error) {
+	var res []PublishedArticle
+	const ArticleStatusPublished = 2
+	query := a.db.WithContext(ctx).Model(&PublishedArticle{}).Where("status = ? AND deleted_at = 0", ArticleStatusPublished)
+	if utime > 0 {
+		query = query.Where("(utime < ? OR (utime = ? AND id < ?))", utime, utime, id)
+	}
+	err := query.Order("utime DESC, id DESC").
+		Limit(limit).
+		Find(&res).Error
 	return res, err
 }
 
 func (a *ArticleGORMDAO) GetPubById(ctx context.Context, id int64) (PublishedArticle, error) {
 	var art PublishedArticle
-	err := a.db.WithContext(ctx).Model(&PublishedArticle{}).Where("id=?", id).First(&art).Error
+	err := a.db.WithContext(ctx).Model(&PublishedArticle{}).Where("id=? AND deleted_at = 0", id).First(&art).Error
 	return art, err
 }
 
+func (a *ArticleGORMDAO) ListPubIds(ctx context.Context, afterId int64, limit int) ([]int64, error) {
+	var res []int64
+	err := a.db.WithContext(ctx).Model(&PublishedArticle{}).Where("id > ?", afterId).
+		Order("id ASC").Limit(limit).Pluck("id", &res).Error
+	return res, err
+}
+
 func (a *ArticleGORMDAO) GetById(ctx context.Context, id int64) (Article, error) {
 	var art Article
 	err := a.db.WithContext(ctx).Model(&Article{}).Where("id=?", id).First(&art).Error
@@ -57,7 +170,7 @@ func (a *ArticleGORMDAO) GetById(ctx context.Context, id int64) (Article, error)
 
 func (a *ArticleGORMDAO) GetByAuthor(ctx context.Context, uid int64, offset int, limit int) ([]Article, error) {
 	var arts []Article
-	err := a.db.WithContext(ctx).Model(&Article{}).Where("author_id=?", uid).
+	err := a.db.WithContext(ctx).Model(&Article{}).Where("author_id=? AND deleted_at = 0", uid).
 		Offset(offset).
 		Limit(limit).
 		Order("utime DESC").
@@ -71,7 +184,7 @@ func (a *ArticleGORMDAO) GetByAuthor(ctx context.Context, uid int64, offset int,
 func (a *ArticleGORMDAO) SyncStatus(ctx context.Context, id int64, uid int64, status uint8) error {
 	now := time.Now().UnixMilli()
 	return a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		res := tx.Model(&Article{}).Where("id=? AND author_id=?", id, uid).Updates(map[string]any{
+		res := tx.Model(&Article{}).Where("id=? AND deleted_at = 0", id).Updates(map[string]any{
 			"status": status,
 			"utime":  now,
 		})
@@ -79,22 +192,68 @@ func (a *ArticleGORMDAO) SyncStatus(ctx context.Context, id int64, uid int64, st
 			return res.Error
 		}
 		if res.RowsAffected != 1 {
-			return errors.New("ID不对或者创作者不对")
+			return errors.New("ID不对或者文章已经删除")
 		}
-		return tx.Model(&PublishedArticle{}).Where("id=?", id).Updates(map[string]any{
+		err := tx.Model(&PublishedArticle{}).Where("id=?", id).Updates(map[string]any{
 			"status": status,
 			"utime":  now,
 		}).Error
+		if err != nil {
+			return err
+		}
+		return addArticleEvent(tx, statusEventType(status), Article{Id: id, AuthorId: uid, Status: status}, now)
 	})
 }
 
-func (a *ArticleGORMDAO) Sync(ctx context.Context, art Article) (int64, error) {
+func (a *ArticleGORMDAO) Sync(ctx context.Context, art Article, tags []string) (int64, error) {
 	tx := a.db.WithContext(ctx).Begin()
 	if tx.Error != nil {
 		return 0, tx.Error
 	}
 	defer tx.Rollback()
+	id, err := a.sync(ctx, tx, art, tags)
+	if err != nil {
+		return 0, err
+	}
+	return id, tx.Commit().Error
+}
 
+func (a *ArticleGORMDAO) PublishScheduled(ctx context.Context, art Article) error {
+	const ArticleStatusScheduled = 4
+	return a.publishIf(ctx, art, ArticleStatusScheduled, ErrArticleNotScheduled)
+}
+
+func (a *ArticleGORMDAO) PublishReviewed(ctx context.Context, art Article) error {
+	const ArticleStatusPendingReview = 5
+	return a.publishIf(ctx, art, ArticleStatusPendingReview, ErrArticleNotPendingReview)
+}
+
+// publishIf 文章还是 status 状态并且 utime 没变的时候才发表，否则返回 errChanged
+func (a *ArticleGORMDAO) publishIf(ctx context.Context, art Article, status uint8, errChanged error) error {
+	tx := a.db.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+	defer tx.Rollback()
+	// 取消、改期或者修改过的文章 utime 都会变化，这一行同时锁住文章直到发表完
+	res := tx.Model(&Article{}).
+		Where("id=? AND status=? AND utime=? AND deleted_at = 0", art.Id, status, art.Utime).
+		Update("status", art.Status)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return errChanged
+	}
+	_, err := a.sync(ctx, tx, art, nil)
+	if err != nil {
+		return err
+	}
+	return tx.Commit().Error
+}
+
+// sync 在 tx 里面保存制作库并且发表到线上库，由调用者提交
+func (a *ArticleGORMDAO) sync(ctx context.Context, tx *gorm.DB, art Article, tags []string) (int64, error) {
 	var (
 		err error
 		id  = art.Id
@@ -114,11 +273,13 @@ func (a *ArticleGORMDAO) Sync(ctx context.Context, art Article) (int64, error) {
 	publishArt := PublishedArticle(art)
 	publishArt.Ctime = now
 	publishArt.Utime = now
-	err = a.db.Clauses(clause.OnConflict{
+	err = tx.Clauses(clause.OnConflict{
 		Columns: []clause.Column{{Name: "id"}},
 		DoUpdates: clause.Assignments(map[string]interface{}{
 			"title":   publishArt.Title,
 			"content": publishArt.Content,
+			"html":    publishArt.HTML,
+			"summary": publishArt.Summary,
 			"utime":   publishArt.Utime,
 			"status":  publishArt.Status,
 		}),
@@ -126,8 +287,11 @@ func (a *ArticleGORMDAO) Sync(ctx context.Context, art Article) (int64, error) {
 	if err != nil {
 		return 0, err
 	}
-	tx.Commit()
-	return id, nil
+	err = syncArticleTags(tx, id, tags, now)
+	if err != nil {
+		return 0, err
+	}
+	return id, addArticleEvent(tx, ArticleEventPublished, art, now)
 }
 
 func (a *ArticleGORMDAO) SyncV1(ctx context.Context, art Article) (int64, error) {
@@ -166,19 +330,28 @@ func (a *ArticleGORMDAO) SyncV1(ctx context.Context, art Article) (int64, error)
 
 func (a *ArticleGORMDAO) UpdateById(ctx context.Context, art Article) error {
 	now := time.Now().UnixMilli()
-	res := a.db.WithContext(ctx).Model(&Article{}).Where("id=? AND author_id=?", art.Id, art.AuthorId).Updates(map[string]any{
-		"title":   art.Title,
-		"content": art.Content,
-		"status":  art.Status,
-		"utime":   now,
+	return a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&Article{}).Where("id=? AND deleted_at = 0", art.Id).Updates(map[string]any{
+			"title":      art.Title,
+			"content":    art.Content,
+			"html":       art.HTML,
+			"summary":    art.Summary,
+			"status":     art.Status,
+			"publish_at": art.PublishAt,
+			"utime":      now,
+		})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return errors.New("ID不对或者文章已经删除")
+		}
+		err := a.appendRevision(tx, art, now)
+		if err != nil {
+			return err
+		}
+		return addArticleEvent(tx, ArticleEventUpdated, art, now)
 	})
-	if res.Error != nil {
-		return res.Error
-	}
-	if res.RowsAffected == 0 {
-		return errors.New("ID不对或者创作者不对")
-	}
-	return nil
 }
 
 func NewArticleGORMDAO(db *gorm.DB) ArticleDAO {
@@ -191,8 +364,70 @@ func (a *ArticleGORMDAO) Insert(ctx context.Context, art Article) (int64, error)
 	now := time.Now().UnixMilli()
 	art.Ctime = now
 	art.Utime = now
-	err := a.db.WithContext(ctx).Create(&art).Error
+	err := a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Create(&art).Error
+		if err != nil {
+			return err
+		}
+		err = a.appendRevision(tx, art, now)
+		if err != nil {
+			return err
+		}
+		return addArticleEvent(tx, ArticleEventCreated, art, now)
+	})
 	return art.Id, err
 }
 
+func (a *ArticleGORMDAO) ListScheduled(ctx context.Context, before time.Time, limit int) ([]Article, error) {
+	var res []Article
+	const ArticleStatusScheduled = 4
+	err := a.db.WithContext(ctx).
+		Where("status = ? AND publish_at <= ? AND deleted_at = 0", ArticleStatusScheduled, before.UnixMilli()).
+		Order("publish_at ASC").
+		Limit(limit).
+		Find(&res).Error
+	return res, err
+}
+
+// UpdateSchedule 只会修改还处于定时发表状态的文章，避免和定时任务并发发表冲突
+func (a *ArticleGORMDAO) UpdateSchedule(ctx context.Context, id int64, uid int64, status uint8, publishAt int64) error {
+	const ArticleStatusScheduled = 4
+	res := a.db.WithContext(ctx).Model(&Article{}).
+		Where("id=? AND author_id=? AND status=? AND deleted_at = 0", id, uid, ArticleStatusScheduled).
+		Updates(map[string]any{
+			"status":     status,
+			"publish_at": publishAt,
+			"utime":      time.Now().UnixMilli(),
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return errors.New("ID不对或者文章不是定时发表状态")
+	}
+	return nil
+}
+
+var (
+	ErrArticleNotPendingReview = errors.New("文章不在审核状态")
+	ErrArticleNotScheduled     = errors.New("文章不在定时发表状态或者已经被修改")
+)
+
+func (a *ArticleGORMDAO) ResolveReview(ctx context.Context, id int64, status uint8) error {
+	const ArticleStatusPendingReview = 5
+	res := a.db.WithContext(ctx).Model(&Article{}).
+		Where("id=? AND status=? AND deleted_at = 0", id, ArticleStatusPendingReview).
+		Updates(map[string]any{
+			"status": status,
+			"utime":  time.Now().UnixMilli(),
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrArticleNotPendingReview
+	}
+	return nil
+}
+
 type PublishedArticle Article
@@ -0,0 +1,108 @@
+package dao
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+// ArticleCollaborator 文章的合作者，作者本人不在这张表里面
+type ArticleCollaborator struct {
+	Id    int64 `gorm:"primaryKey,autoIncrement"`
+	ArtId int64 `gorm:"uniqueIndex:art_id_uid"`
+	// 按照合作者查询他参与的文章
+	Uid     int64 `gorm:"uniqueIndex:art_id_uid;index"`
+	Role    uint8
+	Inviter int64
+	Ctime   int64
+	Utime   int64
+}
+
+// ArticleAudit 文章的操作记录，只增不改
+type ArticleAudit struct {
+	Id     int64 `gorm:"primaryKey,autoIncrement"`
+	ArtId  int64 `gorm:"index:art_id_ctime"`
+	Uid    int64
+	Action string `gorm:"type:varchar(32)"`
+	Detail string `gorm:"type:varchar(1024)"`
+	Ctime  int64  `gorm:"index:art_id_ctime"`
+}
+
+type ArticleCollaboratorDAO interface {
+	// Upsert 已经是合作者的话修改角色
+	Upsert(ctx context.Context, c ArticleCollaborator) error
+	Delete(ctx context.Context, artId int64, uid int64) error
+	// Get 不是合作者返回 ErrRecordNotFound
+	Get(ctx context.Context, artId int64, uid int64) (ArticleCollaborator, error)
+	List(ctx context.Context, artId int64) ([]ArticleCollaborator, error)
+	// ListArticleIds uid 作为合作者参与的文章 ID。
+	// 文章可能存在 MongoDB 里面，这里只查 ID，文章交给 ArticleDAO 查
+	ListArticleIds(ctx context.Context, uid int64) ([]int64, error)
+	InsertAudit(ctx context.Context, a ArticleAudit) error
+	ListAudits(ctx context.Context, artId int64, offset int, limit int) ([]ArticleAudit, error)
+}
+
+type GORMArticleCollaboratorDAO struct {
+	db *gorm.DB
+}
+
+func NewGORMArticleCollaboratorDAO(db *gorm.DB) ArticleCollaboratorDAO {
+	return &GORMArticleCollaboratorDAO{
+		db: db,
+	}
+}
+
+func (g *GORMArticleCollaboratorDAO) Upsert(ctx context.Context, c ArticleCollaborator) error {
+	now := time.Now().UnixMilli()
+	c.Ctime = now
+	c.Utime = now
+	return g.db.WithContext(ctx).Clauses(clause.OnConflict{
+		DoUpdates: clause.Assignments(map[string]any{
+			"role":    c.Role,
+			"inviter": c.Inviter,
+			"utime":   now,
+		}),
+	}).Create(&c).Error
+}
+
+func (g *GORMArticleCollaboratorDAO) Delete(ctx context.Context, artId int64, uid int64) error {
+	return g.db.WithContext(ctx).Where("art_id = ? AND uid = ?", artId, uid).
+		Delete(&ArticleCollaborator{}).Error
+}
+
+func (g *GORMArticleCollaboratorDAO) Get(ctx context.Context, artId int64, uid int64) (ArticleCollaborator, error) {
+	var res ArticleCollaborator
+	err := g.db.WithContext(ctx).Where("art_id = ? AND uid = ?", artId, uid).First(&res).Error
+	return res, err
+}
+
+func (g *GORMArticleCollaboratorDAO) List(ctx context.Context, artId int64) ([]ArticleCollaborator, error) {
+	var res []ArticleCollaborator
+	err := g.db.WithContext(ctx).Where("art_id = ?", artId).
+		Order("id ASC").Find(&res).Error
+	return res, err
+}
+
+func (g *GORMArticleCollaboratorDAO) ListArticleIds(ctx context.Context, uid int64) ([]int64, error) {
+	var res []int64
+	err := g.db.WithContext(ctx).Model(&ArticleCollaborator{}).
+		Where("uid = ?", uid).
+		Pluck("art_id", &res).Error
+	return res, err
+}
+
+func (g *GORMArticleCollaboratorDAO) InsertAudit(ctx context.Context, a ArticleAudit) error {
+	a.Ctime = time.Now().UnixMilli()
+	return g.db.WithContext(ctx).Create(&a).Error
+}
+
+func (g *GORMArticleCollaboratorDAO) ListAudits(ctx context.Context, artId int64, offset int, limit int) ([]ArticleAudit, error) {
+	var res []ArticleAudit
+	err := g.db.WithContext(ctx).Where("art_id = ?", artId).
+		Order("ctime DESC, id DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&res).Error
+	return res, err
+}
@@ -0,0 +1,158 @@
+package dao
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"gorm.io/gorm"
+	"time"
+)
+
+// 文章生命周期事件的类型，ArticleDAO 在写文章的同一个事务里面记录下来
+const (
+	ArticleEventCreated   = "created"
+	ArticleEventUpdated   = "updated"
+	ArticleEventPublished = "published"
+	ArticleEventWithdrawn = "withdrawn"
+	ArticleEventDeleted   = "deleted"
+	ArticleEventRestored  = "restored"
+	ArticleEventPurged    = "purged"
+)
+
+const (
+	ArticleEventStatusPending uint8 = iota
+	ArticleEventStatusSent
+)
+
+// ArticleEvent 事务发件箱。业务数据和事件一起提交，
+// 再由定时任务投递到 Kafka，避免写库成功了但是发消息失败导致丢事件
+type ArticleEvent struct {
+	Id       int64  `gorm:"primaryKey,autoIncrement" bson:"id"`
+	Type     string `gorm:"type:varchar(32)" bson:"type"`
+	ArtId    int64  `bson:"art_id"`
+	AuthorId int64  `bson:"author_id"`
+	// ArtStatus 事件发生之后文章的状态
+	ArtStatus uint8 `bson:"art_status"`
+	// 按照 status 找待投递的，按照 (status, utime) 清理已经投递的
+	Status  uint8 `gorm:"index:status_utime" bson:"status"`
+	Retries int   `bson:"retries"`
+	Ctime   int64 `bson:"ctime"`
+	Utime   int64 `gorm:"index:status_utime" bson:"utime"`
+}
+
+type ArticleEventDAO interface {
+	// ListPending 按照 id 顺序返回待投递的事件，保证同一篇文章的事件按顺序投递
+	ListPending(ctx context.Context, limit int) ([]ArticleEvent, error)
+	MarkSent(ctx context.Context, id int64) error
+	MarkFailed(ctx context.Context, id int64) error
+	DeleteSent(ctx context.Context, before int64) (int64, error)
+}
+
+type GORMArticleEventDAO struct {
+	db *gorm.DB
+}
+
+func NewGORMArticleEventDAO(db *gorm.DB) ArticleEventDAO {
+	return &GORMArticleEventDAO{
+		db: db,
+	}
+}
+
+func (g *GORMArticleEventDAO) ListPending(ctx context.Context, limit int) ([]ArticleEvent, error) {
+	var res []ArticleEvent
+	err := g.db.WithContext(ctx).Where("status = ?", ArticleEventStatusPending).
+		Order("id ASC").Limit(limit).Find(&res).Error
+	return res, err
+}
+
+func (g *GORMArticleEventDAO) MarkSent(ctx context.Context, id int64) error {
+	return g.db.WithContext(ctx).Model(&ArticleEvent{}).Where("id = ?", id).Updates(map[string]any{
+		"status": ArticleEventStatusSent,
+		"utime":  time.Now().UnixMilli(),
+	}).Error
+}
+
+func (g *GORMArticleEventDAO) MarkFailed(ctx context.Context, id int64) error {
+	return g.db.WithContext(ctx).Model(&ArticleEvent{}).Where("id = ?", id).Updates(map[string]any{
+		"retries": gorm.Expr("retries + 1"),
+		"utime":   time.Now().UnixMilli(),
+	}).Error
+}
+
+func (g *GORMArticleEventDAO) DeleteSent(ctx context.Context, before int64) (int64, error) {
+	res := g.db.WithContext(ctx).Where("status = ? AND utime < ?", ArticleEventStatusSent, before).
+		Delete(&ArticleEvent{})
+	return res.RowsAffected, res.Error
+}
+
+// addArticleEvent 必须传入事务，和文章的修改一起提交
+func addArticleEvent(tx *gorm.DB, typ string, art Article, now int64) error {
+	return tx.Create(&ArticleEvent{
+		Type:      typ,
+		ArtId:     art.Id,
+		AuthorId:  art.AuthorId,
+		ArtStatus: art.Status,
+		Status:    ArticleEventStatusPending,
+		Ctime:     now,
+		Utime:     now,
+	}).Error
+}
+
+// statusEventType 目前只有撤回会单独修改状态
+func statusEventType(status uint8) string {
+	const ArticleStatusPrivate = 3
+	if status == ArticleStatusPrivate {
+		return ArticleEventWithdrawn
+	}
+	return ArticleEventUpdated
+}
+
+type MongoDBArticleEventDAO struct {
+	col *mongo.Collection
+}
+
+func NewMongoDBArticleEventDAO(col *mongo.Collection) ArticleEventDAO {
+	return &MongoDBArticleEventDAO{
+		col: col,
+	}
+}
+
+func (m *MongoDBArticleEventDAO) ListPending(ctx context.Context, limit int) ([]ArticleEvent, error) {
+	filter := bson.D{bson.E{Key: "status", Value: ArticleEventStatusPending}}
+	opts := options.Find().
+		SetSort(bson.D{bson.E{Key: "id", Value: 1}}).
+		SetLimit(int64(limit))
+	cursor, err := m.col.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var res []ArticleEvent
+	err = cursor.All(ctx, &res)
+	return res, err
+}
+
+func (m *MongoDBArticleEventDAO) MarkSent(ctx context.Context, id int64) error {
+	_, err := m.col.UpdateOne(ctx, bson.D{bson.E{Key: "id", Value: id}}, bson.D{
+		bson.E{Key: "$set", Value: bson.D{
+			bson.E{Key: "status", Value: ArticleEventStatusSent},
+			bson.E{Key: "utime", Value: time.Now().UnixMilli()},
+		}}})
+	return err
+}
+
+func (m *MongoDBArticleEventDAO) MarkFailed(ctx context.Context, id int64) error {
+	_, err := m.col.UpdateOne(ctx, bson.D{bson.E{Key: "id", Value: id}}, bson.D{
+		bson.E{Key: "$inc", Value: bson.D{bson.E{Key: "retries", Value: 1}}},
+		bson.E{Key: "$set", Value: bson.D{bson.E{Key: "utime", Value: time.Now().UnixMilli()}}}})
+	return err
+}
+
+func (m *MongoDBArticleEventDAO) DeleteSent(ctx context.Context, before int64) (int64, error) {
+	res, err := m.col.DeleteMany(ctx, bson.D{bson.E{Key: "status", Value: ArticleEventStatusSent},
+		bson.E{Key: "utime", Value: bson.D{bson.E{Key: "$lt", Value: before}}}})
+	if err != nil {
+		return 0, err
+	}
+	return res.DeletedCount, nil
+}
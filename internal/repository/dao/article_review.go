@@ -0,0 +1,111 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"time"
+)
+
+var ErrReviewNotPending = errors.New("审核已经处理过了")
+
+// ArticleReview 文章的人工审核记录
+type ArticleReview struct {
+	Id    int64  `gorm:"primaryKey,autoIncrement"`
+	ArtId int64  `gorm:"index"`
+	Title string `gorm:"type:varchar(1024)"`
+	// AuthorId 通知审核结果用
+	AuthorId  int64
+	Submitter int64
+	// Hits 命中的敏感词，逗号分隔
+	Hits string `gorm:"type:varchar(1024)"`
+	// Status 待审核的列表按照它查询
+	Status   uint8 `gorm:"index"`
+	Reviewer int64
+	Reason   string `gorm:"type:varchar(1024)"`
+	Ctime    int64
+	Utime    int64
+}
+
+type ArticleReviewDAO interface {
+	// Insert 同一篇文章之前还在等待审核的记录会作废
+	Insert(ctx context.Context, r ArticleReview) (int64, error)
+	GetById(ctx context.Context, id int64) (ArticleReview, error)
+	ListByStatus(ctx context.Context, status uint8, offset int, limit int) ([]ArticleReview, error)
+	ListByArticle(ctx context.Context, artId int64, offset int, limit int) ([]ArticleReview, error)
+	// Resolve 只能处理还在等待审核的记录，否则返回 ErrReviewNotPending
+	Resolve(ctx context.Context, id int64, status uint8, reviewer int64, reason string) error
+}
+
+type GORMArticleReviewDAO struct {
+	db *gorm.DB
+}
+
+func NewGORMArticleReviewDAO(db *gorm.DB) ArticleReviewDAO {
+	return &GORMArticleReviewDAO{
+		db: db,
+	}
+}
+
+func (g *GORMArticleReviewDAO) Insert(ctx context.Context, r ArticleReview) (int64, error) {
+	const (
+		ReviewStatusPending   = 1
+		ReviewStatusCancelled = 4
+	)
+	now := time.Now().UnixMilli()
+	r.Ctime = now
+	r.Utime = now
+	err := g.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&ArticleReview{}).
+			Where("art_id = ? AND status = ?", r.ArtId, ReviewStatusPending).
+			Updates(map[string]any{
+				"status": ReviewStatusCancelled,
+				"utime":  now,
+			}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Create(&r).Error
+	})
+	return r.Id, err
+}
+
+func (g *GORMArticleReviewDAO) GetById(ctx context.Context, id int64) (ArticleReview, error) {
+	var res ArticleReview
+	err := g.db.WithContext(ctx).Where("id = ?", id).First(&res).Error
+	return res, err
+}
+
+func (g *GORMArticleReviewDAO) ListByStatus(ctx context.Context, status uint8, offset int, limit int) ([]ArticleReview, error) {
+	var res []ArticleReview
+	// 先提交的先审核
+	err := g.db.WithContext(ctx).Where("status = ?", status).
+		Order("id ASC").Offset(offset).Limit(limit).Find(&res).Error
+	return res, err
+}
+
+func (g *GORMArticleReviewDAO) ListByArticle(ctx context.Context, artId int64, offset int, limit int) ([]ArticleReview, error) {
+	var res []ArticleReview
+	err := g.db.WithContext(ctx).Where("art_id = ?", artId).
+		Order("id DESC").Offset(offset).Limit(limit).Find(&res).Error
+	return res, err
+}
+
+func (g *GORMArticleReviewDAO) Resolve(ctx context.Context, id int64, status uint8, reviewer int64, reason string) error {
+	const ReviewStatusPending = 1
+	res := g.db.WithContext(ctx).Model(&ArticleReview{}).
+		Where("id = ? AND status = ?", id, ReviewStatusPending).
+		Updates(map[string]any{
+			"status":   status,
+			"reviewer": reviewer,
+			"reason":   reason,
+			"utime":    time.Now().UnixMilli(),
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrReviewNotPending
+	}
+	return nil
+}
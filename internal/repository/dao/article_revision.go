@@ -0,0 +1,55 @@
+package dao
+
+import (
+	"context"
+	"gorm.io/gorm"
+)
+
+// ArticleRevision 每次保存或者发表都会追加一条，不会修改
+type ArticleRevision struct {
+	Id int64 `gorm:"primaryKey,autoIncrement" bson:"id"`
+	// 同一篇文章的版本号从 1 开始递增
+	ArtId    int64  `gorm:"uniqueIndex:art_id_version" bson:"art_id"`
+	Version  int64  `gorm:"uniqueIndex:art_id_version" bson:"version"`
+	Title    string `gorm:"type=varchar(4096)" bson:"title"`
+	Content  string `gorm:"type=BLOB" bson:"content"`
+	Status   uint8  `bson:"status"`
+	AuthorId int64  `bson:"author_id"`
+	Ctime    int64  `bson:"ctime"`
+}
+
+func (a *ArticleGORMDAO) ListRevisions(ctx context.Context, artId int64, offset int, limit int) ([]ArticleRevision, error) {
+	var res []ArticleRevision
+	err := a.db.WithContext(ctx).Where("art_id=?", artId).
+		Order("version DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&res).Error
+	return res, err
+}
+
+func (a *ArticleGORMDAO) GetRevision(ctx context.Context, artId int64, version int64) (ArticleRevision, error) {
+	var res ArticleRevision
+	err := a.db.WithContext(ctx).Where("art_id=? AND version=?", artId, version).First(&res).Error
+	return res, err
+}
+
+// appendRevision 必须在更新了文章的事务里面调用，
+// 文章那一行已经被锁住，所以这里取最大版本号不会并发冲突
+func (a *ArticleGORMDAO) appendRevision(tx *gorm.DB, art Article, now int64) error {
+	var version int64
+	err := tx.Model(&ArticleRevision{}).Where("art_id=?", art.Id).
+		Select("COALESCE(MAX(version), 0)").Scan(&version).Error
+	if err != nil {
+		return err
+	}
+	return tx.Create(&ArticleRevision{
+		ArtId:    art.Id,
+		Version:  version + 1,
+		Title:    art.Title,
+		Content:  art.Content,
+		Status:   art.Status,
+		AuthorId: art.AuthorId,
+		Ctime:    now,
+	}).Error
+}
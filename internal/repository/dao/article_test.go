@@ -0,0 +1,82 @@
+package dao
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"regexp"
+	"testing"
+)
+
+func TestArticleGORMDAO_PublishScheduled(t *testing.T) {
+	testCases := []struct {
+		name    string
+		mock    func(mock sqlmock.Sqlmock)
+		wantErr error
+	}{
+		{
+			name: "发表成功",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE `articles` SET `status`=? "+
+					"WHERE id=? AND status=? AND utime=? AND deleted_at = 0")).
+					WithArgs(2, int64(3), 4, int64(100)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("SAVEPOINT").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE `articles`")).WithArgs(anyArgs(8)...).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT COALESCE(MAX(version), 0) FROM `article_revisions`")).
+					WithArgs(int64(3)).
+					WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(1))
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `article_revisions`")).WithArgs(anyArgs(7)...).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `article_events`")).WithArgs(anyArgs(8)...).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `published_articles`")).WithArgs(anyArgs(17)...).
+					WillReturnResult(sqlmock.NewResult(3, 1))
+				expectPublishTags(mock, nil)
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `article_events`")).WithArgs(anyArgs(8)...).
+					WillReturnResult(sqlmock.NewResult(2, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			// 查出来之后作者取消或者修改了，什么都不写
+			name: "已经不是查出来的那个版本",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE `articles` SET `status`=? "+
+					"WHERE id=? AND status=? AND utime=? AND deleted_at = 0")).
+					WithArgs(2, int64(3), 4, int64(100)).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			wantErr: ErrArticleNotScheduled,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock := newSQLMockDB(t)
+			tc.mock(mock)
+			err := NewArticleGORMDAO(db).PublishScheduled(context.Background(),
+				Article{Id: 3, Title: "标题", AuthorId: 1, Status: 2, Utime: 100})
+			assert.Equal(t, tc.wantErr, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestArticleGORMDAO_PublishReviewed(t *testing.T) {
+	db, mock := newSQLMockDB(t)
+	// 审核的时候查出来之后作者又修改了，不能发表
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `articles` SET `status`=? "+
+		"WHERE id=? AND status=? AND utime=? AND deleted_at = 0")).
+		WithArgs(2, int64(3), 5, int64(100)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+	err := NewArticleGORMDAO(db).PublishReviewed(context.Background(),
+		Article{Id: 3, Title: "标题", AuthorId: 1, Status: 2, Utime: 100})
+	assert.Equal(t, ErrArticleNotPendingReview, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
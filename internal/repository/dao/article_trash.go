@@ -0,0 +1,101 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"time"
+)
+
+var ErrArticleNotInTrash = errors.New("文章不在回收站或者已经过期")
+
+func (a *ArticleGORMDAO) Delete(ctx context.Context, id int64, uid int64) error {
+	now := time.Now().UnixMilli()
+	return a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var art Article
+		err := tx.Where("id=? AND author_id=? AND deleted_at = 0", id, uid).First(&art).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Model(&Article{}).Where("id=?", id).Update("deleted_at", now).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Model(&PublishedArticle{}).Where("id=?", id).Update("deleted_at", now).Error
+		if err != nil {
+			return err
+		}
+		return addArticleEvent(tx, ArticleEventDeleted, art, now)
+	})
+}
+
+func (a *ArticleGORMDAO) Restore(ctx context.Context, id int64, uid int64, deletedAfter int64) error {
+	now := time.Now().UnixMilli()
+	return a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var art Article
+		err := tx.Where("id=? AND author_id=? AND deleted_at > ?", id, uid, deletedAfter).First(&art).Error
+		if err == gorm.ErrRecordNotFound {
+			return ErrArticleNotInTrash
+		}
+		if err != nil {
+			return err
+		}
+		err = tx.Model(&Article{}).Where("id=?", id).Update("deleted_at", 0).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Model(&PublishedArticle{}).Where("id=?", id).Update("deleted_at", 0).Error
+		if err != nil {
+			return err
+		}
+		return addArticleEvent(tx, ArticleEventRestored, art, now)
+	})
+}
+
+func (a *ArticleGORMDAO) ListTrash(ctx context.Context, uid int64, offset int, limit int) ([]Article, error) {
+	var res []Article
+	err := a.db.WithContext(ctx).Where("author_id=? AND deleted_at > 0", uid).
+		Order("deleted_at DESC").
+		Offset(offset).Limit(limit).
+		Find(&res).Error
+	return res, err
+}
+
+func (a *ArticleGORMDAO) ListExpiredTrash(ctx context.Context, before int64, limit int) ([]Article, error) {
+	var res []Article
+	err := a.db.WithContext(ctx).Where("deleted_at > 0 AND deleted_at < ?", before).
+		Order("deleted_at ASC").
+		Limit(limit).
+		Find(&res).Error
+	return res, err
+}
+
+func (a *ArticleGORMDAO) Purge(ctx context.Context, id int64, before int64) error {
+	return a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 列出来之后作者可能恢复了，所以要再判断一次
+		var art Article
+		err := tx.Where("id=? AND deleted_at > 0 AND deleted_at < ?", id, before).First(&art).Error
+		if err == gorm.ErrRecordNotFound {
+			return ErrArticleNotInTrash
+		}
+		if err != nil {
+			return err
+		}
+		res := tx.Where("id=? AND deleted_at > 0 AND deleted_at < ?", id, before).Delete(&Article{})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrArticleNotInTrash
+		}
+		err = tx.Where("id=?", id).Delete(&PublishedArticle{}).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Where("art_id=?", id).Delete(&ArticleRevision{}).Error
+		if err != nil {
+			return err
+		}
+		return addArticleEvent(tx, ArticleEventPurged, art, time.Now().UnixMilli())
+	})
+}
@@ -0,0 +1,130 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+var ErrBlobInUse = errors.New("blob 还在被文章引用")
+
+// Blob 上传的文件，内容相同的文件只存一份，Hash 是内容的 sha256
+type Blob struct {
+	Id       int64  `gorm:"primaryKey,autoIncrement"`
+	Hash     string `gorm:"type:char(64);uniqueIndex"`
+	Size     int64
+	MimeType string `gorm:"type:varchar(128)"`
+	// Uploader 第一次上传的人
+	Uploader int64
+	Ctime    int64
+	// Utime 每次有人重复上传都会更新，清理任务按照它判断是不是刚上传还没来得及被引用
+	Utime int64 `gorm:"index"`
+}
+
+// ArticleBlob 文章引用了哪些 blob
+type ArticleBlob struct {
+	Id    int64 `gorm:"primaryKey,autoIncrement"`
+	ArtId int64 `gorm:"uniqueIndex:art_id_blob_id"`
+	// 清理的时候按照 blob 查询有没有被引用
+	BlobId int64 `gorm:"uniqueIndex:art_id_blob_id;index"`
+	Ctime  int64
+}
+
+type BlobDAO interface {
+	// Upsert 内容已经存在的时候只更新 utime，返回 blob 的 ID
+	Upsert(ctx context.Context, b Blob) (Blob, error)
+	GetByHash(ctx context.Context, hash string) (Blob, error)
+	// SetArticleBlobs 用 hashes 覆盖文章引用的 blob，不存在的 hash 会被忽略
+	SetArticleBlobs(ctx context.Context, artId int64, hashes []string) error
+	// ListUnreferenced 没有被任何文章引用，并且 before 之前就上传了的 blob
+	ListUnreferenced(ctx context.Context, before int64, limit int) ([]Blob, error)
+	// Delete 删除没有被引用的 blob，期间被引用或者重新上传了返回 ErrBlobInUse。
+	// fn 在删除记录之后、提交之前执行，用来删除文件，这期间重复上传会被行锁挡住。
+	// fn 返回错误的时候回滚
+	Delete(ctx context.Context, id int64, before int64, fn func() error) error
+}
+
+type GORMBlobDAO struct {
+	db *gorm.DB
+}
+
+func NewGORMBlobDAO(db *gorm.DB) BlobDAO {
+	return &GORMBlobDAO{
+		db: db,
+	}
+}
+
+func (g *GORMBlobDAO) Upsert(ctx context.Context, b Blob) (Blob, error) {
+	now := time.Now().UnixMilli()
+	b.Ctime = now
+	b.Utime = now
+	err := g.db.WithContext(ctx).Clauses(clause.OnConflict{
+		DoUpdates: clause.Assignments(map[string]any{
+			"utime": now,
+		}),
+	}).Create(&b).Error
+	if err != nil {
+		return Blob{}, err
+	}
+	// 冲突的时候拿不到原来的 ID，重新查一次
+	return g.GetByHash(ctx, b.Hash)
+}
+
+func (g *GORMBlobDAO) GetByHash(ctx context.Context, hash string) (Blob, error) {
+	var res Blob
+	err := g.db.WithContext(ctx).Where("hash = ?", hash).First(&res).Error
+	return res, err
+}
+
+func (g *GORMBlobDAO) SetArticleBlobs(ctx context.Context, artId int64, hashes []string) error {
+	now := time.Now().UnixMilli()
+	return g.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var ids []int64
+		if len(hashes) > 0 {
+			err := tx.Model(&Blob{}).Where("hash IN ?", hashes).Pluck("id", &ids).Error
+			if err != nil {
+				return err
+			}
+		}
+		if len(ids) == 0 {
+			return tx.Where("art_id = ?", artId).Delete(&ArticleBlob{}).Error
+		}
+		err := tx.Where("art_id = ? AND blob_id NOT IN ?", artId, ids).Delete(&ArticleBlob{}).Error
+		if err != nil {
+			return err
+		}
+		links := make([]ArticleBlob, 0, len(ids))
+		for _, id := range ids {
+			links = append(links, ArticleBlob{ArtId: artId, BlobId: id, Ctime: now})
+		}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&links).Error
+	})
+}
+
+func (g *GORMBlobDAO) ListUnreferenced(ctx context.Context, before int64, limit int) ([]Blob, error) {
+	var res []Blob
+	err := g.db.WithContext(ctx).
+		Where("utime < ? AND NOT EXISTS (?)", before,
+			g.db.Model(&ArticleBlob{}).Select("1").Where("article_blobs.blob_id = blobs.id")).
+		Order("utime ASC").
+		Limit(limit).
+		Find(&res).Error
+	return res, err
+}
+
+func (g *GORMBlobDAO) Delete(ctx context.Context, id int64, before int64, fn func() error) error {
+	return g.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("id = ? AND utime < ? AND NOT EXISTS (?)", id, before,
+			tx.Model(&ArticleBlob{}).Select("1").Where("article_blobs.blob_id = blobs.id")).
+			Delete(&Blob{})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrBlobInUse
+		}
+		return fn()
+	})
+}
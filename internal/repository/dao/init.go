@@ -5,9 +5,35 @@ import (
 )
 
 func InitTables(db *gorm.DB) error {
-	return db.AutoMigrate(
+	// 线上库的标签是后来拆出来的，第一次建表的时候用制作库的标签初始化
+	backfillPubTags := !db.Migrator().HasTable(&PublishedArticleTag{})
+	err := db.AutoMigrate(
 		&User{},
 		&Article{},
 		&PublishedArticle{},
+		&ArticleRevision{},
+		&Job{},
+		&Tag{},
+		&ArticleTag{},
+		&PublishedArticleTag{},
+		&ArticleEvent{},
+		&ShareLink{},
+		&Series{},
+		&SeriesArticle{},
+		&ArticleCollaborator{},
+		&ArticleAudit{},
+		&Blob{},
+		&ArticleBlob{},
+		&ArticleReview{},
+		&Notification{},
+		&ArchiveTask{},
+		&UserMFA{},
+		&UserRecoveryCode{},
 	)
+	if err != nil || !backfillPubTags {
+		return err
+	}
+	return db.Exec("INSERT INTO published_article_tags (art_id, tag_id, ctime) " +
+		"SELECT article_tags.art_id, article_tags.tag_id, article_tags.ctime FROM article_tags " +
+		"JOIN published_articles ON published_articles.id = article_tags.art_id ORDER BY article_tags.id").Error
 }
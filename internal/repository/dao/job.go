@@ -3,6 +3,7 @@ package dao
 import (
 	"context"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"time"
 )
 
@@ -11,12 +12,28 @@ type JobDAO interface {
 	Release(ctx context.Context, jid int64) error
 	UpdateNextTime(ctx context.Context, id int64, t time.Time) error
 	UpdateUtime(ctx context.Context, id int64) error
+	Insert(ctx context.Context, j Job) error
 }
 
 type GORMJobDAO struct {
 	db *gorm.DB
 }
 
+func NewGORMJobDAO(db *gorm.DB) JobDAO {
+	return &GORMJobDAO{db: db}
+}
+
+// Insert 任务名字是唯一的，已经存在就什么也不做，方便每次启动的时候都注册一遍
+func (g *GORMJobDAO) Insert(ctx context.Context, j Job) error {
+	now := time.Now().UnixMilli()
+	j.Ctime = now
+	j.Utime = now
+	return g.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoNothing: true,
+	}).Create(&j).Error
+}
+
 func (g *GORMJobDAO) Preempt(ctx context.Context, refreshInterval time.Duration) (Job, error) {
 	db := g.db.WithContext(ctx)
 	for {
@@ -25,7 +42,8 @@ func (g *GORMJobDAO) Preempt(ctx context.Context, refreshInterval time.Duration)
 		endTime := now - refreshInterval.Milliseconds()
 		err := db.WithContext(ctx).
 			//增加查询续约失败的情况
-			Where("(status = ? AND next_time < ?) OR utime < ?", jobStatusWaiting, now, endTime).
+			Where("(status = ? AND next_time < ?) OR (status = ? AND utime < ?)",
+				jobStatusWaiting, now, jobStatusRunning, endTime).
 			First(&job).Error
 		if err != nil {
 			return Job{}, err
@@ -38,7 +56,7 @@ func (g *GORMJobDAO) Preempt(ctx context.Context, refreshInterval time.Duration)
 				"utime":   now,
 			})
 		if res.Error != nil {
-			return Job{}, err
+			return Job{}, res.Error
 		}
 		if res.RowsAffected == 0 {
 			continue
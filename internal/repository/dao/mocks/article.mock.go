@@ -0,0 +1,422 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/repository/dao/article.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/repository/dao/article.go -package=daomocks -destination=./internal/repository/dao/mocks/article.mock.go
+//
+// Package daomocks is a generated GoMock package.
+package daomocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+	dao "webook/internal/repository/dao"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockArticleDAO is a mock of ArticleDAO interface.
+type MockArticleDAO struct {
+	ctrl     *gomock.Controller
+	recorder *MockArticleDAOMockRecorder
+}
+
+// MockArticleDAOMockRecorder is the mock recorder for MockArticleDAO.
+type MockArticleDAOMockRecorder struct {
+	mock *MockArticleDAO
+}
+
+// NewMockArticleDAO creates a new mock instance.
+func NewMockArticleDAO(ctrl *gomock.Controller) *MockArticleDAO {
+	mock := &MockArticleDAO{ctrl: ctrl}
+	mock.recorder = &MockArticleDAOMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockArticleDAO) EXPECT() *MockArticleDAOMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockArticleDAO) Delete(ctx context.Context, id, uid int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id, uid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockArticleDAOMockRecorder) Delete(ctx, id, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockArticleDAO)(nil).Delete), ctx, id, uid)
+}
+
+// GetByAuthor mocks base method.
+func (m *MockArticleDAO) GetByAuthor(ctx context.Context, uid int64, offset, limit int) ([]dao.Article, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByAuthor", ctx, uid, offset, limit)
+	ret0, _ := ret[0].([]dao.Article)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByAuthor indicates an expected call of GetByAuthor.
+func (mr *MockArticleDAOMockRecorder) GetByAuthor(ctx, uid, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByAuthor", reflect.TypeOf((*MockArticleDAO)(nil).GetByAuthor), ctx, uid, offset, limit)
+}
+
+// GetByAuthorCursor mocks base method.
+func (m *MockArticleDAO) GetByAuthorCursor(ctx context.Context, uid, utime, id int64, limit int) ([]dao.Article, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByAuthorCursor", ctx, uid, utime, id, limit)
+	ret0, _ := ret[0].([]dao.Article)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByAuthorCursor indicates an expected call of GetByAuthorCursor.
+func (mr *MockArticleDAOMockRecorder) GetByAuthorCursor(ctx, uid, utime, id, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByAuthorCursor", reflect.TypeOf((*MockArticleDAO)(nil).GetByAuthorCursor), ctx, uid, utime, id, limit)
+}
+
+// GetById mocks base method.
+func (m *MockArticleDAO) GetById(ctx context.Context, id int64) (dao.Article, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetById", ctx, id)
+	ret0, _ := ret[0].(dao.Article)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetById indicates an expected call of GetById.
+func (mr *MockArticleDAOMockRecorder) GetById(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockArticleDAO)(nil).GetById), ctx, id)
+}
+
+// GetPubById mocks base method.
+func (m *MockArticleDAO) GetPubById(ctx context.Context, id int64) (dao.PublishedArticle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPubById", ctx, id)
+	ret0, _ := ret[0].(dao.PublishedArticle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPubById indicates an expected call of GetPubById.
+func (mr *MockArticleDAOMockRecorder) GetPubById(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPubById", reflect.TypeOf((*MockArticleDAO)(nil).GetPubById), ctx, id)
+}
+
+// GetRevision mocks base method.
+func (m *MockArticleDAO) GetRevision(ctx context.Context, artId, version int64) (dao.ArticleRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRevision", ctx, artId, version)
+	ret0, _ := ret[0].(dao.ArticleRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRevision indicates an expected call of GetRevision.
+func (mr *MockArticleDAOMockRecorder) GetRevision(ctx, artId, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRevision", reflect.TypeOf((*MockArticleDAO)(nil).GetRevision), ctx, artId, version)
+}
+
+// Insert mocks base method.
+func (m *MockArticleDAO) Insert(ctx context.Context, art dao.Article) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Insert", ctx, art)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Insert indicates an expected call of Insert.
+func (mr *MockArticleDAOMockRecorder) Insert(ctx, art any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Insert", reflect.TypeOf((*MockArticleDAO)(nil).Insert), ctx, art)
+}
+
+// ListByIds mocks base method.
+func (m *MockArticleDAO) ListByIds(ctx context.Context, ids []int64, offset, limit int) ([]dao.Article, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByIds", ctx, ids, offset, limit)
+	ret0, _ := ret[0].([]dao.Article)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByIds indicates an expected call of ListByIds.
+func (mr *MockArticleDAOMockRecorder) ListByIds(ctx, ids, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByIds", reflect.TypeOf((*MockArticleDAO)(nil).ListByIds), ctx, ids, offset, limit)
+}
+
+// ListExpiredTrash mocks base method.
+func (m *MockArticleDAO) ListExpiredTrash(ctx context.Context, before int64, limit int) ([]dao.Article, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExpiredTrash", ctx, before, limit)
+	ret0, _ := ret[0].([]dao.Article)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExpiredTrash indicates an expected call of ListExpiredTrash.
+func (mr *MockArticleDAOMockRecorder) ListExpiredTrash(ctx, before, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExpiredTrash", reflect.TypeOf((*MockArticleDAO)(nil).ListExpiredTrash), ctx, before, limit)
+}
+
+// ListPub mocks base method.
+func (m *MockArticleDAO) ListPub(ctx context.Context, start time.Time, offset, limit int) ([]dao.PublishedArticle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPub", ctx, start, offset, limit)
+	ret0, _ := ret[0].([]dao.PublishedArticle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPub indicates an expected call of ListPub.
+func (mr *MockArticleDAOMockRecorder) ListPub(ctx, start, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPub", reflect.TypeOf((*MockArticleDAO)(nil).ListPub), ctx, start, offset, limit)
+}
+
+// ListPubByAuthor mocks base method.
+func (m *MockArticleDAO) ListPubByAuthor(ctx context.Context, uid int64, offset, limit int) ([]dao.PublishedArticle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPubByAuthor", ctx, uid, offset, limit)
+	ret0, _ := ret[0].([]dao.PublishedArticle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPubByAuthor indicates an expected call of ListPubByAuthor.
+func (mr *MockArticleDAOMockRecorder) ListPubByAuthor(ctx, uid, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPubByAuthor", reflect.TypeOf((*MockArticleDAO)(nil).ListPubByAuthor), ctx, uid, offset, limit)
+}
+
+// ListPubByIds mocks base method.
+func (m *MockArticleDAO) ListPubByIds(ctx context.Context, ids []int64, offset, limit int) ([]dao.PublishedArticle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPubByIds", ctx, ids, offset, limit)
+	ret0, _ := ret[0].([]dao.PublishedArticle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPubByIds indicates an expected call of ListPubByIds.
+func (mr *MockArticleDAOMockRecorder) ListPubByIds(ctx, ids, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPubByIds", reflect.TypeOf((*MockArticleDAO)(nil).ListPubByIds), ctx, ids, offset, limit)
+}
+
+// ListPubCursor mocks base method.
+func (m *MockArticleDAO) ListPubCursor(ctx context.Context, utime, id int64, limit int) ([]dao.PublishedArticle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPubCursor", ctx, utime, id, limit)
+	ret0, _ := ret[0].([]dao.PublishedArticle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPubCursor indicates an expected call of ListPubCursor.
+func (mr *MockArticleDAOMockRecorder) ListPubCursor(ctx, utime, id, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPubCursor", reflect.TypeOf((*MockArticleDAO)(nil).ListPubCursor), ctx, utime, id, limit)
+}
+
+// ListPubIds mocks base method.
+func (m *MockArticleDAO) ListPubIds(ctx context.Context, afterId int64, limit int) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPubIds", ctx, afterId, limit)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPubIds indicates an expected call of ListPubIds.
+func (mr *MockArticleDAOMockRecorder) ListPubIds(ctx, afterId, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPubIds", reflect.TypeOf((*MockArticleDAO)(nil).ListPubIds), ctx, afterId, limit)
+}
+
+// ListRevisions mocks base method.
+func (m *MockArticleDAO) ListRevisions(ctx context.Context, artId int64, offset, limit int) ([]dao.ArticleRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRevisions", ctx, artId, offset, limit)
+	ret0, _ := ret[0].([]dao.ArticleRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRevisions indicates an expected call of ListRevisions.
+func (mr *MockArticleDAOMockRecorder) ListRevisions(ctx, artId, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRevisions", reflect.TypeOf((*MockArticleDAO)(nil).ListRevisions), ctx, artId, offset, limit)
+}
+
+// ListScheduled mocks base method.
+func (m *MockArticleDAO) ListScheduled(ctx context.Context, before time.Time, limit int) ([]dao.Article, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListScheduled", ctx, before, limit)
+	ret0, _ := ret[0].([]dao.Article)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListScheduled indicates an expected call of ListScheduled.
+func (mr *MockArticleDAOMockRecorder) ListScheduled(ctx, before, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListScheduled", reflect.TypeOf((*MockArticleDAO)(nil).ListScheduled), ctx, before, limit)
+}
+
+// ListTrash mocks base method.
+func (m *MockArticleDAO) ListTrash(ctx context.Context, uid int64, offset, limit int) ([]dao.Article, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTrash", ctx, uid, offset, limit)
+	ret0, _ := ret[0].([]dao.Article)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTrash indicates an expected call of ListTrash.
+func (mr *MockArticleDAOMockRecorder) ListTrash(ctx, uid, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTrash", reflect.TypeOf((*MockArticleDAO)(nil).ListTrash), ctx, uid, offset, limit)
+}
+
+// PublishReviewed mocks base method.
+func (m *MockArticleDAO) PublishReviewed(ctx context.Context, art dao.Article) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishReviewed", ctx, art)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PublishReviewed indicates an expected call of PublishReviewed.
+func (mr *MockArticleDAOMockRecorder) PublishReviewed(ctx, art any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishReviewed", reflect.TypeOf((*MockArticleDAO)(nil).PublishReviewed), ctx, art)
+}
+
+// PublishScheduled mocks base method.
+func (m *MockArticleDAO) PublishScheduled(ctx context.Context, art dao.Article) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishScheduled", ctx, art)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PublishScheduled indicates an expected call of PublishScheduled.
+func (mr *MockArticleDAOMockRecorder) PublishScheduled(ctx, art any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishScheduled", reflect.TypeOf((*MockArticleDAO)(nil).PublishScheduled), ctx, art)
+}
+
+// Purge mocks base method.
+func (m *MockArticleDAO) Purge(ctx context.Context, id, before int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", ctx, id, before)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockArticleDAOMockRecorder) Purge(ctx, id, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockArticleDAO)(nil).Purge), ctx, id, before)
+}
+
+// ResolveReview mocks base method.
+func (m *MockArticleDAO) ResolveReview(ctx context.Context, id int64, status uint8) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveReview", ctx, id, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResolveReview indicates an expected call of ResolveReview.
+func (mr *MockArticleDAOMockRecorder) ResolveReview(ctx, id, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveReview", reflect.TypeOf((*MockArticleDAO)(nil).ResolveReview), ctx, id, status)
+}
+
+// Restore mocks base method.
+func (m *MockArticleDAO) Restore(ctx context.Context, id, uid, deletedAfter int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, id, uid, deletedAfter)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockArticleDAOMockRecorder) Restore(ctx, id, uid, deletedAfter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockArticleDAO)(nil).Restore), ctx, id, uid, deletedAfter)
+}
+
+// Sync mocks base method.
+func (m *MockArticleDAO) Sync(ctx context.Context, art dao.Article, tags []string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Sync", ctx, art, tags)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Sync indicates an expected call of Sync.
+func (mr *MockArticleDAOMockRecorder) Sync(ctx, art, tags any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sync", reflect.TypeOf((*MockArticleDAO)(nil).Sync), ctx, art, tags)
+}
+
+// SyncStatus mocks base method.
+func (m *MockArticleDAO) SyncStatus(ctx context.Context, id, uid int64, status uint8) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncStatus", ctx, id, uid, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SyncStatus indicates an expected call of SyncStatus.
+func (mr *MockArticleDAOMockRecorder) SyncStatus(ctx, id, uid, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncStatus", reflect.TypeOf((*MockArticleDAO)(nil).SyncStatus), ctx, id, uid, status)
+}
+
+// UpdateById mocks base method.
+func (m *MockArticleDAO) UpdateById(ctx context.Context, art dao.Article) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateById", ctx, art)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateById indicates an expected call of UpdateById.
+func (mr *MockArticleDAOMockRecorder) UpdateById(ctx, art any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateById", reflect.TypeOf((*MockArticleDAO)(nil).UpdateById), ctx, art)
+}
+
+// UpdateSchedule mocks base method.
+func (m *MockArticleDAO) UpdateSchedule(ctx context.Context, id, uid int64, status uint8, publishAt int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSchedule", ctx, id, uid, status, publishAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateSchedule indicates an expected call of UpdateSchedule.
+func (mr *MockArticleDAOMockRecorder) UpdateSchedule(ctx, id, uid, status, publishAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSchedule", reflect.TypeOf((*MockArticleDAO)(nil).UpdateSchedule), ctx, id, uid, status, publishAt)
+}
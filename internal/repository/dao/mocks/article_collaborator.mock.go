@@ -0,0 +1,142 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/repository/dao/article_collaborator.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/repository/dao/article_collaborator.go -package=daomocks -destination=./internal/repository/dao/mocks/article_collaborator.mock.go
+//
+// Package daomocks is a generated GoMock package.
+package daomocks
+
+import (
+	context "context"
+	reflect "reflect"
+	dao "webook/internal/repository/dao"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockArticleCollaboratorDAO is a mock of ArticleCollaboratorDAO interface.
+type MockArticleCollaboratorDAO struct {
+	ctrl     *gomock.Controller
+	recorder *MockArticleCollaboratorDAOMockRecorder
+}
+
+// MockArticleCollaboratorDAOMockRecorder is the mock recorder for MockArticleCollaboratorDAO.
+type MockArticleCollaboratorDAOMockRecorder struct {
+	mock *MockArticleCollaboratorDAO
+}
+
+// NewMockArticleCollaboratorDAO creates a new mock instance.
+func NewMockArticleCollaboratorDAO(ctrl *gomock.Controller) *MockArticleCollaboratorDAO {
+	mock := &MockArticleCollaboratorDAO{ctrl: ctrl}
+	mock.recorder = &MockArticleCollaboratorDAOMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockArticleCollaboratorDAO) EXPECT() *MockArticleCollaboratorDAOMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockArticleCollaboratorDAO) Delete(ctx context.Context, artId, uid int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, artId, uid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockArticleCollaboratorDAOMockRecorder) Delete(ctx, artId, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockArticleCollaboratorDAO)(nil).Delete), ctx, artId, uid)
+}
+
+// Get mocks base method.
+func (m *MockArticleCollaboratorDAO) Get(ctx context.Context, artId, uid int64) (dao.ArticleCollaborator, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, artId, uid)
+	ret0, _ := ret[0].(dao.ArticleCollaborator)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockArticleCollaboratorDAOMockRecorder) Get(ctx, artId, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockArticleCollaboratorDAO)(nil).Get), ctx, artId, uid)
+}
+
+// InsertAudit mocks base method.
+func (m *MockArticleCollaboratorDAO) InsertAudit(ctx context.Context, a dao.ArticleAudit) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertAudit", ctx, a)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertAudit indicates an expected call of InsertAudit.
+func (mr *MockArticleCollaboratorDAOMockRecorder) InsertAudit(ctx, a any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertAudit", reflect.TypeOf((*MockArticleCollaboratorDAO)(nil).InsertAudit), ctx, a)
+}
+
+// List mocks base method.
+func (m *MockArticleCollaboratorDAO) List(ctx context.Context, artId int64) ([]dao.ArticleCollaborator, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, artId)
+	ret0, _ := ret[0].([]dao.ArticleCollaborator)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockArticleCollaboratorDAOMockRecorder) List(ctx, artId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockArticleCollaboratorDAO)(nil).List), ctx, artId)
+}
+
+// ListArticleIds mocks base method.
+func (m *MockArticleCollaboratorDAO) ListArticleIds(ctx context.Context, uid int64) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListArticleIds", ctx, uid)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListArticleIds indicates an expected call of ListArticleIds.
+func (mr *MockArticleCollaboratorDAOMockRecorder) ListArticleIds(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListArticleIds", reflect.TypeOf((*MockArticleCollaboratorDAO)(nil).ListArticleIds), ctx, uid)
+}
+
+// ListAudits mocks base method.
+func (m *MockArticleCollaboratorDAO) ListAudits(ctx context.Context, artId int64, offset, limit int) ([]dao.ArticleAudit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAudits", ctx, artId, offset, limit)
+	ret0, _ := ret[0].([]dao.ArticleAudit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAudits indicates an expected call of ListAudits.
+func (mr *MockArticleCollaboratorDAOMockRecorder) ListAudits(ctx, artId, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAudits", reflect.TypeOf((*MockArticleCollaboratorDAO)(nil).ListAudits), ctx, artId, offset, limit)
+}
+
+// Upsert mocks base method.
+func (m *MockArticleCollaboratorDAO) Upsert(ctx context.Context, c dao.ArticleCollaborator) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", ctx, c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockArticleCollaboratorDAOMockRecorder) Upsert(ctx, c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockArticleCollaboratorDAO)(nil).Upsert), ctx, c)
+}
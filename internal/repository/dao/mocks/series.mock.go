@@ -0,0 +1,157 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/repository/dao/series.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/repository/dao/series.go -package=daomocks -destination=./internal/repository/dao/mocks/series.mock.go
+//
+// Package daomocks is a generated GoMock package.
+package daomocks
+
+import (
+	context "context"
+	reflect "reflect"
+	dao "webook/internal/repository/dao"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSeriesDAO is a mock of SeriesDAO interface.
+type MockSeriesDAO struct {
+	ctrl     *gomock.Controller
+	recorder *MockSeriesDAOMockRecorder
+}
+
+// MockSeriesDAOMockRecorder is the mock recorder for MockSeriesDAO.
+type MockSeriesDAOMockRecorder struct {
+	mock *MockSeriesDAO
+}
+
+// NewMockSeriesDAO creates a new mock instance.
+func NewMockSeriesDAO(ctrl *gomock.Controller) *MockSeriesDAO {
+	mock := &MockSeriesDAO{ctrl: ctrl}
+	mock.recorder = &MockSeriesDAOMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSeriesDAO) EXPECT() *MockSeriesDAOMockRecorder {
+	return m.recorder
+}
+
+// GetByArticle mocks base method.
+func (m *MockSeriesDAO) GetByArticle(ctx context.Context, artId int64) (dao.Series, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByArticle", ctx, artId)
+	ret0, _ := ret[0].(dao.Series)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByArticle indicates an expected call of GetByArticle.
+func (mr *MockSeriesDAOMockRecorder) GetByArticle(ctx, artId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByArticle", reflect.TypeOf((*MockSeriesDAO)(nil).GetByArticle), ctx, artId)
+}
+
+// GetById mocks base method.
+func (m *MockSeriesDAO) GetById(ctx context.Context, id int64) (dao.Series, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetById", ctx, id)
+	ret0, _ := ret[0].(dao.Series)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetById indicates an expected call of GetById.
+func (mr *MockSeriesDAOMockRecorder) GetById(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockSeriesDAO)(nil).GetById), ctx, id)
+}
+
+// Insert mocks base method.
+func (m *MockSeriesDAO) Insert(ctx context.Context, s dao.Series) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Insert", ctx, s)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Insert indicates an expected call of Insert.
+func (mr *MockSeriesDAOMockRecorder) Insert(ctx, s any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Insert", reflect.TypeOf((*MockSeriesDAO)(nil).Insert), ctx, s)
+}
+
+// ListArticleIds mocks base method.
+func (m *MockSeriesDAO) ListArticleIds(ctx context.Context, id int64) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListArticleIds", ctx, id)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListArticleIds indicates an expected call of ListArticleIds.
+func (mr *MockSeriesDAOMockRecorder) ListArticleIds(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListArticleIds", reflect.TypeOf((*MockSeriesDAO)(nil).ListArticleIds), ctx, id)
+}
+
+// ListByAuthor mocks base method.
+func (m *MockSeriesDAO) ListByAuthor(ctx context.Context, uid int64, offset, limit int) ([]dao.Series, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByAuthor", ctx, uid, offset, limit)
+	ret0, _ := ret[0].([]dao.Series)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByAuthor indicates an expected call of ListByAuthor.
+func (mr *MockSeriesDAOMockRecorder) ListByAuthor(ctx, uid, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByAuthor", reflect.TypeOf((*MockSeriesDAO)(nil).ListByAuthor), ctx, uid, offset, limit)
+}
+
+// SetArticles mocks base method.
+func (m *MockSeriesDAO) SetArticles(ctx context.Context, id, uid int64, artIds []int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetArticles", ctx, id, uid, artIds)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetArticles indicates an expected call of SetArticles.
+func (mr *MockSeriesDAOMockRecorder) SetArticles(ctx, id, uid, artIds any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetArticles", reflect.TypeOf((*MockSeriesDAO)(nil).SetArticles), ctx, id, uid, artIds)
+}
+
+// UpdateById mocks base method.
+func (m *MockSeriesDAO) UpdateById(ctx context.Context, s dao.Series) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateById", ctx, s)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateById indicates an expected call of UpdateById.
+func (mr *MockSeriesDAOMockRecorder) UpdateById(ctx, s any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateById", reflect.TypeOf((*MockSeriesDAO)(nil).UpdateById), ctx, s)
+}
+
+// UpdateStatus mocks base method.
+func (m *MockSeriesDAO) UpdateStatus(ctx context.Context, id, uid int64, status uint8) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatus", ctx, id, uid, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *MockSeriesDAOMockRecorder) UpdateStatus(ctx, id, uid, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockSeriesDAO)(nil).UpdateStatus), ctx, id, uid, status)
+}
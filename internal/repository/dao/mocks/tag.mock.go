@@ -0,0 +1,128 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/repository/dao/tag.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/repository/dao/tag.go -package=daomocks -destination=./internal/repository/dao/mocks/tag.mock.go
+//
+// Package daomocks is a generated GoMock package.
+package daomocks
+
+import (
+	context "context"
+	reflect "reflect"
+	dao "webook/internal/repository/dao"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTagDAO is a mock of TagDAO interface.
+type MockTagDAO struct {
+	ctrl     *gomock.Controller
+	recorder *MockTagDAOMockRecorder
+}
+
+// MockTagDAOMockRecorder is the mock recorder for MockTagDAO.
+type MockTagDAOMockRecorder struct {
+	mock *MockTagDAO
+}
+
+// NewMockTagDAO creates a new mock instance.
+func NewMockTagDAO(ctrl *gomock.Controller) *MockTagDAO {
+	mock := &MockTagDAO{ctrl: ctrl}
+	mock.recorder = &MockTagDAOMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTagDAO) EXPECT() *MockTagDAOMockRecorder {
+	return m.recorder
+}
+
+// GetArticleTags mocks base method.
+func (m *MockTagDAO) GetArticleTags(ctx context.Context, artId int64) ([]dao.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetArticleTags", ctx, artId)
+	ret0, _ := ret[0].([]dao.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetArticleTags indicates an expected call of GetArticleTags.
+func (mr *MockTagDAOMockRecorder) GetArticleTags(ctx, artId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetArticleTags", reflect.TypeOf((*MockTagDAO)(nil).GetArticleTags), ctx, artId)
+}
+
+// GetPubArticleTags mocks base method.
+func (m *MockTagDAO) GetPubArticleTags(ctx context.Context, artId int64) ([]dao.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPubArticleTags", ctx, artId)
+	ret0, _ := ret[0].([]dao.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPubArticleTags indicates an expected call of GetPubArticleTags.
+func (mr *MockTagDAOMockRecorder) GetPubArticleTags(ctx, artId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPubArticleTags", reflect.TypeOf((*MockTagDAO)(nil).GetPubArticleTags), ctx, artId)
+}
+
+// ListPubArticleIds mocks base method.
+func (m *MockTagDAO) ListPubArticleIds(ctx context.Context, name string, limit int) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPubArticleIds", ctx, name, limit)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPubArticleIds indicates an expected call of ListPubArticleIds.
+func (mr *MockTagDAOMockRecorder) ListPubArticleIds(ctx, name, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPubArticleIds", reflect.TypeOf((*MockTagDAO)(nil).ListPubArticleIds), ctx, name, limit)
+}
+
+// ListTags mocks base method.
+func (m *MockTagDAO) ListTags(ctx context.Context, offset, limit int) ([]dao.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTags", ctx, offset, limit)
+	ret0, _ := ret[0].([]dao.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTags indicates an expected call of ListTags.
+func (mr *MockTagDAOMockRecorder) ListTags(ctx, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTags", reflect.TypeOf((*MockTagDAO)(nil).ListTags), ctx, offset, limit)
+}
+
+// PublishArticleTags mocks base method.
+func (m *MockTagDAO) PublishArticleTags(ctx context.Context, artId int64, names []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishArticleTags", ctx, artId, names)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PublishArticleTags indicates an expected call of PublishArticleTags.
+func (mr *MockTagDAOMockRecorder) PublishArticleTags(ctx, artId, names any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishArticleTags", reflect.TypeOf((*MockTagDAO)(nil).PublishArticleTags), ctx, artId, names)
+}
+
+// SetArticleTags mocks base method.
+func (m *MockTagDAO) SetArticleTags(ctx context.Context, artId int64, names []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetArticleTags", ctx, artId, names)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetArticleTags indicates an expected call of SetArticleTags.
+func (mr *MockTagDAOMockRecorder) SetArticleTags(ctx, artId, names any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetArticleTags", reflect.TypeOf((*MockTagDAO)(nil).SetArticleTags), ctx, artId, names)
+}
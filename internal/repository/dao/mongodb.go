@@ -14,33 +14,362 @@ type MongoDBArticleDAO struct {
 	node    *snowflake.Node
 	col     *mongo.Collection
 	liveCol *mongo.Collection
+	revCol  *mongo.Collection
+	// evtCol 生命周期事件，MongoDB 单机没有多文档事务，只能在写完文章之后紧接着写入
+	evtCol *mongo.Collection
+	// tagDao 标签还是存在 MySQL 里面
+	tagDao TagDAO
 }
 
 func (m *MongoDBArticleDAO) ListPub(ctx context.Context, start time.Time, offset int, limit int) ([]PublishedArticle, error) {
-	//TODO implement me
-	panic("implement me")
+	const ArticleStatusPublished = 2
+	filter := bson.D{bson.E{Key: "status", Value: ArticleStatusPublished},
+		bson.E{Key: "utime", Value: bson.D{bson.E{Key: "$lt", Value: start.UnixMilli()}}},
+		notDeleted()}
+	opts := options.Find().
+		SetSort(bson.D{bson.E{Key: "utime", Value: -1}}).
+		SetSkip(int64(offset)).
+		SetLimit(int64(limit))
+	cursor, err := m.liveCol.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var res []PublishedArticle
+	err = cursor.All(ctx, &res)
+	return res, err
+}
+
+func (m *MongoDBArticleDAO) ListPubByAuthor(ctx context.Context, uid int64, offset int, limit int) ([]PublishedArticle, error) {
+	const ArticleStatusPublished = 2
+	filter := bson.D{bson.E{Key: "author_id", Value: uid},
+		bson.E{Key: "status", Value: ArticleStatusPublished},
+		notDeleted()}
+	opts := options.Find().
+		SetSort(bson.D{bson.E{Key: "utime", Value: -1}}).
+		SetSkip(int64(offset)).
+		SetLimit(int64(limit))
+	cursor, err := m.liveCol.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var res []PublishedArticle
+	err = cursor.All(ctx, &res)
+	return res, err
+}
+
+func (m *MongoDBArticleDAO) ListByIds(ctx context.Context, ids []int64, offset int, limit int) ([]Article, error) {
+	var res []Article
+	if len(ids) == 0 {
+		return res, nil
+	}
+	filter := bson.D{bson.E{Key: "id", Value: bson.D{bson.E{Key: "$in", Value: ids}}}, notDeleted()}
+	opts := options.Find().
+		SetProjection(bson.D{bson.E{Key: "content", Value: 0}, bson.E{Key: "html", Value: 0}}).
+		SetSort(bson.D{bson.E{Key: "utime", Value: -1}}).
+		SetSkip(int64(offset)).
+		SetLimit(int64(limit))
+	cursor, err := m.col.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	err = cursor.All(ctx, &res)
+	return res, err
+}
+
+func (m *MongoDBArticleDAO) ListPubByIds(ctx context.Context, ids []int64, offset int, limit int) ([]PublishedArticle, error) {
+	var res []PublishedArticle
+	if len(ids) == 0 {
+		return res, nil
+	}
+	const ArticleStatusPublished = 2
+	filter := bson.D{bson.E{Key: "id", Value: bson.D{bson.E{Key: "$in", Value: ids}}},
+		bson.E{Key: "status", Value: ArticleStatusPublished},
+		notDeleted()}
+	opts := options.Find().
+		SetProjection(bson.D{bson.E{Key: "html", Value: 0}}).
+		SetSort(bson.D{bson.E{Key: "utime", Value: -1}}).
+		SetSkip(int64(offset)).
+		SetLimit(int64(limit))
+	cursor, err := m.liveCol.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	err = cursor.All(ctx, &res)
+	return res, err
 }
 
 func (m *MongoDBArticleDAO) GetByAuthor(ctx context.Context, uid int64, offset int, limit int) ([]Article, error) {
-	//TODO implement me
-	panic("implement me")
+	filter := bson.D{bson.E{Key: "author_id", Value: uid}, notDeleted()}
+	opts := options.Find().
+		SetSort(bson.D{bson.E{Key: "utime", Value: -1}}).
+		SetSkip(int64(offset)).
+		SetLimit(int64(limit))
+	cursor, err := m.col.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var res []Article
+	err = cursor.All(ctx, &res)
+	return res, err
+}
+
+func (m *MongoDBArticleDAO) GetByAuthorCursor(ctx context.Context, uid int64, utime int64, id int64, limit int) ([]Article, error) {
+	filter := bson.D{bson.E{Key: "author_id", Value: uid}, notDeleted()}
+	if utime > 0 {
+		filter = append(filter, m.cursorFilter(utime, id))
+	}
+	cursor, err := m.col.Find(ctx, filter, m.cursorOpts(limit))
+	if err != nil {
+		return nil, err
+	}
+	var res []Article
+	err = cursor.All(ctx, &res)
+	return res, err
+}
+
+func (m *MongoDBArticleDAO) ListPubCursor(ctx context.Context, utime int64, id int64, limit int) ([]PublishedArticle, error) {
+	const ArticleStatusPublished = 2
+	filter := bson.D{bson.E{Key: "status", Value: ArticleStatusPublished}, notDeleted()}
+	if utime > 0 {
+		filter = append(filter, m.cursorFilter(utime, id))
+	}
+	cursor, err := m.liveCol.Find(ctx, filter, m.cursorOpts(limit))
+	if err != nil {
+		return nil, err
+	}
+	var res []PublishedArticle
+	err = cursor.All(ctx, &res)
+	return res, err
+}
+
+// notDeleted 早期写入的文档没有 deleted_at 字段，所以不能直接判断等于 0
+func notDeleted() bson.E {
+	return bson.E{Key: "deleted_at", Value: bson.D{bson.E{Key: "$not", Value: bson.D{bson.E{Key: "$gt", Value: 0}}}}}
+}
+
+// cursorFilter 等价于 utime < ? OR (utime = ? AND id < ?)
+func (m *MongoDBArticleDAO) cursorFilter(utime int64, id int64) bson.E {
+	return bson.E{Key: "$or", Value: bson.A{
+		bson.D{bson.E{Key: "utime", Value: bson.D{bson.E{Key: "$lt", Value: utime}}}},
+		bson.D{bson.E{Key: "utime", Value: utime},
+			bson.E{Key: "id", Value: bson.D{bson.E{Key: "$lt", Value: id}}}},
+	}}
+}
+
+func (m *MongoDBArticleDAO) cursorOpts(limit int) *options.FindOptions {
+	return options.Find().
+		SetSort(bson.D{bson.E{Key: "utime", Value: -1}, bson.E{Key: "id", Value: -1}}).
+		SetLimit(int64(limit))
 }
 
 func (m *MongoDBArticleDAO) GetById(ctx context.Context, id int64) (Article, error) {
-	//TODO implement me
-	panic("implement me")
+	var art Article
+	err := m.col.FindOne(ctx, bson.D{bson.E{Key: "id", Value: id}}).Decode(&art)
+	return art, err
 }
 
 func (m *MongoDBArticleDAO) GetPubById(ctx context.Context, id int64) (PublishedArticle, error) {
-	//TODO implement me
-	panic("implement me")
+	var art PublishedArticle
+	err := m.liveCol.FindOne(ctx, bson.D{bson.E{Key: "id", Value: id}, notDeleted()}).Decode(&art)
+	return art, err
+}
+
+func (m *MongoDBArticleDAO) ListPubIds(ctx context.Context, afterId int64, limit int) ([]int64, error) {
+	filter := bson.D{bson.E{Key: "id", Value: bson.D{bson.E{Key: "$gt", Value: afterId}}}}
+	opts := options.Find().
+		SetSort(bson.D{bson.E{Key: "id", Value: 1}}).
+		SetProjection(bson.D{bson.E{Key: "id", Value: 1}}).
+		SetLimit(int64(limit))
+	cursor, err := m.liveCol.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var arts []PublishedArticle
+	err = cursor.All(ctx, &arts)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]int64, 0, len(arts))
+	for _, art := range arts {
+		res = append(res, art.Id)
+	}
+	return res, nil
 }
 
-func NewMongoDBArticleDAO(node *snowflake.Node, col *mongo.Collection, liveCol *mongo.Collection) ArticleDAO {
+func (m *MongoDBArticleDAO) ListRevisions(ctx context.Context, artId int64, offset int, limit int) ([]ArticleRevision, error) {
+	filter := bson.D{bson.E{Key: "art_id", Value: artId}}
+	opts := options.Find().
+		SetSort(bson.D{bson.E{Key: "version", Value: -1}}).
+		SetSkip(int64(offset)).
+		SetLimit(int64(limit))
+	cursor, err := m.revCol.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var res []ArticleRevision
+	err = cursor.All(ctx, &res)
+	return res, err
+}
+
+func (m *MongoDBArticleDAO) GetRevision(ctx context.Context, artId int64, version int64) (ArticleRevision, error) {
+	filter := bson.D{bson.E{Key: "art_id", Value: artId}, bson.E{Key: "version", Value: version}}
+	var res ArticleRevision
+	err := m.revCol.FindOne(ctx, filter).Decode(&res)
+	return res, err
+}
+
+func (m *MongoDBArticleDAO) ListScheduled(ctx context.Context, before time.Time, limit int) ([]Article, error) {
+	const ArticleStatusScheduled = 4
+	filter := bson.D{bson.E{Key: "status", Value: ArticleStatusScheduled},
+		bson.E{Key: "publish_at", Value: bson.D{bson.E{Key: "$lte", Value: before.UnixMilli()}}},
+		notDeleted()}
+	opts := options.Find().
+		SetSort(bson.D{bson.E{Key: "publish_at", Value: 1}}).
+		SetLimit(int64(limit))
+	cursor, err := m.col.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var res []Article
+	err = cursor.All(ctx, &res)
+	return res, err
+}
+
+func (m *MongoDBArticleDAO) UpdateSchedule(ctx context.Context, id int64, uid int64, status uint8, publishAt int64) error {
+	const ArticleStatusScheduled = 4
+	filter := bson.D{bson.E{Key: "id", Value: id}, bson.E{Key: "author_id", Value: uid},
+		bson.E{Key: "status", Value: ArticleStatusScheduled}, notDeleted()}
+	res, err := m.col.UpdateOne(ctx, filter, bson.D{bson.E{Key: "$set", Value: bson.D{
+		bson.E{Key: "status", Value: status},
+		bson.E{Key: "publish_at", Value: publishAt},
+		bson.E{Key: "utime", Value: time.Now().UnixMilli()},
+	}}})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return errors.New("ID不对或者文章不是定时发表状态")
+	}
+	return nil
+}
+
+func (m *MongoDBArticleDAO) PublishScheduled(ctx context.Context, art Article) error {
+	const ArticleStatusScheduled = 4
+	return m.publishIf(ctx, art, ArticleStatusScheduled, ErrArticleNotScheduled)
+}
+
+func (m *MongoDBArticleDAO) PublishReviewed(ctx context.Context, art Article) error {
+	const ArticleStatusPendingReview = 5
+	return m.publishIf(ctx, art, ArticleStatusPendingReview, ErrArticleNotPendingReview)
+}
+
+// publishIf 文章还是 status 状态并且 utime 没变的时候才发表，否则返回 errChanged
+func (m *MongoDBArticleDAO) publishIf(ctx context.Context, art Article, status uint8, errChanged error) error {
+	// 取消、改期或者修改过的文章 utime 都会变化，只有一个请求能抢到
+	filter := bson.D{bson.E{Key: "id", Value: art.Id},
+		bson.E{Key: "status", Value: status},
+		bson.E{Key: "utime", Value: art.Utime}, notDeleted()}
+	res, err := m.col.UpdateOne(ctx, filter, bson.D{bson.E{Key: "$set", Value: bson.D{
+		bson.E{Key: "status", Value: art.Status},
+	}}})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return errChanged
+	}
+	_, err = m.Sync(ctx, art, nil)
+	if err != nil {
+		// 没有事务，改回原来的状态让下一次重试
+		_, er := m.col.UpdateOne(ctx, bson.D{bson.E{Key: "id", Value: art.Id}, notDeleted()},
+			bson.D{bson.E{Key: "$set", Value: bson.D{
+				bson.E{Key: "status", Value: status},
+				bson.E{Key: "utime", Value: time.Now().UnixMilli()},
+			}}})
+		if er != nil {
+			//记录日志
+		}
+	}
+	return err
+}
+
+func (m *MongoDBArticleDAO) ResolveReview(ctx context.Context, id int64, status uint8) error {
+	const ArticleStatusPendingReview = 5
+	filter := bson.D{bson.E{Key: "id", Value: id},
+		bson.E{Key: "status", Value: ArticleStatusPendingReview}, notDeleted()}
+	res, err := m.col.UpdateOne(ctx, filter, bson.D{bson.E{Key: "$set", Value: bson.D{
+		bson.E{Key: "status", Value: status},
+		bson.E{Key: "utime", Value: time.Now().UnixMilli()},
+	}}})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrArticleNotPendingReview
+	}
+	return nil
+}
+
+// appendRevisionRetries 并发保存同一篇文章的时候版本号冲突的重试次数
+const appendRevisionRetries = 3
+
+// appendRevision 版本号是当前最大的加一，先读再写不是原子的，
+// 并发的时候靠 (art_id, version) 的唯一索引发现冲突，重新读一次版本号
+func (m *MongoDBArticleDAO) appendRevision(ctx context.Context, art Article, now int64) error {
+	var err error
+	for i := 0; i <= appendRevisionRetries; i++ {
+		err = m.insertRevision(ctx, art, now)
+		if !mongo.IsDuplicateKeyError(err) {
+			return err
+		}
+	}
+	return err
+}
+
+func (m *MongoDBArticleDAO) insertRevision(ctx context.Context, art Article, now int64) error {
+	filter := bson.D{bson.E{Key: "art_id", Value: art.Id}}
+	var last ArticleRevision
+	err := m.revCol.FindOne(ctx, filter,
+		options.FindOne().SetSort(bson.D{bson.E{Key: "version", Value: -1}})).Decode(&last)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return err
+	}
+	_, err = m.revCol.InsertOne(ctx, ArticleRevision{
+		Id:       m.node.Generate().Int64(),
+		ArtId:    art.Id,
+		Version:  last.Version + 1,
+		Title:    art.Title,
+		Content:  art.Content,
+		Status:   art.Status,
+		AuthorId: art.AuthorId,
+		Ctime:    now,
+	})
+	return err
+}
+
+func (m *MongoDBArticleDAO) addEvent(ctx context.Context, typ string, art Article, now int64) error {
+	_, err := m.evtCol.InsertOne(ctx, ArticleEvent{
+		Id:        m.node.Generate().Int64(),
+		Type:      typ,
+		ArtId:     art.Id,
+		AuthorId:  art.AuthorId,
+		ArtStatus: art.Status,
+		Status:    ArticleEventStatusPending,
+		Ctime:     now,
+		Utime:     now,
+	})
+	return err
+}
+
+func NewMongoDBArticleDAO(node *snowflake.Node, col *mongo.Collection, liveCol *mongo.Collection,
+	revCol *mongo.Collection, evtCol *mongo.Collection, tagDao TagDAO) ArticleDAO {
 	return &MongoDBArticleDAO{
 		node:    node,
 		col:     col,
 		liveCol: liveCol,
+		revCol:  revCol,
+		evtCol:  evtCol,
+		tagDao:  tagDao,
 	}
 }
 
@@ -50,29 +379,43 @@ func (m *MongoDBArticleDAO) Insert(ctx context.Context, art Article) (int64, err
 	art.Ctime = now
 	art.Utime = now
 	_, err := m.col.InsertOne(ctx, art)
-	return art.Id, err
+	if err != nil {
+		return 0, err
+	}
+	err = m.appendRevision(ctx, art, now)
+	if err != nil {
+		return 0, err
+	}
+	return art.Id, m.addEvent(ctx, ArticleEventCreated, art, now)
 }
 
 func (m *MongoDBArticleDAO) UpdateById(ctx context.Context, art Article) error {
 	now := time.Now().UnixMilli()
-	filter := bson.D{bson.E{"id", art.Id}, bson.E{"author_id", art.AuthorId}}
-	set := bson.D{bson.E{"$set", bson.M{
-		"title":   art.Title,
-		"content": art.Content,
-		"status":  art.Status,
-		"utime":   now,
+	filter := bson.D{bson.E{Key: "id", Value: art.Id}, notDeleted()}
+	set := bson.D{bson.E{Key: "$set", Value: bson.M{
+		"title":      art.Title,
+		"content":    art.Content,
+		"html":       art.HTML,
+		"summary":    art.Summary,
+		"status":     art.Status,
+		"publish_at": art.PublishAt,
+		"utime":      now,
 	}}}
 	res, err := m.col.UpdateOne(ctx, filter, set)
 	if err != nil {
 		return err
 	}
-	if res.ModifiedCount == 0 {
-		return errors.New("ID 不对或者创作者不对")
+	if res.MatchedCount == 0 {
+		return errors.New("ID 不对或者文章已经删除")
 	}
-	return nil
+	err = m.appendRevision(ctx, art, now)
+	if err != nil {
+		return err
+	}
+	return m.addEvent(ctx, ArticleEventUpdated, art, now)
 }
 
-func (m *MongoDBArticleDAO) Sync(ctx context.Context, art Article) (int64, error) {
+func (m *MongoDBArticleDAO) Sync(ctx context.Context, art Article, tags []string) (int64, error) {
 	var err error
 	id := art.Id
 	if art.Id > 0 {
@@ -85,25 +428,189 @@ func (m *MongoDBArticleDAO) Sync(ctx context.Context, art Article) (int64, error
 	}
 	art.Id = id
 	now := time.Now().UnixMilli()
-	publishArt := PublishedArticle(art)
-	publishArt.Utime = now
-	filter := bson.D{bson.E{Key: "id", Value: publishArt.Id}, bson.E{Key: "author_id", Value: publishArt.AuthorId}}
-	_, err = m.liveCol.UpdateOne(ctx, filter, bson.D{bson.E{Key: "$set", Value: art},
+	filter := bson.D{bson.E{Key: "id", Value: art.Id}, bson.E{Key: "author_id", Value: art.AuthorId}}
+	// ctime 只在第一次发表的时候写入，所以不能直接 $set 整个文章
+	_, err = m.liveCol.UpdateOne(ctx, filter, bson.D{
+		bson.E{Key: "$set", Value: bson.D{
+			bson.E{Key: "title", Value: art.Title},
+			bson.E{Key: "content", Value: art.Content},
+			bson.E{Key: "html", Value: art.HTML},
+			bson.E{Key: "summary", Value: art.Summary},
+			bson.E{Key: "status", Value: art.Status},
+			bson.E{Key: "publish_at", Value: art.PublishAt},
+			bson.E{Key: "utime", Value: now},
+		}},
 		bson.E{Key: "$setOnInsert", Value: bson.D{bson.E{Key: "ctime", Value: now}}}},
 		options.Update().SetUpsert(true))
-	return art.Id, err
+	if err != nil {
+		return 0, err
+	}
+	// 和 MySQL 里面的标签没办法放在一个事务里面，失败了返回错误让用户重新发表
+	err = m.tagDao.PublishArticleTags(ctx, art.Id, tags)
+	if err != nil {
+		return 0, err
+	}
+	return art.Id, m.addEvent(ctx, ArticleEventPublished, art, now)
 }
 
 func (m *MongoDBArticleDAO) SyncStatus(ctx context.Context, id int64, uid int64, status uint8) error {
 	now := time.Now().UnixMilli()
-	filter := bson.D{bson.E{Key: "id", Value: id}, bson.E{Key: "author_id", Value: uid}}
+	filter := bson.D{bson.E{Key: "id", Value: id}, notDeleted()}
 	res, err := m.col.UpdateOne(ctx, filter, bson.D{bson.E{Key: "$set", Value: bson.D{bson.E{Key: "status", Value: status}, bson.E{Key: "utime", Value: now}}}})
 	if err != nil {
 		return err
 	}
-	if res.UpsertedCount == 0 {
-		return errors.New("ID不对或者创作者不对")
+	if res.MatchedCount == 0 {
+		return errors.New("ID不对或者文章已经删除")
 	}
 	_, err = m.liveCol.UpdateOne(ctx, filter, bson.D{bson.E{Key: "$set", Value: bson.D{bson.E{Key: "status", Value: status}, bson.E{Key: "utime", Value: now}}}})
+	if err != nil {
+		return err
+	}
+	return m.addEvent(ctx, statusEventType(status), Article{Id: id, AuthorId: uid, Status: status}, now)
+}
+
+// InitCollections 创建文章相关的索引，索引已经存在的时候不会报错
+func InitCollections(db *mongo.Database) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, err := db.Collection("articles").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{bson.E{Key: "id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{bson.E{Key: "author_id", Value: 1},
+				bson.E{Key: "utime", Value: -1}, bson.E{Key: "id", Value: -1}},
+		},
+		{
+			Keys: bson.D{bson.E{Key: "status", Value: 1}, bson.E{Key: "publish_at", Value: 1}},
+		},
+		{
+			Keys: bson.D{bson.E{Key: "deleted_at", Value: 1}},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = db.Collection("published_articles").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{bson.E{Key: "id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{bson.E{Key: "status", Value: 1},
+				bson.E{Key: "utime", Value: -1}, bson.E{Key: "id", Value: -1}},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = db.Collection("article_revisions").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{bson.E{Key: "art_id", Value: 1}, bson.E{Key: "version", Value: -1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = db.Collection("article_events").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{bson.E{Key: "status", Value: 1}, bson.E{Key: "id", Value: 1}},
+		},
+		{
+			Keys: bson.D{bson.E{Key: "status", Value: 1}, bson.E{Key: "utime", Value: 1}},
+		},
+	})
 	return err
 }
+
+func (m *MongoDBArticleDAO) Delete(ctx context.Context, id int64, uid int64) error {
+	now := time.Now().UnixMilli()
+	filter := bson.D{bson.E{Key: "id", Value: id}, bson.E{Key: "author_id", Value: uid}, notDeleted()}
+	var art Article
+	err := m.col.FindOneAndUpdate(ctx, filter, bson.D{bson.E{Key: "$set", Value: bson.D{
+		bson.E{Key: "deleted_at", Value: now}}}}).Decode(&art)
+	if err != nil {
+		return err
+	}
+	_, err = m.liveCol.UpdateOne(ctx, bson.D{bson.E{Key: "id", Value: id}}, bson.D{bson.E{Key: "$set", Value: bson.D{
+		bson.E{Key: "deleted_at", Value: now}}}})
+	if err != nil {
+		return err
+	}
+	return m.addEvent(ctx, ArticleEventDeleted, art, now)
+}
+
+func (m *MongoDBArticleDAO) Restore(ctx context.Context, id int64, uid int64, deletedAfter int64) error {
+	now := time.Now().UnixMilli()
+	filter := bson.D{bson.E{Key: "id", Value: id}, bson.E{Key: "author_id", Value: uid},
+		bson.E{Key: "deleted_at", Value: bson.D{bson.E{Key: "$gt", Value: deletedAfter}}}}
+	var art Article
+	err := m.col.FindOneAndUpdate(ctx, filter, bson.D{bson.E{Key: "$set", Value: bson.D{
+		bson.E{Key: "deleted_at", Value: 0}}}}).Decode(&art)
+	if err == mongo.ErrNoDocuments {
+		return ErrArticleNotInTrash
+	}
+	if err != nil {
+		return err
+	}
+	_, err = m.liveCol.UpdateOne(ctx, bson.D{bson.E{Key: "id", Value: id}}, bson.D{bson.E{Key: "$set", Value: bson.D{
+		bson.E{Key: "deleted_at", Value: 0}}}})
+	if err != nil {
+		return err
+	}
+	return m.addEvent(ctx, ArticleEventRestored, art, now)
+}
+
+func (m *MongoDBArticleDAO) ListTrash(ctx context.Context, uid int64, offset int, limit int) ([]Article, error) {
+	filter := bson.D{bson.E{Key: "author_id", Value: uid},
+		bson.E{Key: "deleted_at", Value: bson.D{bson.E{Key: "$gt", Value: 0}}}}
+	opts := options.Find().
+		SetSort(bson.D{bson.E{Key: "deleted_at", Value: -1}}).
+		SetSkip(int64(offset)).
+		SetLimit(int64(limit))
+	cursor, err := m.col.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var res []Article
+	err = cursor.All(ctx, &res)
+	return res, err
+}
+
+func (m *MongoDBArticleDAO) ListExpiredTrash(ctx context.Context, before int64, limit int) ([]Article, error) {
+	filter := bson.D{bson.E{Key: "deleted_at", Value: bson.D{
+		bson.E{Key: "$gt", Value: 0}, bson.E{Key: "$lt", Value: before}}}}
+	opts := options.Find().
+		SetSort(bson.D{bson.E{Key: "deleted_at", Value: 1}}).
+		SetLimit(int64(limit))
+	cursor, err := m.col.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var res []Article
+	err = cursor.All(ctx, &res)
+	return res, err
+}
+
+func (m *MongoDBArticleDAO) Purge(ctx context.Context, id int64, before int64) error {
+	var art Article
+	err := m.col.FindOneAndDelete(ctx, bson.D{bson.E{Key: "id", Value: id},
+		bson.E{Key: "deleted_at", Value: bson.D{
+			bson.E{Key: "$gt", Value: 0}, bson.E{Key: "$lt", Value: before}}}}).Decode(&art)
+	if err == mongo.ErrNoDocuments {
+		return ErrArticleNotInTrash
+	}
+	if err != nil {
+		return err
+	}
+	_, err = m.liveCol.DeleteOne(ctx, bson.D{bson.E{Key: "id", Value: id}})
+	if err != nil {
+		return err
+	}
+	_, err = m.revCol.DeleteMany(ctx, bson.D{bson.E{Key: "art_id", Value: id}})
+	if err != nil {
+		return err
+	}
+	return m.addEvent(ctx, ArticleEventPurged, art, time.Now().UnixMilli())
+}
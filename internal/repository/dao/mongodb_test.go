@@ -0,0 +1,76 @@
+package dao
+
+import (
+	"context"
+	"github.com/bwmarrin/snowflake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"testing"
+)
+
+func TestMongoDBArticleDAO_appendRevision(t *testing.T) {
+	lastVersion := func(ns string, version int64) bson.D {
+		return mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, bson.D{bson.E{Key: "version", Value: version}})
+	}
+	duplicate := mtest.CreateWriteErrorsResponse(mtest.WriteError{Code: 11000, Message: "duplicate key"})
+	testCases := []struct {
+		name      string
+		responses func(ns string) []bson.D
+		// wantVersion 最后一次插入的版本号
+		wantVersion int64
+		wantDup     bool
+	}{
+		{
+			name: "第一个版本",
+			responses: func(ns string) []bson.D {
+				return []bson.D{mtest.CreateCursorResponse(0, ns, mtest.FirstBatch), mtest.CreateSuccessResponse()}
+			},
+			wantVersion: 1,
+		},
+		{
+			name: "版本号冲突之后重新读",
+			responses: func(ns string) []bson.D {
+				return []bson.D{lastVersion(ns, 1), duplicate, lastVersion(ns, 2), mtest.CreateSuccessResponse()}
+			},
+			wantVersion: 3,
+		},
+		{
+			name: "一直冲突",
+			responses: func(ns string) []bson.D {
+				var res []bson.D
+				for i := int64(0); i <= appendRevisionRetries; i++ {
+					res = append(res, lastVersion(ns, i+1), duplicate)
+				}
+				return res
+			},
+			wantVersion: appendRevisionRetries + 2,
+			wantDup:     true,
+		},
+	}
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	node, err := snowflake.NewNode(1)
+	require.NoError(t, err)
+	for _, tc := range testCases {
+		mt.Run(tc.name, func(mt *mtest.T) {
+			ns := mt.Coll.Database().Name() + "." + mt.Coll.Name()
+			mt.AddMockResponses(tc.responses(ns)...)
+			m := &MongoDBArticleDAO{node: node, revCol: mt.Coll}
+			err := m.appendRevision(context.Background(), Article{Id: 1, Title: "标题"}, 123)
+			assert.Equal(mt, tc.wantDup, mongo.IsDuplicateKeyError(err))
+			if !tc.wantDup {
+				assert.NoError(mt, err)
+			}
+
+			var version int64
+			for _, evt := range mt.GetAllStartedEvents() {
+				if evt.CommandName == "insert" {
+					version = evt.Command.Lookup("documents").Array().Index(0).Value().Document().Lookup("version").Int64()
+				}
+			}
+			assert.Equal(mt, tc.wantVersion, version)
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package dao
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"time"
+)
+
+type Notification struct {
+	Id      int64  `gorm:"primaryKey,autoIncrement"`
+	Uid     int64  `gorm:"index:uid_read"`
+	Type    string `gorm:"type:varchar(64)"`
+	BizId   int64
+	Content string `gorm:"type:varchar(1024)"`
+	// ReadAt 为 0 表示还没有读，查询未读的数量要用到
+	ReadAt int64 `gorm:"index:uid_read"`
+	Ctime  int64
+	Utime  int64
+}
+
+type NotificationDAO interface {
+	Insert(ctx context.Context, n Notification) (int64, error)
+	ListByUid(ctx context.Context, uid int64, offset int, limit int) ([]Notification, error)
+	CountUnread(ctx context.Context, uid int64) (int64, error)
+	// MarkRead ids 为空表示全部标记为已读
+	MarkRead(ctx context.Context, uid int64, ids []int64) error
+}
+
+type GORMNotificationDAO struct {
+	db *gorm.DB
+}
+
+func NewGORMNotificationDAO(db *gorm.DB) NotificationDAO {
+	return &GORMNotificationDAO{
+		db: db,
+	}
+}
+
+func (g *GORMNotificationDAO) Insert(ctx context.Context, n Notification) (int64, error) {
+	now := time.Now().UnixMilli()
+	n.Ctime = now
+	n.Utime = now
+	err := g.db.WithContext(ctx).Create(&n).Error
+	return n.Id, err
+}
+
+func (g *GORMNotificationDAO) ListByUid(ctx context.Context, uid int64, offset int, limit int) ([]Notification, error) {
+	var res []Notification
+	err := g.db.WithContext(ctx).Where("uid = ?", uid).
+		Order("id DESC").Offset(offset).Limit(limit).Find(&res).Error
+	return res, err
+}
+
+func (g *GORMNotificationDAO) CountUnread(ctx context.Context, uid int64) (int64, error) {
+	var res int64
+	err := g.db.WithContext(ctx).Model(&Notification{}).
+		Where("uid = ? AND read_at = ?", uid, 0).Count(&res).Error
+	return res, err
+}
+
+func (g *GORMNotificationDAO) MarkRead(ctx context.Context, uid int64, ids []int64) error {
+	db := g.db.WithContext(ctx).Model(&Notification{}).
+		Where("uid = ? AND read_at = ?", uid, 0)
+	if len(ids) > 0 {
+		db = db.Where("id IN ?", ids)
+	}
+	now := time.Now().UnixMilli()
+	return db.Updates(map[string]any{
+		"read_at": now,
+		"utime":   now,
+	}).Error
+}
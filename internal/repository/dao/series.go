@@ -0,0 +1,160 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
+	"time"
+)
+
+var ErrSeriesArticleConflict = errors.New("文章已经在别的系列里面了")
+
+type Series struct {
+	Id          int64  `gorm:"primaryKey,autoIncrement"`
+	Title       string `gorm:"type:varchar(256)"`
+	Description string `gorm:"type:varchar(1024)"`
+	AuthorId    int64  `gorm:"index"`
+	Status      uint8
+	Ctime       int64
+	Utime       int64
+}
+
+// SeriesArticle 系列里面的文章，一篇文章最多属于一个系列
+type SeriesArticle struct {
+	Id       int64 `gorm:"primaryKey,autoIncrement"`
+	SeriesId int64 `gorm:"index:series_id_position"`
+	ArtId    int64 `gorm:"uniqueIndex"`
+	Position int   `gorm:"index:series_id_position"`
+	Ctime    int64
+}
+
+type SeriesDAO interface {
+	Insert(ctx context.Context, s Series) (int64, error)
+	UpdateById(ctx context.Context, s Series) error
+	UpdateStatus(ctx context.Context, id int64, uid int64, status uint8) error
+	GetById(ctx context.Context, id int64) (Series, error)
+	ListByAuthor(ctx context.Context, uid int64, offset int, limit int) ([]Series, error)
+	// GetByArticle 查找文章所在的系列
+	GetByArticle(ctx context.Context, artId int64) (Series, error)
+	// SetArticles 用 artIds 覆盖系列里面的文章，顺序就是 artIds 的顺序。
+	// 文章是不是存在、是不是这个作者的由调用者校验
+	SetArticles(ctx context.Context, id int64, uid int64, artIds []int64) error
+	// ListArticleIds 系列里面的文章 ID，按照在系列里面的顺序
+	ListArticleIds(ctx context.Context, id int64) ([]int64, error)
+}
+
+type GORMSeriesDAO struct {
+	db *gorm.DB
+}
+
+func NewGORMSeriesDAO(db *gorm.DB) SeriesDAO {
+	return &GORMSeriesDAO{
+		db: db,
+	}
+}
+
+func (g *GORMSeriesDAO) Insert(ctx context.Context, s Series) (int64, error) {
+	now := time.Now().UnixMilli()
+	s.Ctime = now
+	s.Utime = now
+	err := g.db.WithContext(ctx).Create(&s).Error
+	return s.Id, err
+}
+
+func (g *GORMSeriesDAO) UpdateById(ctx context.Context, s Series) error {
+	res := g.db.WithContext(ctx).Model(&Series{}).
+		Where("id = ? AND author_id = ?", s.Id, s.AuthorId).
+		Updates(map[string]any{
+			"title":       s.Title,
+			"description": s.Description,
+			"utime":       time.Now().UnixMilli(),
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return errors.New("ID不对或者创作者不对")
+	}
+	return nil
+}
+
+func (g *GORMSeriesDAO) UpdateStatus(ctx context.Context, id int64, uid int64, status uint8) error {
+	res := g.db.WithContext(ctx).Model(&Series{}).
+		Where("id = ? AND author_id = ?", id, uid).
+		Updates(map[string]any{
+			"status": status,
+			"utime":  time.Now().UnixMilli(),
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return errors.New("ID不对或者创作者不对")
+	}
+	return nil
+}
+
+func (g *GORMSeriesDAO) GetById(ctx context.Context, id int64) (Series, error) {
+	var res Series
+	err := g.db.WithContext(ctx).Where("id = ?", id).First(&res).Error
+	return res, err
+}
+
+func (g *GORMSeriesDAO) ListByAuthor(ctx context.Context, uid int64, offset int, limit int) ([]Series, error) {
+	var res []Series
+	err := g.db.WithContext(ctx).Where("author_id = ?", uid).
+		Order("utime DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&res).Error
+	return res, err
+}
+
+func (g *GORMSeriesDAO) GetByArticle(ctx context.Context, artId int64) (Series, error) {
+	var res Series
+	err := g.db.WithContext(ctx).Model(&Series{}).
+		Joins("JOIN series_articles ON series_articles.series_id = series.id").
+		Where("series_articles.art_id = ?", artId).
+		First(&res).Error
+	return res, err
+}
+
+func (g *GORMSeriesDAO) SetArticles(ctx context.Context, id int64, uid int64, artIds []int64) error {
+	now := time.Now().UnixMilli()
+	return g.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&Series{}).Where("id = ? AND author_id = ?", id, uid).
+			Update("utime", now)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return errors.New("ID不对或者创作者不对")
+		}
+		err := tx.Where("series_id = ?", id).Delete(&SeriesArticle{}).Error
+		if err != nil || len(artIds) == 0 {
+			return err
+		}
+		arts := make([]SeriesArticle, 0, len(artIds))
+		for i, artId := range artIds {
+			arts = append(arts, SeriesArticle{SeriesId: id, ArtId: artId, Position: i, Ctime: now})
+		}
+		err = tx.Create(&arts).Error
+		if me, ok := err.(*mysql.MySQLError); ok {
+			const uniqueIndexErrno uint16 = 1062
+			if me.Number == uniqueIndexErrno {
+				return ErrSeriesArticleConflict
+			}
+		}
+		return err
+	})
+}
+
+func (g *GORMSeriesDAO) ListArticleIds(ctx context.Context, id int64) ([]int64, error) {
+	var res []int64
+	err := g.db.WithContext(ctx).Model(&SeriesArticle{}).
+		Where("series_id = ?", id).
+		Order("position ASC").
+		Pluck("art_id", &res).Error
+	return res, err
+}
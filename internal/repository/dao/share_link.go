@@ -0,0 +1,98 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"time"
+)
+
+var ErrShareLinkExhausted = errors.New("分享链接的访问次数已经用完")
+
+// ShareLink 文章的分享链接，让别人可以看到草稿或者仅自己可见的文章
+type ShareLink struct {
+	Id       int64 `gorm:"primaryKey,autoIncrement"`
+	ArtId    int64 `gorm:"index"`
+	AuthorId int64
+	// Nonce 链接里面的随机部分，签名不落库
+	Nonce    string `gorm:"type:varchar(64);uniqueIndex"`
+	ExpireAt int64
+	// MaxViews 为 0 表示不限制访问次数
+	MaxViews int64
+	Views    int64
+	Revoked  bool
+	Ctime    int64
+	Utime    int64
+}
+
+type ShareLinkDAO interface {
+	Insert(ctx context.Context, link ShareLink) (int64, error)
+	GetByNonce(ctx context.Context, nonce string) (ShareLink, error)
+	ListByArticle(ctx context.Context, artId int64, uid int64) ([]ShareLink, error)
+	Revoke(ctx context.Context, id int64, uid int64) error
+	// IncrViews 访问次数加一，超过 MaxViews 的时候返回 ErrShareLinkExhausted
+	IncrViews(ctx context.Context, id int64) error
+}
+
+type GORMShareLinkDAO struct {
+	db *gorm.DB
+}
+
+func NewGORMShareLinkDAO(db *gorm.DB) ShareLinkDAO {
+	return &GORMShareLinkDAO{
+		db: db,
+	}
+}
+
+func (g *GORMShareLinkDAO) Insert(ctx context.Context, link ShareLink) (int64, error) {
+	now := time.Now().UnixMilli()
+	link.Ctime = now
+	link.Utime = now
+	err := g.db.WithContext(ctx).Create(&link).Error
+	return link.Id, err
+}
+
+func (g *GORMShareLinkDAO) GetByNonce(ctx context.Context, nonce string) (ShareLink, error) {
+	var res ShareLink
+	err := g.db.WithContext(ctx).Where("nonce = ?", nonce).First(&res).Error
+	return res, err
+}
+
+func (g *GORMShareLinkDAO) ListByArticle(ctx context.Context, artId int64, uid int64) ([]ShareLink, error) {
+	var res []ShareLink
+	err := g.db.WithContext(ctx).Where("art_id = ? AND author_id = ?", artId, uid).
+		Order("id DESC").Find(&res).Error
+	return res, err
+}
+
+func (g *GORMShareLinkDAO) Revoke(ctx context.Context, id int64, uid int64) error {
+	res := g.db.WithContext(ctx).Model(&ShareLink{}).Where("id = ? AND author_id = ?", id, uid).
+		Updates(map[string]any{
+			"revoked": true,
+			"utime":   time.Now().UnixMilli(),
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return errors.New("ID不对或者创作者不对")
+	}
+	return nil
+}
+
+func (g *GORMShareLinkDAO) IncrViews(ctx context.Context, id int64) error {
+	// 判断和加一放在一条语句里面，并发访问也不会超过上限
+	res := g.db.WithContext(ctx).Model(&ShareLink{}).
+		Where("id = ? AND (max_views = 0 OR views < max_views)", id).
+		Updates(map[string]any{
+			"views": gorm.Expr("views + 1"),
+			"utime": time.Now().UnixMilli(),
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrShareLinkExhausted
+	}
+	return nil
+}
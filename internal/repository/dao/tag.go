@@ -0,0 +1,151 @@
+package dao
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type Tag struct {
+	Id    int64  `gorm:"primaryKey,autoIncrement"`
+	Name  string `gorm:"type:varchar(64);uniqueIndex"`
+	Ctime int64
+	Utime int64
+}
+
+// ArticleTag 文章和标签的多对多关系
+type ArticleTag struct {
+	Id    int64 `gorm:"primaryKey,autoIncrement"`
+	ArtId int64 `gorm:"uniqueIndex:art_id_tag_id"`
+	// 按照标签查询文章
+	TagId int64 `gorm:"uniqueIndex:art_id_tag_id;index"`
+	Ctime int64
+}
+
+// PublishedArticleTag 线上库的文章标签，只在发表的时候从制作库复制过来，
+// 这样修改草稿的标签不会影响已经发表的版本
+type PublishedArticleTag ArticleTag
+
+type TagDAO interface {
+	// SetArticleTags 只修改制作库的标签
+	SetArticleTags(ctx context.Context, artId int64, names []string) error
+	GetArticleTags(ctx context.Context, artId int64) ([]Tag, error)
+	// PublishArticleTags names 不为 nil 的时候先修改制作库的标签，然后复制到线上库。
+	// GORM 的 ArticleDAO 在 Sync 的事务里面做了同样的事情，这个是给其它存储用的
+	PublishArticleTags(ctx context.Context, artId int64, names []string) error
+	GetPubArticleTags(ctx context.Context, artId int64) ([]Tag, error)
+	ListTags(ctx context.Context, offset int, limit int) ([]Tag, error)
+	// ListPubArticleIds 标签下面最近发表的 limit 篇文章的 ID。
+	// 文章可能存在 MongoDB 里面，这里只查 ID，文章交给 ArticleDAO 查
+	ListPubArticleIds(ctx context.Context, name string, limit int) ([]int64, error)
+}
+
+type GORMTagDAO struct {
+	db *gorm.DB
+}
+
+func NewGORMTagDAO(db *gorm.DB) TagDAO {
+	return &GORMTagDAO{
+		db: db,
+	}
+}
+
+func (g *GORMTagDAO) SetArticleTags(ctx context.Context, artId int64, names []string) error {
+	return g.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return setArticleTags(tx, artId, names, time.Now().UnixMilli())
+	})
+}
+
+func (g *GORMTagDAO) PublishArticleTags(ctx context.Context, artId int64, names []string) error {
+	return g.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return syncArticleTags(tx, artId, names, time.Now().UnixMilli())
+	})
+}
+
+// setArticleTags 用 names 覆盖文章原本的标签，不存在的标签会被创建
+func setArticleTags(tx *gorm.DB, artId int64, names []string, now int64) error {
+	if len(names) == 0 {
+		return tx.Where("art_id=?", artId).Delete(&ArticleTag{}).Error
+	}
+	tags := make([]Tag, 0, len(names))
+	for _, name := range names {
+		tags = append(tags, Tag{Name: name, Ctime: now, Utime: now})
+	}
+	err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&tags).Error
+	if err != nil {
+		return err
+	}
+	var tagIds []int64
+	err = tx.Model(&Tag{}).Where("name IN ?", names).Pluck("id", &tagIds).Error
+	if err != nil {
+		return err
+	}
+	err = tx.Where("art_id=? AND tag_id NOT IN ?", artId, tagIds).Delete(&ArticleTag{}).Error
+	if err != nil {
+		return err
+	}
+	artTags := make([]ArticleTag, 0, len(tagIds))
+	for _, tagId := range tagIds {
+		artTags = append(artTags, ArticleTag{ArtId: artId, TagId: tagId, Ctime: now})
+	}
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&artTags).Error
+}
+
+// syncArticleTags names 为 nil 说明这一次没有修改标签，只把制作库的标签复制到线上库
+func syncArticleTags(tx *gorm.DB, artId int64, names []string, now int64) error {
+	if names != nil {
+		err := setArticleTags(tx, artId, names, now)
+		if err != nil {
+			return err
+		}
+	}
+	err := tx.Where("art_id=?", artId).Delete(&PublishedArticleTag{}).Error
+	if err != nil {
+		return err
+	}
+	return tx.Exec("INSERT INTO published_article_tags (art_id, tag_id, ctime) "+
+		"SELECT art_id, tag_id, ? FROM article_tags WHERE art_id = ? ORDER BY id", now, artId).Error
+}
+
+func (g *GORMTagDAO) GetArticleTags(ctx context.Context, artId int64) ([]Tag, error) {
+	var res []Tag
+	err := g.db.WithContext(ctx).Model(&Tag{}).
+		Joins("JOIN article_tags ON article_tags.tag_id = tags.id").
+		Where("article_tags.art_id = ?", artId).
+		Order("article_tags.id ASC").
+		Find(&res).Error
+	return res, err
+}
+
+func (g *GORMTagDAO) GetPubArticleTags(ctx context.Context, artId int64) ([]Tag, error) {
+	var res []Tag
+	err := g.db.WithContext(ctx).Model(&Tag{}).
+		Joins("JOIN published_article_tags ON published_article_tags.tag_id = tags.id").
+		Where("published_article_tags.art_id = ?", artId).
+		Order("published_article_tags.id ASC").
+		Find(&res).Error
+	return res, err
+}
+
+func (g *GORMTagDAO) ListTags(ctx context.Context, offset int, limit int) ([]Tag, error) {
+	var res []Tag
+	err := g.db.WithContext(ctx).
+		Order("id ASC").
+		Offset(offset).
+		Limit(limit).
+		Find(&res).Error
+	return res, err
+}
+
+func (g *GORMTagDAO) ListPubArticleIds(ctx context.Context, name string, limit int) ([]int64, error) {
+	var res []int64
+	// 每次发表都会重新复制标签，所以 ID 越大发表得越晚
+	err := g.db.WithContext(ctx).Model(&PublishedArticleTag{}).
+		Joins("JOIN tags ON tags.id = published_article_tags.tag_id").
+		Where("tags.name = ?", name).
+		Order("published_article_tags.id DESC").
+		Limit(limit).
+		Pluck("published_article_tags.art_id", &res).Error
+	return res, err
+}
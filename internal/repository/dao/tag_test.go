@@ -0,0 +1,169 @@
+package dao
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"regexp"
+	"testing"
+)
+
+func TestGORMTagDAO_PublishArticleTags(t *testing.T) {
+	testCases := []struct {
+		name    string
+		mock    func(mock sqlmock.Sqlmock)
+		names   []string
+		wantErr error
+	}{
+		{
+			name: "修改标签之后发表",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `tags`")).
+					WithArgs("go", sqlmock.AnyArg(), sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT `id` FROM `tags` WHERE name IN (?)")).
+					WithArgs("go").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `article_tags` WHERE art_id=? AND tag_id NOT IN (?)")).
+					WithArgs(int64(3), int64(1)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `article_tags`")).
+					WithArgs(int64(3), int64(1), sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				expectPublishTags(mock, nil)
+				mock.ExpectCommit()
+			},
+			names: []string{"go"},
+		},
+		{
+			name: "没有修改标签，只复制到线上库",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectPublishTags(mock, nil)
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "复制失败回滚",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectPublishTags(mock, errors.New("mock db error"))
+				mock.ExpectRollback()
+			},
+			wantErr: errors.New("mock db error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock := newSQLMockDB(t)
+			tc.mock(mock)
+			err := NewGORMTagDAO(db).PublishArticleTags(context.Background(), 3, tc.names)
+			assert.Equal(t, tc.wantErr, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// TestGORMTagDAO_SetArticleTags 修改草稿的标签不能碰线上库的标签
+func TestGORMTagDAO_SetArticleTags(t *testing.T) {
+	db, mock := newSQLMockDB(t)
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `article_tags` WHERE art_id=?")).
+		WithArgs(int64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+	err := NewGORMTagDAO(db).SetArticleTags(context.Background(), 3, []string{})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGORMTagDAO_ListPubArticleIds(t *testing.T) {
+	db, mock := newSQLMockDB(t)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT `published_article_tags`.`art_id` FROM `published_article_tags` " +
+		"JOIN tags ON tags.id = published_article_tags.tag_id " +
+		"WHERE tags.name = ? " +
+		"ORDER BY published_article_tags.id DESC LIMIT 10")).
+		WithArgs("go").
+		WillReturnRows(sqlmock.NewRows([]string{"art_id"}).AddRow(4).AddRow(3))
+	ids, err := NewGORMTagDAO(db).ListPubArticleIds(context.Background(), "go", 10)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{4, 3}, ids)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestArticleGORMDAO_ListPubByIds(t *testing.T) {
+	db, mock := newSQLMockDB(t)
+	mock.ExpectQuery(regexp.QuoteMeta("FROM `published_articles` "+
+		"WHERE id IN (?,?) AND status = ? AND deleted_at = 0 "+
+		"ORDER BY utime DESC")).
+		WithArgs(int64(4), int64(3), 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "author_id", "status"}).
+			AddRow(3, "标题", 1, 2))
+	arts, err := NewArticleGORMDAO(db).ListPubByIds(context.Background(), []int64{4, 3}, 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []PublishedArticle{{Id: 3, Title: "标题", AuthorId: 1, Status: 2}}, arts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestArticleGORMDAO_Sync_TagsInTransaction 标签写失败的时候文章也不能发表
+func TestArticleGORMDAO_Sync_TagsInTransaction(t *testing.T) {
+	db, mock := newSQLMockDB(t)
+	mock.ExpectBegin()
+	mock.ExpectExec("SAVEPOINT").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `articles`")).WithArgs(anyArgs(8)...).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COALESCE(MAX(version), 0) FROM `article_revisions`")).
+		WithArgs(int64(3)).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `article_revisions`")).WithArgs(anyArgs(7)...).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `article_events`")).WithArgs(anyArgs(8)...).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `published_articles`")).WithArgs(anyArgs(17)...).WillReturnResult(sqlmock.NewResult(3, 1))
+	expectPublishTags(mock, errors.New("mock db error"))
+	mock.ExpectRollback()
+
+	_, err := NewArticleGORMDAO(db).Sync(context.Background(), Article{Id: 3, Title: "标题", AuthorId: 1, Status: 2}, nil)
+	assert.Equal(t, errors.New("mock db error"), err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// expectPublishTags 用制作库的标签覆盖线上库的标签，err 不为 nil 的时候复制失败
+func expectPublishTags(mock sqlmock.Sqlmock, err error) {
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `published_article_tags` WHERE art_id=?")).
+		WithArgs(int64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	exec := mock.ExpectExec(regexp.QuoteMeta("INSERT INTO published_article_tags (art_id, tag_id, ctime) "+
+		"SELECT art_id, tag_id, ? FROM article_tags WHERE art_id = ? ORDER BY id")).
+		WithArgs(sqlmock.AnyArg(), int64(3))
+	if err != nil {
+		exec.WillReturnError(err)
+		return
+	}
+	exec.WillReturnResult(sqlmock.NewResult(0, 1))
+}
+
+func anyArgs(n int) []driver.Value {
+	res := make([]driver.Value, n)
+	for i := range res {
+		res[i] = sqlmock.AnyArg()
+	}
+	return res
+}
+
+func newSQLMockDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	db, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      sqlDB,
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+	})
+	require.NoError(t, err)
+	return db, mock
+}
@@ -21,6 +21,8 @@ type UserDAO interface {
 	FindById(ctx context.Context, id int64) (User, error)
 	FindByPhone(ctx context.Context, phone string) (User, error)
 	FindByWechat(ctx context.Context, openId string) (User, error)
+	// UpdatePassword password 是加密之后的密码
+	UpdatePassword(ctx context.Context, id int64, password string) error
 }
 
 type GORMUserDAO struct {
@@ -56,6 +58,20 @@ func (dao *GORMUserDAO) Update(ctx context.Context, u User) error {
 	return err
 }
 
+func (dao *GORMUserDAO) UpdatePassword(ctx context.Context, id int64, password string) error {
+	res := dao.db.WithContext(ctx).Model(&User{}).Where("id = ?", id).Updates(map[string]any{
+		"password": password,
+		"utime":    time.Now().UnixMilli(),
+	})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+	return nil
+}
+
 func (dao *GORMUserDAO) FindById(ctx context.Context, id int64) (User, error) {
 	var res User
 	err := dao.db.WithContext(ctx).Where("id=?", id).First(&res).Error
@@ -0,0 +1,150 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+var ErrMFAStepUsed = errors.New("验证码已经使用过了")
+
+// UserMFA 每个用户一条。密钥要能还原出来计算验证码，所以没办法只存哈希
+type UserMFA struct {
+	Id     int64  `gorm:"primaryKey,autoIncrement"`
+	Uid    int64  `gorm:"uniqueIndex"`
+	Secret string `gorm:"type:varchar(64)"`
+	Status uint8
+	// LastStep 同一个时间片的验证码只能用一次
+	LastStep int64
+	Ctime    int64
+	Utime    int64
+}
+
+// UserRecoveryCode 恢复码只保存哈希，每个只能用一次
+type UserRecoveryCode struct {
+	Id       int64  `gorm:"primaryKey,autoIncrement"`
+	Uid      int64  `gorm:"uniqueIndex:uid_code_hash"`
+	CodeHash string `gorm:"type:char(64);uniqueIndex:uid_code_hash"`
+	// UsedAt 0 表示还没有使用
+	UsedAt int64
+	Ctime  int64
+}
+
+type UserMFADAO interface {
+	// UpsertPending 重新生成密钥，已经启用的不能调用
+	UpsertPending(ctx context.Context, uid int64, secret string) error
+	GetByUid(ctx context.Context, uid int64) (UserMFA, error)
+	// Enable 启用两步验证，同时替换全部恢复码
+	Enable(ctx context.Context, uid int64, step int64, codeHashes []string) error
+	// UseStep step 不大于上一次使用的时间片的时候返回 ErrMFAStepUsed
+	UseStep(ctx context.Context, uid int64, step int64) error
+	// UseRecoveryCode 恢复码不存在或者已经用过的时候返回 ErrRecordNotFound
+	UseRecoveryCode(ctx context.Context, uid int64, codeHash string) error
+	Delete(ctx context.Context, uid int64) error
+}
+
+type GORMUserMFADAO struct {
+	db *gorm.DB
+}
+
+func NewGORMUserMFADAO(db *gorm.DB) UserMFADAO {
+	return &GORMUserMFADAO{db: db}
+}
+
+func (g *GORMUserMFADAO) UpsertPending(ctx context.Context, uid int64, secret string) error {
+	now := time.Now().UnixMilli()
+	const statusPending = 1
+	return g.db.WithContext(ctx).Clauses(clause.OnConflict{
+		DoUpdates: clause.Assignments(map[string]any{
+			"secret":    secret,
+			"status":    statusPending,
+			"last_step": 0,
+			"utime":     now,
+		}),
+	}).Create(&UserMFA{
+		Uid:    uid,
+		Secret: secret,
+		Status: statusPending,
+		Ctime:  now,
+		Utime:  now,
+	}).Error
+}
+
+func (g *GORMUserMFADAO) GetByUid(ctx context.Context, uid int64) (UserMFA, error) {
+	var res UserMFA
+	err := g.db.WithContext(ctx).Where("uid = ?", uid).First(&res).Error
+	return res, err
+}
+
+func (g *GORMUserMFADAO) Enable(ctx context.Context, uid int64, step int64, codeHashes []string) error {
+	now := time.Now().UnixMilli()
+	const (
+		statusPending = 1
+		statusEnabled = 2
+	)
+	return g.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&UserMFA{}).
+			Where("uid = ? AND status = ? AND last_step < ?", uid, statusPending, step).
+			Updates(map[string]any{
+				"status":    statusEnabled,
+				"last_step": step,
+				"utime":     now,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrRecordNotFound
+		}
+		err := tx.Where("uid = ?", uid).Delete(&UserRecoveryCode{}).Error
+		if err != nil {
+			return err
+		}
+		codes := make([]UserRecoveryCode, 0, len(codeHashes))
+		for _, h := range codeHashes {
+			codes = append(codes, UserRecoveryCode{Uid: uid, CodeHash: h, Ctime: now})
+		}
+		return tx.Create(&codes).Error
+	})
+}
+
+func (g *GORMUserMFADAO) UseStep(ctx context.Context, uid int64, step int64) error {
+	res := g.db.WithContext(ctx).Model(&UserMFA{}).
+		Where("uid = ? AND last_step < ?", uid, step).
+		Updates(map[string]any{
+			"last_step": step,
+			"utime":     time.Now().UnixMilli(),
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrMFAStepUsed
+	}
+	return nil
+}
+
+func (g *GORMUserMFADAO) UseRecoveryCode(ctx context.Context, uid int64, codeHash string) error {
+	res := g.db.WithContext(ctx).Model(&UserRecoveryCode{}).
+		Where("uid = ? AND code_hash = ? AND used_at = 0", uid, codeHash).
+		Update("used_at", time.Now().UnixMilli())
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+	return nil
+}
+
+func (g *GORMUserMFADAO) Delete(ctx context.Context, uid int64) error {
+	return g.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("uid = ?", uid).Delete(&UserRecoveryCode{}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Where("uid = ?", uid).Delete(&UserMFA{}).Error
+	})
+}
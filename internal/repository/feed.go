@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"context"
+	"webook/internal/domain"
+	"webook/internal/repository/cache"
+)
+
+type FeedRepository interface {
+	// Get 缓存里面没有的时候返回 error
+	Get(ctx context.Context, key string) (domain.Feed, error)
+	Set(ctx context.Context, key string, feed domain.Feed) error
+	Del(ctx context.Context, keys ...string) error
+}
+
+type CachedFeedRepository struct {
+	cache cache.FeedCache
+}
+
+func NewCachedFeedRepository(cache cache.FeedCache) FeedRepository {
+	return &CachedFeedRepository{cache: cache}
+}
+
+func (c *CachedFeedRepository) Get(ctx context.Context, key string) (domain.Feed, error) {
+	return c.cache.Get(ctx, key)
+}
+
+func (c *CachedFeedRepository) Set(ctx context.Context, key string, feed domain.Feed) error {
+	return c.cache.Set(ctx, key, feed)
+}
+
+func (c *CachedFeedRepository) Del(ctx context.Context, keys ...string) error {
+	return c.cache.Del(ctx, keys...)
+}
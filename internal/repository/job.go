@@ -12,12 +12,26 @@ type CronJobRepository interface {
 	ResetNextTime(ctx context.Context, j domain.Job, t time.Time) error
 	UpdateUtime(ctx context.Context, id int64) error
 	Release(ctx context.Context, id int64) error
+	AddJob(ctx context.Context, j domain.Job) error
 }
 
 type PreemptJobRepository struct {
 	dao dao.JobDAO
 }
 
+func NewPreemptJobRepository(dao dao.JobDAO) CronJobRepository {
+	return &PreemptJobRepository{dao: dao}
+}
+
+func (p *PreemptJobRepository) AddJob(ctx context.Context, j domain.Job) error {
+	return p.dao.Insert(ctx, dao.Job{
+		Name:       j.Name,
+		Executor:   j.Executor,
+		Expression: j.Expression,
+		NextTime:   j.NextTime().UnixMilli(),
+	})
+}
+
 func (p *PreemptJobRepository) Release(ctx context.Context, id int64) error {
 	return p.dao.Release(ctx, id)
 }
@@ -0,0 +1,144 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/repository/archive.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/repository/archive.go -package=repomocks -destination=./internal/repository/mocks/archive.mock.go
+//
+// Package repomocks is a generated GoMock package.
+package repomocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+	domain "webook/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockArchiveTaskRepository is a mock of ArchiveTaskRepository interface.
+type MockArchiveTaskRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockArchiveTaskRepositoryMockRecorder
+}
+
+// MockArchiveTaskRepositoryMockRecorder is the mock recorder for MockArchiveTaskRepository.
+type MockArchiveTaskRepositoryMockRecorder struct {
+	mock *MockArchiveTaskRepository
+}
+
+// NewMockArchiveTaskRepository creates a new mock instance.
+func NewMockArchiveTaskRepository(ctrl *gomock.Controller) *MockArchiveTaskRepository {
+	mock := &MockArchiveTaskRepository{ctrl: ctrl}
+	mock.recorder = &MockArchiveTaskRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockArchiveTaskRepository) EXPECT() *MockArchiveTaskRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ClearBlob mocks base method.
+func (m *MockArchiveTaskRepository) ClearBlob(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClearBlob", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ClearBlob indicates an expected call of ClearBlob.
+func (mr *MockArchiveTaskRepositoryMockRecorder) ClearBlob(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearBlob", reflect.TypeOf((*MockArchiveTaskRepository)(nil).ClearBlob), ctx, id)
+}
+
+// Create mocks base method.
+func (m *MockArchiveTaskRepository) Create(ctx context.Context, t domain.ArchiveTask) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, t)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockArchiveTaskRepositoryMockRecorder) Create(ctx, t any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockArchiveTaskRepository)(nil).Create), ctx, t)
+}
+
+// FindById mocks base method.
+func (m *MockArchiveTaskRepository) FindById(ctx context.Context, id int64) (domain.ArchiveTask, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindById", ctx, id)
+	ret0, _ := ret[0].(domain.ArchiveTask)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindById indicates an expected call of FindById.
+func (mr *MockArchiveTaskRepositoryMockRecorder) FindById(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindById", reflect.TypeOf((*MockArchiveTaskRepository)(nil).FindById), ctx, id)
+}
+
+// Finish mocks base method.
+func (m *MockArchiveTaskRepository) Finish(ctx context.Context, t domain.ArchiveTask) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Finish", ctx, t)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Finish indicates an expected call of Finish.
+func (mr *MockArchiveTaskRepositoryMockRecorder) Finish(ctx, t any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Finish", reflect.TypeOf((*MockArchiveTaskRepository)(nil).Finish), ctx, t)
+}
+
+// ListByUid mocks base method.
+func (m *MockArchiveTaskRepository) ListByUid(ctx context.Context, uid int64, offset, limit int) ([]domain.ArchiveTask, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByUid", ctx, uid, offset, limit)
+	ret0, _ := ret[0].([]domain.ArchiveTask)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByUid indicates an expected call of ListByUid.
+func (mr *MockArchiveTaskRepositoryMockRecorder) ListByUid(ctx, uid, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUid", reflect.TypeOf((*MockArchiveTaskRepository)(nil).ListByUid), ctx, uid, offset, limit)
+}
+
+// ListExpired mocks base method.
+func (m *MockArchiveTaskRepository) ListExpired(ctx context.Context, before time.Time, limit int) ([]domain.ArchiveTask, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExpired", ctx, before, limit)
+	ret0, _ := ret[0].([]domain.ArchiveTask)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExpired indicates an expected call of ListExpired.
+func (mr *MockArchiveTaskRepositoryMockRecorder) ListExpired(ctx, before, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExpired", reflect.TypeOf((*MockArchiveTaskRepository)(nil).ListExpired), ctx, before, limit)
+}
+
+// Preempt mocks base method.
+func (m *MockArchiveTaskRepository) Preempt(ctx context.Context, timeout time.Duration) (domain.ArchiveTask, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Preempt", ctx, timeout)
+	ret0, _ := ret[0].(domain.ArchiveTask)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Preempt indicates an expected call of Preempt.
+func (mr *MockArchiveTaskRepositoryMockRecorder) Preempt(ctx, timeout any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Preempt", reflect.TypeOf((*MockArchiveTaskRepository)(nil).Preempt), ctx, timeout)
+}
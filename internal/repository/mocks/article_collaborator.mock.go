@@ -0,0 +1,142 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/repository/article_collaborator.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/repository/article_collaborator.go -package=repomocks -destination=./internal/repository/mocks/article_collaborator.mock.go
+//
+// Package repomocks is a generated GoMock package.
+package repomocks
+
+import (
+	context "context"
+	reflect "reflect"
+	domain "webook/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockArticleCollaboratorRepository is a mock of ArticleCollaboratorRepository interface.
+type MockArticleCollaboratorRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockArticleCollaboratorRepositoryMockRecorder
+}
+
+// MockArticleCollaboratorRepositoryMockRecorder is the mock recorder for MockArticleCollaboratorRepository.
+type MockArticleCollaboratorRepositoryMockRecorder struct {
+	mock *MockArticleCollaboratorRepository
+}
+
+// NewMockArticleCollaboratorRepository creates a new mock instance.
+func NewMockArticleCollaboratorRepository(ctrl *gomock.Controller) *MockArticleCollaboratorRepository {
+	mock := &MockArticleCollaboratorRepository{ctrl: ctrl}
+	mock.recorder = &MockArticleCollaboratorRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockArticleCollaboratorRepository) EXPECT() *MockArticleCollaboratorRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddAudit mocks base method.
+func (m *MockArticleCollaboratorRepository) AddAudit(ctx context.Context, a domain.ArticleAudit) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddAudit", ctx, a)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddAudit indicates an expected call of AddAudit.
+func (mr *MockArticleCollaboratorRepositoryMockRecorder) AddAudit(ctx, a any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAudit", reflect.TypeOf((*MockArticleCollaboratorRepository)(nil).AddAudit), ctx, a)
+}
+
+// Delete mocks base method.
+func (m *MockArticleCollaboratorRepository) Delete(ctx context.Context, artId, uid int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, artId, uid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockArticleCollaboratorRepositoryMockRecorder) Delete(ctx, artId, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockArticleCollaboratorRepository)(nil).Delete), ctx, artId, uid)
+}
+
+// GetRole mocks base method.
+func (m *MockArticleCollaboratorRepository) GetRole(ctx context.Context, artId, uid int64) (domain.ArticleRole, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRole", ctx, artId, uid)
+	ret0, _ := ret[0].(domain.ArticleRole)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRole indicates an expected call of GetRole.
+func (mr *MockArticleCollaboratorRepositoryMockRecorder) GetRole(ctx, artId, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRole", reflect.TypeOf((*MockArticleCollaboratorRepository)(nil).GetRole), ctx, artId, uid)
+}
+
+// List mocks base method.
+func (m *MockArticleCollaboratorRepository) List(ctx context.Context, artId int64) ([]domain.Collaborator, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, artId)
+	ret0, _ := ret[0].([]domain.Collaborator)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockArticleCollaboratorRepositoryMockRecorder) List(ctx, artId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockArticleCollaboratorRepository)(nil).List), ctx, artId)
+}
+
+// ListArticles mocks base method.
+func (m *MockArticleCollaboratorRepository) ListArticles(ctx context.Context, uid int64, offset, limit int) ([]domain.Article, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListArticles", ctx, uid, offset, limit)
+	ret0, _ := ret[0].([]domain.Article)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListArticles indicates an expected call of ListArticles.
+func (mr *MockArticleCollaboratorRepositoryMockRecorder) ListArticles(ctx, uid, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListArticles", reflect.TypeOf((*MockArticleCollaboratorRepository)(nil).ListArticles), ctx, uid, offset, limit)
+}
+
+// ListAudits mocks base method.
+func (m *MockArticleCollaboratorRepository) ListAudits(ctx context.Context, artId int64, offset, limit int) ([]domain.ArticleAudit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAudits", ctx, artId, offset, limit)
+	ret0, _ := ret[0].([]domain.ArticleAudit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAudits indicates an expected call of ListAudits.
+func (mr *MockArticleCollaboratorRepositoryMockRecorder) ListAudits(ctx, artId, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAudits", reflect.TypeOf((*MockArticleCollaboratorRepository)(nil).ListAudits), ctx, artId, offset, limit)
+}
+
+// Save mocks base method.
+func (m *MockArticleCollaboratorRepository) Save(ctx context.Context, artId int64, c domain.Collaborator) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, artId, c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockArticleCollaboratorRepositoryMockRecorder) Save(ctx, artId, c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockArticleCollaboratorRepository)(nil).Save), ctx, artId, c)
+}
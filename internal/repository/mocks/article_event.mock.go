@@ -0,0 +1,99 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/repository/article_event.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/repository/article_event.go -package=repomocks -destination=./internal/repository/mocks/article_event.mock.go
+//
+// Package repomocks is a generated GoMock package.
+package repomocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+	domain "webook/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockArticleEventRepository is a mock of ArticleEventRepository interface.
+type MockArticleEventRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockArticleEventRepositoryMockRecorder
+}
+
+// MockArticleEventRepositoryMockRecorder is the mock recorder for MockArticleEventRepository.
+type MockArticleEventRepositoryMockRecorder struct {
+	mock *MockArticleEventRepository
+}
+
+// NewMockArticleEventRepository creates a new mock instance.
+func NewMockArticleEventRepository(ctrl *gomock.Controller) *MockArticleEventRepository {
+	mock := &MockArticleEventRepository{ctrl: ctrl}
+	mock.recorder = &MockArticleEventRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockArticleEventRepository) EXPECT() *MockArticleEventRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteSent mocks base method.
+func (m *MockArticleEventRepository) DeleteSent(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSent", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteSent indicates an expected call of DeleteSent.
+func (mr *MockArticleEventRepositoryMockRecorder) DeleteSent(ctx, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSent", reflect.TypeOf((*MockArticleEventRepository)(nil).DeleteSent), ctx, before)
+}
+
+// ListPending mocks base method.
+func (m *MockArticleEventRepository) ListPending(ctx context.Context, limit int) ([]domain.ArticleEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPending", ctx, limit)
+	ret0, _ := ret[0].([]domain.ArticleEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPending indicates an expected call of ListPending.
+func (mr *MockArticleEventRepositoryMockRecorder) ListPending(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPending", reflect.TypeOf((*MockArticleEventRepository)(nil).ListPending), ctx, limit)
+}
+
+// MarkFailed mocks base method.
+func (m *MockArticleEventRepository) MarkFailed(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkFailed", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkFailed indicates an expected call of MarkFailed.
+func (mr *MockArticleEventRepositoryMockRecorder) MarkFailed(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkFailed", reflect.TypeOf((*MockArticleEventRepository)(nil).MarkFailed), ctx, id)
+}
+
+// MarkSent mocks base method.
+func (m *MockArticleEventRepository) MarkSent(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkSent", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkSent indicates an expected call of MarkSent.
+func (mr *MockArticleEventRepositoryMockRecorder) MarkSent(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkSent", reflect.TypeOf((*MockArticleEventRepository)(nil).MarkSent), ctx, id)
+}
@@ -0,0 +1,114 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/repository/blob.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/repository/blob.go -package=repomocks -destination=./internal/repository/mocks/blob.mock.go
+//
+// Package repomocks is a generated GoMock package.
+package repomocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+	domain "webook/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockBlobRepository is a mock of BlobRepository interface.
+type MockBlobRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockBlobRepositoryMockRecorder
+}
+
+// MockBlobRepositoryMockRecorder is the mock recorder for MockBlobRepository.
+type MockBlobRepositoryMockRecorder struct {
+	mock *MockBlobRepository
+}
+
+// NewMockBlobRepository creates a new mock instance.
+func NewMockBlobRepository(ctrl *gomock.Controller) *MockBlobRepository {
+	mock := &MockBlobRepository{ctrl: ctrl}
+	mock.recorder = &MockBlobRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBlobRepository) EXPECT() *MockBlobRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockBlobRepository) Delete(ctx context.Context, id int64, before time.Time, fn func() error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id, before, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockBlobRepositoryMockRecorder) Delete(ctx, id, before, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockBlobRepository)(nil).Delete), ctx, id, before, fn)
+}
+
+// FindByHash mocks base method.
+func (m *MockBlobRepository) FindByHash(ctx context.Context, hash string) (domain.Blob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByHash", ctx, hash)
+	ret0, _ := ret[0].(domain.Blob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByHash indicates an expected call of FindByHash.
+func (mr *MockBlobRepositoryMockRecorder) FindByHash(ctx, hash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByHash", reflect.TypeOf((*MockBlobRepository)(nil).FindByHash), ctx, hash)
+}
+
+// ListUnreferenced mocks base method.
+func (m *MockBlobRepository) ListUnreferenced(ctx context.Context, before time.Time, limit int) ([]domain.Blob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUnreferenced", ctx, before, limit)
+	ret0, _ := ret[0].([]domain.Blob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUnreferenced indicates an expected call of ListUnreferenced.
+func (mr *MockBlobRepositoryMockRecorder) ListUnreferenced(ctx, before, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUnreferenced", reflect.TypeOf((*MockBlobRepository)(nil).ListUnreferenced), ctx, before, limit)
+}
+
+// Save mocks base method.
+func (m *MockBlobRepository) Save(ctx context.Context, b domain.Blob) (domain.Blob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, b)
+	ret0, _ := ret[0].(domain.Blob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockBlobRepositoryMockRecorder) Save(ctx, b any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockBlobRepository)(nil).Save), ctx, b)
+}
+
+// SetArticleBlobs mocks base method.
+func (m *MockBlobRepository) SetArticleBlobs(ctx context.Context, artId int64, hashes []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetArticleBlobs", ctx, artId, hashes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetArticleBlobs indicates an expected call of SetArticleBlobs.
+func (mr *MockBlobRepositoryMockRecorder) SetArticleBlobs(ctx, artId, hashes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetArticleBlobs", reflect.TypeOf((*MockBlobRepository)(nil).SetArticleBlobs), ctx, artId, hashes)
+}
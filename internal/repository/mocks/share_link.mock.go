@@ -0,0 +1,113 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/repository/share_link.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/repository/share_link.go -package=repomocks -destination=./internal/repository/mocks/share_link.mock.go
+//
+// Package repomocks is a generated GoMock package.
+package repomocks
+
+import (
+	context "context"
+	reflect "reflect"
+	domain "webook/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockShareLinkRepository is a mock of ShareLinkRepository interface.
+type MockShareLinkRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockShareLinkRepositoryMockRecorder
+}
+
+// MockShareLinkRepositoryMockRecorder is the mock recorder for MockShareLinkRepository.
+type MockShareLinkRepositoryMockRecorder struct {
+	mock *MockShareLinkRepository
+}
+
+// NewMockShareLinkRepository creates a new mock instance.
+func NewMockShareLinkRepository(ctrl *gomock.Controller) *MockShareLinkRepository {
+	mock := &MockShareLinkRepository{ctrl: ctrl}
+	mock.recorder = &MockShareLinkRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockShareLinkRepository) EXPECT() *MockShareLinkRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockShareLinkRepository) Create(ctx context.Context, link domain.ShareLink) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, link)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockShareLinkRepositoryMockRecorder) Create(ctx, link any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockShareLinkRepository)(nil).Create), ctx, link)
+}
+
+// FindByNonce mocks base method.
+func (m *MockShareLinkRepository) FindByNonce(ctx context.Context, nonce string) (domain.ShareLink, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByNonce", ctx, nonce)
+	ret0, _ := ret[0].(domain.ShareLink)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByNonce indicates an expected call of FindByNonce.
+func (mr *MockShareLinkRepositoryMockRecorder) FindByNonce(ctx, nonce any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByNonce", reflect.TypeOf((*MockShareLinkRepository)(nil).FindByNonce), ctx, nonce)
+}
+
+// IncrViews mocks base method.
+func (m *MockShareLinkRepository) IncrViews(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrViews", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IncrViews indicates an expected call of IncrViews.
+func (mr *MockShareLinkRepositoryMockRecorder) IncrViews(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrViews", reflect.TypeOf((*MockShareLinkRepository)(nil).IncrViews), ctx, id)
+}
+
+// ListByArticle mocks base method.
+func (m *MockShareLinkRepository) ListByArticle(ctx context.Context, artId, uid int64) ([]domain.ShareLink, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByArticle", ctx, artId, uid)
+	ret0, _ := ret[0].([]domain.ShareLink)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByArticle indicates an expected call of ListByArticle.
+func (mr *MockShareLinkRepositoryMockRecorder) ListByArticle(ctx, artId, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByArticle", reflect.TypeOf((*MockShareLinkRepository)(nil).ListByArticle), ctx, artId, uid)
+}
+
+// Revoke mocks base method.
+func (m *MockShareLinkRepository) Revoke(ctx context.Context, id, uid int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", ctx, id, uid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockShareLinkRepositoryMockRecorder) Revoke(ctx, id, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockShareLinkRepository)(nil).Revoke), ctx, id, uid)
+}
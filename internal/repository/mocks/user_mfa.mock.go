@@ -0,0 +1,196 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/repository/user_mfa.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/repository/user_mfa.go -package=repomocks -destination=./internal/repository/mocks/user_mfa.mock.go
+//
+// Package repomocks is a generated GoMock package.
+package repomocks
+
+import (
+	context "context"
+	reflect "reflect"
+	domain "webook/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockUserMFARepository is a mock of UserMFARepository interface.
+type MockUserMFARepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserMFARepositoryMockRecorder
+}
+
+// MockUserMFARepositoryMockRecorder is the mock recorder for MockUserMFARepository.
+type MockUserMFARepositoryMockRecorder struct {
+	mock *MockUserMFARepository
+}
+
+// NewMockUserMFARepository creates a new mock instance.
+func NewMockUserMFARepository(ctrl *gomock.Controller) *MockUserMFARepository {
+	mock := &MockUserMFARepository{ctrl: ctrl}
+	mock.recorder = &MockUserMFARepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserMFARepository) EXPECT() *MockUserMFARepositoryMockRecorder {
+	return m.recorder
+}
+
+// AttemptPendingLogin mocks base method.
+func (m *MockUserMFARepository) AttemptPendingLogin(ctx context.Context, token string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AttemptPendingLogin", ctx, token)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AttemptPendingLogin indicates an expected call of AttemptPendingLogin.
+func (mr *MockUserMFARepositoryMockRecorder) AttemptPendingLogin(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttemptPendingLogin", reflect.TypeOf((*MockUserMFARepository)(nil).AttemptPendingLogin), ctx, token)
+}
+
+// AttemptVerify mocks base method.
+func (m *MockUserMFARepository) AttemptVerify(ctx context.Context, uid int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AttemptVerify", ctx, uid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AttemptVerify indicates an expected call of AttemptVerify.
+func (mr *MockUserMFARepositoryMockRecorder) AttemptVerify(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttemptVerify", reflect.TypeOf((*MockUserMFARepository)(nil).AttemptVerify), ctx, uid)
+}
+
+// DelPendingLogin mocks base method.
+func (m *MockUserMFARepository) DelPendingLogin(ctx context.Context, token string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DelPendingLogin", ctx, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DelPendingLogin indicates an expected call of DelPendingLogin.
+func (mr *MockUserMFARepositoryMockRecorder) DelPendingLogin(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DelPendingLogin", reflect.TypeOf((*MockUserMFARepository)(nil).DelPendingLogin), ctx, token)
+}
+
+// Delete mocks base method.
+func (m *MockUserMFARepository) Delete(ctx context.Context, uid int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, uid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockUserMFARepositoryMockRecorder) Delete(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockUserMFARepository)(nil).Delete), ctx, uid)
+}
+
+// Enable mocks base method.
+func (m *MockUserMFARepository) Enable(ctx context.Context, uid, step int64, codeHashes []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Enable", ctx, uid, step, codeHashes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Enable indicates an expected call of Enable.
+func (mr *MockUserMFARepositoryMockRecorder) Enable(ctx, uid, step, codeHashes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enable", reflect.TypeOf((*MockUserMFARepository)(nil).Enable), ctx, uid, step, codeHashes)
+}
+
+// FindByUid mocks base method.
+func (m *MockUserMFARepository) FindByUid(ctx context.Context, uid int64) (domain.UserMFA, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByUid", ctx, uid)
+	ret0, _ := ret[0].(domain.UserMFA)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByUid indicates an expected call of FindByUid.
+func (mr *MockUserMFARepositoryMockRecorder) FindByUid(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUid", reflect.TypeOf((*MockUserMFARepository)(nil).FindByUid), ctx, uid)
+}
+
+// ResetVerify mocks base method.
+func (m *MockUserMFARepository) ResetVerify(ctx context.Context, uid int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetVerify", ctx, uid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetVerify indicates an expected call of ResetVerify.
+func (mr *MockUserMFARepositoryMockRecorder) ResetVerify(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetVerify", reflect.TypeOf((*MockUserMFARepository)(nil).ResetVerify), ctx, uid)
+}
+
+// SavePending mocks base method.
+func (m *MockUserMFARepository) SavePending(ctx context.Context, uid int64, secret string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SavePending", ctx, uid, secret)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SavePending indicates an expected call of SavePending.
+func (mr *MockUserMFARepositoryMockRecorder) SavePending(ctx, uid, secret any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SavePending", reflect.TypeOf((*MockUserMFARepository)(nil).SavePending), ctx, uid, secret)
+}
+
+// SetPendingLogin mocks base method.
+func (m *MockUserMFARepository) SetPendingLogin(ctx context.Context, token string, uid int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPendingLogin", ctx, token, uid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetPendingLogin indicates an expected call of SetPendingLogin.
+func (mr *MockUserMFARepositoryMockRecorder) SetPendingLogin(ctx, token, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPendingLogin", reflect.TypeOf((*MockUserMFARepository)(nil).SetPendingLogin), ctx, token, uid)
+}
+
+// UseRecoveryCode mocks base method.
+func (m *MockUserMFARepository) UseRecoveryCode(ctx context.Context, uid int64, codeHash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseRecoveryCode", ctx, uid, codeHash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UseRecoveryCode indicates an expected call of UseRecoveryCode.
+func (mr *MockUserMFARepositoryMockRecorder) UseRecoveryCode(ctx, uid, codeHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseRecoveryCode", reflect.TypeOf((*MockUserMFARepository)(nil).UseRecoveryCode), ctx, uid, codeHash)
+}
+
+// UseStep mocks base method.
+func (m *MockUserMFARepository) UseStep(ctx context.Context, uid, step int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseStep", ctx, uid, step)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UseStep indicates an expected call of UseStep.
+func (mr *MockUserMFARepositoryMockRecorder) UseStep(ctx, uid, step any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseStep", reflect.TypeOf((*MockUserMFARepository)(nil).UseStep), ctx, uid, step)
+}
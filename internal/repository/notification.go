@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"github.com/gotomicro/ekit/slice"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository/dao"
+)
+
+type NotificationRepository interface {
+	Create(ctx context.Context, n domain.Notification) (int64, error)
+	ListByUid(ctx context.Context, uid int64, offset int, limit int) ([]domain.Notification, error)
+	CountUnread(ctx context.Context, uid int64) (int64, error)
+	MarkRead(ctx context.Context, uid int64, ids []int64) error
+}
+
+type notificationRepository struct {
+	dao dao.NotificationDAO
+}
+
+func NewNotificationRepository(dao dao.NotificationDAO) NotificationRepository {
+	return &notificationRepository{
+		dao: dao,
+	}
+}
+
+func (r *notificationRepository) Create(ctx context.Context, n domain.Notification) (int64, error) {
+	return r.dao.Insert(ctx, dao.Notification{
+		Uid:     n.Uid,
+		Type:    n.Type,
+		BizId:   n.BizId,
+		Content: n.Content,
+	})
+}
+
+func (r *notificationRepository) ListByUid(ctx context.Context, uid int64, offset int, limit int) ([]domain.Notification, error) {
+	res, err := r.dao.ListByUid(ctx, uid, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(res, func(idx int, src dao.Notification) domain.Notification {
+		return domain.Notification{
+			Id:      src.Id,
+			Uid:     src.Uid,
+			Type:    src.Type,
+			BizId:   src.BizId,
+			Content: src.Content,
+			Read:    src.ReadAt > 0,
+			Ctime:   time.UnixMilli(src.Ctime),
+		}
+	}), nil
+}
+
+func (r *notificationRepository) CountUnread(ctx context.Context, uid int64) (int64, error) {
+	return r.dao.CountUnread(ctx, uid)
+}
+
+func (r *notificationRepository) MarkRead(ctx context.Context, uid int64, ids []int64) error {
+	return r.dao.MarkRead(ctx, uid, ids)
+}
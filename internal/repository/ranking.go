@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"github.com/gotomicro/ekit/slice"
+	"github.com/redis/go-redis/v9"
 	"webook/internal/domain"
 	"webook/internal/repository/cache"
 )
@@ -9,6 +11,8 @@ import (
 type RankingRepository interface {
 	ReplaceTopN(ctx context.Context, arts []domain.Article) error
 	GetTopN(ctx context.Context) ([]domain.Article, error)
+	// RemoveFromTopN 文章删除之后要立刻从榜单里面去掉，不能等下一次计算
+	RemoveFromTopN(ctx context.Context, id int64) error
 }
 
 type CachedRankingRepository struct {
@@ -52,3 +56,20 @@ func (c *CachedRankingRepository) ReplaceTopN(ctx context.Context, arts []domain
 func (c *CachedRankingRepository) GetTopN(ctx context.Context) ([]domain.Article, error) {
 	return c.cache.Get(ctx)
 }
+
+func (c *CachedRankingRepository) RemoveFromTopN(ctx context.Context, id int64) error {
+	arts, err := c.cache.Get(ctx)
+	if err == redis.Nil {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	res := slice.FilterDelete(arts, func(idx int, src domain.Article) bool {
+		return src.Id == id
+	})
+	if len(res) == len(arts) {
+		return nil
+	}
+	return c.cache.Set(ctx, res)
+}
@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/gotomicro/ekit/slice"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository/dao"
+)
+
+var (
+	ErrSeriesNotFound        = dao.ErrRecordNotFound
+	ErrSeriesArticleConflict = dao.ErrSeriesArticleConflict
+	ErrSeriesArticleInvalid  = errors.New("文章不存在或者不属于这个作者")
+)
+
+type SeriesRepository interface {
+	Create(ctx context.Context, s domain.Series) (int64, error)
+	Update(ctx context.Context, s domain.Series) error
+	UpdateStatus(ctx context.Context, id int64, uid int64, status domain.SeriesStatus) error
+	GetById(ctx context.Context, id int64) (domain.Series, error)
+	ListByAuthor(ctx context.Context, uid int64, offset int, limit int) ([]domain.Series, error)
+	GetByArticle(ctx context.Context, artId int64) (domain.Series, error)
+	SetArticles(ctx context.Context, id int64, uid int64, artIds []int64) error
+	ListArticles(ctx context.Context, id int64) ([]domain.Article, error)
+	ListPubArticles(ctx context.Context, id int64) ([]domain.Article, error)
+}
+
+type seriesRepository struct {
+	dao dao.SeriesDAO
+	// artDao 文章可能存在 MongoDB 里面，不能和系列的表 JOIN
+	artDao dao.ArticleDAO
+}
+
+func NewSeriesRepository(dao dao.SeriesDAO, artDao dao.ArticleDAO) SeriesRepository {
+	return &seriesRepository{
+		dao:    dao,
+		artDao: artDao,
+	}
+}
+
+func (s *seriesRepository) Create(ctx context.Context, series domain.Series) (int64, error) {
+	return s.dao.Insert(ctx, s.toEntity(series))
+}
+
+func (s *seriesRepository) Update(ctx context.Context, series domain.Series) error {
+	return s.dao.UpdateById(ctx, s.toEntity(series))
+}
+
+func (s *seriesRepository) UpdateStatus(ctx context.Context, id int64, uid int64, status domain.SeriesStatus) error {
+	return s.dao.UpdateStatus(ctx, id, uid, status.ToUint8())
+}
+
+func (s *seriesRepository) GetById(ctx context.Context, id int64) (domain.Series, error) {
+	series, err := s.dao.GetById(ctx, id)
+	if err != nil {
+		return domain.Series{}, err
+	}
+	return s.toDomain(series), nil
+}
+
+func (s *seriesRepository) ListByAuthor(ctx context.Context, uid int64, offset int, limit int) ([]domain.Series, error) {
+	res, err := s.dao.ListByAuthor(ctx, uid, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(res, func(idx int, src dao.Series) domain.Series {
+		return s.toDomain(src)
+	}), nil
+}
+
+func (s *seriesRepository) GetByArticle(ctx context.Context, artId int64) (domain.Series, error) {
+	series, err := s.dao.GetByArticle(ctx, artId)
+	if err != nil {
+		return domain.Series{}, err
+	}
+	return s.toDomain(series), nil
+}
+
+func (s *seriesRepository) SetArticles(ctx context.Context, id int64, uid int64, artIds []int64) error {
+	if len(artIds) > 0 {
+		arts, err := s.artDao.ListByIds(ctx, artIds, 0, len(artIds))
+		if err != nil {
+			return err
+		}
+		// 重复的 ID 只会查出来一篇，数量也对不上
+		cnt := 0
+		for _, art := range arts {
+			if art.AuthorId == uid {
+				cnt++
+			}
+		}
+		if cnt != len(artIds) {
+			return ErrSeriesArticleInvalid
+		}
+	}
+	return s.dao.SetArticles(ctx, id, uid, artIds)
+}
+
+func (s *seriesRepository) ListArticles(ctx context.Context, id int64) ([]domain.Article, error) {
+	ids, err := s.dao.ListArticleIds(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	arts, err := s.artDao.ListByIds(ctx, ids, 0, len(ids))
+	if err != nil {
+		return nil, err
+	}
+	return s.orderByIds(ids, slice.Map(arts, func(idx int, src dao.Article) domain.Article {
+		return s.articleToDomain(src)
+	})), nil
+}
+
+func (s *seriesRepository) ListPubArticles(ctx context.Context, id int64) ([]domain.Article, error) {
+	ids, err := s.dao.ListArticleIds(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	arts, err := s.artDao.ListPubByIds(ctx, ids, 0, len(ids))
+	if err != nil {
+		return nil, err
+	}
+	return s.orderByIds(ids, slice.Map(arts, func(idx int, src dao.PublishedArticle) domain.Article {
+		return s.articleToDomain(dao.Article(src))
+	})), nil
+}
+
+// orderByIds 按照文章在系列里面的顺序排列，查不到的文章跳过
+func (s *seriesRepository) orderByIds(ids []int64, arts []domain.Article) []domain.Article {
+	m := make(map[int64]domain.Article, len(arts))
+	for _, art := range arts {
+		m[art.Id] = art
+	}
+	res := make([]domain.Article, 0, len(arts))
+	for _, id := range ids {
+		if art, ok := m[id]; ok {
+			res = append(res, art)
+		}
+	}
+	return res
+}
+
+func (s *seriesRepository) toEntity(series domain.Series) dao.Series {
+	return dao.Series{
+		Id:          series.Id,
+		Title:       series.Title,
+		Description: series.Description,
+		AuthorId:    series.Author.Id,
+		Status:      series.Status.ToUint8(),
+	}
+}
+
+func (s *seriesRepository) toDomain(series dao.Series) domain.Series {
+	return domain.Series{
+		Id:          series.Id,
+		Title:       series.Title,
+		Description: series.Description,
+		Author: domain.Author{
+			Id: series.AuthorId,
+		},
+		Status: domain.SeriesStatus(series.Status),
+		Ctime:  time.UnixMilli(series.Ctime),
+		Utime:  time.UnixMilli(series.Utime),
+	}
+}
+
+// articleToDomain 系列里面的文章只用来展示列表，没有 HTML
+func (s *seriesRepository) articleToDomain(art dao.Article) domain.Article {
+	return domain.Article{
+		Id:      art.Id,
+		Title:   art.Title,
+		Content: art.Content,
+		Summary: art.Summary,
+		Author: domain.Author{
+			Id: art.AuthorId,
+		},
+		Status: domain.ArticleStatus(art.Status),
+		Ctime:  time.UnixMilli(art.Ctime),
+		Utime:  time.UnixMilli(art.Utime),
+	}
+}
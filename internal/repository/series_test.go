@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"webook/internal/repository/dao"
+	daomocks "webook/internal/repository/dao/mocks"
+)
+
+func TestSeriesRepository_ListPubArticles(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	seriesDao := daomocks.NewMockSeriesDAO(ctrl)
+	artDao := daomocks.NewMockArticleDAO(ctrl)
+	seriesDao.EXPECT().ListArticleIds(gomock.Any(), int64(1)).Return([]int64{3, 1, 2}, nil)
+	// 文章按照修改时间排序，2 还没有发表
+	artDao.EXPECT().ListPubByIds(gomock.Any(), []int64{3, 1, 2}, 0, 3).
+		Return([]dao.PublishedArticle{{Id: 1, Title: "一"}, {Id: 3, Title: "三"}}, nil)
+	arts, err := NewSeriesRepository(seriesDao, artDao).ListPubArticles(context.Background(), 1)
+	require.NoError(t, err)
+	require.Len(t, arts, 2)
+	assert.Equal(t, int64(3), arts[0].Id)
+	assert.Equal(t, int64(1), arts[1].Id)
+}
+
+func TestSeriesRepository_SetArticles(t *testing.T) {
+	testCases := []struct {
+		name    string
+		mock    func(seriesDao *daomocks.MockSeriesDAO, artDao *daomocks.MockArticleDAO)
+		artIds  []int64
+		wantErr error
+	}{
+		{
+			name: "设置成功",
+			mock: func(seriesDao *daomocks.MockSeriesDAO, artDao *daomocks.MockArticleDAO) {
+				artDao.EXPECT().ListByIds(gomock.Any(), []int64{2, 3}, 0, 2).
+					Return([]dao.Article{{Id: 3, AuthorId: 1}, {Id: 2, AuthorId: 1}}, nil)
+				seriesDao.EXPECT().SetArticles(gomock.Any(), int64(1), int64(1), []int64{2, 3}).Return(nil)
+			},
+			artIds: []int64{2, 3},
+		},
+		{
+			name: "别人的文章",
+			mock: func(seriesDao *daomocks.MockSeriesDAO, artDao *daomocks.MockArticleDAO) {
+				artDao.EXPECT().ListByIds(gomock.Any(), []int64{2, 3}, 0, 2).
+					Return([]dao.Article{{Id: 3, AuthorId: 1}, {Id: 2, AuthorId: 2}}, nil)
+			},
+			artIds:  []int64{2, 3},
+			wantErr: ErrSeriesArticleInvalid,
+		},
+		{
+			name: "重复的文章",
+			mock: func(seriesDao *daomocks.MockSeriesDAO, artDao *daomocks.MockArticleDAO) {
+				artDao.EXPECT().ListByIds(gomock.Any(), []int64{2, 2}, 0, 2).
+					Return([]dao.Article{{Id: 2, AuthorId: 1}}, nil)
+			},
+			artIds:  []int64{2, 2},
+			wantErr: ErrSeriesArticleInvalid,
+		},
+		{
+			name: "清空",
+			mock: func(seriesDao *daomocks.MockSeriesDAO, artDao *daomocks.MockArticleDAO) {
+				seriesDao.EXPECT().SetArticles(gomock.Any(), int64(1), int64(1), []int64(nil)).Return(nil)
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			seriesDao := daomocks.NewMockSeriesDAO(ctrl)
+			artDao := daomocks.NewMockArticleDAO(ctrl)
+			tc.mock(seriesDao, artDao)
+			err := NewSeriesRepository(seriesDao, artDao).SetArticles(context.Background(), 1, 1, tc.artIds)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"github.com/gotomicro/ekit/slice"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository/dao"
+)
+
+var (
+	ErrShareLinkNotFound  = dao.ErrRecordNotFound
+	ErrShareLinkExhausted = dao.ErrShareLinkExhausted
+)
+
+type ShareLinkRepository interface {
+	Create(ctx context.Context, link domain.ShareLink) (int64, error)
+	FindByNonce(ctx context.Context, nonce string) (domain.ShareLink, error)
+	ListByArticle(ctx context.Context, artId int64, uid int64) ([]domain.ShareLink, error)
+	Revoke(ctx context.Context, id int64, uid int64) error
+	IncrViews(ctx context.Context, id int64) error
+}
+
+type shareLinkRepository struct {
+	dao dao.ShareLinkDAO
+}
+
+func NewShareLinkRepository(dao dao.ShareLinkDAO) ShareLinkRepository {
+	return &shareLinkRepository{
+		dao: dao,
+	}
+}
+
+func (s *shareLinkRepository) Create(ctx context.Context, link domain.ShareLink) (int64, error) {
+	return s.dao.Insert(ctx, dao.ShareLink{
+		ArtId:    link.ArticleId,
+		AuthorId: link.AuthorId,
+		Nonce:    link.Nonce,
+		ExpireAt: link.ExpireAt.UnixMilli(),
+		MaxViews: link.MaxViews,
+	})
+}
+
+func (s *shareLinkRepository) FindByNonce(ctx context.Context, nonce string) (domain.ShareLink, error) {
+	link, err := s.dao.GetByNonce(ctx, nonce)
+	if err != nil {
+		return domain.ShareLink{}, err
+	}
+	return s.toDomain(link), nil
+}
+
+func (s *shareLinkRepository) ListByArticle(ctx context.Context, artId int64, uid int64) ([]domain.ShareLink, error) {
+	links, err := s.dao.ListByArticle(ctx, artId, uid)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(links, func(idx int, src dao.ShareLink) domain.ShareLink {
+		return s.toDomain(src)
+	}), nil
+}
+
+func (s *shareLinkRepository) Revoke(ctx context.Context, id int64, uid int64) error {
+	return s.dao.Revoke(ctx, id, uid)
+}
+
+func (s *shareLinkRepository) IncrViews(ctx context.Context, id int64) error {
+	return s.dao.IncrViews(ctx, id)
+}
+
+func (s *shareLinkRepository) toDomain(link dao.ShareLink) domain.ShareLink {
+	return domain.ShareLink{
+		Id:        link.Id,
+		ArticleId: link.ArtId,
+		AuthorId:  link.AuthorId,
+		Nonce:     link.Nonce,
+		ExpireAt:  time.UnixMilli(link.ExpireAt),
+		MaxViews:  link.MaxViews,
+		Views:     link.Views,
+		Revoked:   link.Revoked,
+		Ctime:     time.UnixMilli(link.Ctime),
+	}
+}
@@ -23,6 +23,8 @@ type UserRepository interface {
 	FindById(ctx context.Context, id int64) (domain.User, error)
 	FindByPhone(ctx context.Context, phone string) (domain.User, error)
 	FindByWechat(ctx context.Context, openId string) (domain.User, error)
+	// UpdatePassword password 是加密之后的密码
+	UpdatePassword(ctx context.Context, id int64, password string) error
 }
 
 type CacheUserRepository struct {
@@ -113,6 +115,15 @@ func (repo *CacheUserRepository) Update(ctx context.Context, u domain.User) erro
 	return err
 }
 
+func (repo *CacheUserRepository) UpdatePassword(ctx context.Context, id int64, password string) error {
+	err := repo.dao.UpdatePassword(ctx, id, password)
+	if err != nil {
+		return err
+	}
+	// 缓存里面有加密之后的密码，不删掉的话旧密码还能用一段时间
+	return repo.cache.Del(ctx, id)
+}
+
 func (repo *CacheUserRepository) FindById(ctx context.Context, id int64) (domain.User, error) {
 	du, err := repo.cache.Get(ctx, id)
 	switch err {
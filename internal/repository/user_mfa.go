@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"webook/internal/domain"
+	"webook/internal/repository/cache"
+	"webook/internal/repository/dao"
+)
+
+var (
+	ErrMFANotFound         = dao.ErrRecordNotFound
+	ErrMFAStepUsed         = dao.ErrMFAStepUsed
+	ErrRecoveryCodeInvalid = dao.ErrRecordNotFound
+	ErrMFAPendingNotFound  = cache.ErrKeyNotExist
+	ErrMFATooManyAttempts  = cache.ErrMFATooManyAttempts
+	ErrMFALocked           = cache.ErrMFALocked
+)
+
+type UserMFARepository interface {
+	SavePending(ctx context.Context, uid int64, secret string) error
+	// FindByUid 没有设置过两步验证的时候返回 ErrMFANotFound
+	FindByUid(ctx context.Context, uid int64) (domain.UserMFA, error)
+	// Enable codeHashes 是恢复码的哈希
+	Enable(ctx context.Context, uid int64, step int64, codeHashes []string) error
+	UseStep(ctx context.Context, uid int64, step int64) error
+	UseRecoveryCode(ctx context.Context, uid int64, codeHash string) error
+	Delete(ctx context.Context, uid int64) error
+
+	SetPendingLogin(ctx context.Context, token string, uid int64) error
+	AttemptPendingLogin(ctx context.Context, token string) (int64, error)
+	DelPendingLogin(ctx context.Context, token string) error
+	// AttemptVerify 校验验证码之前调用，失败太多次返回 ErrMFALocked
+	AttemptVerify(ctx context.Context, uid int64) error
+	ResetVerify(ctx context.Context, uid int64) error
+}
+
+type CachedUserMFARepository struct {
+	dao   dao.UserMFADAO
+	cache cache.UserMFACache
+}
+
+func NewCachedUserMFARepository(dao dao.UserMFADAO, cache cache.UserMFACache) UserMFARepository {
+	return &CachedUserMFARepository{
+		dao:   dao,
+		cache: cache,
+	}
+}
+
+func (c *CachedUserMFARepository) SavePending(ctx context.Context, uid int64, secret string) error {
+	return c.dao.UpsertPending(ctx, uid, secret)
+}
+
+func (c *CachedUserMFARepository) FindByUid(ctx context.Context, uid int64) (domain.UserMFA, error) {
+	m, err := c.dao.GetByUid(ctx, uid)
+	if err != nil {
+		return domain.UserMFA{}, err
+	}
+	return domain.UserMFA{
+		Uid:      m.Uid,
+		Secret:   m.Secret,
+		Status:   domain.MFAStatus(m.Status),
+		LastStep: m.LastStep,
+	}, nil
+}
+
+func (c *CachedUserMFARepository) Enable(ctx context.Context, uid int64, step int64, codeHashes []string) error {
+	return c.dao.Enable(ctx, uid, step, codeHashes)
+}
+
+func (c *CachedUserMFARepository) UseStep(ctx context.Context, uid int64, step int64) error {
+	return c.dao.UseStep(ctx, uid, step)
+}
+
+func (c *CachedUserMFARepository) UseRecoveryCode(ctx context.Context, uid int64, codeHash string) error {
+	return c.dao.UseRecoveryCode(ctx, uid, codeHash)
+}
+
+func (c *CachedUserMFARepository) Delete(ctx context.Context, uid int64) error {
+	return c.dao.Delete(ctx, uid)
+}
+
+func (c *CachedUserMFARepository) SetPendingLogin(ctx context.Context, token string, uid int64) error {
+	return c.cache.SetPending(ctx, token, uid)
+}
+
+func (c *CachedUserMFARepository) AttemptPendingLogin(ctx context.Context, token string) (int64, error) {
+	return c.cache.AttemptPending(ctx, token)
+}
+
+func (c *CachedUserMFARepository) DelPendingLogin(ctx context.Context, token string) error {
+	return c.cache.DelPending(ctx, token)
+}
+
+func (c *CachedUserMFARepository) AttemptVerify(ctx context.Context, uid int64) error {
+	return c.cache.AttemptVerify(ctx, uid)
+}
+
+func (c *CachedUserMFARepository) ResetVerify(ctx context.Context, uid int64) error {
+	return c.cache.ResetVerify(ctx, uid)
+}
@@ -0,0 +1,505 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	"webook/pkg/blobx"
+	"webook/pkg/limiter"
+	"webook/pkg/logger"
+	"webook/pkg/markdown"
+	"webook/pkg/signx"
+)
+
+var (
+	ErrArchiveTaskNotFound = errors.New("任务不存在")
+	ErrArchiveTooLarge     = errors.New("压缩包太大")
+	ErrArchiveLinkInvalid  = errors.New("下载链接无效")
+	ErrArchiveLinkExpired  = errors.New("下载链接已经过期")
+	ErrArchiveTooFrequent  = errors.New("导出太频繁")
+)
+
+const (
+	// ArchiveTaskTimeout 执行超过这个时间还没有结束的任务，认为执行的实例已经挂了，可以被重新抢占
+	ArchiveTaskTimeout = 30 * time.Minute
+	// ArchiveRetention 导出的压缩包保留的时间，也是下载链接的有效期
+	ArchiveRetention = 7 * 24 * time.Hour
+	// ArchiveMaxFiles 导入的压缩包里面最多的文件数量
+	ArchiveMaxFiles = 1000
+	// ArchiveMaxFileSize 导入的单篇文章解压之后最大的字节数
+	ArchiveMaxFileSize = 1 << 20
+)
+
+// archiveStatusNames 导出到 front matter 里面的状态，导入的时候忽略，全部变成草稿
+var archiveStatusNames = map[domain.ArticleStatus]string{
+	domain.ArticleStatusUnpublished:   "draft",
+	domain.ArticleStatusPublished:     "published",
+	domain.ArticleStatusPrivate:       "private",
+	domain.ArticleStatusScheduled:     "scheduled",
+	domain.ArticleStatusPendingReview: "pending_review",
+}
+
+//go:generate mockgen -source=./archive.go -package=svcmocks -destination=./mocks/archive.mock.go ArchiveService
+type ArchiveService interface {
+	// RequestExport 创建导出任务，压缩包由定时任务异步生成
+	RequestExport(ctx context.Context, uid int64) (domain.ArchiveTask, error)
+	// RequestImport 先保存上传的压缩包，再创建导入任务
+	RequestImport(ctx context.Context, uid int64, r io.Reader, size int64) (domain.ArchiveTask, error)
+	// Get 只能查询自己的任务
+	Get(ctx context.Context, uid int64, id int64) (domain.ArchiveTask, error)
+	List(ctx context.Context, uid int64, offset int, limit int) ([]domain.ArchiveTask, error)
+	// Open 校验下载凭证，返回的 ReadCloser 必须由调用方关闭
+	Open(ctx context.Context, token string) (domain.ArchiveTask, io.ReadCloser, error)
+	// RunNext 执行一个任务，没有可以执行的任务返回 false
+	RunNext(ctx context.Context) (bool, error)
+	// Cleanup 删除过期的导出结果，返回这一批处理的数量
+	Cleanup(ctx context.Context, limit int) (int, error)
+}
+
+type archiveService struct {
+	repo    repository.ArchiveTaskRepository
+	artRepo repository.ArticleRepository
+	artSvc  ArticleService
+	store   blobx.BlobStore
+	signer  *signx.Signer
+	maxSize int64
+	// exportLimiter 每个用户导出的频率，导出要把所有文章都打包一遍
+	exportLimiter limiter.Limiter
+	l             logger.LoggerV1
+}
+
+func NewArchiveService(repo repository.ArchiveTaskRepository, artRepo repository.ArticleRepository,
+	artSvc ArticleService, store blobx.BlobStore, key []byte, maxSize int64,
+	exportLimiter limiter.Limiter, l logger.LoggerV1) ArchiveService {
+	return &archiveService{
+		repo:          repo,
+		artRepo:       artRepo,
+		artSvc:        artSvc,
+		store:         store,
+		signer:        signx.NewSigner(key),
+		maxSize:       maxSize,
+		exportLimiter: exportLimiter,
+		l:             l,
+	}
+}
+
+func (s *archiveService) RequestExport(ctx context.Context, uid int64) (domain.ArchiveTask, error) {
+	limited, err := s.exportLimiter.Limit(ctx, "archive:export:"+strconv.FormatInt(uid, 10))
+	if err != nil {
+		return domain.ArchiveTask{}, err
+	}
+	if limited {
+		return domain.ArchiveTask{}, ErrArchiveTooFrequent
+	}
+	t := domain.ArchiveTask{
+		Uid:    uid,
+		Type:   domain.ArchiveTaskTypeExport,
+		Status: domain.ArchiveTaskStatusPending,
+	}
+	t.Id, err = s.repo.Create(ctx, t)
+	if err != nil {
+		return domain.ArchiveTask{}, err
+	}
+	t.Ctime = time.Now()
+	t.Utime = t.Ctime
+	return t, nil
+}
+
+func (s *archiveService) RequestImport(ctx context.Context, uid int64, r io.Reader, size int64) (domain.ArchiveTask, error) {
+	if size > s.maxSize {
+		return domain.ArchiveTask{}, ErrArchiveTooLarge
+	}
+	key, err := s.newKey()
+	if err != nil {
+		return domain.ArchiveTask{}, err
+	}
+	err = s.store.Put(ctx, key, r, size, "application/zip")
+	if err != nil {
+		return domain.ArchiveTask{}, err
+	}
+	t := domain.ArchiveTask{
+		Uid:     uid,
+		Type:    domain.ArchiveTaskTypeImport,
+		Status:  domain.ArchiveTaskStatusPending,
+		BlobKey: key,
+	}
+	t.Id, err = s.repo.Create(ctx, t)
+	if err != nil {
+		s.deleteBlob(ctx, key)
+		return domain.ArchiveTask{}, err
+	}
+	t.Ctime = time.Now()
+	t.Utime = t.Ctime
+	return t, nil
+}
+
+func (s *archiveService) Get(ctx context.Context, uid int64, id int64) (domain.ArchiveTask, error) {
+	t, err := s.repo.FindById(ctx, id)
+	if err == repository.ErrArchiveTaskNotFound {
+		return domain.ArchiveTask{}, ErrArchiveTaskNotFound
+	}
+	if err != nil {
+		return domain.ArchiveTask{}, err
+	}
+	if t.Uid != uid {
+		return domain.ArchiveTask{}, ErrArchiveTaskNotFound
+	}
+	return s.withToken(t), nil
+}
+
+func (s *archiveService) List(ctx context.Context, uid int64, offset int, limit int) ([]domain.ArchiveTask, error) {
+	ts, err := s.repo.ListByUid(ctx, uid, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	for i := range ts {
+		ts[i] = s.withToken(ts[i])
+	}
+	return ts, nil
+}
+
+func (s *archiveService) Open(ctx context.Context, token string) (domain.ArchiveTask, io.ReadCloser, error) {
+	// 先校验签名和过期时间，伪造的和过期的链接不需要查数据库
+	payload, _, err := s.signer.Verify(token)
+	switch err {
+	case nil:
+	case signx.ErrExpired:
+		return domain.ArchiveTask{}, nil, ErrArchiveLinkExpired
+	default:
+		return domain.ArchiveTask{}, nil, ErrArchiveLinkInvalid
+	}
+	id, err := strconv.ParseInt(payload, 36, 64)
+	if err != nil {
+		return domain.ArchiveTask{}, nil, ErrArchiveLinkInvalid
+	}
+	t, err := s.repo.FindById(ctx, id)
+	if err == repository.ErrArchiveTaskNotFound {
+		return domain.ArchiveTask{}, nil, ErrArchiveLinkInvalid
+	}
+	if err != nil {
+		return domain.ArchiveTask{}, nil, err
+	}
+	if !s.downloadable(t) {
+		return domain.ArchiveTask{}, nil, ErrArchiveLinkExpired
+	}
+	rc, err := s.store.Get(ctx, t.BlobKey)
+	if err == blobx.ErrNotFound {
+		return domain.ArchiveTask{}, nil, ErrArchiveLinkExpired
+	}
+	if err != nil {
+		return domain.ArchiveTask{}, nil, err
+	}
+	return t, rc, nil
+}
+
+func (s *archiveService) RunNext(ctx context.Context) (bool, error) {
+	t, err := s.repo.Preempt(ctx, ArchiveTaskTimeout)
+	if err == repository.ErrArchiveTaskNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	switch t.Type {
+	case domain.ArchiveTaskTypeExport:
+		t = s.runExport(ctx, t)
+	case domain.ArchiveTaskTypeImport:
+		t = s.runImport(ctx, t)
+	default:
+		t.Status = domain.ArchiveTaskStatusFailed
+		t.Reason = "未知的任务类型"
+	}
+	return true, s.repo.Finish(ctx, t)
+}
+
+func (s *archiveService) Cleanup(ctx context.Context, limit int) (int, error) {
+	ts, err := s.repo.ListExpired(ctx, time.Now().Add(-ArchiveRetention), limit)
+	if err != nil {
+		return 0, err
+	}
+	for _, t := range ts {
+		err = s.store.Delete(ctx, t.BlobKey)
+		if err != nil {
+			return 0, err
+		}
+		err = s.repo.ClearBlob(ctx, t.Id)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(ts), nil
+}
+
+// runExport 先写到临时文件里面，写完再知道大小，然后上传到 BlobStore
+func (s *archiveService) runExport(ctx context.Context, t domain.ArchiveTask) domain.ArchiveTask {
+	f, err := os.CreateTemp("", "webook-export-*.zip")
+	if err != nil {
+		return s.fail(t, err)
+	}
+	defer func() {
+		f.Close()
+		os.Remove(f.Name())
+	}()
+	cnt, err := s.writeArchive(ctx, t.Uid, f)
+	if err != nil {
+		return s.fail(t, err)
+	}
+	size, err := f.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return s.fail(t, err)
+	}
+	_, err = f.Seek(0, io.SeekStart)
+	if err != nil {
+		return s.fail(t, err)
+	}
+	key, err := s.newKey()
+	if err != nil {
+		return s.fail(t, err)
+	}
+	err = s.store.Put(ctx, key, f, size, "application/zip")
+	if err != nil {
+		return s.fail(t, err)
+	}
+	t.Status = domain.ArchiveTaskStatusDone
+	t.BlobKey = key
+	t.Cnt = cnt
+	return t
+}
+
+func (s *archiveService) writeArchive(ctx context.Context, uid int64, w io.Writer) (int, error) {
+	const batchSize = 50
+	zw := zip.NewWriter(w)
+	cnt := 0
+	var cursor domain.ArticleCursor
+	for {
+		arts, err := s.artRepo.GetByAuthorCursor(ctx, uid, cursor, batchSize)
+		if err != nil {
+			return 0, err
+		}
+		for _, art := range arts {
+			// 列表里面没有标签，重新查一次详情
+			art, err = s.artRepo.GetById(ctx, art.Id)
+			if err != nil {
+				return 0, err
+			}
+			err = s.writeArticle(zw, art)
+			if err != nil {
+				return 0, err
+			}
+			cnt++
+		}
+		cursor = domain.NextArticleCursor(arts, batchSize)
+		if cursor.IsZero() {
+			break
+		}
+	}
+	return cnt, zw.Close()
+}
+
+func (s *archiveService) writeArticle(zw *zip.Writer, art domain.Article) error {
+	fw, err := zw.CreateHeader(&zip.FileHeader{
+		Name:     fmt.Sprintf("articles/%d.md", art.Id),
+		Method:   zip.Deflate,
+		Modified: art.Utime,
+	})
+	if err != nil {
+		return err
+	}
+	tags := art.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	metas := []markdown.Meta{
+		{Key: "id", Value: art.Id},
+		{Key: "title", Value: art.Title},
+		{Key: "status", Value: archiveStatusNames[art.Status]},
+		{Key: "tags", Value: tags},
+		{Key: "ctime", Value: art.Ctime.Format(time.RFC3339)},
+		{Key: "utime", Value: art.Utime.Format(time.RFC3339)},
+	}
+	if art.Status == domain.ArticleStatusScheduled {
+		metas = append(metas, markdown.Meta{Key: "publish_at", Value: art.PublishAt.Format(time.RFC3339)})
+	}
+	return markdown.WriteFrontMatter(fw, metas, art.Content)
+}
+
+// runImport 每个文件单独创建一篇草稿，单个文件失败不影响其它文件
+func (s *archiveService) runImport(ctx context.Context, t domain.ArchiveTask) domain.ArchiveTask {
+	// 不管成功还是失败，上传的压缩包都没有用了
+	defer s.deleteBlob(ctx, t.BlobKey)
+	f, size, err := s.download(ctx, t.BlobKey)
+	if err != nil {
+		return s.fail(t, err)
+	}
+	defer func() {
+		f.Close()
+		os.Remove(f.Name())
+	}()
+	zr, err := zip.NewReader(f, size)
+	if err != nil {
+		t.Status = domain.ArchiveTaskStatusFailed
+		t.Reason = "不是合法的 zip 文件"
+		t.BlobKey = ""
+		return t
+	}
+	files := 0
+	for _, zf := range zr.File {
+		if zf.FileInfo().IsDir() || ignoredArchiveFile(zf.Name) {
+			continue
+		}
+		files++
+		if files > ArchiveMaxFiles {
+			t.Errors = append(t.Errors, domain.ArchiveFileError{
+				File: zf.Name,
+				Err:  fmt.Sprintf("文件太多，只导入前 %d 个", ArchiveMaxFiles),
+			})
+			break
+		}
+		er := s.importFile(ctx, t.Uid, zf)
+		if er != nil {
+			t.Errors = append(t.Errors, domain.ArchiveFileError{File: zf.Name, Err: er.Error()})
+			continue
+		}
+		t.Cnt++
+	}
+	t.Status = domain.ArchiveTaskStatusDone
+	t.BlobKey = ""
+	return t
+}
+
+// importFile 返回的错误会展示给用户，系统错误只记录日志
+func (s *archiveService) importFile(ctx context.Context, uid int64, zf *zip.File) error {
+	if !strings.EqualFold(path.Ext(zf.Name), ".md") {
+		return errors.New("不是 Markdown 文件")
+	}
+	if zf.UncompressedSize64 > ArchiveMaxFileSize {
+		return errors.New("文件太大")
+	}
+	rc, err := zf.Open()
+	if err != nil {
+		return errors.New("解压失败")
+	}
+	defer rc.Close()
+	// 压缩包里面记录的大小不可信，读的时候再限制一次
+	data, err := io.ReadAll(io.LimitReader(rc, ArchiveMaxFileSize+1))
+	if err != nil {
+		return errors.New("解压失败")
+	}
+	if len(data) > ArchiveMaxFileSize {
+		return errors.New("文件太大")
+	}
+	metas, body, err := markdown.ParseFrontMatter(data)
+	if err != nil {
+		return err
+	}
+	art := domain.Article{
+		Content: body,
+		Author:  domain.Author{Id: uid},
+	}
+	if val, ok := metas["title"]; ok {
+		if json.Unmarshal(val, &art.Title) != nil {
+			return errors.New("title 格式不对")
+		}
+	}
+	if art.Title == "" {
+		art.Title = strings.TrimSuffix(path.Base(zf.Name), path.Ext(zf.Name))
+	}
+	if val, ok := metas["tags"]; ok {
+		if json.Unmarshal(val, &art.Tags) != nil {
+			return errors.New("tags 格式不对")
+		}
+	}
+	_, err = s.artSvc.Save(ctx, art)
+	switch err {
+	case nil:
+		return nil
+	case ErrInvalidTags, ErrArticleTooLarge:
+		return err
+	default:
+		s.l.Error("导入文章失败", logger.Int64("uid", uid),
+			logger.String("file", zf.Name), logger.Error(err))
+		return errors.New("系统错误")
+	}
+}
+
+// download 把压缩包复制到临时文件，zip 需要随机读
+func (s *archiveService) download(ctx context.Context, key string) (*os.File, int64, error) {
+	rc, err := s.store.Get(ctx, key)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rc.Close()
+	f, err := os.CreateTemp("", "webook-import-*.zip")
+	if err != nil {
+		return nil, 0, err
+	}
+	size, err := io.Copy(f, io.LimitReader(rc, s.maxSize))
+	if err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return nil, 0, err
+	}
+	return f, size, nil
+}
+
+func (s *archiveService) fail(t domain.ArchiveTask, err error) domain.ArchiveTask {
+	s.l.Error("执行压缩包任务失败", logger.Int64("id", t.Id), logger.Error(err))
+	t.Status = domain.ArchiveTaskStatusFailed
+	t.Reason = "系统错误"
+	t.BlobKey = ""
+	return t
+}
+
+func (s *archiveService) deleteBlob(ctx context.Context, key string) {
+	if key == "" {
+		return
+	}
+	err := s.store.Delete(ctx, key)
+	if err != nil {
+		s.l.Error("删除压缩包失败", logger.String("key", key), logger.Error(err))
+	}
+}
+
+func (s *archiveService) newKey() (string, error) {
+	b := make([]byte, 16)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return "archive-" + hex.EncodeToString(b) + ".zip", nil
+}
+
+func (s *archiveService) downloadable(t domain.ArchiveTask) bool {
+	return t.Type == domain.ArchiveTaskTypeExport &&
+		t.Status == domain.ArchiveTaskStatusDone && t.BlobKey != ""
+}
+
+// withToken 链接的有效期和压缩包保留的时间一致
+func (s *archiveService) withToken(t domain.ArchiveTask) domain.ArchiveTask {
+	if s.downloadable(t) {
+		t.Token = s.sign(t.Id, t.Utime.Add(ArchiveRetention))
+	}
+	return t
+}
+
+// sign 凭证里面带上任务 ID
+func (s *archiveService) sign(id int64, expireAt time.Time) string {
+	return s.signer.Sign(strconv.FormatInt(id, 36), expireAt)
+}
+
+// ignoredArchiveFile macOS 压缩的时候会带上这些文件
+func ignoredArchiveFile(name string) bool {
+	return strings.HasPrefix(name, "__MACOSX/") || strings.HasPrefix(path.Base(name), ".")
+}
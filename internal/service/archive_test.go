@@ -0,0 +1,148 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+	"webook/internal/domain"
+	repomocks "webook/internal/repository/mocks"
+	svcmocks "webook/internal/service/mocks"
+	"webook/pkg/blobx"
+	"webook/pkg/limiter"
+	"webook/pkg/logger"
+)
+
+func TestArchiveService_RequestExport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := repomocks.NewMockArchiveTaskRepository(ctrl)
+	repo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(int64(1), nil).Times(2)
+	mr := miniredis.RunT(t)
+	l := limiter.NewRedisSlidingWindowLimiter(redis.NewClient(&redis.Options{Addr: mr.Addr()}), time.Hour, 1)
+	svc := NewArchiveService(repo, nil, nil, nil, nil, 1024, l, logger.NewNopLogger())
+
+	ctx := context.Background()
+	_, err := svc.RequestExport(ctx, 1)
+	require.NoError(t, err)
+	_, err = svc.RequestExport(ctx, 1)
+	assert.Equal(t, ErrArchiveTooFrequent, err)
+	// 按照用户限流
+	_, err = svc.RequestExport(ctx, 2)
+	assert.NoError(t, err)
+}
+
+func TestArchiveService_RequestImport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	svc := NewArchiveService(repomocks.NewMockArchiveTaskRepository(ctrl), nil, nil, nil, nil,
+		1024, nil, logger.NewNopLogger())
+	_, err := svc.RequestImport(context.Background(), 1, bytes.NewReader(make([]byte, 1025)), 1025)
+	assert.Equal(t, ErrArchiveTooLarge, err)
+}
+
+func TestArchiveService_RunNext_Import(t *testing.T) {
+	testCases := []struct {
+		name string
+		// files 压缩包里面的文件名和内容
+		files      map[string]string
+		saveTimes  int
+		wantCnt    int
+		wantErrors []domain.ArchiveFileError
+	}{
+		{
+			name: "跳过不能导入的文件",
+			files: map[string]string{
+				"a.md":          "# a",
+				"b.txt":         "b",
+				"c.md":          strings.Repeat("c", ArchiveMaxFileSize+1),
+				"__MACOSX/d.md": "d",
+			},
+			saveTimes: 1,
+			wantCnt:   1,
+			wantErrors: []domain.ArchiveFileError{
+				{File: "b.txt", Err: "不是 Markdown 文件"},
+				{File: "c.md", Err: "文件太大"},
+			},
+		},
+		{
+			name:      "文件太多",
+			files:     archiveFiles(ArchiveMaxFiles + 2),
+			saveTimes: ArchiveMaxFiles,
+			wantCnt:   ArchiveMaxFiles,
+			wantErrors: []domain.ArchiveFileError{
+				{File: fmt.Sprintf("%04d.md", ArchiveMaxFiles), Err: fmt.Sprintf("文件太多，只导入前 %d 个", ArchiveMaxFiles)},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			ctx := context.Background()
+			store, err := blobx.NewLocalStore(t.TempDir())
+			require.NoError(t, err)
+			data := newArchive(t, tc.files)
+			require.NoError(t, store.Put(ctx, "archive-test.zip", bytes.NewReader(data), int64(len(data)), ""))
+
+			repo := repomocks.NewMockArchiveTaskRepository(ctrl)
+			repo.EXPECT().Preempt(gomock.Any(), ArchiveTaskTimeout).Return(domain.ArchiveTask{
+				Id: 1, Uid: 2, Type: domain.ArchiveTaskTypeImport, BlobKey: "archive-test.zip",
+			}, nil)
+			var res domain.ArchiveTask
+			repo.EXPECT().Finish(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, t domain.ArchiveTask) error {
+				res = t
+				return nil
+			})
+			artSvc := svcmocks.NewMockArticleService(ctrl)
+			artSvc.EXPECT().Save(gomock.Any(), gomock.Any()).Return(int64(1), nil).Times(tc.saveTimes)
+
+			svc := NewArchiveService(repo, nil, artSvc, store, nil, 10<<20, nil, logger.NewNopLogger())
+			ok, err := svc.RunNext(ctx)
+			require.NoError(t, err)
+			assert.True(t, ok)
+			assert.Equal(t, domain.ArchiveTaskStatusDone, res.Status)
+			assert.Equal(t, tc.wantCnt, res.Cnt)
+			assert.Equal(t, tc.wantErrors, res.Errors)
+			// 上传的压缩包用完就删掉
+			_, err = store.Get(ctx, "archive-test.zip")
+			assert.Equal(t, blobx.ErrNotFound, err)
+		})
+	}
+}
+
+func archiveFiles(n int) map[string]string {
+	res := make(map[string]string, n)
+	for i := 0; i < n; i++ {
+		res[fmt.Sprintf("%04d.md", i)] = "# hello"
+	}
+	return res
+}
+
+// newArchive 按照文件名排序写进压缩包，方便断言
+func newArchive(t *testing.T, files map[string]string) []byte {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, name := range names {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(files[name]))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
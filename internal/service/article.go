@@ -7,7 +7,9 @@ import (
 	"webook/internal/domain"
 	"webook/internal/events/article"
 	"webook/internal/repository"
+	"webook/pkg/diffx"
 	"webook/pkg/logger"
+	"webook/pkg/sensitive"
 )
 
 //go:generate mockgen -source=./article.go -package=svcmocks -destination=./mocks/article.mock.go ArticleService
@@ -17,13 +19,51 @@ type ArticleService interface {
 	Withdraw(ctx context.Context, id int64, uid int64) error
 	GetByAuthor(ctx context.Context, uid int64, offset int, limit int) ([]domain.Article, error)
 	GetById(ctx context.Context, id int64) (domain.Article, error)
-	GetPubById(ctx context.Context, uid, id int64) (domain.Article, error)
+	// GetByIdForUser 作者和合作者查看草稿，带上合作者
+	GetByIdForUser(ctx context.Context, id int64, uid int64) (domain.Article, error)
+	// GetPubById device 是读者的设备 ID，没有登录的时候用来统计 UV
+	GetPubById(ctx context.Context, uid, id int64, device string) (domain.Article, error)
 	ListPub(ctx context.Context, start time.Time, offset, limit int) ([]domain.Article, error)
+	GetByAuthorCursor(ctx context.Context, uid int64, cursor domain.ArticleCursor, limit int) ([]domain.Article, error)
+	ListPubCursor(ctx context.Context, cursor domain.ArticleCursor, limit int) ([]domain.Article, error)
+	ListRevisions(ctx context.Context, id int64, uid int64, offset int, limit int) ([]domain.ArticleRevision, error)
+	GetRevision(ctx context.Context, id int64, uid int64, version int64) (domain.ArticleRevision, error)
+	DiffRevisions(ctx context.Context, id int64, uid int64, from int64, to int64) ([]diffx.Line, error)
+	RestoreRevision(ctx context.Context, id int64, uid int64, version int64) error
+	SchedulePublish(ctx context.Context, art domain.Article, publishAt time.Time) (int64, error)
+	CancelSchedule(ctx context.Context, id int64, uid int64) error
+	Reschedule(ctx context.Context, id int64, uid int64, publishAt time.Time) error
+	// PublishDue 发表 now 之前到期的定时文章，返回这一批处理的文章数量
+	PublishDue(ctx context.Context, now time.Time, limit int) (int, error)
+	ListTags(ctx context.Context, offset int, limit int) ([]domain.Tag, error)
+	ListPubByTag(ctx context.Context, tag string, offset int, limit int) ([]domain.Article, error)
+	// Delete 移入回收站，ArticleTrashRetention 之内可以恢复
+	Delete(ctx context.Context, id int64, uid int64) error
+	Restore(ctx context.Context, id int64, uid int64) error
+	ListTrash(ctx context.Context, uid int64, offset int, limit int) ([]domain.Article, error)
+	// PurgeExpired 彻底删除回收站里面过期的文章，返回这一批处理的文章数量
+	PurgeExpired(ctx context.Context, now time.Time, limit int) (int, error)
+	// AddCollaborator 作者邀请合作者，已经是合作者的话修改角色
+	AddCollaborator(ctx context.Context, id int64, uid int64, collaborator int64, role domain.ArticleRole) error
+	// RemoveCollaborator 作者移除合作者，合作者也可以自己退出
+	RemoveCollaborator(ctx context.Context, id int64, uid int64, collaborator int64) error
+	ListCollaborators(ctx context.Context, id int64, uid int64) ([]domain.Collaborator, error)
+	// ListCollaborating uid 作为合作者参与的文章
+	ListCollaborating(ctx context.Context, uid int64, offset int, limit int) ([]domain.Article, error)
+	ListAudits(ctx context.Context, id int64, uid int64, offset int, limit int) ([]domain.ArticleAudit, error)
+	// ListReviews 文章的审核记录，最新的在前面
+	ListReviews(ctx context.Context, id int64, uid int64, offset int, limit int) ([]domain.ArticleReview, error)
+	// RebuildPubFilter 重建线上库文章 ID 的布隆过滤器
+	RebuildPubFilter(ctx context.Context, batchSize int) error
 }
 
 type articleService struct {
-	repo     repository.ArticleRepository
-	producer article.Producer
+	repo        repository.ArticleRepository
+	collabRepo  repository.ArticleCollaboratorRepository
+	rankingRepo repository.RankingRepository
+	producer    article.Producer
+	matcher     sensitive.Matcher
+	reviewRepo  repository.ArticleReviewRepository
 	//v1
 	readerRepo repository.ArticleReaderRepository
 	authorRepo repository.ArticleAuthorRepository
@@ -34,14 +74,35 @@ func (a *articleService) ListPub(ctx context.Context, start time.Time, offset, l
 	return a.repo.ListPub(ctx, start, offset, limit)
 }
 
-func (a *articleService) GetPubById(ctx context.Context, uid, id int64) (domain.Article, error) {
+func (a *articleService) GetByAuthorCursor(ctx context.Context, uid int64, cursor domain.ArticleCursor, limit int) ([]domain.Article, error) {
+	return a.repo.GetByAuthorCursor(ctx, uid, cursor, limit)
+}
+
+func (a *articleService) ListPubCursor(ctx context.Context, cursor domain.ArticleCursor, limit int) ([]domain.Article, error) {
+	return a.repo.ListPubCursor(ctx, cursor, limit)
+}
+
+func (a *articleService) GetPubById(ctx context.Context, uid, id int64, device string) (domain.Article, error) {
 	res, err := a.repo.GetPubById(ctx, id)
+	if err == nil && res.HTML == "" {
+		// 渲染功能上线之前发表的文章没有 HTML
+		res = renderArticle(res)
+	}
+	if err == nil {
+		var er error
+		res.Collaborators, er = a.collabRepo.List(ctx, id)
+		if er != nil {
+			a.l.Error("查询合作者失败", logger.Int64("aid", id), logger.Error(er))
+		}
+	}
 	go func() {
 		if err == nil {
 			//发送消息
 			er := a.producer.ProduceReadEvent(article.ReadEvent{
-				Aid: id,
-				Uid: uid,
+				Aid:    id,
+				Uid:    uid,
+				Device: device,
+				Ctime:  time.Now().UnixMilli(),
 			})
 			if er != nil {
 				a.l.Error("发送 ReadEvent 失败",
@@ -58,17 +119,53 @@ func (a *articleService) GetById(ctx context.Context, id int64) (domain.Article,
 	return a.repo.GetById(ctx, id)
 }
 
+func (a *articleService) RebuildPubFilter(ctx context.Context, batchSize int) error {
+	return a.repo.RebuildPubFilter(ctx, batchSize)
+}
+
 func (a *articleService) GetByAuthor(ctx context.Context, uid int64, offset int, limit int) ([]domain.Article, error) {
 	return a.repo.GetByAuthor(ctx, uid, offset, limit)
 }
 
 func (a *articleService) Withdraw(ctx context.Context, id int64, uid int64) error {
-	return a.repo.SyncStatus(ctx, id, uid, domain.ArticleStatusPrivate)
+	art, err := a.checkRole(ctx, id, uid, domain.ArticleRoleEditor)
+	if err != nil {
+		return err
+	}
+	err = a.repo.SyncStatus(ctx, id, art.Author.Id, domain.ArticleStatusPrivate)
+	if err != nil {
+		return err
+	}
+	a.audit(ctx, id, uid, domain.ArticleAuditWithdraw, "")
+	return nil
 }
 
 func (a *articleService) Publish(ctx context.Context, art domain.Article) (int64, error) {
+	if len(art.Content) > ArticleMaxContentLen {
+		return 0, ErrArticleTooLarge
+	}
+	var err error
+	art.Tags, err = normalizeTags(art.Tags)
+	if err != nil {
+		return 0, err
+	}
+	uid := art.Author.Id
+	if art.Id > 0 {
+		art.Author, err = a.checkEditor(ctx, art.Id, uid)
+		if err != nil {
+			return 0, err
+		}
+	}
+	if hits := a.moderate(art); len(hits) > 0 {
+		return a.submitReview(ctx, art, uid, hits)
+	}
 	art.Status = domain.ArticleStatusPublished
-	return a.repo.Sync(ctx, art)
+	id, err := a.repo.Sync(ctx, renderArticle(art))
+	if err != nil {
+		return 0, err
+	}
+	a.audit(ctx, id, uid, domain.ArticleAuditPublish, "")
+	return id, nil
 }
 
 func (a *articleService) PublishV1(ctx context.Context, art domain.Article) (int64, error) {
@@ -107,20 +204,47 @@ func NewArticleServiceV1(authorRepo repository.ArticleAuthorRepository, readerRe
 	}
 }
 
-func NewArticleService(repo repository.ArticleRepository, producer article.Producer) ArticleService {
+func NewArticleService(repo repository.ArticleRepository, collabRepo repository.ArticleCollaboratorRepository,
+	rankingRepo repository.RankingRepository, producer article.Producer,
+	matcher sensitive.Matcher, reviewRepo repository.ArticleReviewRepository, l logger.LoggerV1) ArticleService {
 	return &articleService{
-		repo:     repo,
-		producer: producer,
+		repo:        repo,
+		collabRepo:  collabRepo,
+		rankingRepo: rankingRepo,
+		producer:    producer,
+		matcher:     matcher,
+		reviewRepo:  reviewRepo,
+		l:           l,
 	}
 }
 
 func (a *articleService) Save(ctx context.Context, art domain.Article) (int64, error) {
+	if len(art.Content) > ArticleMaxContentLen {
+		return 0, ErrArticleTooLarge
+	}
+	var err error
+	art.Tags, err = normalizeTags(art.Tags)
+	if err != nil {
+		return 0, err
+	}
 	art.Status = domain.ArticleStatusUnpublished
+	uid := art.Author.Id
 	if art.Id > 0 {
-		err := a.repo.Update(ctx, art)
-		return art.Id, err
-	} else {
-		return a.repo.Create(ctx, art)
+		art.Author, err = a.checkEditor(ctx, art.Id, uid)
+		if err != nil {
+			return 0, err
+		}
+		err = a.repo.Update(ctx, art)
+		if err != nil {
+			return 0, err
+		}
+		a.audit(ctx, art.Id, uid, domain.ArticleAuditEdit, "")
+		return art.Id, nil
 	}
-
+	id, err := a.repo.Create(ctx, art)
+	if err != nil {
+		return 0, err
+	}
+	a.audit(ctx, id, uid, domain.ArticleAuditCreate, "")
+	return id, nil
 }
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"webook/internal/domain"
+	"webook/pkg/logger"
+)
+
+var (
+	ErrInvalidArticleRole  = errors.New("合作者只能是编辑或者读者")
+	ErrInvalidCollaborator = errors.New("不能把作者自己设置成合作者")
+)
+
+func (a *articleService) GetByIdForUser(ctx context.Context, id int64, uid int64) (domain.Article, error) {
+	art, err := a.checkRole(ctx, id, uid, domain.ArticleRoleViewer)
+	if err != nil {
+		return domain.Article{}, err
+	}
+	art.Collaborators, err = a.collabRepo.List(ctx, id)
+	return art, err
+}
+
+func (a *articleService) AddCollaborator(ctx context.Context, id int64, uid int64, collaborator int64, role domain.ArticleRole) error {
+	if role != domain.ArticleRoleViewer && role != domain.ArticleRoleEditor {
+		return ErrInvalidArticleRole
+	}
+	art, err := a.checkRole(ctx, id, uid, domain.ArticleRoleOwner)
+	if err != nil {
+		return err
+	}
+	if collaborator == art.Author.Id {
+		return ErrInvalidCollaborator
+	}
+	err = a.collabRepo.Save(ctx, id, domain.Collaborator{
+		Uid:     collaborator,
+		Role:    role,
+		Inviter: uid,
+	})
+	if err != nil {
+		return err
+	}
+	a.audit(ctx, id, uid, domain.ArticleAuditAddCollaborator,
+		"uid="+strconv.FormatInt(collaborator, 10)+" role="+role.String())
+	return nil
+}
+
+func (a *articleService) RemoveCollaborator(ctx context.Context, id int64, uid int64, collaborator int64) error {
+	// 自己退出不需要作者同意
+	if collaborator != uid {
+		_, err := a.checkRole(ctx, id, uid, domain.ArticleRoleOwner)
+		if err != nil {
+			return err
+		}
+	}
+	err := a.collabRepo.Delete(ctx, id, collaborator)
+	if err != nil {
+		return err
+	}
+	a.audit(ctx, id, uid, domain.ArticleAuditDelCollaborator, "uid="+strconv.FormatInt(collaborator, 10))
+	return nil
+}
+
+func (a *articleService) ListCollaborators(ctx context.Context, id int64, uid int64) ([]domain.Collaborator, error) {
+	_, err := a.checkRole(ctx, id, uid, domain.ArticleRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	return a.collabRepo.List(ctx, id)
+}
+
+func (a *articleService) ListCollaborating(ctx context.Context, uid int64, offset int, limit int) ([]domain.Article, error) {
+	return a.collabRepo.ListArticles(ctx, uid, offset, limit)
+}
+
+func (a *articleService) ListAudits(ctx context.Context, id int64, uid int64, offset int, limit int) ([]domain.ArticleAudit, error) {
+	_, err := a.checkRole(ctx, id, uid, domain.ArticleRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	return a.collabRepo.ListAudits(ctx, id, offset, limit)
+}
+
+// checkRole uid 在文章上的角色至少是 need，返回文章本身
+func (a *articleService) checkRole(ctx context.Context, id int64, uid int64, need domain.ArticleRole) (domain.Article, error) {
+	art, err := a.repo.GetById(ctx, id)
+	if err != nil {
+		return domain.Article{}, err
+	}
+	if art.Author.Id == uid {
+		return art, nil
+	}
+	role, err := a.collabRepo.GetRole(ctx, id, uid)
+	if err != nil {
+		return domain.Article{}, err
+	}
+	if role < need {
+		return domain.Article{}, ErrArticlePermissionDenied
+	}
+	return art, nil
+}
+
+// checkEditor 校验 uid 可以修改文章，返回文章的作者。
+// 合作者修改的时候作者不变，所以写回去的时候要用这个作者
+func (a *articleService) checkEditor(ctx context.Context, id int64, uid int64) (domain.Author, error) {
+	art, err := a.checkRole(ctx, id, uid, domain.ArticleRoleEditor)
+	return art.Author, err
+}
+
+// audit 记录失败不影响操作本身
+func (a *articleService) audit(ctx context.Context, id int64, uid int64, action string, detail string) {
+	err := a.collabRepo.AddAudit(ctx, domain.ArticleAudit{
+		ArticleId: id,
+		Uid:       uid,
+		Action:    action,
+		Detail:    detail,
+	})
+	if err != nil {
+		a.l.Error("记录文章操作失败",
+			logger.Int64("aid", id),
+			logger.Int64("uid", uid),
+			logger.String("action", action),
+			logger.Error(err))
+	}
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/events/article"
+	"webook/internal/repository"
+	"webook/pkg/logger"
+)
+
+// ArticleEventService 把发件箱里面的文章生命周期事件投递到 Kafka
+type ArticleEventService interface {
+	// Relay 按顺序投递一批事件，返回投递成功的数量。
+	// 遇到失败就停下来，下次从失败的那一条开始，避免同一篇文章的事件乱序
+	Relay(ctx context.Context, limit int) (int, error)
+	// Clean 删除 before 之前已经投递的事件
+	Clean(ctx context.Context, before time.Time) (int64, error)
+}
+
+type articleEventService struct {
+	repo     repository.ArticleEventRepository
+	producer article.Producer
+	l        logger.LoggerV1
+}
+
+func NewArticleEventService(repo repository.ArticleEventRepository, producer article.Producer, l logger.LoggerV1) ArticleEventService {
+	return &articleEventService{
+		repo:     repo,
+		producer: producer,
+		l:        l,
+	}
+}
+
+func (a *articleEventService) Relay(ctx context.Context, limit int) (int, error) {
+	evts, err := a.repo.ListPending(ctx, limit)
+	if err != nil {
+		return 0, err
+	}
+	for i, evt := range evts {
+		err = a.producer.ProduceLifecycleEvent(a.toLifecycleEvent(evt))
+		if err == nil {
+			err = a.produceLegacy(evt)
+		}
+		if err != nil {
+			a.l.Error("投递文章事件失败",
+				logger.Int64("eid", evt.Id),
+				logger.Int64("aid", evt.Article.Id),
+				logger.Int("retries", evt.Retries),
+				logger.Error(err))
+			er := a.repo.MarkFailed(ctx, evt.Id)
+			if er != nil {
+				a.l.Error("记录文章事件投递失败", logger.Int64("eid", evt.Id), logger.Error(er))
+			}
+			return i, err
+		}
+		// 这里失败了下次会重复投递，消费者按照 EventId 去重
+		err = a.repo.MarkSent(ctx, evt.Id)
+		if err != nil {
+			return i, err
+		}
+	}
+	return len(evts), nil
+}
+
+func (a *articleEventService) Clean(ctx context.Context, before time.Time) (int64, error) {
+	return a.repo.DeleteSent(ctx, before)
+}
+
+// produceLegacy 老的消费者只订阅了发表和撤回，失败了整个事件重新投递
+func (a *articleEventService) produceLegacy(evt domain.ArticleEvent) error {
+	switch article.LifecycleEventType(evt.Type) {
+	case article.LifecyclePublished:
+		return a.producer.ProducePublishEvent(article.PublishEvent{
+			Aid: evt.Article.Id,
+			Uid: evt.Article.Author.Id,
+		})
+	case article.LifecycleWithdrawn:
+		return a.producer.ProduceWithdrawEvent(article.WithdrawEvent{
+			Aid: evt.Article.Id,
+			Uid: evt.Article.Author.Id,
+		})
+	default:
+		return nil
+	}
+}
+
+func (a *articleEventService) toLifecycleEvent(evt domain.ArticleEvent) article.LifecycleEvent {
+	return article.LifecycleEvent{
+		SchemaVersion: article.LifecycleSchemaVersion,
+		EventId:       evt.Id,
+		Type:          article.LifecycleEventType(evt.Type),
+		Aid:           evt.Article.Id,
+		Uid:           evt.Article.Author.Id,
+		Status:        evt.Article.Status.ToUint8(),
+		Ctime:         evt.Ctime.UnixMilli(),
+	}
+}
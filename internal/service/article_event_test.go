@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/events/article"
+	evtmocks "webook/internal/events/article/mocks"
+	"webook/internal/repository"
+	repomocks "webook/internal/repository/mocks"
+	"webook/pkg/logger"
+)
+
+func TestArticleEventService_Relay(t *testing.T) {
+	ctime := time.UnixMilli(1000)
+	evts := []domain.ArticleEvent{
+		{Id: 1, Type: "published", Article: domain.Article{Id: 10, Author: domain.Author{Id: 2}}, Ctime: ctime},
+		{Id: 2, Type: "withdrawn", Article: domain.Article{Id: 10, Author: domain.Author{Id: 2}}, Ctime: ctime},
+		{Id: 3, Type: "updated", Article: domain.Article{Id: 11, Author: domain.Author{Id: 2}}, Ctime: ctime},
+	}
+	testCases := []struct {
+		name    string
+		mock    func(ctrl *gomock.Controller) (repository.ArticleEventRepository, article.Producer)
+		wantCnt int
+		wantErr error
+	}{
+		{
+			name: "发表和撤回同时投递到老的 topic",
+			mock: func(ctrl *gomock.Controller) (repository.ArticleEventRepository, article.Producer) {
+				repo := repomocks.NewMockArticleEventRepository(ctrl)
+				producer := evtmocks.NewMockProducer(ctrl)
+				repo.EXPECT().ListPending(gomock.Any(), 10).Return(evts, nil)
+				producer.EXPECT().ProduceLifecycleEvent(gomock.Any()).Return(nil).Times(3)
+				producer.EXPECT().ProducePublishEvent(article.PublishEvent{Aid: 10, Uid: 2}).Return(nil)
+				producer.EXPECT().ProduceWithdrawEvent(article.WithdrawEvent{Aid: 10, Uid: 2}).Return(nil)
+				repo.EXPECT().MarkSent(gomock.Any(), gomock.Any()).Return(nil).Times(3)
+				return repo, producer
+			},
+			wantCnt: 3,
+		},
+		{
+			name: "老的 topic 投递失败",
+			mock: func(ctrl *gomock.Controller) (repository.ArticleEventRepository, article.Producer) {
+				repo := repomocks.NewMockArticleEventRepository(ctrl)
+				producer := evtmocks.NewMockProducer(ctrl)
+				repo.EXPECT().ListPending(gomock.Any(), 10).Return(evts, nil)
+				producer.EXPECT().ProduceLifecycleEvent(gomock.Any()).Return(nil)
+				producer.EXPECT().ProducePublishEvent(gomock.Any()).Return(errors.New("mock kafka error"))
+				// 停下来，下次整个事件重新投递
+				repo.EXPECT().MarkFailed(gomock.Any(), int64(1)).Return(nil)
+				return repo, producer
+			},
+			wantErr: errors.New("mock kafka error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo, producer := tc.mock(ctrl)
+			svc := NewArticleEventService(repo, producer, logger.NewNopLogger())
+			cnt, err := svc.Relay(context.Background(), 10)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantCnt, cnt)
+		})
+	}
+}
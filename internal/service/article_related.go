@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strings"
+	intrv1 "webook/api/proto/gen/intr/v1"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	"webook/pkg/logger"
+	"webook/pkg/searchx"
+)
+
+//go:generate mockgen -source=./article_related.go -package=svcmocks -destination=./mocks/article_related.mock.go ArticleRelatedService
+type ArticleRelatedService interface {
+	// Related 已经发表的文章的相关推荐，还没有计算过的文章用作者的其它文章代替
+	Related(ctx context.Context, id int64, limit int) ([]domain.Article, error)
+	// Recompute 重新计算最近发表的文章的相关推荐，返回计算的文章数量
+	Recompute(ctx context.Context) (int, error)
+}
+
+const (
+	// RelatedSize 每篇文章保存的相关文章数量
+	RelatedSize = 10
+	// RelatedMaxArticles 只计算最近修改的这么多篇文章，TF-IDF 全部在内存里面算
+	RelatedMaxArticles = 20000
+
+	relatedCandidateSize = 50
+	relatedMaxTerms      = 64
+	relatedBatchSize     = 100
+
+	// 三种信号的权重，内容相似度和共同点赞都归一化到 [0, 1]
+	relatedWeightText   = 0.6
+	relatedWeightCoLike = 0.3
+	relatedWeightAuthor = 0.1
+)
+
+type articleRelatedService struct {
+	repo    repository.ArticleRelatedRepository
+	artRepo repository.ArticleRepository
+	intrSvc intrv1.InteractiveServiceClient
+	biz     string
+	l       logger.LoggerV1
+}
+
+func NewArticleRelatedService(repo repository.ArticleRelatedRepository, artRepo repository.ArticleRepository,
+	intrSvc intrv1.InteractiveServiceClient, l logger.LoggerV1) ArticleRelatedService {
+	return &articleRelatedService{
+		repo:    repo,
+		artRepo: artRepo,
+		intrSvc: intrSvc,
+		biz:     "article",
+		l:       l,
+	}
+}
+
+func (s *articleRelatedService) Related(ctx context.Context, id int64, limit int) ([]domain.Article, error) {
+	art, err := s.artRepo.GetPubById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if art.Status != domain.ArticleStatusPublished {
+		return nil, repository.ErrArticleNotFound
+	}
+	related, err := s.repo.Get(ctx, id)
+	if err != nil {
+		// 新发表的文章要等下一次计算
+		arts, er := s.artRepo.ListPubByAuthor(ctx, art.Author.Id, 0, limit+1)
+		if er != nil {
+			return nil, er
+		}
+		return s.filter(arts, id, limit), nil
+	}
+	res := make([]domain.Article, 0, limit)
+	for _, r := range related {
+		if len(res) >= limit {
+			break
+		}
+		// 计算之后可能已经撤回或者删除了
+		a, er := s.artRepo.GetPubById(ctx, r.Id)
+		if er == repository.ErrArticleNotFound {
+			continue
+		}
+		if er != nil {
+			return nil, er
+		}
+		if a.Status == domain.ArticleStatusPublished {
+			res = append(res, a)
+		}
+	}
+	return res, nil
+}
+
+func (s *articleRelatedService) filter(arts []domain.Article, id int64, limit int) []domain.Article {
+	res := make([]domain.Article, 0, len(arts))
+	for _, a := range arts {
+		if a.Id != id && len(res) < limit {
+			res = append(res, a)
+		}
+	}
+	return res
+}
+
+func (s *articleRelatedService) Recompute(ctx context.Context) (int, error) {
+	tfidf := searchx.NewTFIDF(map[string]float64{
+		searchFieldTitle:   3,
+		searchFieldTags:    2,
+		searchFieldContent: 1,
+	}, relatedMaxTerms)
+	var (
+		cursor domain.ArticleCursor
+		// authors 文章的作者，也用来判断共同点赞的文章是不是还在线上
+		authors = make(map[int64]int64)
+		// byAuthor 作者最近修改的文章，最近的在前面
+		byAuthor = make(map[int64][]int64)
+	)
+	for tfidf.Len() < RelatedMaxArticles {
+		arts, err := s.artRepo.ListPubCursor(ctx, cursor, relatedBatchSize)
+		if err != nil {
+			return 0, err
+		}
+		for _, art := range arts {
+			// 列表里面没有标签
+			full, err := s.artRepo.GetPubById(ctx, art.Id)
+			if err == repository.ErrArticleNotFound {
+				continue
+			}
+			if err != nil {
+				return 0, err
+			}
+			tfidf.Add(searchx.Doc{
+				Id: full.Id,
+				Fields: map[string]string{
+					searchFieldTitle:   full.Title,
+					searchFieldTags:    strings.Join(full.Tags, "\n"),
+					searchFieldContent: full.Content,
+				},
+			})
+			authors[full.Id] = full.Author.Id
+			byAuthor[full.Author.Id] = append(byAuthor[full.Author.Id], full.Id)
+		}
+		cursor = domain.NextArticleCursor(arts, relatedBatchSize)
+		if cursor.IsZero() {
+			break
+		}
+	}
+
+	sims := tfidf.Similar(relatedCandidateSize)
+	batch := make(map[int64][]domain.RelatedArticle, relatedBatchSize)
+	for id, authorId := range authors {
+		scores := make(map[int64]float64)
+		for _, sim := range sims[id] {
+			scores[sim.Id] += relatedWeightText * sim.Score
+		}
+		s.addCoLiked(ctx, id, authors, scores)
+		sameAuthor := byAuthor[authorId]
+		if len(sameAuthor) > relatedCandidateSize {
+			sameAuthor = sameAuthor[:relatedCandidateSize]
+		}
+		for _, other := range sameAuthor {
+			if other != id {
+				scores[other] += relatedWeightAuthor
+			}
+		}
+		batch[id] = s.topN(scores, RelatedSize)
+		if len(batch) >= relatedBatchSize {
+			err := s.repo.SetBatch(ctx, batch)
+			if err != nil {
+				return 0, err
+			}
+			batch = make(map[int64][]domain.RelatedArticle, relatedBatchSize)
+		}
+	}
+	err := s.repo.SetBatch(ctx, batch)
+	if err != nil {
+		return 0, err
+	}
+	return len(authors), nil
+}
+
+// addCoLiked 共同点赞的人数按照最多的那篇归一化
+func (s *articleRelatedService) addCoLiked(ctx context.Context, id int64,
+	authors map[int64]int64, scores map[int64]float64) {
+	resp, err := s.intrSvc.GetCoLiked(ctx, &intrv1.GetCoLikedRequest{
+		Biz:   s.biz,
+		BizId: id,
+		Limit: relatedCandidateSize,
+	})
+	if err != nil {
+		// 少了共同点赞还有内容相似度，不影响整个任务
+		s.l.Warn("查询共同点赞的文章失败", logger.Int64("id", id), logger.Error(err))
+		return
+	}
+	items := resp.GetItems()
+	if len(items) == 0 || items[0].GetCnt() <= 0 {
+		return
+	}
+	maxCnt := float64(items[0].GetCnt())
+	for _, item := range items {
+		if _, ok := authors[item.GetBizId()]; !ok {
+			continue
+		}
+		scores[item.GetBizId()] += relatedWeightCoLike * float64(item.GetCnt()) / maxCnt
+	}
+}
+
+func (s *articleRelatedService) topN(scores map[int64]float64, n int) []domain.RelatedArticle {
+	res := make([]domain.RelatedArticle, 0, len(scores))
+	for id, score := range scores {
+		res = append(res, domain.RelatedArticle{Id: id, Score: score})
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Score != res[j].Score {
+			return res[i].Score > res[j].Score
+		}
+		return res[i].Id > res[j].Id
+	})
+	if len(res) > n {
+		res = res[:n]
+	}
+	return res
+}
@@ -0,0 +1,23 @@
+package service
+
+import (
+	"errors"
+	"webook/internal/domain"
+	"webook/pkg/markdown"
+)
+
+const (
+	// articleSummaryLen 和 domain.Article.Abstract 截断的长度保持一致
+	articleSummaryLen = 128
+	// ArticleMaxContentLen 正文最大的字节数，和导入的单篇文章的限制一致
+	ArticleMaxContentLen = ArchiveMaxFileSize
+)
+
+var ErrArticleTooLarge = errors.New("文章内容过长")
+
+// renderArticle 发表的时候把 markdown 渲染成清洗过的 HTML，再从 HTML 里面提取摘要
+func renderArticle(art domain.Article) domain.Article {
+	art.HTML = markdown.ToHTML(art.Content)
+	art.Summary = markdown.PlainText(art.HTML, articleSummaryLen)
+	return art
+}
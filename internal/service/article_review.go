@@ -0,0 +1,210 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	"webook/pkg/logger"
+)
+
+var (
+	// ErrArticlePendingReview 文章已经保存，但是命中了敏感词，要等管理员审核之后才会发表
+	ErrArticlePendingReview = errors.New("文章需要审核")
+	ErrReviewNotFound       = errors.New("审核记录不存在")
+	ErrReviewNotPending     = repository.ErrReviewNotPending
+	// ErrReviewOutdated 提交审核之后作者又修改了文章，这次审核作废
+	ErrReviewOutdated = errors.New("文章已经修改，审核作废")
+	ErrInvalidReview  = errors.New("驳回的原因不能为空，最多 512 个字")
+)
+
+// moderate 没有命中敏感词返回 nil，否则返回命中的词
+func (a *articleService) moderate(art domain.Article) []string {
+	return a.matcher.Match(art.Title + "\n" + art.Content)
+}
+
+// submitReview 保存文章并且进入审核队列，线上库不受影响
+func (a *articleService) submitReview(ctx context.Context, art domain.Article, uid int64, hits []string) (int64, error) {
+	art.Status = domain.ArticleStatusPendingReview
+	id := art.Id
+	var err error
+	if art.Id > 0 {
+		err = a.repo.Update(ctx, art)
+	} else {
+		id, err = a.repo.Create(ctx, art)
+	}
+	if err != nil {
+		return 0, err
+	}
+	err = a.createReview(ctx, id, art, uid, hits)
+	if err != nil {
+		return 0, err
+	}
+	return id, ErrArticlePendingReview
+}
+
+func (a *articleService) createReview(ctx context.Context, id int64, art domain.Article, uid int64, hits []string) error {
+	_, err := a.reviewRepo.Create(ctx, domain.ArticleReview{
+		ArticleId: id,
+		Title:     art.Title,
+		Author:    art.Author.Id,
+		Submitter: uid,
+		Hits:      hits,
+		Status:    domain.ReviewStatusPending,
+	})
+	if err != nil {
+		return err
+	}
+	a.audit(ctx, id, uid, domain.ArticleAuditSubmitReview, strings.Join(hits, ","))
+	return nil
+}
+
+func (a *articleService) ListReviews(ctx context.Context, id int64, uid int64, offset int, limit int) ([]domain.ArticleReview, error) {
+	_, err := a.checkRole(ctx, id, uid, domain.ArticleRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	return a.reviewRepo.ListByArticle(ctx, id, offset, limit)
+}
+
+//go:generate mockgen -source=./article_review.go -package=svcmocks -destination=./mocks/article_review.mock.go ArticleReviewService
+type ArticleReviewService interface {
+	// ListPending 等待审核的文章，先提交的在前面
+	ListPending(ctx context.Context, offset int, limit int) ([]domain.ArticleReview, error)
+	// Approve 通过之后按照文章当前的内容发表，并且通知作者
+	Approve(ctx context.Context, id int64, reviewer int64) error
+	// Reject 驳回之后文章退回草稿状态，并且通知作者
+	Reject(ctx context.Context, id int64, reviewer int64, reason string) error
+}
+
+type articleReviewService struct {
+	repo       repository.ArticleReviewRepository
+	artRepo    repository.ArticleRepository
+	collabRepo repository.ArticleCollaboratorRepository
+	notifySvc  NotificationService
+	l          logger.LoggerV1
+}
+
+func NewArticleReviewService(repo repository.ArticleReviewRepository, artRepo repository.ArticleRepository,
+	collabRepo repository.ArticleCollaboratorRepository, notifySvc NotificationService, l logger.LoggerV1) ArticleReviewService {
+	return &articleReviewService{
+		repo:       repo,
+		artRepo:    artRepo,
+		collabRepo: collabRepo,
+		notifySvc:  notifySvc,
+		l:          l,
+	}
+}
+
+func (s *articleReviewService) ListPending(ctx context.Context, offset int, limit int) ([]domain.ArticleReview, error) {
+	return s.repo.ListByStatus(ctx, domain.ReviewStatusPending, offset, limit)
+}
+
+func (s *articleReviewService) Approve(ctx context.Context, id int64, reviewer int64) error {
+	review, err := s.pending(ctx, id)
+	if err != nil {
+		return err
+	}
+	art, err := s.artRepo.GetById(ctx, review.ArticleId)
+	if err != nil {
+		return err
+	}
+	if art.Status != domain.ArticleStatusPendingReview || !art.DeletedAt.IsZero() {
+		return s.outdated(ctx, review, reviewer)
+	}
+	art.Status = domain.ArticleStatusPublished
+	// 查出来之后作者又修改了的话不能发表，不然发表的是没有审核过的内容
+	err = s.artRepo.PublishReviewed(ctx, renderArticle(art))
+	if err == repository.ErrArticleNotPendingReview {
+		return s.outdated(ctx, review, reviewer)
+	}
+	if err != nil {
+		return err
+	}
+	err = s.repo.Resolve(ctx, id, domain.ReviewStatusApproved, reviewer, "")
+	if err != nil {
+		return err
+	}
+	s.audit(ctx, review.ArticleId, reviewer, domain.ArticleAuditApprove, "")
+	s.notify(ctx, domain.Notification{
+		Uid:     review.Author,
+		Type:    domain.NotificationArticleApproved,
+		BizId:   review.ArticleId,
+		Content: "你的文章《" + art.Title + "》已经通过审核并发表",
+	})
+	return nil
+}
+
+func (s *articleReviewService) Reject(ctx context.Context, id int64, reviewer int64, reason string) error {
+	reason = strings.TrimSpace(reason)
+	if reason == "" || len([]rune(reason)) > 512 {
+		return ErrInvalidReview
+	}
+	review, err := s.pending(ctx, id)
+	if err != nil {
+		return err
+	}
+	// 只有还在审核的文章才会退回草稿，作者已经修改过的话不动它
+	err = s.artRepo.ResolveReview(ctx, review.ArticleId, review.Author, domain.ArticleStatusUnpublished)
+	if err == repository.ErrArticleNotPendingReview {
+		return s.outdated(ctx, review, reviewer)
+	}
+	if err != nil {
+		return err
+	}
+	err = s.repo.Resolve(ctx, id, domain.ReviewStatusRejected, reviewer, reason)
+	if err != nil {
+		return err
+	}
+	s.audit(ctx, review.ArticleId, reviewer, domain.ArticleAuditReject, reason)
+	s.notify(ctx, domain.Notification{
+		Uid:     review.Author,
+		Type:    domain.NotificationArticleRejected,
+		BizId:   review.ArticleId,
+		Content: "你的文章《" + review.Title + "》没有通过审核：" + reason,
+	})
+	return nil
+}
+
+func (s *articleReviewService) pending(ctx context.Context, id int64) (domain.ArticleReview, error) {
+	review, err := s.repo.FindById(ctx, id)
+	if err == repository.ErrReviewNotFound {
+		return domain.ArticleReview{}, ErrReviewNotFound
+	}
+	if err != nil {
+		return domain.ArticleReview{}, err
+	}
+	if review.Status != domain.ReviewStatusPending {
+		return domain.ArticleReview{}, ErrReviewNotPending
+	}
+	return review, nil
+}
+
+func (s *articleReviewService) outdated(ctx context.Context, review domain.ArticleReview, reviewer int64) error {
+	err := s.repo.Resolve(ctx, review.Id, domain.ReviewStatusCancelled, reviewer, "")
+	if err != nil && err != repository.ErrReviewNotPending {
+		return err
+	}
+	return ErrReviewOutdated
+}
+
+// audit 和 notify 失败都不影响审核结果
+func (s *articleReviewService) audit(ctx context.Context, id int64, reviewer int64, action string, detail string) {
+	err := s.collabRepo.AddAudit(ctx, domain.ArticleAudit{
+		ArticleId: id,
+		Uid:       reviewer,
+		Action:    action,
+		Detail:    detail,
+	})
+	if err != nil {
+		s.l.Error("记录文章操作失败", logger.Int64("aid", id), logger.String("action", action), logger.Error(err))
+	}
+}
+
+func (s *articleReviewService) notify(ctx context.Context, n domain.Notification) {
+	err := s.notifySvc.Notify(ctx, n)
+	if err != nil {
+		s.l.Error("通知作者审核结果失败", logger.Int64("uid", n.Uid), logger.Int64("aid", n.BizId), logger.Error(err))
+	}
+}
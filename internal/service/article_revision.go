@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"webook/internal/domain"
+	"webook/pkg/diffx"
+)
+
+var ErrArticlePermissionDenied = errors.New("无权操作该文章")
+
+func (a *articleService) ListRevisions(ctx context.Context, id int64, uid int64, offset int, limit int) ([]domain.ArticleRevision, error) {
+	_, err := a.checkRole(ctx, id, uid, domain.ArticleRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	return a.repo.ListRevisions(ctx, id, offset, limit)
+}
+
+func (a *articleService) GetRevision(ctx context.Context, id int64, uid int64, version int64) (domain.ArticleRevision, error) {
+	_, err := a.checkRole(ctx, id, uid, domain.ArticleRoleViewer)
+	if err != nil {
+		return domain.ArticleRevision{}, err
+	}
+	return a.repo.GetRevision(ctx, id, version)
+}
+
+func (a *articleService) DiffRevisions(ctx context.Context, id int64, uid int64, from int64, to int64) ([]diffx.Line, error) {
+	_, err := a.checkRole(ctx, id, uid, domain.ArticleRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	src, err := a.repo.GetRevision(ctx, id, from)
+	if err != nil {
+		return nil, err
+	}
+	dst, err := a.repo.GetRevision(ctx, id, to)
+	if err != nil {
+		return nil, err
+	}
+	return diffx.Lines(src.Content, dst.Content)
+}
+
+// RestoreRevision 把历史版本恢复成草稿，恢复本身也会产生一个新的版本
+func (a *articleService) RestoreRevision(ctx context.Context, id int64, uid int64, version int64) error {
+	author, err := a.checkEditor(ctx, id, uid)
+	if err != nil {
+		return err
+	}
+	rev, err := a.repo.GetRevision(ctx, id, version)
+	if err != nil {
+		return err
+	}
+	err = a.repo.Update(ctx, domain.Article{
+		Id:      id,
+		Title:   rev.Title,
+		Content: rev.Content,
+		Author:  author,
+		Status:  domain.ArticleStatusUnpublished,
+	})
+	if err != nil {
+		return err
+	}
+	a.audit(ctx, id, uid, domain.ArticleAuditRestoreRevision, "version="+strconv.FormatInt(version, 10))
+	return nil
+}
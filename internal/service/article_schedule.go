@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	"webook/pkg/logger"
+)
+
+var ErrInvalidPublishTime = errors.New("定时发表时间必须晚于当前时间")
+
+// SchedulePublish 保存文章并且等待 publishAt 之后由定时任务发表，
+// 在此之前线上库不受影响
+func (a *articleService) SchedulePublish(ctx context.Context, art domain.Article, publishAt time.Time) (int64, error) {
+	if !publishAt.After(time.Now()) {
+		return 0, ErrInvalidPublishTime
+	}
+	if len(art.Content) > ArticleMaxContentLen {
+		return 0, ErrArticleTooLarge
+	}
+	var err error
+	art.Tags, err = normalizeTags(art.Tags)
+	if err != nil {
+		return 0, err
+	}
+	art.Status = domain.ArticleStatusScheduled
+	art.PublishAt = publishAt
+	uid := art.Author.Id
+	id := art.Id
+	if art.Id > 0 {
+		art.Author, err = a.checkEditor(ctx, art.Id, uid)
+		if err != nil {
+			return 0, err
+		}
+		err = a.repo.Update(ctx, art)
+	} else {
+		id, err = a.repo.Create(ctx, art)
+	}
+	if err != nil {
+		return 0, err
+	}
+	a.audit(ctx, id, uid, domain.ArticleAuditSchedule, publishAt.Format(time.DateTime))
+	return id, nil
+}
+
+// CancelSchedule 取消定时发表，文章退回草稿状态
+func (a *articleService) CancelSchedule(ctx context.Context, id int64, uid int64) error {
+	author, err := a.checkEditor(ctx, id, uid)
+	if err != nil {
+		return err
+	}
+	err = a.repo.UpdateSchedule(ctx, id, author.Id, domain.ArticleStatusUnpublished, time.Time{})
+	if err != nil {
+		return err
+	}
+	a.audit(ctx, id, uid, domain.ArticleAuditCancelSchedule, "")
+	return nil
+}
+
+func (a *articleService) Reschedule(ctx context.Context, id int64, uid int64, publishAt time.Time) error {
+	if !publishAt.After(time.Now()) {
+		return ErrInvalidPublishTime
+	}
+	author, err := a.checkEditor(ctx, id, uid)
+	if err != nil {
+		return err
+	}
+	err = a.repo.UpdateSchedule(ctx, id, author.Id, domain.ArticleStatusScheduled, publishAt)
+	if err != nil {
+		return err
+	}
+	a.audit(ctx, id, uid, domain.ArticleAuditSchedule, publishAt.Format(time.DateTime))
+	return nil
+}
+
+func (a *articleService) PublishDue(ctx context.Context, now time.Time, limit int) (int, error) {
+	arts, err := a.repo.ListScheduled(ctx, now, limit)
+	if err != nil {
+		return 0, err
+	}
+	var lastErr error
+	for _, art := range arts {
+		if hits := a.moderate(art); len(hits) > 0 {
+			er := a.scheduleReview(ctx, art, hits)
+			if er != nil {
+				lastErr = er
+				a.l.Error("定时发表的文章提交审核失败",
+					logger.Int64("aid", art.Id),
+					logger.Int64("uid", art.Author.Id),
+					logger.Error(er))
+			}
+			continue
+		}
+		art.Status = domain.ArticleStatusPublished
+		er := a.repo.PublishScheduled(ctx, renderArticle(art))
+		if errors.Is(er, repository.ErrArticleNotScheduled) {
+			// 查出来之后作者取消或者修改了定时发表，以作者的操作为准
+			a.l.Info("定时发表的文章已经被修改，跳过",
+				logger.Int64("aid", art.Id),
+				logger.Int64("uid", art.Author.Id))
+			continue
+		}
+		if er != nil {
+			lastErr = er
+			a.l.Error("定时发表文章失败",
+				logger.Int64("aid", art.Id),
+				logger.Int64("uid", art.Author.Id),
+				logger.Error(er))
+		}
+	}
+	return len(arts), lastErr
+}
+
+// scheduleReview 定时发表的文章命中了敏感词，到期之后转入审核
+func (a *articleService) scheduleReview(ctx context.Context, art domain.Article, hits []string) error {
+	err := a.repo.UpdateSchedule(ctx, art.Id, art.Author.Id, domain.ArticleStatusPendingReview, time.Time{})
+	if err != nil {
+		return err
+	}
+	return a.createReview(ctx, art.Id, art, art.Author.Id, hits)
+}
@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	repomocks "webook/internal/repository/mocks"
+	"webook/pkg/logger"
+	"webook/pkg/sensitive"
+)
+
+func TestArticleService_SchedulePublish(t *testing.T) {
+	publishAt := time.Now().Add(time.Hour).Truncate(time.Second)
+	testCases := []struct {
+		name      string
+		mock      func(repo *repomocks.MockArticleRepository, collabRepo *repomocks.MockArticleCollaboratorRepository)
+		art       domain.Article
+		publishAt time.Time
+		wantId    int64
+		wantErr   error
+	}{
+		{
+			name: "新建定时发表",
+			mock: func(repo *repomocks.MockArticleRepository, collabRepo *repomocks.MockArticleCollaboratorRepository) {
+				repo.EXPECT().Create(gomock.Any(), domain.Article{
+					Title:     "标题",
+					Content:   "内容",
+					Author:    domain.Author{Id: 1},
+					Status:    domain.ArticleStatusScheduled,
+					PublishAt: publishAt,
+				}).Return(int64(3), nil)
+				collabRepo.EXPECT().AddAudit(gomock.Any(), domain.ArticleAudit{
+					ArticleId: 3,
+					Uid:       1,
+					Action:    domain.ArticleAuditSchedule,
+					Detail:    publishAt.Format(time.DateTime),
+				}).Return(nil)
+			},
+			art:       domain.Article{Title: "标题", Content: "内容", Author: domain.Author{Id: 1}},
+			publishAt: publishAt,
+			wantId:    3,
+		},
+		{
+			name: "修改已有的文章",
+			mock: func(repo *repomocks.MockArticleRepository, collabRepo *repomocks.MockArticleCollaboratorRepository) {
+				repo.EXPECT().GetById(gomock.Any(), int64(3)).
+					Return(domain.Article{Id: 3, Author: domain.Author{Id: 1}}, nil)
+				repo.EXPECT().Update(gomock.Any(), domain.Article{
+					Id:        3,
+					Title:     "标题",
+					Content:   "内容",
+					Author:    domain.Author{Id: 1},
+					Status:    domain.ArticleStatusScheduled,
+					PublishAt: publishAt,
+				}).Return(nil)
+				collabRepo.EXPECT().AddAudit(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			art:       domain.Article{Id: 3, Title: "标题", Content: "内容", Author: domain.Author{Id: 1}},
+			publishAt: publishAt,
+			wantId:    3,
+		},
+		{
+			name: "不是作者也不是合作者",
+			mock: func(repo *repomocks.MockArticleRepository, collabRepo *repomocks.MockArticleCollaboratorRepository) {
+				repo.EXPECT().GetById(gomock.Any(), int64(3)).
+					Return(domain.Article{Id: 3, Author: domain.Author{Id: 2}}, nil)
+				collabRepo.EXPECT().GetRole(gomock.Any(), int64(3), int64(1)).
+					Return(domain.ArticleRoleUnknown, nil)
+			},
+			art:       domain.Article{Id: 3, Title: "标题", Content: "内容", Author: domain.Author{Id: 1}},
+			publishAt: publishAt,
+			wantErr:   ErrArticlePermissionDenied,
+		},
+		{
+			name:      "发表时间已经过了",
+			mock:      func(repo *repomocks.MockArticleRepository, collabRepo *repomocks.MockArticleCollaboratorRepository) {},
+			art:       domain.Article{Title: "标题", Content: "内容", Author: domain.Author{Id: 1}},
+			publishAt: time.Now().Add(-time.Minute),
+			wantErr:   ErrInvalidPublishTime,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc, repo, collabRepo := newScheduleService(ctrl)
+			tc.mock(repo, collabRepo)
+			id, err := svc.SchedulePublish(context.Background(), tc.art, tc.publishAt)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantId, id)
+		})
+	}
+}
+
+func TestArticleService_CancelSchedule(t *testing.T) {
+	testCases := []struct {
+		name    string
+		mock    func(repo *repomocks.MockArticleRepository, collabRepo *repomocks.MockArticleCollaboratorRepository)
+		wantErr error
+	}{
+		{
+			name: "取消成功",
+			mock: func(repo *repomocks.MockArticleRepository, collabRepo *repomocks.MockArticleCollaboratorRepository) {
+				repo.EXPECT().GetById(gomock.Any(), int64(3)).
+					Return(domain.Article{Id: 3, Author: domain.Author{Id: 1}}, nil)
+				repo.EXPECT().UpdateSchedule(gomock.Any(), int64(3), int64(1),
+					domain.ArticleStatus(domain.ArticleStatusUnpublished), time.Time{}).Return(nil)
+				collabRepo.EXPECT().AddAudit(gomock.Any(), domain.ArticleAudit{
+					ArticleId: 3,
+					Uid:       1,
+					Action:    domain.ArticleAuditCancelSchedule,
+				}).Return(nil)
+			},
+		},
+		{
+			name: "已经被定时任务发表了",
+			mock: func(repo *repomocks.MockArticleRepository, collabRepo *repomocks.MockArticleCollaboratorRepository) {
+				repo.EXPECT().GetById(gomock.Any(), int64(3)).
+					Return(domain.Article{Id: 3, Author: domain.Author{Id: 1}}, nil)
+				repo.EXPECT().UpdateSchedule(gomock.Any(), int64(3), int64(1),
+					domain.ArticleStatus(domain.ArticleStatusUnpublished), time.Time{}).
+					Return(errors.New("ID不对或者文章不是定时发表状态"))
+			},
+			wantErr: errors.New("ID不对或者文章不是定时发表状态"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc, repo, collabRepo := newScheduleService(ctrl)
+			tc.mock(repo, collabRepo)
+			err := svc.CancelSchedule(context.Background(), 3, 1)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func TestArticleService_PublishDue(t *testing.T) {
+	now := time.Now()
+	art1 := domain.Article{Id: 1, Title: "标题1", Content: "内容1", Author: domain.Author{Id: 1},
+		Status: domain.ArticleStatusScheduled, PublishAt: now.Add(-time.Minute), Utime: now.Add(-time.Hour)}
+	art2 := domain.Article{Id: 2, Title: "标题2", Content: "内容2", Author: domain.Author{Id: 2},
+		Status: domain.ArticleStatusScheduled, PublishAt: now.Add(-time.Minute), Utime: now.Add(-time.Hour)}
+	published := func(art domain.Article) domain.Article {
+		art.Status = domain.ArticleStatusPublished
+		return renderArticle(art)
+	}
+	testCases := []struct {
+		name    string
+		mock    func(repo *repomocks.MockArticleRepository)
+		wantCnt int
+		wantErr error
+	}{
+		{
+			name: "全部发表",
+			mock: func(repo *repomocks.MockArticleRepository) {
+				repo.EXPECT().ListScheduled(gomock.Any(), now, 10).
+					Return([]domain.Article{art1, art2}, nil)
+				repo.EXPECT().PublishScheduled(gomock.Any(), published(art1)).Return(nil)
+				repo.EXPECT().PublishScheduled(gomock.Any(), published(art2)).Return(nil)
+			},
+			wantCnt: 2,
+		},
+		{
+			// 查出来之后作者取消了定时发表，不能把查出来的旧数据发表出去
+			name: "和取消并发",
+			mock: func(repo *repomocks.MockArticleRepository) {
+				repo.EXPECT().ListScheduled(gomock.Any(), now, 10).
+					Return([]domain.Article{art1, art2}, nil)
+				repo.EXPECT().PublishScheduled(gomock.Any(), published(art1)).
+					Return(repository.ErrArticleNotScheduled)
+				repo.EXPECT().PublishScheduled(gomock.Any(), published(art2)).Return(nil)
+			},
+			wantCnt: 2,
+		},
+		{
+			name: "发表失败继续发表下一篇",
+			mock: func(repo *repomocks.MockArticleRepository) {
+				repo.EXPECT().ListScheduled(gomock.Any(), now, 10).
+					Return([]domain.Article{art1, art2}, nil)
+				repo.EXPECT().PublishScheduled(gomock.Any(), published(art1)).
+					Return(errors.New("mock db error"))
+				repo.EXPECT().PublishScheduled(gomock.Any(), published(art2)).Return(nil)
+			},
+			wantCnt: 2,
+			wantErr: errors.New("mock db error"),
+		},
+		{
+			name: "查询失败",
+			mock: func(repo *repomocks.MockArticleRepository) {
+				repo.EXPECT().ListScheduled(gomock.Any(), now, 10).
+					Return(nil, errors.New("mock db error"))
+			},
+			wantErr: errors.New("mock db error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc, repo, _ := newScheduleService(ctrl)
+			tc.mock(repo)
+			cnt, err := svc.PublishDue(context.Background(), now, 10)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantCnt, cnt)
+		})
+	}
+}
+
+func newScheduleService(ctrl *gomock.Controller) (ArticleService,
+	*repomocks.MockArticleRepository, *repomocks.MockArticleCollaboratorRepository) {
+	repo := repomocks.NewMockArticleRepository(ctrl)
+	collabRepo := repomocks.NewMockArticleCollaboratorRepository(ctrl)
+	svc := NewArticleService(repo, collabRepo, nil, nil, sensitive.NewTrie(nil), nil, logger.NewNopLogger())
+	return svc, repo, collabRepo
+}
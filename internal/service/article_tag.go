@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode/utf8"
+	"webook/internal/domain"
+)
+
+const (
+	maxTagsPerArticle = 5
+	maxTagLength      = 20
+)
+
+var ErrInvalidTags = errors.New("标签数量过多或者标签过长")
+
+func (a *articleService) ListTags(ctx context.Context, offset int, limit int) ([]domain.Tag, error) {
+	return a.repo.ListTags(ctx, offset, limit)
+}
+
+func (a *articleService) ListPubByTag(ctx context.Context, tag string, offset int, limit int) ([]domain.Article, error) {
+	return a.repo.ListPubByTag(ctx, strings.TrimSpace(tag), offset, limit)
+}
+
+// normalizeTags 去掉空白和重复的标签，保持原本的顺序。
+// nil 原样返回，表示不修改标签
+func normalizeTags(tags []string) ([]string, error) {
+	if tags == nil {
+		return nil, nil
+	}
+	res := make([]string, 0, len(tags))
+	seen := make(map[string]struct{}, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+		if utf8.RuneCountInString(tag) > maxTagLength {
+			return nil, ErrInvalidTags
+		}
+		if _, ok := seen[tag]; ok {
+			continue
+		}
+		seen[tag] = struct{}{}
+		res = append(res, tag)
+	}
+	if len(res) > maxTagsPerArticle {
+		return nil, ErrInvalidTags
+	}
+	return res, nil
+}
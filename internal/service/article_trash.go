@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	"webook/pkg/logger"
+)
+
+// ArticleTrashRetention 回收站里面的文章保留的时间，过期之后就会被彻底删除
+const ArticleTrashRetention = 30 * 24 * time.Hour
+
+var ErrArticleNotInTrash = repository.ErrArticleNotInTrash
+
+func (a *articleService) Delete(ctx context.Context, id int64, uid int64) error {
+	err := a.repo.Delete(ctx, id, uid)
+	if err != nil {
+		return err
+	}
+	er := a.rankingRepo.RemoveFromTopN(ctx, id)
+	if er != nil {
+		a.l.Error("从热榜删除文章失败",
+			logger.Int64("aid", id),
+			logger.Int64("uid", uid),
+			logger.Error(er))
+	}
+	a.audit(ctx, id, uid, domain.ArticleAuditDelete, "")
+	return nil
+}
+
+// Restore 回收站只有作者自己能操作，DAO 会校验作者
+func (a *articleService) Restore(ctx context.Context, id int64, uid int64) error {
+	err := a.repo.Restore(ctx, id, uid, time.Now().Add(-ArticleTrashRetention))
+	if err != nil {
+		return err
+	}
+	a.audit(ctx, id, uid, domain.ArticleAuditRestore, "")
+	return nil
+}
+
+func (a *articleService) ListTrash(ctx context.Context, uid int64, offset int, limit int) ([]domain.Article, error) {
+	return a.repo.ListTrash(ctx, uid, offset, limit)
+}
+
+func (a *articleService) PurgeExpired(ctx context.Context, now time.Time, limit int) (int, error) {
+	before := now.Add(-ArticleTrashRetention)
+	arts, err := a.repo.ListExpiredTrash(ctx, before, limit)
+	if err != nil {
+		return 0, err
+	}
+	var lastErr error
+	for _, art := range arts {
+		er := a.repo.Purge(ctx, art.Id, before)
+		// 列出来之后又被恢复了
+		if er == repository.ErrArticleNotInTrash {
+			continue
+		}
+		if er != nil {
+			lastErr = er
+			a.l.Error("彻底删除文章失败",
+				logger.Int64("aid", art.Id),
+				logger.Int64("uid", art.Author.Id),
+				logger.Error(er))
+		}
+	}
+	return len(arts), lastErr
+}
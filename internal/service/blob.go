@@ -0,0 +1,175 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"regexp"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	"webook/pkg/blobx"
+	"webook/pkg/logger"
+)
+
+var (
+	ErrBlobTooLarge       = errors.New("文件太大")
+	ErrBlobTypeNotAllowed = errors.New("不支持的文件类型")
+	ErrBlobNotFound       = errors.New("文件不存在")
+)
+
+// BlobGCGracePeriod 刚上传的文件还没来得及保存到文章里面，这段时间之内不清理
+const BlobGCGracePeriod = 24 * time.Hour
+
+// allowedBlobTypes 按照内容识别出来的类型，不相信客户端传的类型。
+// SVG 里面可以有脚本，不允许上传
+var allowedBlobTypes = map[string]bool{
+	"image/png":       true,
+	"image/jpeg":      true,
+	"image/gif":       true,
+	"image/webp":      true,
+	"application/pdf": true,
+	"application/zip": true,
+}
+
+var blobRefRegexp = regexp.MustCompile(`/blobs/([0-9a-f]{64})`)
+
+//go:generate mockgen -source=./blob.go -package=svcmocks -destination=./mocks/blob.mock.go BlobService
+type BlobService interface {
+	// Upload 内容相同的文件只存一份，重复上传返回已有的文件
+	Upload(ctx context.Context, uid int64, data []byte) (domain.Blob, error)
+	// Open 返回的 ReadCloser 必须由调用方关闭
+	Open(ctx context.Context, hash string) (domain.Blob, io.ReadCloser, error)
+	// SyncArticle 按照文章草稿和线上版本的内容重新计算引用了哪些文件，
+	// 文章已经被彻底删除的话清空引用
+	SyncArticle(ctx context.Context, artId int64) error
+	// GC 清理 before 之前上传并且没有被引用的文件，返回这一批处理的数量
+	GC(ctx context.Context, before time.Time, limit int) (int, error)
+}
+
+type blobService struct {
+	repo    repository.BlobRepository
+	artRepo repository.ArticleRepository
+	store   blobx.BlobStore
+	maxSize int64
+	l       logger.LoggerV1
+}
+
+func NewBlobService(repo repository.BlobRepository, artRepo repository.ArticleRepository,
+	store blobx.BlobStore, maxSize int64, l logger.LoggerV1) BlobService {
+	return &blobService{
+		repo:    repo,
+		artRepo: artRepo,
+		store:   store,
+		maxSize: maxSize,
+		l:       l,
+	}
+}
+
+func (s *blobService) Upload(ctx context.Context, uid int64, data []byte) (domain.Blob, error) {
+	if int64(len(data)) > s.maxSize {
+		return domain.Blob{}, ErrBlobTooLarge
+	}
+	mime := http.DetectContentType(data)
+	if !allowedBlobTypes[mime] {
+		return domain.Blob{}, ErrBlobTypeNotAllowed
+	}
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+	// 先写数据库刷新 utime，之后清理任务就不会再删它。正在清理的话会等清理完再插入新的记录。
+	// 文件是按照内容寻址的，已经存在也再写一次，不然可能正好被清理掉了
+	b, err := s.repo.Save(ctx, domain.Blob{
+		Hash:     hash,
+		Size:     int64(len(data)),
+		MimeType: mime,
+		Uploader: uid,
+	})
+	if err != nil {
+		return domain.Blob{}, err
+	}
+	// 写失败的话留下一条没有文件的记录，重新上传就好了，没人上传的话会被清理掉
+	err = s.store.Put(ctx, hash, bytes.NewReader(data), int64(len(data)), mime)
+	if err != nil {
+		return domain.Blob{}, err
+	}
+	return b, nil
+}
+
+func (s *blobService) Open(ctx context.Context, hash string) (domain.Blob, io.ReadCloser, error) {
+	b, err := s.repo.FindByHash(ctx, hash)
+	if err == repository.ErrBlobNotFound {
+		return domain.Blob{}, nil, ErrBlobNotFound
+	}
+	if err != nil {
+		return domain.Blob{}, nil, err
+	}
+	rc, err := s.store.Get(ctx, hash)
+	if err == blobx.ErrNotFound {
+		return domain.Blob{}, nil, ErrBlobNotFound
+	}
+	if err != nil {
+		return domain.Blob{}, nil, err
+	}
+	return b, rc, nil
+}
+
+func (s *blobService) SyncArticle(ctx context.Context, artId int64) error {
+	art, err := s.artRepo.GetById(ctx, artId)
+	if err == repository.ErrArticleNotFound {
+		return s.repo.SetArticleBlobs(ctx, artId, nil)
+	}
+	if err != nil {
+		return err
+	}
+	content := art.Content
+	// 草稿删掉的图片线上版本可能还在用
+	pub, err := s.artRepo.GetPubById(ctx, artId)
+	switch err {
+	case nil:
+		content += pub.Content
+	case repository.ErrArticleNotFound:
+	default:
+		return err
+	}
+	return s.repo.SetArticleBlobs(ctx, artId, blobRefs(content))
+}
+
+func (s *blobService) GC(ctx context.Context, before time.Time, limit int) (int, error) {
+	blobs, err := s.repo.ListUnreferenced(ctx, before, limit)
+	if err != nil {
+		return 0, err
+	}
+	for _, b := range blobs {
+		// 删文件的时候还没有提交，重复上传要等到提交之后才能插入记录再写文件，
+		// 不会被这里删掉。删文件失败的话记录也回滚，下一次再清理
+		err = s.repo.Delete(ctx, b.Id, before, func() error {
+			return s.store.Delete(ctx, b.Hash)
+		})
+		if err == repository.ErrBlobInUse {
+			// 列出来之后又被引用或者重新上传了
+			continue
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(blobs), nil
+}
+
+func blobRefs(content string) []string {
+	matches := blobRefRegexp.FindAllStringSubmatch(content, -1)
+	seen := make(map[string]bool, len(matches))
+	res := make([]string, 0, len(matches))
+	for _, m := range matches {
+		if seen[m[1]] {
+			continue
+		}
+		seen[m[1]] = true
+		res = append(res, m[1])
+	}
+	return res
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	repomocks "webook/internal/repository/mocks"
+	"webook/pkg/blobx"
+	"webook/pkg/logger"
+)
+
+func TestBlobService_GC(t *testing.T) {
+	before := time.Now()
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) repository.BlobRepository
+		// wantFiles 清理之后还剩下的文件
+		wantFiles []string
+		wantCnt   int
+		wantErr   error
+	}{
+		{
+			name: "删除记录和文件",
+			mock: func(ctrl *gomock.Controller) repository.BlobRepository {
+				repo := repomocks.NewMockBlobRepository(ctrl)
+				repo.EXPECT().ListUnreferenced(gomock.Any(), before, 10).
+					Return([]domain.Blob{{Id: 1, Hash: "aaaa"}, {Id: 2, Hash: "bbbb"}}, nil)
+				repo.EXPECT().Delete(gomock.Any(), int64(1), before, gomock.Any()).
+					DoAndReturn(deleteBlob)
+				repo.EXPECT().Delete(gomock.Any(), int64(2), before, gomock.Any()).
+					DoAndReturn(deleteBlob)
+				return repo
+			},
+			wantFiles: []string{"cccc"},
+			wantCnt:   2,
+		},
+		{
+			name: "列出来之后又被引用了",
+			mock: func(ctrl *gomock.Controller) repository.BlobRepository {
+				repo := repomocks.NewMockBlobRepository(ctrl)
+				repo.EXPECT().ListUnreferenced(gomock.Any(), before, 10).
+					Return([]domain.Blob{{Id: 1, Hash: "aaaa"}, {Id: 2, Hash: "bbbb"}}, nil)
+				repo.EXPECT().Delete(gomock.Any(), int64(1), before, gomock.Any()).
+					Return(repository.ErrBlobInUse)
+				repo.EXPECT().Delete(gomock.Any(), int64(2), before, gomock.Any()).
+					DoAndReturn(deleteBlob)
+				return repo
+			},
+			// 没有删掉记录的不能删文件
+			wantFiles: []string{"aaaa", "cccc"},
+			wantCnt:   2,
+		},
+		{
+			name: "数据库出错",
+			mock: func(ctrl *gomock.Controller) repository.BlobRepository {
+				repo := repomocks.NewMockBlobRepository(ctrl)
+				repo.EXPECT().ListUnreferenced(gomock.Any(), before, 10).
+					Return([]domain.Blob{{Id: 1, Hash: "aaaa"}, {Id: 2, Hash: "bbbb"}}, nil)
+				repo.EXPECT().Delete(gomock.Any(), int64(1), before, gomock.Any()).
+					Return(errors.New("mock db error"))
+				return repo
+			},
+			wantFiles: []string{"aaaa", "bbbb", "cccc"},
+			wantErr:   errors.New("mock db error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			store, err := blobx.NewLocalStore(t.TempDir())
+			require.NoError(t, err)
+			ctx := context.Background()
+			for _, hash := range []string{"aaaa", "bbbb", "cccc"} {
+				require.NoError(t, store.Put(ctx, hash, bytes.NewReader([]byte(hash)), 4, ""))
+			}
+			svc := NewBlobService(tc.mock(ctrl), nil, store, 1024, logger.NewNopLogger())
+			cnt, err := svc.GC(ctx, before, 10)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantCnt, cnt)
+			for _, hash := range []string{"aaaa", "bbbb", "cccc"} {
+				_, er := store.Get(ctx, hash)
+				if er == nil {
+					assert.Contains(t, tc.wantFiles, hash)
+				} else {
+					assert.NotContains(t, tc.wantFiles, hash)
+				}
+			}
+		})
+	}
+}
+
+// deleteBlob 模拟删除成功，提交之前删文件
+func deleteBlob(ctx context.Context, id int64, before time.Time, fn func() error) error {
+	return fn()
+}
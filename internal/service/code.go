@@ -6,6 +6,7 @@ import (
 	"math/rand"
 	"time"
 	"webook/internal/repository"
+	"webook/internal/service/email"
 	"webook/internal/service/sms"
 )
 
@@ -13,19 +14,23 @@ var ErrCodeSendTooMany = repository.ErrCodeSendTooMany
 
 type CodeService interface {
 	Send(ctx context.Context, biz, phone string) error
+	// SendEmail 验证码发到邮箱，和手机的验证码一样用 Verify 校验
+	SendEmail(ctx context.Context, biz, email string) error
 	Verify(ctx context.Context, biz, phone, inputCode string) (bool, error)
 	generateCode() string
 }
 
 type CacheCodeService struct {
-	repo repository.CodeRepository
-	sms  sms.Service
+	repo  repository.CodeRepository
+	sms   sms.Service
+	email email.Service
 }
 
-func NewCacheCodeService(repo repository.CodeRepository, sms sms.Service) CodeService {
+func NewCacheCodeService(repo repository.CodeRepository, sms sms.Service, email email.Service) CodeService {
 	return &CacheCodeService{
-		repo: repo,
-		sms:  sms,
+		repo:  repo,
+		sms:   sms,
+		email: email,
 	}
 }
 
@@ -41,6 +46,16 @@ func (svc *CacheCodeService) Send(ctx context.Context, biz, phone string) error
 	return err
 }
 
+func (svc *CacheCodeService) SendEmail(ctx context.Context, biz, email string) error {
+	code := svc.generateCode()
+	err := svc.repo.Set(ctx, biz, email, code)
+	if err != nil {
+		return err
+	}
+	return svc.email.Send(ctx, "webook 验证码",
+		fmt.Sprintf("你的验证码是 %s，10 分钟内有效", code), email)
+}
+
 func (svc *CacheCodeService) Verify(ctx context.Context, biz, phone, inputCode string) (bool, error) {
 	ok, err := svc.repo.Verify(ctx, biz, phone, inputCode)
 	if err == repository.ErrCodeVerifyTooMany {
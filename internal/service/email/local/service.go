@@ -0,0 +1,18 @@
+package local
+
+import (
+	"context"
+	"log"
+)
+
+type Service struct {
+}
+
+func NewLocalEmailService() *Service {
+	return &Service{}
+}
+
+func (s *Service) Send(ctx context.Context, subject string, content string, to ...string) error {
+	log.Printf("send email to %v: %s %s\n", to, subject, content)
+	return nil
+}
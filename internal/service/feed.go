@@ -0,0 +1,220 @@
+package service
+
+import (
+	"context"
+	"crypto/sha1"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	"webook/pkg/feedx"
+	"webook/pkg/logger"
+)
+
+// FeedSize 订阅里面最多的文章数量
+const FeedSize = 20
+
+var ErrFeedAuthorNotFound = errors.New("作者不存在")
+
+//go:generate mockgen -source=./feed.go -package=svcmocks -destination=./mocks/feed.mock.go FeedService
+type FeedService interface {
+	// SiteFeed 全站最新发表的文章
+	SiteFeed(ctx context.Context) (domain.Feed, error)
+	AuthorFeed(ctx context.Context, uid int64) (domain.Feed, error)
+	Sitemap(ctx context.Context) (domain.Feed, error)
+	// Invalidate 作者发表或者撤回文章之后清理缓存
+	Invalidate(ctx context.Context, uid int64) error
+}
+
+type feedService struct {
+	artRepo  repository.ArticleRepository
+	userRepo repository.UserRepository
+	repo     repository.FeedRepository
+	// siteURL 站点的地址，不带最后的 /
+	siteURL string
+	title   string
+	l       logger.LoggerV1
+}
+
+func NewFeedService(artRepo repository.ArticleRepository, userRepo repository.UserRepository,
+	repo repository.FeedRepository, siteURL string, title string, l logger.LoggerV1) FeedService {
+	return &feedService{
+		artRepo:  artRepo,
+		userRepo: userRepo,
+		repo:     repo,
+		siteURL:  siteURL,
+		title:    title,
+		l:        l,
+	}
+}
+
+const (
+	feedKeySite    = "site"
+	feedKeySitemap = "sitemap"
+)
+
+func feedKeyAuthor(uid int64) string {
+	return "author:" + strconv.FormatInt(uid, 10)
+}
+
+func (s *feedService) SiteFeed(ctx context.Context) (domain.Feed, error) {
+	return s.cached(ctx, feedKeySite, func() (domain.Feed, error) {
+		arts, err := s.artRepo.ListPub(ctx, time.Now(), 0, FeedSize)
+		if err != nil {
+			return domain.Feed{}, err
+		}
+		return s.atom(ctx, s.siteURL+"/feeds/articles.atom", s.title, arts)
+	})
+}
+
+func (s *feedService) AuthorFeed(ctx context.Context, uid int64) (domain.Feed, error) {
+	return s.cached(ctx, feedKeyAuthor(uid), func() (domain.Feed, error) {
+		u, err := s.userRepo.FindById(ctx, uid)
+		if err == repository.ErrUserNotFound {
+			return domain.Feed{}, ErrFeedAuthorNotFound
+		}
+		if err != nil {
+			return domain.Feed{}, err
+		}
+		arts, err := s.artRepo.ListPubByAuthor(ctx, uid, 0, FeedSize)
+		if err != nil {
+			return domain.Feed{}, err
+		}
+		self := fmt.Sprintf("%s/feeds/authors/%d/articles.atom", s.siteURL, uid)
+		return s.atom(ctx, self, s.authorName(u)+" - "+s.title, arts)
+	})
+}
+
+func (s *feedService) Sitemap(ctx context.Context) (domain.Feed, error) {
+	return s.cached(ctx, feedKeySitemap, func() (domain.Feed, error) {
+		const batchSize = 500
+		var (
+			set   feedx.URLSet
+			mtime time.Time
+		)
+		start := time.Now()
+		for offset := 0; offset < feedx.MaxSitemapURLs; offset += batchSize {
+			arts, err := s.artRepo.ListPub(ctx, start, offset, batchSize)
+			if err != nil {
+				return domain.Feed{}, err
+			}
+			for _, art := range arts {
+				set.URLs = append(set.URLs, feedx.URL{
+					Loc:     s.articleURL(art.Id),
+					LastMod: feedx.Date(art.Utime),
+				})
+				if art.Utime.After(mtime) {
+					mtime = art.Utime
+				}
+			}
+			if len(arts) < batchSize {
+				break
+			}
+		}
+		content, err := set.Marshal()
+		if err != nil {
+			return domain.Feed{}, err
+		}
+		return newFeed(content, mtime), nil
+	})
+}
+
+func (s *feedService) Invalidate(ctx context.Context, uid int64) error {
+	return s.repo.Del(ctx, feedKeySite, feedKeySitemap, feedKeyAuthor(uid))
+}
+
+func (s *feedService) cached(ctx context.Context, key string, build func() (domain.Feed, error)) (domain.Feed, error) {
+	res, err := s.repo.Get(ctx, key)
+	if err == nil {
+		return res, nil
+	}
+	res, err = build()
+	if err != nil {
+		return domain.Feed{}, err
+	}
+	er := s.repo.Set(ctx, key, res)
+	if er != nil {
+		s.l.Error("缓存订阅失败", logger.String("key", key), logger.Error(er))
+	}
+	return res, nil
+}
+
+func (s *feedService) atom(ctx context.Context, self string, title string, arts []domain.Article) (domain.Feed, error) {
+	feed := feedx.Feed{
+		Id:    self,
+		Title: title,
+		Links: []feedx.Link{
+			{Href: self, Rel: "self", Type: "application/atom+xml"},
+			{Href: s.siteURL, Rel: "alternate", Type: "text/html"},
+		},
+	}
+	var mtime time.Time
+	names := make(map[int64]string)
+	for _, art := range arts {
+		if art.HTML == "" {
+			art = renderArticle(art)
+		}
+		name, ok := names[art.Author.Id]
+		if !ok {
+			name = s.findAuthorName(ctx, art.Author.Id)
+			names[art.Author.Id] = name
+		}
+		feed.Entries = append(feed.Entries, feedx.Entry{
+			Id:      s.articleURL(art.Id),
+			Title:   art.Title,
+			Updated: feedx.Time(art.Utime),
+			Links:   []feedx.Link{{Href: s.articleURL(art.Id), Rel: "alternate", Type: "text/html"}},
+			Author: &feedx.Person{
+				Name: name,
+				URI:  fmt.Sprintf("%s/authors/%d", s.siteURL, art.Author.Id),
+			},
+			Summary: &feedx.Text{Type: "text", Body: art.Abstract()},
+			Content: &feedx.Text{Type: "html", Body: art.HTML},
+		})
+		if art.Utime.After(mtime) {
+			mtime = art.Utime
+		}
+	}
+	if mtime.IsZero() {
+		// 没有文章的时候 updated 也是必须的
+		mtime = time.Now()
+	}
+	feed.Updated = feedx.Time(mtime)
+	content, err := feed.Marshal()
+	if err != nil {
+		return domain.Feed{}, err
+	}
+	return newFeed(content, mtime), nil
+}
+
+// findAuthorName 查不到作者不影响生成订阅
+func (s *feedService) findAuthorName(ctx context.Context, uid int64) string {
+	u, err := s.userRepo.FindById(ctx, uid)
+	if err != nil {
+		s.l.Warn("查询作者失败", logger.Int64("uid", uid), logger.Error(err))
+		return strconv.FormatInt(uid, 10)
+	}
+	return s.authorName(u)
+}
+
+func (s *feedService) authorName(u domain.User) string {
+	if u.Nickname != "" {
+		return u.Nickname
+	}
+	return strconv.FormatInt(u.Id, 10)
+}
+
+func (s *feedService) articleURL(id int64) string {
+	return fmt.Sprintf("%s/articles/%d", s.siteURL, id)
+}
+
+// newFeed HTTP 的时间只精确到秒，比较 If-Modified-Since 之前先截断
+func newFeed(content []byte, mtime time.Time) domain.Feed {
+	return domain.Feed{
+		Content: content,
+		ETag:    fmt.Sprintf(`"%x"`, sha1.Sum(content)),
+		Mtime:   mtime.Truncate(time.Second),
+	}
+}
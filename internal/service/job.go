@@ -11,6 +11,7 @@ import (
 type CronJobService interface {
 	Preempt(ctx context.Context) (domain.Job, error)
 	ResetNextTime(ctx context.Context, j domain.Job) error
+	AddJob(ctx context.Context, j domain.Job) error
 }
 
 type cronJobService struct {
@@ -19,20 +20,40 @@ type cronJobService struct {
 	refreshInterval time.Duration
 }
 
+func NewCronJobService(repo repository.CronJobRepository, l logger.LoggerV1) CronJobService {
+	return &cronJobService{
+		repo:            repo,
+		l:               l,
+		refreshInterval: time.Minute,
+	}
+}
+
+func (c *cronJobService) AddJob(ctx context.Context, j domain.Job) error {
+	return c.repo.AddJob(ctx, j)
+}
+
 func (c *cronJobService) Preempt(ctx context.Context) (domain.Job, error) {
 	ctx = context.WithValue(ctx, "refreshInterval", c.refreshInterval)
 	job, err := c.repo.Preempt(ctx, c.refreshInterval)
 	if err != nil {
 		return domain.Job{}, err
 	}
-	ticker := time.NewTimer(c.refreshInterval)
+	// 续约的间隔要比判定续约失败的时间短，不然别的节点会在两次续约之间把任务抢走
+	ticker := time.NewTicker(c.refreshInterval / 2)
+	done := make(chan struct{})
 	go func() {
-		for range ticker.C {
-			c.refresh(job.Id)
+		for {
+			select {
+			case <-ticker.C:
+				c.refresh(job.Id)
+			case <-done:
+				return
+			}
 		}
 	}()
 	job.CancelFunc = func() {
 		ticker.Stop()
+		close(done)
 		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 		defer cancel()
 		er := c.repo.Release(ctx, job.Id)
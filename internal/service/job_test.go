@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"sync/atomic"
+	"testing"
+	"time"
+	"webook/internal/domain"
+	repomocks "webook/internal/repository/mocks"
+	"webook/pkg/logger"
+)
+
+// TestCronJobService_Preempt 任务执行期间一直续约，释放之后不再续约
+func TestCronJobService_Preempt(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := repomocks.NewMockCronJobRepository(ctrl)
+	repo.EXPECT().Preempt(gomock.Any(), gomock.Any()).Return(domain.Job{Id: 1}, nil)
+	var cnt atomic.Int64
+	repo.EXPECT().UpdateUtime(gomock.Any(), int64(1)).DoAndReturn(func(ctx context.Context, id int64) error {
+		cnt.Add(1)
+		return nil
+	}).AnyTimes()
+	repo.EXPECT().Release(gomock.Any(), int64(1)).Return(nil)
+
+	svc := &cronJobService{repo: repo, l: logger.NewNopLogger(), refreshInterval: time.Millisecond * 20}
+	job, err := svc.Preempt(context.Background())
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		return cnt.Load() >= 3
+	}, time.Second, time.Millisecond*5)
+
+	job.CancelFunc()
+	// 释放的时候可能正好有一次续约在跑
+	time.Sleep(time.Millisecond * 10)
+	renewed := cnt.Load()
+	time.Sleep(time.Millisecond * 50)
+	assert.Equal(t, renewed, cnt.Load())
+}
@@ -0,0 +1,147 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./archive.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./archive.go -package=svcmocks -destination=./mocks/archive.mock.go ArchiveService
+//
+// Package svcmocks is a generated GoMock package.
+package svcmocks
+
+import (
+	context "context"
+	io "io"
+	reflect "reflect"
+	domain "webook/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockArchiveService is a mock of ArchiveService interface.
+type MockArchiveService struct {
+	ctrl     *gomock.Controller
+	recorder *MockArchiveServiceMockRecorder
+}
+
+// MockArchiveServiceMockRecorder is the mock recorder for MockArchiveService.
+type MockArchiveServiceMockRecorder struct {
+	mock *MockArchiveService
+}
+
+// NewMockArchiveService creates a new mock instance.
+func NewMockArchiveService(ctrl *gomock.Controller) *MockArchiveService {
+	mock := &MockArchiveService{ctrl: ctrl}
+	mock.recorder = &MockArchiveServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockArchiveService) EXPECT() *MockArchiveServiceMockRecorder {
+	return m.recorder
+}
+
+// Cleanup mocks base method.
+func (m *MockArchiveService) Cleanup(ctx context.Context, limit int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cleanup", ctx, limit)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Cleanup indicates an expected call of Cleanup.
+func (mr *MockArchiveServiceMockRecorder) Cleanup(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cleanup", reflect.TypeOf((*MockArchiveService)(nil).Cleanup), ctx, limit)
+}
+
+// Get mocks base method.
+func (m *MockArchiveService) Get(ctx context.Context, uid, id int64) (domain.ArchiveTask, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, uid, id)
+	ret0, _ := ret[0].(domain.ArchiveTask)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockArchiveServiceMockRecorder) Get(ctx, uid, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockArchiveService)(nil).Get), ctx, uid, id)
+}
+
+// List mocks base method.
+func (m *MockArchiveService) List(ctx context.Context, uid int64, offset, limit int) ([]domain.ArchiveTask, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, uid, offset, limit)
+	ret0, _ := ret[0].([]domain.ArchiveTask)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockArchiveServiceMockRecorder) List(ctx, uid, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockArchiveService)(nil).List), ctx, uid, offset, limit)
+}
+
+// Open mocks base method.
+func (m *MockArchiveService) Open(ctx context.Context, token string) (domain.ArchiveTask, io.ReadCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Open", ctx, token)
+	ret0, _ := ret[0].(domain.ArchiveTask)
+	ret1, _ := ret[1].(io.ReadCloser)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Open indicates an expected call of Open.
+func (mr *MockArchiveServiceMockRecorder) Open(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Open", reflect.TypeOf((*MockArchiveService)(nil).Open), ctx, token)
+}
+
+// RequestExport mocks base method.
+func (m *MockArchiveService) RequestExport(ctx context.Context, uid int64) (domain.ArchiveTask, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestExport", ctx, uid)
+	ret0, _ := ret[0].(domain.ArchiveTask)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RequestExport indicates an expected call of RequestExport.
+func (mr *MockArchiveServiceMockRecorder) RequestExport(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestExport", reflect.TypeOf((*MockArchiveService)(nil).RequestExport), ctx, uid)
+}
+
+// RequestImport mocks base method.
+func (m *MockArchiveService) RequestImport(ctx context.Context, uid int64, r io.Reader, size int64) (domain.ArchiveTask, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestImport", ctx, uid, r, size)
+	ret0, _ := ret[0].(domain.ArchiveTask)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RequestImport indicates an expected call of RequestImport.
+func (mr *MockArchiveServiceMockRecorder) RequestImport(ctx, uid, r, size any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestImport", reflect.TypeOf((*MockArchiveService)(nil).RequestImport), ctx, uid, r, size)
+}
+
+// RunNext mocks base method.
+func (m *MockArchiveService) RunNext(ctx context.Context) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunNext", ctx)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RunNext indicates an expected call of RunNext.
+func (mr *MockArchiveServiceMockRecorder) RunNext(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunNext", reflect.TypeOf((*MockArchiveService)(nil).RunNext), ctx)
+}
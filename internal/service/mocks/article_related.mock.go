@@ -0,0 +1,70 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./article_related.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./article_related.go -package=svcmocks -destination=./mocks/article_related.mock.go ArticleRelatedService
+//
+// Package svcmocks is a generated GoMock package.
+package svcmocks
+
+import (
+	context "context"
+	reflect "reflect"
+	domain "webook/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockArticleRelatedService is a mock of ArticleRelatedService interface.
+type MockArticleRelatedService struct {
+	ctrl     *gomock.Controller
+	recorder *MockArticleRelatedServiceMockRecorder
+}
+
+// MockArticleRelatedServiceMockRecorder is the mock recorder for MockArticleRelatedService.
+type MockArticleRelatedServiceMockRecorder struct {
+	mock *MockArticleRelatedService
+}
+
+// NewMockArticleRelatedService creates a new mock instance.
+func NewMockArticleRelatedService(ctrl *gomock.Controller) *MockArticleRelatedService {
+	mock := &MockArticleRelatedService{ctrl: ctrl}
+	mock.recorder = &MockArticleRelatedServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockArticleRelatedService) EXPECT() *MockArticleRelatedServiceMockRecorder {
+	return m.recorder
+}
+
+// Recompute mocks base method.
+func (m *MockArticleRelatedService) Recompute(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Recompute", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Recompute indicates an expected call of Recompute.
+func (mr *MockArticleRelatedServiceMockRecorder) Recompute(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recompute", reflect.TypeOf((*MockArticleRelatedService)(nil).Recompute), ctx)
+}
+
+// Related mocks base method.
+func (m *MockArticleRelatedService) Related(ctx context.Context, id int64, limit int) ([]domain.Article, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Related", ctx, id, limit)
+	ret0, _ := ret[0].([]domain.Article)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Related indicates an expected call of Related.
+func (mr *MockArticleRelatedServiceMockRecorder) Related(ctx, id, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Related", reflect.TypeOf((*MockArticleRelatedService)(nil).Related), ctx, id, limit)
+}
@@ -0,0 +1,102 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./blob.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./blob.go -package=svcmocks -destination=./mocks/blob.mock.go BlobService
+//
+// Package svcmocks is a generated GoMock package.
+package svcmocks
+
+import (
+	context "context"
+	io "io"
+	reflect "reflect"
+	time "time"
+	domain "webook/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockBlobService is a mock of BlobService interface.
+type MockBlobService struct {
+	ctrl     *gomock.Controller
+	recorder *MockBlobServiceMockRecorder
+}
+
+// MockBlobServiceMockRecorder is the mock recorder for MockBlobService.
+type MockBlobServiceMockRecorder struct {
+	mock *MockBlobService
+}
+
+// NewMockBlobService creates a new mock instance.
+func NewMockBlobService(ctrl *gomock.Controller) *MockBlobService {
+	mock := &MockBlobService{ctrl: ctrl}
+	mock.recorder = &MockBlobServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBlobService) EXPECT() *MockBlobServiceMockRecorder {
+	return m.recorder
+}
+
+// GC mocks base method.
+func (m *MockBlobService) GC(ctx context.Context, before time.Time, limit int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GC", ctx, before, limit)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GC indicates an expected call of GC.
+func (mr *MockBlobServiceMockRecorder) GC(ctx, before, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GC", reflect.TypeOf((*MockBlobService)(nil).GC), ctx, before, limit)
+}
+
+// Open mocks base method.
+func (m *MockBlobService) Open(ctx context.Context, hash string) (domain.Blob, io.ReadCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Open", ctx, hash)
+	ret0, _ := ret[0].(domain.Blob)
+	ret1, _ := ret[1].(io.ReadCloser)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Open indicates an expected call of Open.
+func (mr *MockBlobServiceMockRecorder) Open(ctx, hash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Open", reflect.TypeOf((*MockBlobService)(nil).Open), ctx, hash)
+}
+
+// SyncArticle mocks base method.
+func (m *MockBlobService) SyncArticle(ctx context.Context, artId int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncArticle", ctx, artId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SyncArticle indicates an expected call of SyncArticle.
+func (mr *MockBlobServiceMockRecorder) SyncArticle(ctx, artId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncArticle", reflect.TypeOf((*MockBlobService)(nil).SyncArticle), ctx, artId)
+}
+
+// Upload mocks base method.
+func (m *MockBlobService) Upload(ctx context.Context, uid int64, data []byte) (domain.Blob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upload", ctx, uid, data)
+	ret0, _ := ret[0].(domain.Blob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Upload indicates an expected call of Upload.
+func (mr *MockBlobServiceMockRecorder) Upload(ctx, uid, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upload", reflect.TypeOf((*MockBlobService)(nil).Upload), ctx, uid, data)
+}
@@ -0,0 +1,99 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./feed.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./feed.go -package=svcmocks -destination=./mocks/feed.mock.go FeedService
+//
+// Package svcmocks is a generated GoMock package.
+package svcmocks
+
+import (
+	context "context"
+	reflect "reflect"
+	domain "webook/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockFeedService is a mock of FeedService interface.
+type MockFeedService struct {
+	ctrl     *gomock.Controller
+	recorder *MockFeedServiceMockRecorder
+}
+
+// MockFeedServiceMockRecorder is the mock recorder for MockFeedService.
+type MockFeedServiceMockRecorder struct {
+	mock *MockFeedService
+}
+
+// NewMockFeedService creates a new mock instance.
+func NewMockFeedService(ctrl *gomock.Controller) *MockFeedService {
+	mock := &MockFeedService{ctrl: ctrl}
+	mock.recorder = &MockFeedServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFeedService) EXPECT() *MockFeedServiceMockRecorder {
+	return m.recorder
+}
+
+// AuthorFeed mocks base method.
+func (m *MockFeedService) AuthorFeed(ctx context.Context, uid int64) (domain.Feed, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthorFeed", ctx, uid)
+	ret0, _ := ret[0].(domain.Feed)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AuthorFeed indicates an expected call of AuthorFeed.
+func (mr *MockFeedServiceMockRecorder) AuthorFeed(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthorFeed", reflect.TypeOf((*MockFeedService)(nil).AuthorFeed), ctx, uid)
+}
+
+// Invalidate mocks base method.
+func (m *MockFeedService) Invalidate(ctx context.Context, uid int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Invalidate", ctx, uid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Invalidate indicates an expected call of Invalidate.
+func (mr *MockFeedServiceMockRecorder) Invalidate(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Invalidate", reflect.TypeOf((*MockFeedService)(nil).Invalidate), ctx, uid)
+}
+
+// SiteFeed mocks base method.
+func (m *MockFeedService) SiteFeed(ctx context.Context) (domain.Feed, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SiteFeed", ctx)
+	ret0, _ := ret[0].(domain.Feed)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SiteFeed indicates an expected call of SiteFeed.
+func (mr *MockFeedServiceMockRecorder) SiteFeed(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SiteFeed", reflect.TypeOf((*MockFeedService)(nil).SiteFeed), ctx)
+}
+
+// Sitemap mocks base method.
+func (m *MockFeedService) Sitemap(ctx context.Context) (domain.Feed, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Sitemap", ctx)
+	ret0, _ := ret[0].(domain.Feed)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Sitemap indicates an expected call of Sitemap.
+func (mr *MockFeedServiceMockRecorder) Sitemap(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sitemap", reflect.TypeOf((*MockFeedService)(nil).Sitemap), ctx)
+}
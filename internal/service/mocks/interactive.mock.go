@@ -10,9 +10,11 @@ package svcmocks
 
 import (
 	context "context"
-	gomock "go.uber.org/mock/gomock"
 	reflect "reflect"
-	"webook/interactive/domain"
+	time "time"
+	domain "webook/interactive/domain"
+
+	gomock "go.uber.org/mock/gomock"
 )
 
 // MockInteractiveService is a mock of InteractiveService interface.
@@ -108,6 +110,36 @@ func (mr *MockInteractiveServiceMockRecorder) GetByIds(ctx, biz, ids any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIds", reflect.TypeOf((*MockInteractiveService)(nil).GetByIds), ctx, biz, ids)
 }
 
+// GetCoLiked mocks base method.
+func (m *MockInteractiveService) GetCoLiked(ctx context.Context, biz string, bizId int64, limit int) ([]domain.CoLiked, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCoLiked", ctx, biz, bizId, limit)
+	ret0, _ := ret[0].([]domain.CoLiked)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCoLiked indicates an expected call of GetCoLiked.
+func (mr *MockInteractiveServiceMockRecorder) GetCoLiked(ctx, biz, bizId, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCoLiked", reflect.TypeOf((*MockInteractiveService)(nil).GetCoLiked), ctx, biz, bizId, limit)
+}
+
+// GetDailyStats mocks base method.
+func (m *MockInteractiveService) GetDailyStats(ctx context.Context, biz string, bizId int64, start, end time.Time) ([]domain.DailyStat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDailyStats", ctx, biz, bizId, start, end)
+	ret0, _ := ret[0].([]domain.DailyStat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDailyStats indicates an expected call of GetDailyStats.
+func (mr *MockInteractiveServiceMockRecorder) GetDailyStats(ctx, biz, bizId, start, end any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDailyStats", reflect.TypeOf((*MockInteractiveService)(nil).GetDailyStats), ctx, biz, bizId, start, end)
+}
+
 // IncrReadCnt mocks base method.
 func (m *MockInteractiveService) IncrReadCnt(ctx context.Context, biz string, bizId int64) error {
 	m.ctrl.T.Helper()
@@ -150,3 +182,18 @@ func (mr *MockInteractiveServiceMockRecorder) LikeTopN(ctx, biz, num any) *gomoc
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LikeTopN", reflect.TypeOf((*MockInteractiveService)(nil).LikeTopN), ctx, biz, num)
 }
+
+// RollupDaily mocks base method.
+func (m *MockInteractiveService) RollupDaily(ctx context.Context, day time.Time, batchSize int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RollupDaily", ctx, day, batchSize)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RollupDaily indicates an expected call of RollupDaily.
+func (mr *MockInteractiveServiceMockRecorder) RollupDaily(ctx, day, batchSize any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RollupDaily", reflect.TypeOf((*MockInteractiveService)(nil).RollupDaily), ctx, day, batchSize)
+}
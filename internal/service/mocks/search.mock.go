@@ -0,0 +1,126 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./search.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./search.go -package=svcmocks -destination=./mocks/search.mock.go SearchService
+//
+// Package svcmocks is a generated GoMock package.
+package svcmocks
+
+import (
+	context "context"
+	reflect "reflect"
+	domain "webook/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSearchService is a mock of SearchService interface.
+type MockSearchService struct {
+	ctrl     *gomock.Controller
+	recorder *MockSearchServiceMockRecorder
+}
+
+// MockSearchServiceMockRecorder is the mock recorder for MockSearchService.
+type MockSearchServiceMockRecorder struct {
+	mock *MockSearchService
+}
+
+// NewMockSearchService creates a new mock instance.
+func NewMockSearchService(ctrl *gomock.Controller) *MockSearchService {
+	mock := &MockSearchService{ctrl: ctrl}
+	mock.recorder = &MockSearchServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSearchService) EXPECT() *MockSearchServiceMockRecorder {
+	return m.recorder
+}
+
+// IndexArticle mocks base method.
+func (m *MockSearchService) IndexArticle(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IndexArticle", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IndexArticle indicates an expected call of IndexArticle.
+func (mr *MockSearchServiceMockRecorder) IndexArticle(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IndexArticle", reflect.TypeOf((*MockSearchService)(nil).IndexArticle), ctx, id)
+}
+
+// LoadSnapshot mocks base method.
+func (m *MockSearchService) LoadSnapshot() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadSnapshot")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LoadSnapshot indicates an expected call of LoadSnapshot.
+func (mr *MockSearchServiceMockRecorder) LoadSnapshot() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadSnapshot", reflect.TypeOf((*MockSearchService)(nil).LoadSnapshot))
+}
+
+// Rebuild mocks base method.
+func (m *MockSearchService) Rebuild(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rebuild", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Rebuild indicates an expected call of Rebuild.
+func (mr *MockSearchServiceMockRecorder) Rebuild(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rebuild", reflect.TypeOf((*MockSearchService)(nil).Rebuild), ctx)
+}
+
+// RemoveArticle mocks base method.
+func (m *MockSearchService) RemoveArticle(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveArticle", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveArticle indicates an expected call of RemoveArticle.
+func (mr *MockSearchServiceMockRecorder) RemoveArticle(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveArticle", reflect.TypeOf((*MockSearchService)(nil).RemoveArticle), ctx, id)
+}
+
+// SaveSnapshot mocks base method.
+func (m *MockSearchService) SaveSnapshot() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveSnapshot")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveSnapshot indicates an expected call of SaveSnapshot.
+func (mr *MockSearchServiceMockRecorder) SaveSnapshot() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveSnapshot", reflect.TypeOf((*MockSearchService)(nil).SaveSnapshot))
+}
+
+// Search mocks base method.
+func (m *MockSearchService) Search(ctx context.Context, query string, offset, limit int) (domain.SearchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, query, offset, limit)
+	ret0, _ := ret[0].(domain.SearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockSearchServiceMockRecorder) Search(ctx, query, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockSearchService)(nil).Search), ctx, query, offset, limit)
+}
@@ -0,0 +1,171 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./series.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./series.go -package=svcmocks -destination=./mocks/series.mock.go SeriesService
+//
+// Package svcmocks is a generated GoMock package.
+package svcmocks
+
+import (
+	context "context"
+	reflect "reflect"
+	domain "webook/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSeriesService is a mock of SeriesService interface.
+type MockSeriesService struct {
+	ctrl     *gomock.Controller
+	recorder *MockSeriesServiceMockRecorder
+}
+
+// MockSeriesServiceMockRecorder is the mock recorder for MockSeriesService.
+type MockSeriesServiceMockRecorder struct {
+	mock *MockSeriesService
+}
+
+// NewMockSeriesService creates a new mock instance.
+func NewMockSeriesService(ctrl *gomock.Controller) *MockSeriesService {
+	mock := &MockSeriesService{ctrl: ctrl}
+	mock.recorder = &MockSeriesServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSeriesService) EXPECT() *MockSeriesServiceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockSeriesService) Create(ctx context.Context, s domain.Series) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, s)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockSeriesServiceMockRecorder) Create(ctx, s any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockSeriesService)(nil).Create), ctx, s)
+}
+
+// GetById mocks base method.
+func (m *MockSeriesService) GetById(ctx context.Context, id, uid int64) (domain.Series, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetById", ctx, id, uid)
+	ret0, _ := ret[0].(domain.Series)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetById indicates an expected call of GetById.
+func (mr *MockSeriesServiceMockRecorder) GetById(ctx, id, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockSeriesService)(nil).GetById), ctx, id, uid)
+}
+
+// GetPub mocks base method.
+func (m *MockSeriesService) GetPub(ctx context.Context, id int64) (domain.Series, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPub", ctx, id)
+	ret0, _ := ret[0].(domain.Series)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPub indicates an expected call of GetPub.
+func (mr *MockSeriesServiceMockRecorder) GetPub(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPub", reflect.TypeOf((*MockSeriesService)(nil).GetPub), ctx, id)
+}
+
+// ListByAuthor mocks base method.
+func (m *MockSeriesService) ListByAuthor(ctx context.Context, uid int64, offset, limit int) ([]domain.Series, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByAuthor", ctx, uid, offset, limit)
+	ret0, _ := ret[0].([]domain.Series)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByAuthor indicates an expected call of ListByAuthor.
+func (mr *MockSeriesServiceMockRecorder) ListByAuthor(ctx, uid, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByAuthor", reflect.TypeOf((*MockSeriesService)(nil).ListByAuthor), ctx, uid, offset, limit)
+}
+
+// Nav mocks base method.
+func (m *MockSeriesService) Nav(ctx context.Context, artId int64) (domain.SeriesNav, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Nav", ctx, artId)
+	ret0, _ := ret[0].(domain.SeriesNav)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Nav indicates an expected call of Nav.
+func (mr *MockSeriesServiceMockRecorder) Nav(ctx, artId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Nav", reflect.TypeOf((*MockSeriesService)(nil).Nav), ctx, artId)
+}
+
+// Publish mocks base method.
+func (m *MockSeriesService) Publish(ctx context.Context, id, uid int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Publish", ctx, id, uid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockSeriesServiceMockRecorder) Publish(ctx, id, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockSeriesService)(nil).Publish), ctx, id, uid)
+}
+
+// SetArticles mocks base method.
+func (m *MockSeriesService) SetArticles(ctx context.Context, id, uid int64, artIds []int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetArticles", ctx, id, uid, artIds)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetArticles indicates an expected call of SetArticles.
+func (mr *MockSeriesServiceMockRecorder) SetArticles(ctx, id, uid, artIds any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetArticles", reflect.TypeOf((*MockSeriesService)(nil).SetArticles), ctx, id, uid, artIds)
+}
+
+// Update mocks base method.
+func (m *MockSeriesService) Update(ctx context.Context, s domain.Series) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, s)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockSeriesServiceMockRecorder) Update(ctx, s any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockSeriesService)(nil).Update), ctx, s)
+}
+
+// Withdraw mocks base method.
+func (m *MockSeriesService) Withdraw(ctx context.Context, id, uid int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Withdraw", ctx, id, uid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Withdraw indicates an expected call of Withdraw.
+func (mr *MockSeriesServiceMockRecorder) Withdraw(ctx, id, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Withdraw", reflect.TypeOf((*MockSeriesService)(nil).Withdraw), ctx, id, uid)
+}
@@ -0,0 +1,129 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./user_mfa.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./user_mfa.go -package=svcmocks -destination=./mocks/user_mfa.mock.go UserMFAService
+//
+// Package svcmocks is a generated GoMock package.
+package svcmocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockUserMFAService is a mock of UserMFAService interface.
+type MockUserMFAService struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserMFAServiceMockRecorder
+}
+
+// MockUserMFAServiceMockRecorder is the mock recorder for MockUserMFAService.
+type MockUserMFAServiceMockRecorder struct {
+	mock *MockUserMFAService
+}
+
+// NewMockUserMFAService creates a new mock instance.
+func NewMockUserMFAService(ctrl *gomock.Controller) *MockUserMFAService {
+	mock := &MockUserMFAService{ctrl: ctrl}
+	mock.recorder = &MockUserMFAServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserMFAService) EXPECT() *MockUserMFAServiceMockRecorder {
+	return m.recorder
+}
+
+// BeginLogin mocks base method.
+func (m *MockUserMFAService) BeginLogin(ctx context.Context, uid int64) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeginLogin", ctx, uid)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BeginLogin indicates an expected call of BeginLogin.
+func (mr *MockUserMFAServiceMockRecorder) BeginLogin(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginLogin", reflect.TypeOf((*MockUserMFAService)(nil).BeginLogin), ctx, uid)
+}
+
+// CompleteLogin mocks base method.
+func (m *MockUserMFAService) CompleteLogin(ctx context.Context, token, code string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteLogin", ctx, token, code)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompleteLogin indicates an expected call of CompleteLogin.
+func (mr *MockUserMFAServiceMockRecorder) CompleteLogin(ctx, token, code any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteLogin", reflect.TypeOf((*MockUserMFAService)(nil).CompleteLogin), ctx, token, code)
+}
+
+// Confirm mocks base method.
+func (m *MockUserMFAService) Confirm(ctx context.Context, uid int64, code string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Confirm", ctx, uid, code)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Confirm indicates an expected call of Confirm.
+func (mr *MockUserMFAServiceMockRecorder) Confirm(ctx, uid, code any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Confirm", reflect.TypeOf((*MockUserMFAService)(nil).Confirm), ctx, uid, code)
+}
+
+// Disable mocks base method.
+func (m *MockUserMFAService) Disable(ctx context.Context, uid int64, password, code string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Disable", ctx, uid, password, code)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Disable indicates an expected call of Disable.
+func (mr *MockUserMFAServiceMockRecorder) Disable(ctx, uid, password, code any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Disable", reflect.TypeOf((*MockUserMFAService)(nil).Disable), ctx, uid, password, code)
+}
+
+// Enabled mocks base method.
+func (m *MockUserMFAService) Enabled(ctx context.Context, uid int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Enabled", ctx, uid)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Enabled indicates an expected call of Enabled.
+func (mr *MockUserMFAServiceMockRecorder) Enabled(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enabled", reflect.TypeOf((*MockUserMFAService)(nil).Enabled), ctx, uid)
+}
+
+// Enroll mocks base method.
+func (m *MockUserMFAService) Enroll(ctx context.Context, uid int64) (string, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Enroll", ctx, uid)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Enroll indicates an expected call of Enroll.
+func (mr *MockUserMFAServiceMockRecorder) Enroll(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enroll", reflect.TypeOf((*MockUserMFAService)(nil).Enroll), ctx, uid)
+}
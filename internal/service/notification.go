@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+	"webook/internal/domain"
+	"webook/internal/repository"
+)
+
+//go:generate mockgen -source=./notification.go -package=svcmocks -destination=./mocks/notification.mock.go NotificationService
+type NotificationService interface {
+	Notify(ctx context.Context, n domain.Notification) error
+	List(ctx context.Context, uid int64, offset int, limit int) ([]domain.Notification, error)
+	CountUnread(ctx context.Context, uid int64) (int64, error)
+	// MarkRead ids 为空表示全部标记为已读
+	MarkRead(ctx context.Context, uid int64, ids []int64) error
+}
+
+type notificationService struct {
+	repo repository.NotificationRepository
+}
+
+func NewNotificationService(repo repository.NotificationRepository) NotificationService {
+	return &notificationService{
+		repo: repo,
+	}
+}
+
+func (s *notificationService) Notify(ctx context.Context, n domain.Notification) error {
+	_, err := s.repo.Create(ctx, n)
+	return err
+}
+
+func (s *notificationService) List(ctx context.Context, uid int64, offset int, limit int) ([]domain.Notification, error) {
+	return s.repo.ListByUid(ctx, uid, offset, limit)
+}
+
+func (s *notificationService) CountUnread(ctx context.Context, uid int64) (int64, error) {
+	return s.repo.CountUnread(ctx, uid)
+}
+
+func (s *notificationService) MarkRead(ctx context.Context, uid int64, ids []int64) error {
+	return s.repo.MarkRead(ctx, uid, ids)
+}
@@ -47,7 +47,8 @@ func (b *BatchRankingService) TopN(ctx context.Context) error {
 func (b *BatchRankingService) topN(ctx context.Context) ([]domain.Article, error) {
 	start := time.Now()
 	ddl := start.Add(-7 * 24 * time.Hour)
-	offset := 0
+	// Id 为 0 的时候只会查询 utime 早于 start 的文章
+	cursor := domain.ArticleCursor{Utime: start}
 	type Score struct {
 		score float64
 		art   domain.Article
@@ -62,7 +63,7 @@ func (b *BatchRankingService) topN(ctx context.Context) ([]domain.Article, error
 		}
 	})
 	for {
-		arts, err := b.artSvc.ListPub(ctx, start, offset, b.batchSize)
+		arts, err := b.artSvc.ListPubCursor(ctx, cursor, b.batchSize)
 		if err != nil {
 			return nil, err
 		}
@@ -92,8 +93,8 @@ func (b *BatchRankingService) topN(ctx context.Context) ([]domain.Article, error
 				}
 			}
 		}
-		offset = offset + len(arts)
-		if len(arts) < b.batchSize || arts[len(arts)-1].Utime.Before(ddl) {
+		cursor = domain.NextArticleCursor(arts, b.batchSize)
+		if cursor.IsZero() || cursor.Utime.Before(ddl) {
 			break
 		}
 	}
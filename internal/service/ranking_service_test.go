@@ -6,8 +6,7 @@ import (
 	"go.uber.org/mock/gomock"
 	"testing"
 	"time"
-	domain2 "webook/interactive/domain"
-	"webook/interactive/service"
+	intrv1 "webook/api/proto/gen/intr/v1"
 	"webook/internal/domain"
 	svcmocks "webook/internal/service/mocks"
 )
@@ -17,39 +16,39 @@ func TestBatchRankingService_TopN(t *testing.T) {
 	now := time.Now()
 	testCases := []struct {
 		name     string
-		mock     func(ctrl *gomock.Controller) (service.InteractiveService, ArticleService)
+		mock     func(ctrl *gomock.Controller) (intrv1.InteractiveServiceClient, ArticleService)
 		wantArts []domain.Article
 		wantErr  error
 	}{
 		{
 			name: "成功获取",
-			mock: func(ctrl *gomock.Controller) (service.InteractiveService, ArticleService) {
-				intrSvc := svcmocks.NewMockInteractiveService(ctrl)
+			mock: func(ctrl *gomock.Controller) (intrv1.InteractiveServiceClient, ArticleService) {
+				intrSvc := svcmocks.NewMockInteractiveServiceClient(ctrl)
 				artSvc := svcmocks.NewMockArticleService(ctrl)
-				artSvc.EXPECT().ListPub(gomock.Any(), gomock.Any(), 0, 2).
+				artSvc.EXPECT().ListPubCursor(gomock.Any(), gomock.Any(), 2).
 					Return([]domain.Article{
 						{Id: 1, Utime: now},
 						{Id: 2, Utime: now},
 					}, nil)
-				artSvc.EXPECT().ListPub(gomock.Any(), gomock.Any(), 2, 2).
+				artSvc.EXPECT().ListPubCursor(gomock.Any(), domain.ArticleCursor{Utime: now, Id: 2}, 2).
 					Return([]domain.Article{
 						{Id: 3, Utime: now},
 						{Id: 4, Utime: now},
 					}, nil)
-				artSvc.EXPECT().ListPub(gomock.Any(), gomock.Any(), 4, 2).
+				artSvc.EXPECT().ListPubCursor(gomock.Any(), domain.ArticleCursor{Utime: now, Id: 4}, 2).
 					Return([]domain.Article{}, nil)
-				intrSvc.EXPECT().GetByIds(gomock.Any(), gomock.Any(), []int64{1, 2}).
-					Return(map[int64]domain2.InteractiveArticle{
+				intrSvc.EXPECT().GetByIds(gomock.Any(), &intrv1.GetByIdsRequest{Biz: "article", Ids: []int64{1, 2}}).
+					Return(&intrv1.GetByIdsResponse{Intrs: map[int64]*intrv1.Interactive{
 						1: {LikeCnt: 1},
 						2: {LikeCnt: 2},
-					}, nil)
-				intrSvc.EXPECT().GetByIds(gomock.Any(), gomock.Any(), []int64{3, 4}).
-					Return(map[int64]domain2.InteractiveArticle{
+					}}, nil)
+				intrSvc.EXPECT().GetByIds(gomock.Any(), &intrv1.GetByIdsRequest{Biz: "article", Ids: []int64{3, 4}}).
+					Return(&intrv1.GetByIdsResponse{Intrs: map[int64]*intrv1.Interactive{
 						3: {LikeCnt: 3},
 						4: {LikeCnt: 4},
-					}, nil)
-				intrSvc.EXPECT().GetByIds(gomock.Any(), gomock.Any(), []int64{}).
-					Return(map[int64]domain2.InteractiveArticle{}, nil)
+					}}, nil)
+				intrSvc.EXPECT().GetByIds(gomock.Any(), &intrv1.GetByIdsRequest{Biz: "article", Ids: []int64{}}).
+					Return(&intrv1.GetByIdsResponse{}, nil)
 				return intrSvc, artSvc
 			},
 			wantErr: nil,
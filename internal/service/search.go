@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	"webook/pkg/logger"
+	"webook/pkg/searchx"
+)
+
+//go:generate mockgen -source=./search.go -package=svcmocks -destination=./mocks/search.mock.go SearchService
+type SearchService interface {
+	Search(ctx context.Context, query string, offset int, limit int) (domain.SearchResult, error)
+	// IndexArticle 从线上库读取文章，已经发表的加入索引，否则从索引里面删除
+	IndexArticle(ctx context.Context, id int64) error
+	RemoveArticle(ctx context.Context, id int64) error
+	// Rebuild 从数据库重建整个索引，返回索引的文章数量
+	Rebuild(ctx context.Context) (int, error)
+	SaveSnapshot() error
+	LoadSnapshot() error
+}
+
+const (
+	searchFieldTitle    = "title"
+	searchFieldContent  = "content"
+	searchFieldAuthor   = "author"
+	searchFieldTags     = "tags"
+	searchFieldAuthorId = "author_id"
+
+	searchSnippetLength = 120
+	searchRebuildBatch  = 100
+)
+
+// LocalSearchService 索引完全在进程内，每个实例各自维护一份
+type LocalSearchService struct {
+	idx          *searchx.Index
+	repo         repository.ArticleRepository
+	snapshotPath string
+	l            logger.LoggerV1
+}
+
+func NewLocalSearchService(repo repository.ArticleRepository, snapshotPath string, l logger.LoggerV1) SearchService {
+	return &LocalSearchService{
+		idx: searchx.NewIndex(map[string]float64{
+			searchFieldTitle:   3,
+			searchFieldTags:    2,
+			searchFieldAuthor:  1.5,
+			searchFieldContent: 1,
+		}),
+		repo:         repo,
+		snapshotPath: snapshotPath,
+		l:            l,
+	}
+}
+
+func (s *LocalSearchService) Search(ctx context.Context, query string, offset int, limit int) (domain.SearchResult, error) {
+	hits, total := s.idx.Search(query, offset, limit)
+	arts := make([]domain.SearchArticle, 0, len(hits))
+	for _, hit := range hits {
+		fields := hit.Doc.Fields
+		authorId, _ := strconv.ParseInt(fields[searchFieldAuthorId], 10, 64)
+		var tags []string
+		if fields[searchFieldTags] != "" {
+			tags = strings.Split(fields[searchFieldTags], "\n")
+		}
+		arts = append(arts, domain.SearchArticle{
+			Id:      hit.Id,
+			Title:   searchx.Highlight(fields[searchFieldTitle], query, 0, "<em>", "</em>"),
+			Snippet: searchx.Highlight(fields[searchFieldContent], query, searchSnippetLength, "<em>", "</em>"),
+			Author: domain.Author{
+				Id:   authorId,
+				Name: fields[searchFieldAuthor],
+			},
+			Tags:  tags,
+			Score: hit.Score,
+		})
+	}
+	return domain.SearchResult{
+		Total:    total,
+		Articles: arts,
+	}, nil
+}
+
+func (s *LocalSearchService) IndexArticle(ctx context.Context, id int64) error {
+	art, err := s.repo.GetPubById(ctx, id)
+	if err != nil {
+		return err
+	}
+	if art.Status != domain.ArticleStatusPublished {
+		s.idx.Delete(id)
+		return nil
+	}
+	s.idx.Put(s.toDoc(art))
+	return nil
+}
+
+func (s *LocalSearchService) RemoveArticle(ctx context.Context, id int64) error {
+	s.idx.Delete(id)
+	return nil
+}
+
+func (s *LocalSearchService) Rebuild(ctx context.Context) (int, error) {
+	var (
+		docs   []searchx.Doc
+		cursor domain.ArticleCursor
+	)
+	for {
+		arts, err := s.repo.ListPubCursor(ctx, cursor, searchRebuildBatch)
+		if err != nil {
+			return 0, err
+		}
+		for _, art := range arts {
+			// 列表里面没有作者名字和标签
+			full, err := s.repo.GetPubById(ctx, art.Id)
+			if err != nil {
+				return 0, err
+			}
+			docs = append(docs, s.toDoc(full))
+		}
+		cursor = domain.NextArticleCursor(arts, searchRebuildBatch)
+		if cursor.IsZero() {
+			break
+		}
+	}
+	s.idx.Replace(docs)
+	return len(docs), nil
+}
+
+// SaveSnapshot 先写临时文件再改名，避免进程崩溃留下写了一半的快照
+func (s *LocalSearchService) SaveSnapshot() error {
+	if s.snapshotPath == "" {
+		return nil
+	}
+	err := os.MkdirAll(filepath.Dir(s.snapshotPath), 0o755)
+	if err != nil {
+		return err
+	}
+	tmp := s.snapshotPath + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	err = s.idx.Snapshot(f)
+	if er := f.Close(); err == nil {
+		err = er
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, s.snapshotPath)
+}
+
+var ErrSearchSnapshotNotFound = errors.New("搜索索引快照不存在")
+
+func (s *LocalSearchService) LoadSnapshot() error {
+	if s.snapshotPath == "" {
+		return ErrSearchSnapshotNotFound
+	}
+	f, err := os.Open(s.snapshotPath)
+	if os.IsNotExist(err) {
+		return ErrSearchSnapshotNotFound
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	err = s.idx.Restore(f)
+	if err == nil {
+		s.l.Info("加载搜索索引快照", logger.String("path", s.snapshotPath),
+			logger.Int("cnt", s.idx.Len()))
+	}
+	return err
+}
+
+func (s *LocalSearchService) toDoc(art domain.Article) searchx.Doc {
+	return searchx.Doc{
+		Id: art.Id,
+		Fields: map[string]string{
+			searchFieldTitle:    art.Title,
+			searchFieldContent:  art.Content,
+			searchFieldAuthor:   art.Author.Name,
+			searchFieldTags:     strings.Join(art.Tags, "\n"),
+			searchFieldAuthorId: strconv.FormatInt(art.Author.Id, 10),
+		},
+	}
+}
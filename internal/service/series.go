@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"unicode/utf8"
+	"webook/internal/domain"
+	"webook/internal/repository"
+)
+
+const (
+	seriesTitleMaxLen = 256
+	// seriesMaxArticles 一个系列最多多少篇文章
+	seriesMaxArticles = 200
+)
+
+var (
+	ErrSeriesNotFound        = errors.New("系列不存在")
+	ErrInvalidSeries         = errors.New("系列标题为空或者过长")
+	ErrInvalidSeriesArticles = errors.New("系列文章过多或者有重复")
+	ErrSeriesArticleConflict = repository.ErrSeriesArticleConflict
+	ErrSeriesArticleInvalid  = repository.ErrSeriesArticleInvalid
+)
+
+//go:generate mockgen -source=./series.go -package=svcmocks -destination=./mocks/series.mock.go SeriesService
+type SeriesService interface {
+	Create(ctx context.Context, s domain.Series) (int64, error)
+	Update(ctx context.Context, s domain.Series) error
+	// SetArticles 覆盖系列里面的文章，添加、移除和调整顺序都用这个
+	SetArticles(ctx context.Context, id int64, uid int64, artIds []int64) error
+	Publish(ctx context.Context, id int64, uid int64) error
+	Withdraw(ctx context.Context, id int64, uid int64) error
+	// GetById 作者自己查看系列，包括草稿
+	GetById(ctx context.Context, id int64, uid int64) (domain.Series, error)
+	ListByAuthor(ctx context.Context, uid int64, offset int, limit int) ([]domain.Series, error)
+	// GetPub 已经发表的系列，只有已经发表的文章
+	GetPub(ctx context.Context, id int64) (domain.Series, error)
+	// Nav 文章在已经发表的系列里面的上一篇和下一篇，不在系列里面返回 ErrSeriesNotFound
+	Nav(ctx context.Context, artId int64) (domain.SeriesNav, error)
+}
+
+type seriesService struct {
+	repo repository.SeriesRepository
+}
+
+func NewSeriesService(repo repository.SeriesRepository) SeriesService {
+	return &seriesService{
+		repo: repo,
+	}
+}
+
+func (s *seriesService) Create(ctx context.Context, series domain.Series) (int64, error) {
+	if !validSeriesTitle(series.Title) {
+		return 0, ErrInvalidSeries
+	}
+	series.Status = domain.SeriesStatusUnpublished
+	return s.repo.Create(ctx, series)
+}
+
+func (s *seriesService) Update(ctx context.Context, series domain.Series) error {
+	if !validSeriesTitle(series.Title) {
+		return ErrInvalidSeries
+	}
+	return s.repo.Update(ctx, series)
+}
+
+func (s *seriesService) SetArticles(ctx context.Context, id int64, uid int64, artIds []int64) error {
+	if len(artIds) > seriesMaxArticles {
+		return ErrInvalidSeriesArticles
+	}
+	seen := make(map[int64]struct{}, len(artIds))
+	for _, artId := range artIds {
+		if _, ok := seen[artId]; ok {
+			return ErrInvalidSeriesArticles
+		}
+		seen[artId] = struct{}{}
+	}
+	return s.repo.SetArticles(ctx, id, uid, artIds)
+}
+
+func (s *seriesService) Publish(ctx context.Context, id int64, uid int64) error {
+	return s.repo.UpdateStatus(ctx, id, uid, domain.SeriesStatusPublished)
+}
+
+func (s *seriesService) Withdraw(ctx context.Context, id int64, uid int64) error {
+	return s.repo.UpdateStatus(ctx, id, uid, domain.SeriesStatusUnpublished)
+}
+
+func (s *seriesService) GetById(ctx context.Context, id int64, uid int64) (domain.Series, error) {
+	series, err := s.get(ctx, id)
+	if err != nil {
+		return domain.Series{}, err
+	}
+	if series.Author.Id != uid {
+		return domain.Series{}, ErrSeriesNotFound
+	}
+	series.Articles, err = s.repo.ListArticles(ctx, id)
+	return series, err
+}
+
+func (s *seriesService) ListByAuthor(ctx context.Context, uid int64, offset int, limit int) ([]domain.Series, error) {
+	return s.repo.ListByAuthor(ctx, uid, offset, limit)
+}
+
+func (s *seriesService) GetPub(ctx context.Context, id int64) (domain.Series, error) {
+	series, err := s.get(ctx, id)
+	if err != nil {
+		return domain.Series{}, err
+	}
+	if series.Status != domain.SeriesStatusPublished {
+		return domain.Series{}, ErrSeriesNotFound
+	}
+	series.Articles, err = s.repo.ListPubArticles(ctx, id)
+	return series, err
+}
+
+func (s *seriesService) Nav(ctx context.Context, artId int64) (domain.SeriesNav, error) {
+	series, err := s.repo.GetByArticle(ctx, artId)
+	if err == repository.ErrSeriesNotFound {
+		return domain.SeriesNav{}, ErrSeriesNotFound
+	}
+	if err != nil {
+		return domain.SeriesNav{}, err
+	}
+	if series.Status != domain.SeriesStatusPublished {
+		return domain.SeriesNav{}, ErrSeriesNotFound
+	}
+	arts, err := s.repo.ListPubArticles(ctx, series.Id)
+	if err != nil {
+		return domain.SeriesNav{}, err
+	}
+	nav := domain.SeriesNav{Series: series, Total: len(arts)}
+	for i, art := range arts {
+		if art.Id != artId {
+			continue
+		}
+		nav.Index = i
+		if i > 0 {
+			nav.Prev = arts[i-1]
+		}
+		if i+1 < len(arts) {
+			nav.Next = arts[i+1]
+		}
+		return nav, nil
+	}
+	// 文章还没有发表，或者已经撤回了
+	return domain.SeriesNav{}, ErrSeriesNotFound
+}
+
+func (s *seriesService) get(ctx context.Context, id int64) (domain.Series, error) {
+	series, err := s.repo.GetById(ctx, id)
+	if err == repository.ErrSeriesNotFound {
+		return domain.Series{}, ErrSeriesNotFound
+	}
+	return series, err
+}
+
+func validSeriesTitle(title string) bool {
+	n := utf8.RuneCountInString(title)
+	return n > 0 && n <= seriesTitleMaxLen
+}
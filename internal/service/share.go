@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	"webook/pkg/logger"
+	"webook/pkg/signx"
+)
+
+var (
+	ErrShareLinkInvalid     = errors.New("分享链接无效")
+	ErrShareLinkExpired     = errors.New("分享链接已经过期")
+	ErrShareLinkExhausted   = repository.ErrShareLinkExhausted
+	ErrShareArticleNotFound = errors.New("文章不存在")
+)
+
+//go:generate mockgen -source=./share.go -package=svcmocks -destination=./mocks/share.mock.go ShareService
+type ShareService interface {
+	// Create 给自己的文章创建分享链接，maxViews 为 0 表示不限制访问次数
+	Create(ctx context.Context, uid int64, artId int64, ttl time.Duration, maxViews int64) (domain.ShareLink, error)
+	List(ctx context.Context, uid int64, artId int64) ([]domain.ShareLink, error)
+	Revoke(ctx context.Context, uid int64, id int64) error
+	// Resolve 校验分享链接，成功的话访问次数加一，返回的文章带有渲染好的 HTML
+	Resolve(ctx context.Context, token string) (domain.ShareLink, domain.Article, error)
+}
+
+type shareService struct {
+	repo    repository.ShareLinkRepository
+	artRepo repository.ArticleRepository
+	signer  *signx.Signer
+	l       logger.LoggerV1
+}
+
+func NewShareService(repo repository.ShareLinkRepository, artRepo repository.ArticleRepository,
+	key []byte, l logger.LoggerV1) ShareService {
+	return &shareService{
+		repo:    repo,
+		artRepo: artRepo,
+		signer:  signx.NewSigner(key),
+		l:       l,
+	}
+}
+
+func (s *shareService) Create(ctx context.Context, uid int64, artId int64, ttl time.Duration, maxViews int64) (domain.ShareLink, error) {
+	art, err := s.artRepo.GetById(ctx, artId)
+	if err != nil {
+		return domain.ShareLink{}, err
+	}
+	if art.Author.Id != uid || !art.DeletedAt.IsZero() {
+		return domain.ShareLink{}, ErrShareArticleNotFound
+	}
+	nonce, err := signx.NewNonce()
+	if err != nil {
+		return domain.ShareLink{}, err
+	}
+	now := time.Now()
+	link := domain.ShareLink{
+		ArticleId: artId,
+		AuthorId:  uid,
+		Nonce:     nonce,
+		// 数据库里面存的是毫秒，这里截断一下，保证签名和数据库里面的一致
+		ExpireAt: now.Add(ttl).Truncate(time.Millisecond),
+		MaxViews: maxViews,
+		Ctime:    now,
+	}
+	link.Id, err = s.repo.Create(ctx, link)
+	if err != nil {
+		return domain.ShareLink{}, err
+	}
+	link.Token = s.sign(link)
+	return link, nil
+}
+
+func (s *shareService) List(ctx context.Context, uid int64, artId int64) ([]domain.ShareLink, error) {
+	links, err := s.repo.ListByArticle(ctx, artId, uid)
+	if err != nil {
+		return nil, err
+	}
+	for i := range links {
+		links[i].Token = s.sign(links[i])
+	}
+	return links, nil
+}
+
+func (s *shareService) Revoke(ctx context.Context, uid int64, id int64) error {
+	return s.repo.Revoke(ctx, id, uid)
+}
+
+func (s *shareService) Resolve(ctx context.Context, token string) (domain.ShareLink, domain.Article, error) {
+	// 先校验签名和过期时间，伪造的和过期的链接不需要查数据库
+	nonce, expireAt, err := s.signer.Verify(token)
+	switch err {
+	case nil:
+	case signx.ErrExpired:
+		return domain.ShareLink{}, domain.Article{}, ErrShareLinkExpired
+	default:
+		return domain.ShareLink{}, domain.Article{}, ErrShareLinkInvalid
+	}
+	link, err := s.repo.FindByNonce(ctx, nonce)
+	if err == repository.ErrShareLinkNotFound {
+		return domain.ShareLink{}, domain.Article{}, ErrShareLinkInvalid
+	}
+	if err != nil {
+		return domain.ShareLink{}, domain.Article{}, err
+	}
+	if link.Revoked || !link.ExpireAt.Equal(expireAt) {
+		return domain.ShareLink{}, domain.Article{}, ErrShareLinkInvalid
+	}
+	art, err := s.artRepo.GetById(ctx, link.ArticleId)
+	if err != nil {
+		return domain.ShareLink{}, domain.Article{}, err
+	}
+	if !art.DeletedAt.IsZero() {
+		return domain.ShareLink{}, domain.Article{}, ErrShareLinkInvalid
+	}
+	err = s.repo.IncrViews(ctx, link.Id)
+	if err != nil {
+		return domain.ShareLink{}, domain.Article{}, err
+	}
+	link.Views++
+	link.Token = token
+	// 草稿没有渲染过，分享出去的时候现场渲染
+	return link, renderArticle(art), nil
+}
+
+// sign 凭证里面带上 nonce，校验通过之后再按照 nonce 查数据库
+func (s *shareService) sign(link domain.ShareLink) string {
+	return s.signer.Sign(link.Nonce, link.ExpireAt)
+}
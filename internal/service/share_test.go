@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	repomocks "webook/internal/repository/mocks"
+	"webook/pkg/logger"
+	"webook/pkg/signx"
+)
+
+func TestShareService_Resolve(t *testing.T) {
+	key := []byte("test-share-key-0123456789abcdefgh")
+	exp := time.Now().Add(time.Hour).Truncate(time.Millisecond)
+	token := signx.NewSigner(key).Sign("nonce", exp)
+	testCases := []struct {
+		name      string
+		mock      func(ctrl *gomock.Controller) (repository.ShareLinkRepository, repository.ArticleRepository)
+		token     string
+		wantViews int64
+		wantErr   error
+	}{
+		{
+			name: "正常访问",
+			mock: func(ctrl *gomock.Controller) (repository.ShareLinkRepository, repository.ArticleRepository) {
+				repo := repomocks.NewMockShareLinkRepository(ctrl)
+				artRepo := repomocks.NewMockArticleRepository(ctrl)
+				repo.EXPECT().FindByNonce(gomock.Any(), "nonce").
+					Return(domain.ShareLink{Id: 1, ArticleId: 2, Nonce: "nonce", ExpireAt: exp, Views: 3}, nil)
+				artRepo.EXPECT().GetById(gomock.Any(), int64(2)).
+					Return(domain.Article{Id: 2, Content: "hello"}, nil)
+				repo.EXPECT().IncrViews(gomock.Any(), int64(1)).Return(nil)
+				return repo, artRepo
+			},
+			token:     token,
+			wantViews: 4,
+		},
+		{
+			name: "过期的不查数据库",
+			mock: func(ctrl *gomock.Controller) (repository.ShareLinkRepository, repository.ArticleRepository) {
+				return repomocks.NewMockShareLinkRepository(ctrl), repomocks.NewMockArticleRepository(ctrl)
+			},
+			token:   signx.NewSigner(key).Sign("nonce", time.Now().Add(-time.Minute)),
+			wantErr: ErrShareLinkExpired,
+		},
+		{
+			name: "伪造的签名",
+			mock: func(ctrl *gomock.Controller) (repository.ShareLinkRepository, repository.ArticleRepository) {
+				return repomocks.NewMockShareLinkRepository(ctrl), repomocks.NewMockArticleRepository(ctrl)
+			},
+			token:   signx.NewSigner([]byte("another-key-0123456789abcdefghij")).Sign("nonce", exp),
+			wantErr: ErrShareLinkInvalid,
+		},
+		{
+			name: "过期时间和数据库里面的不一致",
+			mock: func(ctrl *gomock.Controller) (repository.ShareLinkRepository, repository.ArticleRepository) {
+				repo := repomocks.NewMockShareLinkRepository(ctrl)
+				repo.EXPECT().FindByNonce(gomock.Any(), "nonce").
+					Return(domain.ShareLink{Id: 1, ArticleId: 2, Nonce: "nonce", ExpireAt: exp.Add(-time.Minute)}, nil)
+				return repo, repomocks.NewMockArticleRepository(ctrl)
+			},
+			token:   token,
+			wantErr: ErrShareLinkInvalid,
+		},
+		{
+			name: "已经撤销",
+			mock: func(ctrl *gomock.Controller) (repository.ShareLinkRepository, repository.ArticleRepository) {
+				repo := repomocks.NewMockShareLinkRepository(ctrl)
+				repo.EXPECT().FindByNonce(gomock.Any(), "nonce").
+					Return(domain.ShareLink{Id: 1, ArticleId: 2, Nonce: "nonce", ExpireAt: exp, Revoked: true}, nil)
+				return repo, repomocks.NewMockArticleRepository(ctrl)
+			},
+			token:   token,
+			wantErr: ErrShareLinkInvalid,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo, artRepo := tc.mock(ctrl)
+			svc := NewShareService(repo, artRepo, key, logger.NewNopLogger())
+			link, _, err := svc.Resolve(context.Background(), tc.token)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantViews, link.Views)
+		})
+	}
+}
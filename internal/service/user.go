@@ -11,6 +11,7 @@ import (
 var (
 	ErrDuplicateEmail        = repository.ErrDuplicateUser
 	ErrInvalidUserOrPassword = errors.New("用户或密码不正确")
+	ErrUserNotFound          = repository.ErrUserNotFound
 )
 
 type UserService interface {
@@ -20,6 +21,11 @@ type UserService interface {
 	Profile(ctx context.Context, id int64) (domain.User, error)
 	FindOrCreate(ctx context.Context, phone string) (domain.User, error)
 	FindOrCreateByWechat(ctx context.Context, wechatInfo domain.WechatInfo) (domain.User, error)
+	// ChangePassword 旧密码不对的时候返回 ErrInvalidUserOrPassword
+	ChangePassword(ctx context.Context, uid int64, oldPassword string, newPassword string) error
+	// ResetPassword 忘记密码的时候用，验证码由调用者校验。
+	// isEmail 为 true 的时候 target 是邮箱，否则是手机号，返回用户 ID
+	ResetPassword(ctx context.Context, target string, isEmail bool, password string) (int64, error)
 }
 type CacheUserService struct {
 	repo repository.UserRepository
@@ -99,3 +105,40 @@ func (svc *CacheUserService) FindOrCreate(ctx context.Context, phone string) (do
 
 	return svc.repo.FindByPhone(ctx, phone)
 }
+
+func (svc *CacheUserService) ChangePassword(ctx context.Context, uid int64, oldPassword string, newPassword string) error {
+	u, err := svc.repo.FindById(ctx, uid)
+	if err != nil {
+		return err
+	}
+	// 手机号和微信登录的用户没有密码，只能走找回密码
+	err = bcrypt.CompareHashAndPassword([]byte(u.Password), []byte(oldPassword))
+	if err != nil {
+		return ErrInvalidUserOrPassword
+	}
+	return svc.updatePassword(ctx, uid, newPassword)
+}
+
+func (svc *CacheUserService) ResetPassword(ctx context.Context, target string, isEmail bool, password string) (int64, error) {
+	var (
+		u   domain.User
+		err error
+	)
+	if isEmail {
+		u, err = svc.repo.FindByEmail(ctx, target)
+	} else {
+		u, err = svc.repo.FindByPhone(ctx, target)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return u.Id, svc.updatePassword(ctx, u.Id, password)
+}
+
+func (svc *CacheUserService) updatePassword(ctx context.Context, uid int64, password string) error {
+	encrypted, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	return svc.repo.UpdatePassword(ctx, uid, string(encrypted))
+}
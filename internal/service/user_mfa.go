@@ -0,0 +1,265 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"golang.org/x/crypto/bcrypt"
+	"strconv"
+	"strings"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	"webook/pkg/totp"
+)
+
+var (
+	ErrMFAAlreadyEnabled  = errors.New("已经启用了两步验证")
+	ErrMFANotEnabled      = errors.New("没有启用两步验证")
+	ErrInvalidMFACode     = errors.New("两步验证的验证码不对")
+	ErrMFALoginExpired    = errors.New("登录已经过期")
+	ErrMFATooManyAttempts = repository.ErrMFATooManyAttempts
+	ErrMFALocked          = repository.ErrMFALocked
+)
+
+const (
+	mfaIssuer = "webook"
+	// mfaSkew 允许前后各差一个时间片
+	mfaSkew         = 1
+	recoveryCodeCnt = 10
+	// recoveryCodeChars base32 的字母表，32 个字符取模没有偏差
+	recoveryCodeChars = "abcdefghijklmnopqrstuvwxyz234567"
+)
+
+//go:generate mockgen -source=./user_mfa.go -package=svcmocks -destination=./mocks/user_mfa.mock.go UserMFAService
+type UserMFAService interface {
+	// Enroll 生成新的密钥，返回密钥和 otpauth 链接，确认之前不生效
+	Enroll(ctx context.Context, uid int64) (string, string, error)
+	// Confirm 用第一个验证码确认，返回恢复码，明文只有这一次能看到
+	Confirm(ctx context.Context, uid int64, code string) ([]string, error)
+	Enabled(ctx context.Context, uid int64) (bool, error)
+	// Disable 要重新输入密码，并且提供验证码或者恢复码
+	Disable(ctx context.Context, uid int64, password string, code string) error
+	// BeginLogin 密码校验通过之后调用，返回等待两步验证的 token
+	BeginLogin(ctx context.Context, uid int64) (string, error)
+	// CompleteLogin code 可以是验证码也可以是恢复码，返回用户 ID
+	CompleteLogin(ctx context.Context, token string, code string) (int64, error)
+}
+
+type userMFAService struct {
+	repo     repository.UserMFARepository
+	userRepo repository.UserRepository
+}
+
+func NewUserMFAService(repo repository.UserMFARepository, userRepo repository.UserRepository) UserMFAService {
+	return &userMFAService{
+		repo:     repo,
+		userRepo: userRepo,
+	}
+}
+
+func (s *userMFAService) Enroll(ctx context.Context, uid int64) (string, string, error) {
+	m, err := s.repo.FindByUid(ctx, uid)
+	if err != nil && err != repository.ErrMFANotFound {
+		return "", "", err
+	}
+	if m.Status == domain.MFAStatusEnabled {
+		return "", "", ErrMFAAlreadyEnabled
+	}
+	u, err := s.userRepo.FindById(ctx, uid)
+	if err != nil {
+		return "", "", err
+	}
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return "", "", err
+	}
+	err = s.repo.SavePending(ctx, uid, secret)
+	if err != nil {
+		return "", "", err
+	}
+	return secret, totp.URI(mfaIssuer, s.account(u), secret), nil
+}
+
+// account 验证器 App 里面显示的账号
+func (s *userMFAService) account(u domain.User) string {
+	switch {
+	case u.Email != "":
+		return u.Email
+	case u.Phone != "":
+		return u.Phone
+	default:
+		return strconv.FormatInt(u.Id, 10)
+	}
+}
+
+func (s *userMFAService) Confirm(ctx context.Context, uid int64, code string) ([]string, error) {
+	m, err := s.repo.FindByUid(ctx, uid)
+	if err == repository.ErrMFANotFound {
+		return nil, ErrMFANotEnabled
+	}
+	if err != nil {
+		return nil, err
+	}
+	if m.Status == domain.MFAStatusEnabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+	step, ok := totp.Validate(m.Secret, strings.TrimSpace(code), time.Now(), mfaSkew)
+	if !ok {
+		return nil, ErrInvalidMFACode
+	}
+	codes := make([]string, 0, recoveryCodeCnt)
+	hashes := make([]string, 0, recoveryCodeCnt)
+	for i := 0; i < recoveryCodeCnt; i++ {
+		c, er := s.newRecoveryCode()
+		if er != nil {
+			return nil, er
+		}
+		codes = append(codes, c)
+		hashes = append(hashes, s.hashRecoveryCode(c))
+	}
+	err = s.repo.Enable(ctx, uid, step, hashes)
+	if err == repository.ErrMFANotFound {
+		// 并发确认，或者同一个验证码用了两次
+		return nil, ErrInvalidMFACode
+	}
+	if err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+func (s *userMFAService) Enabled(ctx context.Context, uid int64) (bool, error) {
+	m, err := s.repo.FindByUid(ctx, uid)
+	if err == repository.ErrMFANotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return m.Status == domain.MFAStatusEnabled, nil
+}
+
+func (s *userMFAService) Disable(ctx context.Context, uid int64, password string, code string) error {
+	u, err := s.userRepo.FindById(ctx, uid)
+	if err != nil {
+		return err
+	}
+	err = bcrypt.CompareHashAndPassword([]byte(u.Password), []byte(password))
+	if err != nil {
+		return ErrInvalidUserOrPassword
+	}
+	m, err := s.repo.FindByUid(ctx, uid)
+	if err == repository.ErrMFANotFound {
+		return ErrMFANotEnabled
+	}
+	if err != nil {
+		return err
+	}
+	if m.Status == domain.MFAStatusEnabled {
+		err = s.verify(ctx, m, code)
+		if err != nil {
+			return err
+		}
+	}
+	return s.repo.Delete(ctx, uid)
+}
+
+func (s *userMFAService) BeginLogin(ctx context.Context, uid int64) (string, error) {
+	buf := make([]byte, 32)
+	_, err := rand.Read(buf)
+	if err != nil {
+		return "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(buf)
+	return token, s.repo.SetPendingLogin(ctx, token, uid)
+}
+
+func (s *userMFAService) CompleteLogin(ctx context.Context, token string, code string) (int64, error) {
+	uid, err := s.repo.AttemptPendingLogin(ctx, token)
+	if err == repository.ErrMFAPendingNotFound {
+		return 0, ErrMFALoginExpired
+	}
+	if err != nil {
+		return 0, err
+	}
+	m, err := s.repo.FindByUid(ctx, uid)
+	switch {
+	case err == nil && m.Status == domain.MFAStatusEnabled:
+		err = s.verify(ctx, m, code)
+		if err != nil {
+			return 0, err
+		}
+	case err == nil, err == repository.ErrMFANotFound:
+		// 输入密码之后两步验证被关掉了，密码已经校验过，直接放行
+	default:
+		return 0, err
+	}
+	// 删除失败也没关系，token 很快就过期了
+	_ = s.repo.DelPendingLogin(ctx, token)
+	return uid, nil
+}
+
+// verify 按照用户统计失败的次数，失败太多次之后一段时间之内都不能再试
+func (s *userMFAService) verify(ctx context.Context, m domain.UserMFA, code string) error {
+	err := s.repo.AttemptVerify(ctx, m.Uid)
+	if err != nil {
+		return err
+	}
+	err = s.checkCode(ctx, m, code)
+	if err == nil {
+		// 删除失败也没关系，窗口过期之后就清掉了
+		_ = s.repo.ResetVerify(ctx, m.Uid)
+	}
+	return err
+}
+
+// checkCode 6 位数字的是验证码，其它的当成恢复码
+func (s *userMFAService) checkCode(ctx context.Context, m domain.UserMFA, code string) error {
+	code = strings.TrimSpace(code)
+	if len(code) == totp.Digits {
+		if _, er := strconv.Atoi(code); er == nil {
+			step, ok := totp.Validate(m.Secret, code, time.Now(), mfaSkew)
+			if !ok || step <= m.LastStep {
+				return ErrInvalidMFACode
+			}
+			err := s.repo.UseStep(ctx, m.Uid, step)
+			if err == repository.ErrMFAStepUsed {
+				return ErrInvalidMFACode
+			}
+			return err
+		}
+	}
+	err := s.repo.UseRecoveryCode(ctx, m.Uid, s.hashRecoveryCode(code))
+	if err == repository.ErrRecoveryCodeInvalid {
+		return ErrInvalidMFACode
+	}
+	return err
+}
+
+// newRecoveryCode 形如 abcde-fghij，50 位的熵
+func (s *userMFAService) newRecoveryCode() (string, error) {
+	buf := make([]byte, 10)
+	_, err := rand.Read(buf)
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	for i, b := range buf {
+		if i == 5 {
+			sb.WriteByte('-')
+		}
+		sb.WriteByte(recoveryCodeChars[b&31])
+	}
+	return sb.String(), nil
+}
+
+// hashRecoveryCode 恢复码是随机生成的，熵足够，不需要 bcrypt 这种慢哈希
+func (s *userMFAService) hashRecoveryCode(code string) string {
+	code = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
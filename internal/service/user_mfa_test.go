@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	repomocks "webook/internal/repository/mocks"
+	"webook/pkg/totp"
+)
+
+func TestUserMFAService_CompleteLogin(t *testing.T) {
+	secret, err := totp.GenerateSecret()
+	require.NoError(t, err)
+	step := totp.Step(time.Now())
+	code, err := totp.Code(secret, step)
+	require.NoError(t, err)
+	recovery := "abcde-fghij"
+
+	testCases := []struct {
+		name    string
+		mock    func(ctrl *gomock.Controller) repository.UserMFARepository
+		code    string
+		wantUid int64
+		wantErr error
+	}{
+		{
+			name: "验证码正确",
+			mock: func(ctrl *gomock.Controller) repository.UserMFARepository {
+				repo := mockMFALogin(ctrl, domain.UserMFA{Uid: 1, Secret: secret, Status: domain.MFAStatusEnabled})
+				repo.EXPECT().UseStep(gomock.Any(), int64(1), step).Return(nil)
+				repo.EXPECT().ResetVerify(gomock.Any(), int64(1)).Return(nil)
+				repo.EXPECT().DelPendingLogin(gomock.Any(), "token").Return(nil)
+				return repo
+			},
+			code:    code,
+			wantUid: 1,
+		},
+		{
+			name: "验证码已经用过",
+			mock: func(ctrl *gomock.Controller) repository.UserMFARepository {
+				return mockMFALogin(ctrl, domain.UserMFA{Uid: 1, Secret: secret,
+					Status: domain.MFAStatusEnabled, LastStep: step})
+			},
+			code:    code,
+			wantErr: ErrInvalidMFACode,
+		},
+		{
+			name: "并发重放同一个验证码",
+			mock: func(ctrl *gomock.Controller) repository.UserMFARepository {
+				repo := mockMFALogin(ctrl, domain.UserMFA{Uid: 1, Secret: secret, Status: domain.MFAStatusEnabled})
+				repo.EXPECT().UseStep(gomock.Any(), int64(1), step).Return(repository.ErrMFAStepUsed)
+				return repo
+			},
+			code:    code,
+			wantErr: ErrInvalidMFACode,
+		},
+		{
+			name: "恢复码正确",
+			mock: func(ctrl *gomock.Controller) repository.UserMFARepository {
+				repo := mockMFALogin(ctrl, domain.UserMFA{Uid: 1, Secret: secret, Status: domain.MFAStatusEnabled})
+				// 大小写和分隔符都不影响
+				repo.EXPECT().UseRecoveryCode(gomock.Any(), int64(1),
+					(&userMFAService{}).hashRecoveryCode(recovery)).Return(nil)
+				repo.EXPECT().ResetVerify(gomock.Any(), int64(1)).Return(nil)
+				repo.EXPECT().DelPendingLogin(gomock.Any(), "token").Return(nil)
+				return repo
+			},
+			code:    " ABCDEFGHIJ ",
+			wantUid: 1,
+		},
+		{
+			name: "恢复码错误",
+			mock: func(ctrl *gomock.Controller) repository.UserMFARepository {
+				repo := mockMFALogin(ctrl, domain.UserMFA{Uid: 1, Secret: secret, Status: domain.MFAStatusEnabled})
+				repo.EXPECT().UseRecoveryCode(gomock.Any(), int64(1), gomock.Any()).
+					Return(repository.ErrRecoveryCodeInvalid)
+				return repo
+			},
+			code:    recovery,
+			wantErr: ErrInvalidMFACode,
+		},
+		{
+			name: "失败太多次被锁定",
+			mock: func(ctrl *gomock.Controller) repository.UserMFARepository {
+				repo := repomocks.NewMockUserMFARepository(ctrl)
+				repo.EXPECT().AttemptPendingLogin(gomock.Any(), "token").Return(int64(1), nil)
+				repo.EXPECT().FindByUid(gomock.Any(), int64(1)).
+					Return(domain.UserMFA{Uid: 1, Secret: secret, Status: domain.MFAStatusEnabled}, nil)
+				repo.EXPECT().AttemptVerify(gomock.Any(), int64(1)).Return(repository.ErrMFALocked)
+				return repo
+			},
+			// 锁定之后正确的验证码也不行
+			code:    code,
+			wantErr: ErrMFALocked,
+		},
+		{
+			name: "token 过期",
+			mock: func(ctrl *gomock.Controller) repository.UserMFARepository {
+				repo := repomocks.NewMockUserMFARepository(ctrl)
+				repo.EXPECT().AttemptPendingLogin(gomock.Any(), "token").
+					Return(int64(0), repository.ErrMFAPendingNotFound)
+				return repo
+			},
+			code:    code,
+			wantErr: ErrMFALoginExpired,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewUserMFAService(tc.mock(ctrl), nil)
+			uid, err := svc.CompleteLogin(context.Background(), "token", tc.code)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantUid, uid)
+		})
+	}
+}
+
+func mockMFALogin(ctrl *gomock.Controller, m domain.UserMFA) *repomocks.MockUserMFARepository {
+	repo := repomocks.NewMockUserMFARepository(ctrl)
+	repo.EXPECT().AttemptPendingLogin(gomock.Any(), "token").Return(m.Uid, nil)
+	repo.EXPECT().FindByUid(gomock.Any(), m.Uid).Return(m, nil)
+	repo.EXPECT().AttemptVerify(gomock.Any(), m.Uid).Return(nil)
+	return repo
+}
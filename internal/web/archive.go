@@ -0,0 +1,236 @@
+package web
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/gotomicro/ekit/slice"
+	"net/http"
+	"strconv"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/service"
+	"webook/internal/web/jwt"
+	"webook/pkg/logger"
+)
+
+// ArchiveHandler 导出和导入文章的 Markdown 压缩包，都是异步执行的
+type ArchiveHandler struct {
+	svc service.ArchiveService
+	// maxSize 导入的压缩包最大的字节数
+	maxSize int64
+	l       logger.LoggerV1
+}
+
+func NewArchiveHandler(svc service.ArchiveService, maxSize int64, l logger.LoggerV1) *ArchiveHandler {
+	return &ArchiveHandler{
+		svc:     svc,
+		maxSize: maxSize,
+		l:       l,
+	}
+}
+
+func (h *ArchiveHandler) RegisterRoutes(server *gin.Engine) {
+	g := server.Group("/archives")
+	g.POST("/export", h.Export)
+	g.POST("/import", h.Import)
+	g.POST("/list", h.List)
+	g.GET("/detail/:id", h.Detail)
+	// 不需要登录，凭证里面有签名
+	g.GET("/download/:token", h.Download)
+}
+
+func (h *ArchiveHandler) Export(ctx *gin.Context) {
+	uc := ctx.MustGet("user").(jwt.UserClaims)
+	t, err := h.svc.RequestExport(ctx, uc.Uid)
+	if err == service.ErrArchiveTooFrequent {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "导出太频繁，请稍后再试",
+		})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.l.Error("创建导出任务失败", logger.Int64("uid", uc.Uid), logger.Error(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Data: h.toVO(t),
+	})
+}
+
+func (h *ArchiveHandler) Import(ctx *gin.Context) {
+	// multipart 本身还有一些开销，多留一点
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, h.maxSize+1<<20)
+	fh, err := ctx.FormFile("file")
+	if err != nil {
+		var mbe *http.MaxBytesError
+		if errors.As(err, &mbe) {
+			ctx.JSON(http.StatusOK, Result{
+				Code: 4,
+				Msg:  "压缩包太大",
+			})
+			return
+		}
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "没有上传文件",
+		})
+		return
+	}
+	if fh.Size > h.maxSize {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "压缩包太大",
+		})
+		return
+	}
+	f, err := fh.Open()
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.l.Error("打开上传的压缩包失败", logger.Error(err))
+		return
+	}
+	defer f.Close()
+	uc := ctx.MustGet("user").(jwt.UserClaims)
+	t, err := h.svc.RequestImport(ctx, uc.Uid, f, fh.Size)
+	switch err {
+	case nil:
+		ctx.JSON(http.StatusOK, Result{
+			Data: h.toVO(t),
+		})
+	case service.ErrArchiveTooLarge:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "压缩包太大",
+		})
+	default:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.l.Error("创建导入任务失败", logger.Int64("uid", uc.Uid), logger.Error(err))
+	}
+}
+
+func (h *ArchiveHandler) List(ctx *gin.Context) {
+	type Req struct {
+		Offset int `json:"offset"`
+		Limit  int `json:"limit"`
+	}
+	var req Req
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	if req.Limit <= 0 || req.Limit > 100 {
+		req.Limit = 100
+	}
+	uc := ctx.MustGet("user").(jwt.UserClaims)
+	ts, err := h.svc.List(ctx, uc.Uid, req.Offset, req.Limit)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.l.Error("查询导出导入任务失败", logger.Int64("uid", uc.Uid), logger.Error(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Data: slice.Map(ts, func(idx int, src domain.ArchiveTask) ArchiveTaskVO {
+			return h.toVO(src)
+		}),
+	})
+}
+
+func (h *ArchiveHandler) Detail(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "id 参数错误",
+		})
+		return
+	}
+	uc := ctx.MustGet("user").(jwt.UserClaims)
+	t, err := h.svc.Get(ctx, uc.Uid, id)
+	switch err {
+	case nil:
+		ctx.JSON(http.StatusOK, Result{
+			Data: h.toVO(t),
+		})
+	case service.ErrArchiveTaskNotFound:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "任务不存在",
+		})
+	default:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.l.Error("查询导出导入任务失败", logger.Int64("uid", uc.Uid),
+			logger.Int64("id", id), logger.Error(err))
+	}
+}
+
+func (h *ArchiveHandler) Download(ctx *gin.Context) {
+	t, rc, err := h.svc.Open(ctx, ctx.Param("token"))
+	switch err {
+	case nil:
+	case service.ErrArchiveLinkInvalid, service.ErrArchiveLinkExpired:
+		ctx.Status(http.StatusNotFound)
+		return
+	default:
+		ctx.Status(http.StatusInternalServerError)
+		h.l.Error("下载导出的压缩包失败", logger.Error(err))
+		return
+	}
+	defer rc.Close()
+	name := fmt.Sprintf("webook-%d-%s.zip", t.Uid, t.Utime.Format("20060102"))
+	ctx.Header("Cache-Control", "private, no-store")
+	ctx.DataFromReader(http.StatusOK, -1, "application/zip", rc, map[string]string{
+		"Content-Disposition": `attachment; filename="` + name + `"`,
+	})
+}
+
+func (h *ArchiveHandler) toVO(t domain.ArchiveTask) ArchiveTaskVO {
+	res := ArchiveTaskVO{
+		Id:     t.Id,
+		Type:   uint8(t.Type),
+		Status: uint8(t.Status),
+		Cnt:    t.Cnt,
+		Errors: t.Errors,
+		Reason: t.Reason,
+		Ctime:  t.Ctime.Format(time.DateTime),
+		Utime:  t.Utime.Format(time.DateTime),
+	}
+	if t.Token != "" {
+		res.DownloadPath = "/archives/download/" + t.Token
+		res.ExpireAt = t.Utime.Add(service.ArchiveRetention).Format(time.DateTime)
+	}
+	return res
+}
+
+type ArchiveTaskVO struct {
+	Id int64 `json:"id"`
+	// Type 1 导出，2 导入
+	Type uint8 `json:"type"`
+	// Status 1 等待执行，2 执行中，3 完成，4 失败
+	Status uint8 `json:"status"`
+	Cnt    int   `json:"cnt"`
+	// Errors 导入失败的文件
+	Errors []domain.ArchiveFileError `json:"errors,omitempty"`
+	Reason string                    `json:"reason,omitempty"`
+	// DownloadPath 导出完成之后下载压缩包的地址
+	DownloadPath string `json:"downloadPath,omitempty"`
+	ExpireAt     string `json:"expireAt,omitempty"`
+	Ctime        string `json:"ctime"`
+	Utime        string `json:"utime"`
+}
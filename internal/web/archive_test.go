@@ -0,0 +1,83 @@
+package web
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/service"
+	svcmocks "webook/internal/service/mocks"
+	"webook/pkg/logger"
+)
+
+func TestArchiveHandler_Download(t *testing.T) {
+	testCases := []struct {
+		name     string
+		mock     func(ctrl *gomock.Controller) service.ArchiveService
+		wantCode int
+		wantBody string
+	}{
+		{
+			name: "下载成功",
+			mock: func(ctrl *gomock.Controller) service.ArchiveService {
+				svc := svcmocks.NewMockArchiveService(ctrl)
+				svc.EXPECT().Open(gomock.Any(), "abc").Return(domain.ArchiveTask{
+					Id:    1,
+					Uid:   2,
+					Utime: time.Date(2024, 3, 1, 8, 0, 0, 0, time.Local),
+				}, io.NopCloser(strings.NewReader("zip")), nil)
+				return svc
+			},
+			wantCode: http.StatusOK,
+			wantBody: "zip",
+		},
+		{
+			name: "链接过期",
+			mock: func(ctrl *gomock.Controller) service.ArchiveService {
+				svc := svcmocks.NewMockArchiveService(ctrl)
+				svc.EXPECT().Open(gomock.Any(), "abc").
+					Return(domain.ArchiveTask{}, nil, service.ErrArchiveLinkExpired)
+				return svc
+			},
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name: "系统错误",
+			mock: func(ctrl *gomock.Controller) service.ArchiveService {
+				svc := svcmocks.NewMockArchiveService(ctrl)
+				svc.EXPECT().Open(gomock.Any(), "abc").
+					Return(domain.ArchiveTask{}, nil, io.ErrUnexpectedEOF)
+				return svc
+			},
+			wantCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, "/archives/download/abc", nil)
+			assert.NoError(t, err)
+			recorder := httptest.NewRecorder()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			hdl := NewArchiveHandler(tc.mock(ctrl), 1<<20, logger.NewNopLogger())
+			server := gin.Default()
+			hdl.RegisterRoutes(server)
+
+			server.ServeHTTP(recorder, req)
+
+			assert.Equal(t, tc.wantCode, recorder.Code)
+			assert.Equal(t, tc.wantBody, recorder.Body.String())
+			if tc.wantCode == http.StatusOK {
+				assert.Equal(t, `attachment; filename="webook-2-20240301.zip"`,
+					recorder.Header().Get("Content-Disposition"))
+			}
+		})
+	}
+}
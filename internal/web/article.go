@@ -15,18 +15,21 @@ import (
 )
 
 type ArticleHandler struct {
-	svc     service.ArticleService
-	intrSvc intrv1.InteractiveServiceClient
-	log     logger.LoggerV1
-	biz     string
+	svc       service.ArticleService
+	seriesSvc service.SeriesService
+	intrSvc   intrv1.InteractiveServiceClient
+	log       logger.LoggerV1
+	biz       string
 }
 
-func NewArticleHandler(svc service.ArticleService, intrSvc intrv1.InteractiveServiceClient, log logger.LoggerV1) *ArticleHandler {
+func NewArticleHandler(svc service.ArticleService, seriesSvc service.SeriesService,
+	intrSvc intrv1.InteractiveServiceClient, log logger.LoggerV1) *ArticleHandler {
 	return &ArticleHandler{
-		svc:     svc,
-		intrSvc: intrSvc,
-		log:     log,
-		biz:     "article",
+		svc:       svc,
+		seriesSvc: seriesSvc,
+		intrSvc:   intrSvc,
+		log:       log,
+		biz:       "article",
 	}
 }
 
@@ -35,10 +38,32 @@ func (h *ArticleHandler) RegisterRoutes(server *gin.Engine) {
 	g.POST("/edit", h.Edit)
 	g.POST("/publish", h.Publish)
 	g.POST("/withdraw", h.Withdraw)
+	g.POST("/delete", h.Delete)
+	trash := g.Group("/trash")
+	trash.POST("/list", h.ListTrash)
+	trash.POST("/restore", h.RestoreTrash)
+	g.POST("/schedule/cancel", h.CancelSchedule)
+	g.POST("/schedule/reschedule", h.Reschedule)
 	g.GET("/detail/:id", h.Detail)
 	g.POST("/list", h.List)
+	collab := g.Group("/collaborators")
+	collab.POST("/add", h.AddCollaborator)
+	collab.POST("/remove", h.RemoveCollaborator)
+	collab.POST("/articles", h.ListCollaborating)
+	g.GET("/:id/collaborators", h.ListCollaborators)
+	g.GET("/:id/audits", h.ListAudits)
+	g.GET("/:id/reviews", h.ListReviews)
+	g.GET("/:id/stats", h.Stats)
+	rev := g.Group("/:id/revisions")
+	rev.GET("", h.ListRevisions)
+	rev.GET("/diff", h.DiffRevisions)
+	rev.GET("/:version", h.GetRevision)
+	rev.POST("/:version/restore", h.RestoreRevision)
 	pub := g.Group("/pub")
+	pub.GET("/list", h.PubList)
 	pub.GET("/:id", h.PubDetail)
+	pub.GET("/tags", h.ListTags)
+	pub.GET("/tags/:name", h.ListPubByTag)
 	pub.POST("/like", h.Like)
 	pub.POST("/collect", h.Collect)
 	//pub.GET("/like-top/:num", h.LikeTopN)
@@ -50,6 +75,7 @@ func (h *ArticleHandler) Edit(ctx *gin.Context) {
 		Id      int64
 		Title   string
 		Content string
+		Tags    []string
 	}
 	req := Req{}
 	if err := ctx.Bind(&req); err != nil {
@@ -60,10 +86,32 @@ func (h *ArticleHandler) Edit(ctx *gin.Context) {
 		Id:      req.Id,
 		Title:   req.Title,
 		Content: req.Content,
+		Tags:    req.Tags,
 		Author: domain.Author{
 			Id: uc.Uid,
 		},
 	})
+	if err == service.ErrInvalidTags {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "标签数量过多或者标签过长",
+		})
+		return
+	}
+	if err == service.ErrArticleTooLarge {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "文章内容过长",
+		})
+		return
+	}
+	if err == service.ErrArticlePermissionDenied {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "没有权限",
+		})
+		return
+	}
 	if err != nil {
 		ctx.JSON(http.StatusOK, Result{
 			Msg: "系统错误",
@@ -81,20 +129,70 @@ func (h *ArticleHandler) Publish(ctx *gin.Context) {
 		Id      int64  `json:"id"`
 		Title   string `json:"title"`
 		Content string `json:"content"`
+		// Tags 不传表示不修改标签
+		Tags []string `json:"tags"`
+		// PublishAt 毫秒时间戳，不传就立刻发表
+		PublishAt int64 `json:"publish_at"`
 	}
 	req := Req{}
 	if err := ctx.Bind(&req); err != nil {
 		return
 	}
 	uc := ctx.MustGet("user").(jwt.UserClaims)
-	id, err := h.svc.Publish(ctx, domain.Article{
+	art := domain.Article{
 		Id:      req.Id,
 		Title:   req.Title,
 		Content: req.Content,
+		Tags:    req.Tags,
 		Author: domain.Author{
 			Id: uc.Uid,
 		},
-	})
+	}
+	var (
+		id  int64
+		err error
+	)
+	if req.PublishAt > 0 {
+		id, err = h.svc.SchedulePublish(ctx, art, time.UnixMilli(req.PublishAt))
+	} else {
+		id, err = h.svc.Publish(ctx, art)
+	}
+	if err == service.ErrInvalidPublishTime {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "定时发表时间必须晚于当前时间",
+		})
+		return
+	}
+	if err == service.ErrInvalidTags {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "标签数量过多或者标签过长",
+		})
+		return
+	}
+	if err == service.ErrArticleTooLarge {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "文章内容过长",
+		})
+		return
+	}
+	if err == service.ErrArticlePermissionDenied {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "没有权限",
+		})
+		return
+	}
+	if err == service.ErrArticlePendingReview {
+		// 文章已经保存了，前端可以拿 id 查审核进度
+		ctx.JSON(http.StatusOK, Result{
+			Msg:  "文章包含敏感内容，需要审核之后才能发表",
+			Data: id,
+		})
+		return
+	}
 	if err != nil {
 		ctx.JSON(http.StatusOK, Result{
 			Msg: "系统错误",
@@ -117,6 +215,13 @@ func (h *ArticleHandler) Withdraw(ctx *gin.Context) {
 	}
 	uc := ctx.MustGet("user").(jwt.UserClaims)
 	err := h.svc.Withdraw(ctx, req.Id, uc.Uid)
+	if err == service.ErrArticlePermissionDenied {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "没有权限",
+		})
+		return
+	}
 	if err != nil {
 		ctx.JSON(http.StatusOK, Result{
 			Msg: "系统错误",
@@ -129,6 +234,59 @@ func (h *ArticleHandler) Withdraw(ctx *gin.Context) {
 	})
 }
 
+func (h *ArticleHandler) CancelSchedule(ctx *gin.Context) {
+	type Req struct {
+		Id int64 `json:"id"`
+	}
+	req := Req{}
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	uc := ctx.MustGet("user").(jwt.UserClaims)
+	err := h.svc.CancelSchedule(ctx, req.Id, uc.Uid)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.log.Error("取消定时发表失败", logger.Int64("uid", uc.Uid), logger.Int64("id", req.Id), logger.Error(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Msg: "OK",
+	})
+}
+
+func (h *ArticleHandler) Reschedule(ctx *gin.Context) {
+	type Req struct {
+		Id        int64 `json:"id"`
+		PublishAt int64 `json:"publish_at"`
+	}
+	req := Req{}
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	uc := ctx.MustGet("user").(jwt.UserClaims)
+	err := h.svc.Reschedule(ctx, req.Id, uc.Uid, time.UnixMilli(req.PublishAt))
+	switch err {
+	case nil:
+		ctx.JSON(http.StatusOK, Result{
+			Msg: "OK",
+		})
+	case service.ErrInvalidPublishTime:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "定时发表时间必须晚于当前时间",
+		})
+	default:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.log.Error("修改定时发表时间失败", logger.Int64("uid", uc.Uid), logger.Int64("id", req.Id), logger.Error(err))
+	}
+}
+
 func (h *ArticleHandler) Detail(ctx *gin.Context) {
 	idStr := ctx.Param("id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
@@ -143,29 +301,29 @@ func (h *ArticleHandler) Detail(ctx *gin.Context) {
 		return
 	}
 
-	art, err := h.svc.GetById(ctx, id)
+	uc := ctx.MustGet("user").(jwt.UserClaims)
+	art, err := h.svc.GetByIdForUser(ctx, id, uc.Uid)
 
-	if err != nil {
+	if err == service.ErrArticlePermissionDenied {
+		// 有人在搞鬼
 		ctx.JSON(http.StatusOK, Result{
 			Msg:  "系统错误",
 			Code: 5,
 		})
-		h.log.Warn("查询文章失败",
+		h.log.Error("非法查询文章",
 			logger.Int64("id", id),
-			logger.Error(err))
+			logger.Int64("uid", uc.Uid))
 		return
 	}
 
-	uc := ctx.MustGet("user").(jwt.UserClaims)
-	if uc.Uid != art.Author.Id {
-		// 有人在搞鬼
+	if err != nil {
 		ctx.JSON(http.StatusOK, Result{
 			Msg:  "系统错误",
 			Code: 5,
 		})
-		h.log.Error("非法查询文章",
+		h.log.Warn("查询文章失败",
 			logger.Int64("id", id),
-			logger.Int64("uid", uc.Uid))
+			logger.Error(err))
 		return
 	}
 
@@ -175,10 +333,15 @@ func (h *ArticleHandler) Detail(ctx *gin.Context) {
 		Abstract: art.Abstract(),
 		Content:  art.Content,
 		AuthorId: art.Author.Id,
+		Authors:  h.toAuthorsVO(art),
+		Tags:     art.Tags,
 		Status:   art.Status.ToUint8(),
 		Ctime:    art.Ctime.Format(time.DateTime),
 		Utime:    art.Utime.Format(time.DateTime),
 	}
+	if art.Status == domain.ArticleStatusScheduled {
+		vo.PublishAt = art.PublishAt.Format(time.DateTime)
+	}
 
 	ctx.JSON(http.StatusOK, Result{
 		Data: vo,
@@ -186,13 +349,59 @@ func (h *ArticleHandler) Detail(ctx *gin.Context) {
 }
 
 func (h *ArticleHandler) List(ctx *gin.Context) {
-	var page Page
-	if err := ctx.Bind(&page); err != nil {
+	type Req struct {
+		Page
+		// Cursor 上一页返回的 next_cursor，第一页传空字符串。
+		// 不传的是还在用 offset 翻页的调用方，返回原来的数组
+		Cursor *string `json:"cursor"`
+	}
+	var req Req
+	if err := ctx.Bind(&req); err != nil {
 		return
 	}
+	if req.Limit <= 0 {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "limit 参数错误",
+		})
+		return
+	}
+	if req.Limit > 100 {
+		req.Limit = 100
+	}
 
 	uc := ctx.MustGet("user").(jwt.UserClaims)
-	arts, err := h.svc.GetByAuthor(ctx, uc.Uid, page.Offset, page.Limit)
+	if req.Cursor == nil {
+		arts, err := h.svc.GetByAuthor(ctx, uc.Uid, req.Offset, req.Limit)
+		if err != nil {
+			ctx.JSON(http.StatusOK, Result{
+				Code: 5,
+				Msg:  "系统错误",
+			})
+			h.log.Error("查找文章列表失败",
+				logger.Error(err),
+				logger.Int("offset", req.Offset),
+				logger.Int("limit", req.Limit),
+				logger.Int64("uid", uc.Uid))
+			return
+		}
+		ctx.JSON(http.StatusOK, Result{
+			Data: slice.Map[domain.Article, ArticleVO](arts, func(idx int, src domain.Article) ArticleVO {
+				return h.toListVO(src)
+			}),
+		})
+		return
+	}
+
+	cursor, err := decodeArticleCursor(*req.Cursor)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "cursor 参数错误",
+		})
+		return
+	}
+	arts, err := h.svc.GetByAuthorCursor(ctx, uc.Uid, cursor, req.Limit)
 	if err != nil {
 		ctx.JSON(http.StatusOK, Result{
 			Code: 5,
@@ -200,23 +409,89 @@ func (h *ArticleHandler) List(ctx *gin.Context) {
 		})
 		h.log.Error("查找文章列表失败",
 			logger.Error(err),
-			logger.Int("offset", page.Offset),
-			logger.Int("limit", page.Limit),
+			logger.String("cursor", *req.Cursor),
+			logger.Int("limit", req.Limit),
 			logger.Int64("uid", uc.Uid))
 		return
 	}
 	ctx.JSON(http.StatusOK, Result{
-		Data: slice.Map[domain.Article, ArticleVO](arts, func(idx int, src domain.Article) ArticleVO {
-			return ArticleVO{
-				Id:       src.Id,
-				Title:    src.Title,
-				Abstract: src.Abstract(),
-				AuthorId: src.Author.Id,
-				Status:   src.Status.ToUint8(),
-				Ctime:    src.Ctime.Format(time.DateTime),
-				Utime:    src.Utime.Format(time.DateTime),
-			}
-		}),
+		Data: ArticleListVO{
+			NextCursor: encodeArticleCursor(domain.NextArticleCursor(arts, req.Limit)),
+			List: slice.Map[domain.Article, ArticleVO](arts, func(idx int, src domain.Article) ArticleVO {
+				return h.toListVO(src)
+			}),
+		},
+	})
+}
+
+// toListVO 列表里面不返回内容
+func (h *ArticleHandler) toListVO(src domain.Article) ArticleVO {
+	vo := ArticleVO{
+		Id:       src.Id,
+		Title:    src.Title,
+		Abstract: src.Abstract(),
+		AuthorId: src.Author.Id,
+		Status:   src.Status.ToUint8(),
+		Ctime:    src.Ctime.Format(time.DateTime),
+		Utime:    src.Utime.Format(time.DateTime),
+	}
+	if src.Status == domain.ArticleStatusScheduled {
+		vo.PublishAt = src.PublishAt.Format(time.DateTime)
+	}
+	return vo
+}
+
+func (h *ArticleHandler) PubList(ctx *gin.Context) {
+	type Req struct {
+		Limit  int    `form:"limit"`
+		Cursor string `form:"cursor"`
+	}
+	var req Req
+	if err := ctx.BindQuery(&req); err != nil {
+		return
+	}
+	if req.Limit <= 0 || req.Limit > 100 {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "limit 参数错误",
+		})
+		return
+	}
+	cursor, err := decodeArticleCursor(req.Cursor)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "cursor 参数错误",
+		})
+		return
+	}
+	arts, err := h.svc.ListPubCursor(ctx, cursor, req.Limit)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.log.Error("查找线上文章列表失败",
+			logger.Error(err),
+			logger.String("cursor", req.Cursor),
+			logger.Int("limit", req.Limit))
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Data: ArticleListVO{
+			NextCursor: encodeArticleCursor(domain.NextArticleCursor(arts, req.Limit)),
+			List: slice.Map(arts, func(idx int, src domain.Article) ArticleVO {
+				return ArticleVO{
+					Id:       src.Id,
+					Title:    src.Title,
+					Abstract: src.Abstract(),
+					AuthorId: src.Author.Id,
+					Status:   src.Status.ToUint8(),
+					Ctime:    src.Ctime.Format(time.DateTime),
+					Utime:    src.Utime.Format(time.DateTime),
+				}
+			}),
+		},
 	})
 }
 
@@ -238,13 +513,14 @@ func (h *ArticleHandler) PubDetail(ctx *gin.Context) {
 		eg   errgroup.Group
 		art  domain.Article
 		intr *intrv1.GetResponse
+		nav  *SeriesNavVO
 	)
 
 	uc := ctx.MustGet("user").(jwt.UserClaims)
 
 	eg.Go(func() error {
 		var er error
-		art, er = h.svc.GetPubById(ctx, uc.Uid, id)
+		art, er = h.svc.GetPubById(ctx, uc.Uid, id, deviceId(ctx))
 		return er
 	})
 
@@ -256,6 +532,19 @@ func (h *ArticleHandler) PubDetail(ctx *gin.Context) {
 		return er
 	})
 
+	eg.Go(func() error {
+		res, er := h.seriesSvc.Nav(ctx, id)
+		switch er {
+		case nil:
+			nav = h.toSeriesNavVO(res)
+		case service.ErrSeriesNotFound:
+		default:
+			// 导航拿不到不影响看文章
+			h.log.Error("查询文章所在的系列失败", logger.Int64("id", id), logger.Error(er))
+		}
+		return nil
+	})
+
 	err = eg.Wait()
 
 	if err != nil {
@@ -281,20 +570,57 @@ func (h *ArticleHandler) PubDetail(ctx *gin.Context) {
 			Title: art.Title,
 
 			Content:    art.Content,
+			Html:       art.HTML,
+			Abstract:   art.Abstract(),
 			AuthorId:   art.Author.Id,
 			AuthorName: art.Author.Name,
+			Authors:    h.toAuthorsVO(art),
+			Tags:       art.Tags,
 			Status:     art.Status.ToUint8(),
 			ReadCnt:    intr.Intr.ReadCnt,
 			LikeCnt:    intr.Intr.LikeCnt,
 			Liked:      intr.Intr.Liked,
 			Collected:  intr.Intr.Collected,
 			CollectCnt: intr.Intr.CollectCnt,
+			CommentCnt: intr.Intr.CommentCnt,
 			Ctime:      art.Ctime.Format(time.DateTime),
 			Utime:      art.Utime.Format(time.DateTime),
+			Series:     nav,
 		},
 	})
 }
 
+func (h *ArticleHandler) toSeriesNavVO(nav domain.SeriesNav) *SeriesNavVO {
+	res := &SeriesNavVO{
+		Id:    nav.Series.Id,
+		Title: nav.Series.Title,
+		Index: nav.Index,
+		Total: nav.Total,
+	}
+	if nav.Prev.Id > 0 {
+		res.Prev = &SeriesPartVO{Id: nav.Prev.Id, Title: nav.Prev.Title}
+	}
+	if nav.Next.Id > 0 {
+		res.Next = &SeriesPartVO{Id: nav.Next.Id, Title: nav.Next.Title}
+	}
+	return res
+}
+
+// maxDeviceIdLen 设备 ID 是客户端传上来的，太长的直接丢掉
+const maxDeviceIdLen = 64
+
+// deviceId App 在 X-Device-Id 里面带上设备 ID，浏览器放在 device_id 这个 cookie 里面
+func deviceId(ctx *gin.Context) string {
+	id := ctx.GetHeader("X-Device-Id")
+	if id == "" {
+		id, _ = ctx.Cookie("device_id")
+	}
+	if len(id) > maxDeviceIdLen {
+		return ""
+	}
+	return id
+}
+
 func (h *ArticleHandler) Like(ctx *gin.Context) {
 	type Req struct {
 		Id   int64 `json:"id"`
@@ -0,0 +1,200 @@
+package web
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/gotomicro/ekit/slice"
+	"net/http"
+	"strconv"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/service"
+	"webook/internal/web/jwt"
+	"webook/pkg/logger"
+)
+
+var articleRoles = map[string]domain.ArticleRole{
+	domain.ArticleRoleViewer.String(): domain.ArticleRoleViewer,
+	domain.ArticleRoleEditor.String(): domain.ArticleRoleEditor,
+}
+
+func (h *ArticleHandler) AddCollaborator(ctx *gin.Context) {
+	type Req struct {
+		Id  int64 `json:"id"`
+		Uid int64 `json:"uid"`
+		// Role editor 或者 viewer
+		Role string `json:"role"`
+	}
+	req := Req{}
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	uc := ctx.MustGet("user").(jwt.UserClaims)
+	err := h.svc.AddCollaborator(ctx, req.Id, uc.Uid, req.Uid, articleRoles[req.Role])
+	switch err {
+	case nil:
+		ctx.JSON(http.StatusOK, Result{
+			Msg: "OK",
+		})
+	case service.ErrInvalidArticleRole:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "角色只能是 editor 或者 viewer",
+		})
+	case service.ErrInvalidCollaborator:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "不能邀请自己",
+		})
+	default:
+		h.collaboratorError(ctx, err, "邀请合作者失败", req.Id, uc.Uid)
+	}
+}
+
+func (h *ArticleHandler) RemoveCollaborator(ctx *gin.Context) {
+	type Req struct {
+		Id  int64 `json:"id"`
+		Uid int64 `json:"uid"`
+	}
+	req := Req{}
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	uc := ctx.MustGet("user").(jwt.UserClaims)
+	err := h.svc.RemoveCollaborator(ctx, req.Id, uc.Uid, req.Uid)
+	if err != nil {
+		h.collaboratorError(ctx, err, "移除合作者失败", req.Id, uc.Uid)
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Msg: "OK",
+	})
+}
+
+func (h *ArticleHandler) ListCollaborators(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Msg:  "id 参数错误",
+			Code: 4,
+		})
+		return
+	}
+	uc := ctx.MustGet("user").(jwt.UserClaims)
+	cs, err := h.svc.ListCollaborators(ctx, id, uc.Uid)
+	if err != nil {
+		h.collaboratorError(ctx, err, "查找合作者失败", id, uc.Uid)
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Data: slice.Map(cs, func(idx int, src domain.Collaborator) AuthorVO {
+			return h.toAuthorVO(src)
+		}),
+	})
+}
+
+// ListCollaborating 我作为合作者参与的文章
+func (h *ArticleHandler) ListCollaborating(ctx *gin.Context) {
+	var req Page
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	uc := ctx.MustGet("user").(jwt.UserClaims)
+	arts, err := h.svc.ListCollaborating(ctx, uc.Uid, req.Offset, req.Limit)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.log.Error("查找参与的文章失败",
+			logger.Error(err),
+			logger.Int("offset", req.Offset),
+			logger.Int("limit", req.Limit),
+			logger.Int64("uid", uc.Uid))
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Data: slice.Map(arts, func(idx int, src domain.Article) ArticleVO {
+			return ArticleVO{
+				Id:       src.Id,
+				Title:    src.Title,
+				Abstract: src.Abstract(),
+				AuthorId: src.Author.Id,
+				Status:   src.Status.ToUint8(),
+				Ctime:    src.Ctime.Format(time.DateTime),
+				Utime:    src.Utime.Format(time.DateTime),
+			}
+		}),
+	})
+}
+
+func (h *ArticleHandler) ListAudits(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Msg:  "id 参数错误",
+			Code: 4,
+		})
+		return
+	}
+	var page Page
+	if err = ctx.BindQuery(&page); err != nil {
+		return
+	}
+	uc := ctx.MustGet("user").(jwt.UserClaims)
+	audits, err := h.svc.ListAudits(ctx, id, uc.Uid, page.Offset, page.Limit)
+	if err != nil {
+		h.collaboratorError(ctx, err, "查找文章操作记录失败", id, uc.Uid)
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Data: slice.Map(audits, func(idx int, src domain.ArticleAudit) ArticleAuditVO {
+			return ArticleAuditVO{
+				Id:     src.Id,
+				Uid:    src.Uid,
+				Action: src.Action,
+				Detail: src.Detail,
+				Ctime:  src.Ctime.Format(time.DateTime),
+			}
+		}),
+	})
+}
+
+func (h *ArticleHandler) collaboratorError(ctx *gin.Context, err error, msg string, id int64, uid int64) {
+	if err == service.ErrArticlePermissionDenied {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "没有权限",
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Code: 5,
+		Msg:  "系统错误",
+	})
+	h.log.Error(msg,
+		logger.Int64("id", id),
+		logger.Int64("uid", uid),
+		logger.Error(err))
+}
+
+// toAuthorsVO 作者排在第一个，后面是合作者
+func (h *ArticleHandler) toAuthorsVO(art domain.Article) []AuthorVO {
+	res := make([]AuthorVO, 0, len(art.Collaborators)+1)
+	res = append(res, AuthorVO{
+		Id:   art.Author.Id,
+		Name: art.Author.Name,
+		Role: domain.ArticleRoleOwner.String(),
+	})
+	for _, c := range art.Collaborators {
+		res = append(res, h.toAuthorVO(c))
+	}
+	return res
+}
+
+func (h *ArticleHandler) toAuthorVO(c domain.Collaborator) AuthorVO {
+	return AuthorVO{
+		Id:   c.Uid,
+		Name: c.Name,
+		Role: c.Role.String(),
+	}
+}
@@ -0,0 +1,199 @@
+package web
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"webook/internal/domain"
+	"webook/internal/service"
+	svcmocks "webook/internal/service/mocks"
+	ijwt "webook/internal/web/jwt"
+	"webook/pkg/logger"
+)
+
+func TestArticleHandler_AddCollaborator(t *testing.T) {
+	testCases := []struct {
+		name    string
+		mock    func(ctrl *gomock.Controller) service.ArticleService
+		reqBody string
+		wantRes Result
+	}{
+		{
+			name: "邀请编辑",
+			mock: func(ctrl *gomock.Controller) service.ArticleService {
+				svc := svcmocks.NewMockArticleService(ctrl)
+				svc.EXPECT().AddCollaborator(gomock.Any(), int64(2), int64(1), int64(3), domain.ArticleRoleEditor).
+					Return(nil)
+				return svc
+			},
+			reqBody: `{"id":2,"uid":3,"role":"editor"}`,
+			wantRes: Result{
+				Msg: "OK",
+			},
+		},
+		{
+			name: "角色不对",
+			mock: func(ctrl *gomock.Controller) service.ArticleService {
+				svc := svcmocks.NewMockArticleService(ctrl)
+				svc.EXPECT().AddCollaborator(gomock.Any(), int64(2), int64(1), int64(3), domain.ArticleRoleUnknown).
+					Return(service.ErrInvalidArticleRole)
+				return svc
+			},
+			reqBody: `{"id":2,"uid":3,"role":"owner"}`,
+			wantRes: Result{
+				Code: 4,
+				Msg:  "角色只能是 editor 或者 viewer",
+			},
+		},
+		{
+			name: "不是作者",
+			mock: func(ctrl *gomock.Controller) service.ArticleService {
+				svc := svcmocks.NewMockArticleService(ctrl)
+				svc.EXPECT().AddCollaborator(gomock.Any(), int64(2), int64(1), int64(3), domain.ArticleRoleViewer).
+					Return(service.ErrArticlePermissionDenied)
+				return svc
+			},
+			reqBody: `{"id":2,"uid":3,"role":"viewer"}`,
+			wantRes: Result{
+				Code: 4,
+				Msg:  "没有权限",
+			},
+		},
+		{
+			name: "系统错误",
+			mock: func(ctrl *gomock.Controller) service.ArticleService {
+				svc := svcmocks.NewMockArticleService(ctrl)
+				svc.EXPECT().AddCollaborator(gomock.Any(), int64(2), int64(1), int64(3), domain.ArticleRoleViewer).
+					Return(errors.New("mock db error"))
+				return svc
+			},
+			reqBody: `{"id":2,"uid":3,"role":"viewer"}`,
+			wantRes: Result{
+				Code: 5,
+				Msg:  "系统错误",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost,
+				"/articles/collaborators/add",
+				bytes.NewBufferString(tc.reqBody))
+			req.Header.Set("Content-Type", "application/json")
+			assert.NoError(t, err)
+			recorder := httptest.NewRecorder()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			hdl := NewArticleHandler(tc.mock(ctrl), nil, nil, logger.NewNopLogger())
+			server := gin.Default()
+			server.Use(func(ctx *gin.Context) {
+				ctx.Set("user", ijwt.UserClaims{
+					Uid: 1,
+				})
+			})
+			hdl.RegisterRoutes(server)
+
+			server.ServeHTTP(recorder, req)
+
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			var res Result
+			err = json.NewDecoder(recorder.Body).Decode(&res)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantRes, res)
+		})
+	}
+}
+
+// TestArticleHandler_PermissionDenied 合作者没有编辑权限的时候不是系统错误
+func TestArticleHandler_PermissionDenied(t *testing.T) {
+	testCases := []struct {
+		name    string
+		mock    func(svc *svcmocks.MockArticleService)
+		method  string
+		path    string
+		reqBody string
+	}{
+		{
+			name: "修改",
+			mock: func(svc *svcmocks.MockArticleService) {
+				svc.EXPECT().Save(gomock.Any(), gomock.Any()).Return(int64(0), service.ErrArticlePermissionDenied)
+			},
+			path:    "/articles/edit",
+			reqBody: `{"id":2,"title":"标题","content":"内容"}`,
+		},
+		{
+			name: "发表",
+			mock: func(svc *svcmocks.MockArticleService) {
+				svc.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(int64(0), service.ErrArticlePermissionDenied)
+			},
+			path:    "/articles/publish",
+			reqBody: `{"id":2,"title":"标题","content":"内容"}`,
+		},
+		{
+			name: "撤回",
+			mock: func(svc *svcmocks.MockArticleService) {
+				svc.EXPECT().Withdraw(gomock.Any(), int64(2), int64(1)).Return(service.ErrArticlePermissionDenied)
+			},
+			path:    "/articles/withdraw",
+			reqBody: `{"id":2}`,
+		},
+		{
+			name: "查看历史版本",
+			mock: func(svc *svcmocks.MockArticleService) {
+				// limit 太大的时候按照 100 查
+				svc.EXPECT().ListRevisions(gomock.Any(), int64(2), int64(1), 0, 100).
+					Return(nil, service.ErrArticlePermissionDenied)
+			},
+			method: http.MethodGet,
+			path:   "/articles/2/revisions?limit=100000",
+		},
+		{
+			name: "恢复历史版本",
+			mock: func(svc *svcmocks.MockArticleService) {
+				svc.EXPECT().RestoreRevision(gomock.Any(), int64(2), int64(1), int64(3)).
+					Return(service.ErrArticlePermissionDenied)
+			},
+			path: "/articles/2/revisions/3/restore",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			method := tc.method
+			if method == "" {
+				method = http.MethodPost
+			}
+			req, err := http.NewRequest(method, tc.path, bytes.NewBufferString(tc.reqBody))
+			req.Header.Set("Content-Type", "application/json")
+			assert.NoError(t, err)
+			recorder := httptest.NewRecorder()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := svcmocks.NewMockArticleService(ctrl)
+			tc.mock(svc)
+			hdl := NewArticleHandler(svc, nil, nil, logger.NewNopLogger())
+			server := gin.Default()
+			server.Use(func(ctx *gin.Context) {
+				ctx.Set("user", ijwt.UserClaims{
+					Uid: 1,
+				})
+			})
+			hdl.RegisterRoutes(server)
+
+			server.ServeHTTP(recorder, req)
+
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			var res Result
+			err = json.NewDecoder(recorder.Body).Decode(&res)
+			assert.NoError(t, err)
+			assert.Equal(t, Result{Code: 4, Msg: "没有权限"}, res)
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package web
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"webook/internal/domain"
+	svcmocks "webook/internal/service/mocks"
+	ijwt "webook/internal/web/jwt"
+	"webook/pkg/logger"
+)
+
+func TestArticleHandler_List(t *testing.T) {
+	utime := time.UnixMilli(1700000000000)
+	art := domain.Article{Id: 3, Title: "标题", Author: domain.Author{Id: 1},
+		Status: domain.ArticleStatusUnpublished, Ctime: utime, Utime: utime}
+	vo := ArticleVO{Id: 3, Title: "标题", AuthorId: 1, Status: domain.ArticleStatusUnpublished,
+		Ctime: utime.Format(time.DateTime), Utime: utime.Format(time.DateTime)}
+	testCases := []struct {
+		name    string
+		mock    func(svc *svcmocks.MockArticleService)
+		reqBody string
+		wantRes Result
+	}{
+		{
+			name: "不传 cursor 还是返回数组",
+			mock: func(svc *svcmocks.MockArticleService) {
+				svc.EXPECT().GetByAuthor(gomock.Any(), int64(1), 0, 10).
+					Return([]domain.Article{art}, nil)
+			},
+			reqBody: `{"offset":0,"limit":10}`,
+			wantRes: Result{Data: []ArticleVO{vo}},
+		},
+		{
+			name: "游标翻页第一页",
+			mock: func(svc *svcmocks.MockArticleService) {
+				svc.EXPECT().GetByAuthorCursor(gomock.Any(), int64(1), domain.ArticleCursor{}, 1).
+					Return([]domain.Article{art}, nil)
+			},
+			reqBody: `{"limit":1,"cursor":""}`,
+			wantRes: Result{Data: ArticleListVO{
+				NextCursor: encodeArticleCursor(domain.ArticleCursor{Utime: utime, Id: 3}),
+				List:       []ArticleVO{vo},
+			}},
+		},
+		{
+			name: "limit 太大的时候只返回 100 条",
+			mock: func(svc *svcmocks.MockArticleService) {
+				svc.EXPECT().GetByAuthorCursor(gomock.Any(), int64(1), domain.ArticleCursor{}, 100).
+					Return([]domain.Article{}, nil)
+			},
+			reqBody: `{"limit":1000,"cursor":""}`,
+			wantRes: Result{Data: ArticleListVO{List: []ArticleVO{}}},
+		},
+		{
+			name:    "limit 不合法",
+			mock:    func(svc *svcmocks.MockArticleService) {},
+			reqBody: `{"offset":0,"limit":0}`,
+			wantRes: Result{Code: 4, Msg: "limit 参数错误"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := svcmocks.NewMockArticleService(ctrl)
+			tc.mock(svc)
+			hdl := NewArticleHandler(svc, nil, nil, logger.NewNopLogger())
+			server := gin.New()
+			server.Use(func(ctx *gin.Context) {
+				ctx.Set("user", ijwt.UserClaims{Uid: 1})
+			})
+			hdl.RegisterRoutes(server)
+
+			req, err := http.NewRequest(http.MethodPost, "/articles/list", bytes.NewBufferString(tc.reqBody))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+			recorder := httptest.NewRecorder()
+			server.ServeHTTP(recorder, req)
+
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			want, err := json.Marshal(tc.wantRes)
+			require.NoError(t, err)
+			assert.JSONEq(t, string(want), recorder.Body.String())
+		})
+	}
+}
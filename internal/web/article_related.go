@@ -0,0 +1,97 @@
+package web
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/gotomicro/ekit/slice"
+	"net/http"
+	"strconv"
+	"time"
+	intrv1 "webook/api/proto/gen/intr/v1"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	"webook/internal/service"
+	"webook/pkg/logger"
+)
+
+// ArticleRelatedHandler 文章详情页下面的相关推荐
+type ArticleRelatedHandler struct {
+	svc     service.ArticleRelatedService
+	intrSvc intrv1.InteractiveServiceClient
+	biz     string
+	l       logger.LoggerV1
+}
+
+func NewArticleRelatedHandler(svc service.ArticleRelatedService, intrSvc intrv1.InteractiveServiceClient,
+	l logger.LoggerV1) *ArticleRelatedHandler {
+	return &ArticleRelatedHandler{
+		svc:     svc,
+		intrSvc: intrSvc,
+		biz:     "article",
+		l:       l,
+	}
+}
+
+func (h *ArticleRelatedHandler) RegisterRoutes(server *gin.Engine) {
+	server.GET("/articles/pub/:id/related", h.Related)
+}
+
+func (h *ArticleRelatedHandler) Related(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "id 参数错误",
+		})
+		return
+	}
+	arts, err := h.svc.Related(ctx, id, service.RelatedSize)
+	if err == repository.ErrArticleNotFound {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "文章不存在",
+		})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.l.Error("查询相关文章失败", logger.Int64("id", id), logger.Error(err))
+		return
+	}
+	var intrs map[int64]*intrv1.Interactive
+	if len(arts) > 0 {
+		resp, er := h.intrSvc.GetByIds(ctx, &intrv1.GetByIdsRequest{
+			Biz: h.biz,
+			Ids: slice.Map(arts, func(idx int, src domain.Article) int64 {
+				return src.Id
+			}),
+		})
+		if er != nil {
+			// 计数拿不到不影响看推荐
+			h.l.Error("查询相关文章的互动数据失败", logger.Int64("id", id), logger.Error(er))
+		} else {
+			intrs = resp.GetIntrs()
+		}
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Data: slice.Map(arts, func(idx int, src domain.Article) ArticleVO {
+			intr := intrs[src.Id]
+			return ArticleVO{
+				Id:         src.Id,
+				Title:      src.Title,
+				Abstract:   src.Abstract(),
+				AuthorId:   src.Author.Id,
+				AuthorName: src.Author.Name,
+				Tags:       src.Tags,
+				ReadCnt:    intr.GetReadCnt(),
+				LikeCnt:    intr.GetLikeCnt(),
+				CollectCnt: intr.GetCollectCnt(),
+				CommentCnt: intr.GetCommentCnt(),
+				Ctime:      src.Ctime.Format(time.DateTime),
+				Utime:      src.Utime.Format(time.DateTime),
+			}
+		}),
+	})
+}
@@ -0,0 +1,124 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	intrdomain "webook/interactive/domain"
+	"webook/internal/client"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	"webook/internal/service"
+	svcmocks "webook/internal/service/mocks"
+	"webook/pkg/logger"
+)
+
+func TestArticleRelatedHandler_Related(t *testing.T) {
+	now := time.UnixMilli(1700000000000)
+	type Res struct {
+		Code int         `json:"code"`
+		Msg  string      `json:"msg"`
+		Data []ArticleVO `json:"data"`
+	}
+	testCases := []struct {
+		name    string
+		mock    func(ctrl *gomock.Controller) (service.ArticleRelatedService, *svcmocks.MockInteractiveService)
+		id      string
+		wantRes Res
+	}{
+		{
+			name: "带上互动数据",
+			mock: func(ctrl *gomock.Controller) (service.ArticleRelatedService, *svcmocks.MockInteractiveService) {
+				svc := svcmocks.NewMockArticleRelatedService(ctrl)
+				svc.EXPECT().Related(gomock.Any(), int64(1), service.RelatedSize).Return([]domain.Article{
+					{Id: 3, Title: "标题3", Author: domain.Author{Id: 7, Name: "作者"}, Ctime: now, Utime: now},
+					{Id: 2, Title: "标题2", Author: domain.Author{Id: 8}, Ctime: now, Utime: now},
+				}, nil)
+				intrSvc := svcmocks.NewMockInteractiveService(ctrl)
+				intrSvc.EXPECT().GetByIds(gomock.Any(), "article", []int64{3, 2}).
+					Return(map[int64]intrdomain.Interactive{
+						3: {BizId: 3, ReadCnt: 10, LikeCnt: 2, CollectCnt: 1},
+					}, nil)
+				return svc, intrSvc
+			},
+			id: "1",
+			wantRes: Res{
+				Data: []ArticleVO{
+					{Id: 3, Title: "标题3", AuthorId: 7, AuthorName: "作者", ReadCnt: 10, LikeCnt: 2, CollectCnt: 1,
+						Ctime: now.Format(time.DateTime), Utime: now.Format(time.DateTime)},
+					{Id: 2, Title: "标题2", AuthorId: 8,
+						Ctime: now.Format(time.DateTime), Utime: now.Format(time.DateTime)},
+				},
+			},
+		},
+		{
+			name: "互动数据出错",
+			mock: func(ctrl *gomock.Controller) (service.ArticleRelatedService, *svcmocks.MockInteractiveService) {
+				svc := svcmocks.NewMockArticleRelatedService(ctrl)
+				svc.EXPECT().Related(gomock.Any(), int64(1), service.RelatedSize).Return([]domain.Article{
+					{Id: 2, Title: "标题2", Ctime: now, Utime: now},
+				}, nil)
+				intrSvc := svcmocks.NewMockInteractiveService(ctrl)
+				intrSvc.EXPECT().GetByIds(gomock.Any(), "article", []int64{2}).
+					Return(nil, errors.New("mock error"))
+				return svc, intrSvc
+			},
+			id: "1",
+			wantRes: Res{
+				Data: []ArticleVO{
+					{Id: 2, Title: "标题2", Ctime: now.Format(time.DateTime), Utime: now.Format(time.DateTime)},
+				},
+			},
+		},
+		{
+			name: "文章不存在",
+			mock: func(ctrl *gomock.Controller) (service.ArticleRelatedService, *svcmocks.MockInteractiveService) {
+				svc := svcmocks.NewMockArticleRelatedService(ctrl)
+				svc.EXPECT().Related(gomock.Any(), int64(1), service.RelatedSize).
+					Return(nil, repository.ErrArticleNotFound)
+				return svc, svcmocks.NewMockInteractiveService(ctrl)
+			},
+			id:      "1",
+			wantRes: Res{Code: 4, Msg: "文章不存在"},
+		},
+		{
+			name: "id 不对",
+			mock: func(ctrl *gomock.Controller) (service.ArticleRelatedService, *svcmocks.MockInteractiveService) {
+				return svcmocks.NewMockArticleRelatedService(ctrl), svcmocks.NewMockInteractiveService(ctrl)
+			},
+			id:      "abc",
+			wantRes: Res{Code: 4, Msg: "id 参数错误"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc, intrSvc := tc.mock(ctrl)
+			hdl := NewArticleRelatedHandler(svc, client.NewLocalInteractiveServiceAdapter(intrSvc), logger.NewNopLogger())
+			server := gin.Default()
+			// 和文章详情的路由注册在一起不能冲突
+			NewArticleHandler(nil, nil, nil, logger.NewNopLogger()).RegisterRoutes(server)
+			hdl.RegisterRoutes(server)
+
+			req, err := http.NewRequest(http.MethodGet, "/articles/pub/"+tc.id+"/related", nil)
+			require.NoError(t, err)
+			recorder := httptest.NewRecorder()
+			server.ServeHTTP(recorder, req)
+
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			var res Res
+			err = json.NewDecoder(recorder.Body).Decode(&res)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantRes, res)
+		})
+	}
+}
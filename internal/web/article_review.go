@@ -0,0 +1,184 @@
+package web
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/gotomicro/ekit/slice"
+	"net/http"
+	"strconv"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/service"
+	"webook/internal/web/jwt"
+	"webook/pkg/logger"
+)
+
+// ReviewHandler 管理员审核命中敏感词的文章
+type ReviewHandler struct {
+	svc service.ArticleReviewService
+	// admins 有审核权限的用户
+	admins map[int64]bool
+	l      logger.LoggerV1
+}
+
+func NewReviewHandler(svc service.ArticleReviewService, admins []int64, l logger.LoggerV1) *ReviewHandler {
+	m := make(map[int64]bool, len(admins))
+	for _, uid := range admins {
+		m[uid] = true
+	}
+	return &ReviewHandler{
+		svc:    svc,
+		admins: m,
+		l:      l,
+	}
+}
+
+func (h *ReviewHandler) RegisterRoutes(server *gin.Engine) {
+	g := server.Group("/admin/reviews", h.checkAdmin)
+	g.POST("/list", h.List)
+	g.POST("/approve", h.Approve)
+	g.POST("/reject", h.Reject)
+}
+
+func (h *ReviewHandler) checkAdmin(ctx *gin.Context) {
+	uc := ctx.MustGet("user").(jwt.UserClaims)
+	if !h.admins[uc.Uid] {
+		ctx.AbortWithStatus(http.StatusForbidden)
+		h.l.Warn("非管理员访问审核接口", logger.Int64("uid", uc.Uid))
+		return
+	}
+}
+
+func (h *ReviewHandler) List(ctx *gin.Context) {
+	var page Page
+	if err := ctx.Bind(&page); err != nil {
+		return
+	}
+	reviews, err := h.svc.ListPending(ctx, page.Offset, page.Limit)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.l.Error("查找待审核文章失败", logger.Error(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Data: toReviewVOs(reviews),
+	})
+}
+
+func (h *ReviewHandler) Approve(ctx *gin.Context) {
+	type Req struct {
+		Id int64 `json:"id"`
+	}
+	req := Req{}
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	uc := ctx.MustGet("user").(jwt.UserClaims)
+	err := h.svc.Approve(ctx, req.Id, uc.Uid)
+	if err != nil {
+		h.reviewError(ctx, err, "审核通过文章失败", req.Id, uc.Uid)
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Msg: "OK",
+	})
+}
+
+func (h *ReviewHandler) Reject(ctx *gin.Context) {
+	type Req struct {
+		Id     int64  `json:"id"`
+		Reason string `json:"reason"`
+	}
+	req := Req{}
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	uc := ctx.MustGet("user").(jwt.UserClaims)
+	err := h.svc.Reject(ctx, req.Id, uc.Uid, req.Reason)
+	if err != nil {
+		h.reviewError(ctx, err, "驳回文章失败", req.Id, uc.Uid)
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Msg: "OK",
+	})
+}
+
+func (h *ReviewHandler) reviewError(ctx *gin.Context, err error, msg string, id int64, uid int64) {
+	switch err {
+	case service.ErrInvalidReview:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "驳回的原因不能为空，最多 512 个字",
+		})
+	case service.ErrReviewNotFound:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "审核记录不存在",
+		})
+	case service.ErrReviewNotPending:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "已经审核过了",
+		})
+	case service.ErrReviewOutdated:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "作者已经修改了文章，这次审核作废",
+		})
+	default:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.l.Error(msg,
+			logger.Int64("id", id),
+			logger.Int64("uid", uid),
+			logger.Error(err))
+	}
+}
+
+// ListReviews 作者和合作者查看文章的审核记录
+func (h *ArticleHandler) ListReviews(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Msg:  "id 参数错误",
+			Code: 4,
+		})
+		return
+	}
+	var page Page
+	if err = ctx.BindQuery(&page); err != nil {
+		return
+	}
+	uc := ctx.MustGet("user").(jwt.UserClaims)
+	reviews, err := h.svc.ListReviews(ctx, id, uc.Uid, page.Offset, page.Limit)
+	if err != nil {
+		h.collaboratorError(ctx, err, "查找文章审核记录失败", id, uc.Uid)
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Data: toReviewVOs(reviews),
+	})
+}
+
+func toReviewVOs(reviews []domain.ArticleReview) []ReviewVO {
+	return slice.Map(reviews, func(idx int, src domain.ArticleReview) ReviewVO {
+		return ReviewVO{
+			Id:        src.Id,
+			ArticleId: src.ArticleId,
+			Title:     src.Title,
+			Author:    src.Author,
+			Submitter: src.Submitter,
+			Hits:      src.Hits,
+			Status:    src.Status.String(),
+			Reviewer:  src.Reviewer,
+			Reason:    src.Reason,
+			Ctime:     src.Ctime.Format(time.DateTime),
+			Utime:     src.Utime.Format(time.DateTime),
+		}
+	})
+}
@@ -0,0 +1,99 @@
+package web
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"webook/internal/service"
+	svcmocks "webook/internal/service/mocks"
+	ijwt "webook/internal/web/jwt"
+	"webook/pkg/logger"
+)
+
+func TestReviewHandler_Reject(t *testing.T) {
+	testCases := []struct {
+		name     string
+		mock     func(ctrl *gomock.Controller) service.ArticleReviewService
+		uid      int64
+		reqBody  string
+		wantCode int
+		wantRes  Result
+	}{
+		{
+			name: "驳回成功",
+			mock: func(ctrl *gomock.Controller) service.ArticleReviewService {
+				svc := svcmocks.NewMockArticleReviewService(ctrl)
+				svc.EXPECT().Reject(gomock.Any(), int64(2), int64(1), "包含广告").Return(nil)
+				return svc
+			},
+			uid:      1,
+			reqBody:  `{"id":2,"reason":"包含广告"}`,
+			wantCode: http.StatusOK,
+			wantRes: Result{
+				Msg: "OK",
+			},
+		},
+		{
+			name: "作者已经修改了文章",
+			mock: func(ctrl *gomock.Controller) service.ArticleReviewService {
+				svc := svcmocks.NewMockArticleReviewService(ctrl)
+				svc.EXPECT().Reject(gomock.Any(), int64(2), int64(1), "包含广告").
+					Return(service.ErrReviewOutdated)
+				return svc
+			},
+			uid:      1,
+			reqBody:  `{"id":2,"reason":"包含广告"}`,
+			wantCode: http.StatusOK,
+			wantRes: Result{
+				Code: 4,
+				Msg:  "作者已经修改了文章，这次审核作废",
+			},
+		},
+		{
+			name: "不是管理员",
+			mock: func(ctrl *gomock.Controller) service.ArticleReviewService {
+				return svcmocks.NewMockArticleReviewService(ctrl)
+			},
+			uid:      3,
+			reqBody:  `{"id":2,"reason":"包含广告"}`,
+			wantCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost,
+				"/admin/reviews/reject",
+				bytes.NewBufferString(tc.reqBody))
+			req.Header.Set("Content-Type", "application/json")
+			assert.NoError(t, err)
+			recorder := httptest.NewRecorder()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			hdl := NewReviewHandler(tc.mock(ctrl), []int64{1}, logger.NewNopLogger())
+			server := gin.Default()
+			server.Use(func(ctx *gin.Context) {
+				ctx.Set("user", ijwt.UserClaims{
+					Uid: tc.uid,
+				})
+			})
+			hdl.RegisterRoutes(server)
+
+			server.ServeHTTP(recorder, req)
+
+			assert.Equal(t, tc.wantCode, recorder.Code)
+			if tc.wantCode != http.StatusOK {
+				return
+			}
+			var res Result
+			err = json.NewDecoder(recorder.Body).Decode(&res)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantRes, res)
+		})
+	}
+}
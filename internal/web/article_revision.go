@@ -0,0 +1,180 @@
+package web
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/gotomicro/ekit/slice"
+	"net/http"
+	"strconv"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/service"
+	"webook/internal/web/jwt"
+	"webook/pkg/diffx"
+	"webook/pkg/logger"
+)
+
+func (h *ArticleHandler) ListRevisions(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Msg:  "id 参数错误",
+			Code: 4,
+		})
+		return
+	}
+	var page Page
+	if err = ctx.BindQuery(&page); err != nil {
+		return
+	}
+	if page.Limit <= 0 || page.Limit > 100 {
+		page.Limit = 100
+	}
+	uc := ctx.MustGet("user").(jwt.UserClaims)
+	revs, err := h.svc.ListRevisions(ctx, id, uc.Uid, page.Offset, page.Limit)
+	if err != nil {
+		h.revisionError(ctx, err, "查找文章历史版本失败", id, uc.Uid)
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Data: slice.Map(revs, func(idx int, src domain.ArticleRevision) ArticleRevisionVO {
+			vo := h.toRevisionVO(src)
+			vo.Content = ""
+			vo.Abstract = domain.Article{Content: src.Content}.Abstract()
+			return vo
+		}),
+	})
+}
+
+func (h *ArticleHandler) GetRevision(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Msg:  "id 参数错误",
+			Code: 4,
+		})
+		return
+	}
+	version, err := strconv.ParseInt(ctx.Param("version"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Msg:  "version 参数错误",
+			Code: 4,
+		})
+		return
+	}
+	uc := ctx.MustGet("user").(jwt.UserClaims)
+	rev, err := h.svc.GetRevision(ctx, id, uc.Uid, version)
+	if err != nil {
+		h.revisionError(ctx, err, "查询文章历史版本失败", id, uc.Uid)
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Data: h.toRevisionVO(rev),
+	})
+}
+
+func (h *ArticleHandler) DiffRevisions(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Msg:  "id 参数错误",
+			Code: 4,
+		})
+		return
+	}
+	from, err1 := strconv.ParseInt(ctx.Query("from"), 10, 64)
+	to, err2 := strconv.ParseInt(ctx.Query("to"), 10, 64)
+	if err1 != nil || err2 != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Msg:  "from 或者 to 参数错误",
+			Code: 4,
+		})
+		return
+	}
+	uc := ctx.MustGet("user").(jwt.UserClaims)
+	lines, err := h.svc.DiffRevisions(ctx, id, uc.Uid, from, to)
+	if err != nil {
+		h.revisionError(ctx, err, "比较文章历史版本失败", id, uc.Uid)
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Data: RevisionDiffVO{
+			From: from,
+			To:   to,
+			Lines: slice.Map(lines, func(idx int, src diffx.Line) DiffLineVO {
+				return DiffLineVO{
+					Op:   src.Op.String(),
+					Text: src.Text,
+				}
+			}),
+		},
+	})
+}
+
+func (h *ArticleHandler) RestoreRevision(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Msg:  "id 参数错误",
+			Code: 4,
+		})
+		return
+	}
+	version, err := strconv.ParseInt(ctx.Param("version"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Msg:  "version 参数错误",
+			Code: 4,
+		})
+		return
+	}
+	uc := ctx.MustGet("user").(jwt.UserClaims)
+	err = h.svc.RestoreRevision(ctx, id, uc.Uid, version)
+	if err != nil {
+		h.revisionError(ctx, err, "恢复文章历史版本失败", id, uc.Uid)
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Msg: "OK",
+	})
+}
+
+func (h *ArticleHandler) revisionError(ctx *gin.Context, err error, msg string, id int64, uid int64) {
+	switch err {
+	case service.ErrArticlePermissionDenied:
+		// 有人在搞鬼
+		h.log.Error("非法访问文章历史版本",
+			logger.Int64("id", id),
+			logger.Int64("uid", uid))
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "没有权限",
+		})
+	case diffx.ErrTooManyLines:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "文章太长，无法比较",
+		})
+	default:
+		h.log.Error(msg,
+			logger.Int64("id", id),
+			logger.Int64("uid", uid),
+			logger.Error(err))
+		ctx.JSON(http.StatusOK, Result{
+			Msg:  "系统错误",
+			Code: 5,
+		})
+	}
+}
+
+func (h *ArticleHandler) toRevisionVO(rev domain.ArticleRevision) ArticleRevisionVO {
+	return ArticleRevisionVO{
+		Version:   rev.Version,
+		ArticleId: rev.ArticleId,
+		Title:     rev.Title,
+		Content:   rev.Content,
+		Status:    rev.Status.ToUint8(),
+		AuthorId:  rev.Author.Id,
+		Ctime:     rev.Ctime.Format(time.DateTime),
+	}
+}
@@ -0,0 +1,64 @@
+package web
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/gotomicro/ekit/slice"
+	"net/http"
+	"strconv"
+	"time"
+	intrv1 "webook/api/proto/gen/intr/v1"
+	"webook/internal/web/jwt"
+)
+
+// Stats 作者和合作者查看文章最近几天的 UV 和 PV
+func (h *ArticleHandler) Stats(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Msg:  "id 参数错误",
+			Code: 4,
+		})
+		return
+	}
+	type Req struct {
+		// Days 包含今天，默认 7 天
+		Days int `form:"days"`
+	}
+	req := Req{Days: 7}
+	if err = ctx.BindQuery(&req); err != nil {
+		return
+	}
+	if req.Days <= 0 || req.Days > 90 {
+		ctx.JSON(http.StatusOK, Result{
+			Msg:  "最多查询 90 天",
+			Code: 4,
+		})
+		return
+	}
+	uc := ctx.MustGet("user").(jwt.UserClaims)
+	_, err = h.svc.GetByIdForUser(ctx, id, uc.Uid)
+	if err != nil {
+		h.collaboratorError(ctx, err, "查找文章失败", id, uc.Uid)
+		return
+	}
+	now := time.Now()
+	resp, err := h.intrSvc.GetDailyStats(ctx, &intrv1.GetDailyStatsRequest{
+		Biz:   h.biz,
+		BizId: id,
+		Start: now.AddDate(0, 0, 1-req.Days).UnixMilli(),
+		End:   now.UnixMilli(),
+	})
+	if err != nil {
+		h.collaboratorError(ctx, err, "查找文章访问量失败", id, uc.Uid)
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Data: slice.Map(resp.GetStats(), func(idx int, src *intrv1.DailyStat) DailyStatVO {
+			return DailyStatVO{
+				Day: time.UnixMilli(src.GetDay()).Format(time.DateOnly),
+				UV:  src.GetUv(),
+				PV:  src.GetPv(),
+			}
+		}),
+	})
+}
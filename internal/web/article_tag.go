@@ -0,0 +1,77 @@
+package web
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/gotomicro/ekit/slice"
+	"net/http"
+	"time"
+	"webook/internal/domain"
+	"webook/pkg/logger"
+)
+
+func (h *ArticleHandler) ListTags(ctx *gin.Context) {
+	var page Page
+	if err := ctx.BindQuery(&page); err != nil {
+		return
+	}
+	if page.Limit <= 0 || page.Limit > 100 {
+		page.Limit = 100
+	}
+	tags, err := h.svc.ListTags(ctx, page.Offset, page.Limit)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.log.Error("查找标签列表失败",
+			logger.Int("offset", page.Offset),
+			logger.Int("limit", page.Limit),
+			logger.Error(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Data: slice.Map(tags, func(idx int, src domain.Tag) TagVO {
+			return TagVO{
+				Id:   src.Id,
+				Name: src.Name,
+			}
+		}),
+	})
+}
+
+func (h *ArticleHandler) ListPubByTag(ctx *gin.Context) {
+	var page Page
+	if err := ctx.BindQuery(&page); err != nil {
+		return
+	}
+	if page.Limit <= 0 || page.Limit > 100 {
+		page.Limit = 100
+	}
+	tag := ctx.Param("name")
+	arts, err := h.svc.ListPubByTag(ctx, tag, page.Offset, page.Limit)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.log.Error("按照标签查找文章失败",
+			logger.String("tag", tag),
+			logger.Int("offset", page.Offset),
+			logger.Int("limit", page.Limit),
+			logger.Error(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Data: slice.Map(arts, func(idx int, src domain.Article) ArticleVO {
+			return ArticleVO{
+				Id:       src.Id,
+				Title:    src.Title,
+				Abstract: src.Abstract(),
+				AuthorId: src.Author.Id,
+				Status:   src.Status.ToUint8(),
+				Ctime:    src.Ctime.Format(time.DateTime),
+				Utime:    src.Utime.Format(time.DateTime),
+			}
+		}),
+	})
+}
@@ -0,0 +1,100 @@
+package web
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/gotomicro/ekit/slice"
+	"net/http"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/service"
+	"webook/internal/web/jwt"
+	"webook/pkg/logger"
+)
+
+func (h *ArticleHandler) Delete(ctx *gin.Context) {
+	type Req struct {
+		Id int64 `json:"id"`
+	}
+	req := Req{}
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	uc := ctx.MustGet("user").(jwt.UserClaims)
+	err := h.svc.Delete(ctx, req.Id, uc.Uid)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.log.Error("删除文章失败", logger.Int64("uid", uc.Uid), logger.Int64("id", req.Id), logger.Error(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Msg: "OK",
+	})
+}
+
+func (h *ArticleHandler) RestoreTrash(ctx *gin.Context) {
+	type Req struct {
+		Id int64 `json:"id"`
+	}
+	req := Req{}
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	uc := ctx.MustGet("user").(jwt.UserClaims)
+	err := h.svc.Restore(ctx, req.Id, uc.Uid)
+	switch err {
+	case nil:
+		ctx.JSON(http.StatusOK, Result{
+			Msg: "OK",
+		})
+	case service.ErrArticleNotInTrash:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "文章不在回收站或者已经超过恢复期限",
+		})
+	default:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.log.Error("恢复文章失败", logger.Int64("uid", uc.Uid), logger.Int64("id", req.Id), logger.Error(err))
+	}
+}
+
+func (h *ArticleHandler) ListTrash(ctx *gin.Context) {
+	var req Page
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	uc := ctx.MustGet("user").(jwt.UserClaims)
+	arts, err := h.svc.ListTrash(ctx, uc.Uid, req.Offset, req.Limit)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.log.Error("查找回收站失败",
+			logger.Error(err),
+			logger.Int("offset", req.Offset),
+			logger.Int("limit", req.Limit),
+			logger.Int64("uid", uc.Uid))
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Data: slice.Map[domain.Article, ArticleVO](arts, func(idx int, src domain.Article) ArticleVO {
+			return ArticleVO{
+				Id:        src.Id,
+				Title:     src.Title,
+				Abstract:  src.Abstract(),
+				AuthorId:  src.Author.Id,
+				Status:    src.Status.ToUint8(),
+				Ctime:     src.Ctime.Format(time.DateTime),
+				Utime:     src.Utime.Format(time.DateTime),
+				DeletedAt: src.DeletedAt.Format(time.DateTime),
+				PurgeAt:   src.DeletedAt.Add(service.ArticleTrashRetention).Format(time.DateTime),
+			}
+		}),
+	})
+}
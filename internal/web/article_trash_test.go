@@ -0,0 +1,96 @@
+package web
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"webook/internal/service"
+	svcmocks "webook/internal/service/mocks"
+	ijwt "webook/internal/web/jwt"
+	"webook/pkg/logger"
+)
+
+func TestArticleHandler_RestoreTrash(t *testing.T) {
+	testCases := []struct {
+		name    string
+		mock    func(ctrl *gomock.Controller) service.ArticleService
+		reqBody string
+		wantRes Result
+	}{
+		{
+			name: "恢复成功",
+			mock: func(ctrl *gomock.Controller) service.ArticleService {
+				svc := svcmocks.NewMockArticleService(ctrl)
+				svc.EXPECT().Restore(gomock.Any(), int64(2), int64(1)).Return(nil)
+				return svc
+			},
+			reqBody: `{"id":2}`,
+			wantRes: Result{
+				Msg: "OK",
+			},
+		},
+		{
+			name: "不在回收站或者已经过期",
+			mock: func(ctrl *gomock.Controller) service.ArticleService {
+				svc := svcmocks.NewMockArticleService(ctrl)
+				svc.EXPECT().Restore(gomock.Any(), int64(2), int64(1)).
+					Return(service.ErrArticleNotInTrash)
+				return svc
+			},
+			reqBody: `{"id":2}`,
+			wantRes: Result{
+				Code: 4,
+				Msg:  "文章不在回收站或者已经超过恢复期限",
+			},
+		},
+		{
+			name: "系统错误",
+			mock: func(ctrl *gomock.Controller) service.ArticleService {
+				svc := svcmocks.NewMockArticleService(ctrl)
+				svc.EXPECT().Restore(gomock.Any(), int64(2), int64(1)).
+					Return(errors.New("mock db error"))
+				return svc
+			},
+			reqBody: `{"id":2}`,
+			wantRes: Result{
+				Code: 5,
+				Msg:  "系统错误",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost,
+				"/articles/trash/restore",
+				bytes.NewBufferString(tc.reqBody))
+			req.Header.Set("Content-Type", "application/json")
+			assert.NoError(t, err)
+			recorder := httptest.NewRecorder()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			hdl := NewArticleHandler(tc.mock(ctrl), nil, nil, logger.NewNopLogger())
+			server := gin.Default()
+			server.Use(func(ctx *gin.Context) {
+				ctx.Set("user", ijwt.UserClaims{
+					Uid: 1,
+				})
+			})
+			hdl.RegisterRoutes(server)
+
+			server.ServeHTTP(recorder, req)
+
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			var res Result
+			err = json.NewDecoder(recorder.Body).Decode(&res)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantRes, res)
+		})
+	}
+}
@@ -1,19 +1,101 @@
 package web
 
 type ArticleVO struct {
-	Id         int64  `json:"id,omitempty"`
-	Title      string `json:"title,omitempty"`
-	Content    string `json:"content,omitempty"`
-	Status     uint8  `json:"status,omitempty"`
-	AuthorId   int64  `json:"authorId,omitempty"`
-	AuthorName string `json:"authorName,omitempty"`
-	Abstract   string `json:"abstract,omitempty"`
-	Ctime      string `json:"ctime,omitempty"`
-	Utime      string `json:"utime,omitempty"`
+	Id      int64  `json:"id,omitempty"`
+	Title   string `json:"title,omitempty"`
+	Content string `json:"content,omitempty"`
+	// Html 发表的时候渲染好的 HTML，已经清洗过了，可以直接展示
+	Html       string   `json:"html,omitempty"`
+	Status     uint8    `json:"status,omitempty"`
+	AuthorId   int64    `json:"authorId,omitempty"`
+	AuthorName string   `json:"authorName,omitempty"`
+	Abstract   string   `json:"abstract,omitempty"`
+	Tags       []string `json:"tags,omitempty"`
+	PublishAt  string   `json:"publishAt,omitempty"`
+	Ctime      string   `json:"ctime,omitempty"`
+	Utime      string   `json:"utime,omitempty"`
+	// DeletedAt 和 PurgeAt 只有回收站里面的文章才有
+	DeletedAt string `json:"deletedAt,omitempty"`
+	PurgeAt   string `json:"purgeAt,omitempty"`
+	// Series 文章所在的系列和上一篇、下一篇，只有详情页有
+	Series *SeriesNavVO `json:"series,omitempty"`
+	// Authors 作者和合作者，只有详情页有
+	Authors []AuthorVO `json:"authors,omitempty"`
 
 	ReadCnt    int64 `json:"readCnt"`
 	LikeCnt    int64 `json:"likeCnt"`
 	CollectCnt int64 `json:"collectCnt"`
+	CommentCnt int64 `json:"commentCnt"`
 	Liked      bool  `json:"liked"`
 	Collected  bool  `json:"collected"`
 }
+
+type AuthorVO struct {
+	Id   int64  `json:"id"`
+	Name string `json:"name,omitempty"`
+	// Role owner、editor 或者 viewer
+	Role string `json:"role"`
+}
+
+type ArticleAuditVO struct {
+	Id     int64  `json:"id"`
+	Uid    int64  `json:"uid"`
+	Action string `json:"action"`
+	Detail string `json:"detail,omitempty"`
+	Ctime  string `json:"ctime"`
+}
+
+type ArticleListVO struct {
+	List []ArticleVO `json:"list"`
+	// NextCursor 为空说明没有下一页了
+	NextCursor string `json:"next_cursor"`
+}
+
+type TagVO struct {
+	Id   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+type ArticleRevisionVO struct {
+	Version   int64  `json:"version"`
+	ArticleId int64  `json:"articleId"`
+	Title     string `json:"title,omitempty"`
+	Content   string `json:"content,omitempty"`
+	Abstract  string `json:"abstract,omitempty"`
+	Status    uint8  `json:"status,omitempty"`
+	AuthorId  int64  `json:"authorId,omitempty"`
+	Ctime     string `json:"ctime,omitempty"`
+}
+
+type RevisionDiffVO struct {
+	From  int64        `json:"from"`
+	To    int64        `json:"to"`
+	Lines []DiffLineVO `json:"lines"`
+}
+
+type DiffLineVO struct {
+	// Op 取值 " "、"+"、"-"
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+type ReviewVO struct {
+	Id        int64    `json:"id"`
+	ArticleId int64    `json:"article_id"`
+	Title     string   `json:"title"`
+	Author    int64    `json:"author"`
+	Submitter int64    `json:"submitter"`
+	Hits      []string `json:"hits"`
+	// Status pending, approved, rejected 或者 cancelled
+	Status   string `json:"status"`
+	Reviewer int64  `json:"reviewer,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+	Ctime    string `json:"ctime"`
+	Utime    string `json:"utime"`
+}
+
+type DailyStatVO struct {
+	Day string `json:"day"`
+	UV  int64  `json:"uv"`
+	PV  int64  `json:"pv"`
+}
@@ -0,0 +1,161 @@
+package web
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"webook/internal/domain"
+	"webook/internal/service"
+	"webook/internal/web/jwt"
+	"webook/pkg/logger"
+)
+
+var blobHashRegexp = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+type BlobHandler struct {
+	svc service.BlobService
+	// maxSize 和 service 里面的一致，这里用来限制读取请求的大小
+	maxSize int64
+	l       logger.LoggerV1
+}
+
+func NewBlobHandler(svc service.BlobService, maxSize int64, l logger.LoggerV1) *BlobHandler {
+	return &BlobHandler{
+		svc:     svc,
+		maxSize: maxSize,
+		l:       l,
+	}
+}
+
+func (h *BlobHandler) RegisterRoutes(server *gin.Engine) {
+	server.POST("/uploads", h.Upload)
+	// 不需要登录，文章里面直接引用
+	server.GET("/blobs/:hash", h.Get)
+}
+
+func (h *BlobHandler) Upload(ctx *gin.Context) {
+	// multipart 本身还有一些开销，多留一点
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, h.maxSize+1<<20)
+	fh, err := ctx.FormFile("file")
+	if err != nil {
+		var mbe *http.MaxBytesError
+		if errors.As(err, &mbe) {
+			ctx.JSON(http.StatusOK, Result{
+				Code: 4,
+				Msg:  "文件太大",
+			})
+			return
+		}
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "没有上传文件",
+		})
+		return
+	}
+	if fh.Size > h.maxSize {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "文件太大",
+		})
+		return
+	}
+	f, err := fh.Open()
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.l.Error("打开上传的文件失败", logger.Error(err))
+		return
+	}
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.l.Error("读取上传的文件失败", logger.Error(err))
+		return
+	}
+	uc := ctx.MustGet("user").(jwt.UserClaims)
+	b, err := h.svc.Upload(ctx, uc.Uid, data)
+	switch err {
+	case nil:
+		ctx.JSON(http.StatusOK, Result{
+			Data: BlobVO{
+				Hash: b.Hash,
+				Path: b.Path(),
+				Size: b.Size,
+				Mime: b.MimeType,
+			},
+		})
+	case service.ErrBlobTooLarge:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "文件太大",
+		})
+	case service.ErrBlobTypeNotAllowed:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "只支持图片、PDF 和 zip 文件",
+		})
+	default:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.l.Error("上传文件失败", logger.Int64("uid", uc.Uid), logger.Error(err))
+	}
+}
+
+func (h *BlobHandler) Get(ctx *gin.Context) {
+	hash := ctx.Param("hash")
+	if !blobHashRegexp.MatchString(hash) {
+		ctx.Status(http.StatusNotFound)
+		return
+	}
+	etag := `"` + hash + `"`
+	// 内容由 hash 决定，不会变，不需要查询
+	if match := ctx.GetHeader("If-None-Match"); match != "" && strings.Contains(match, etag) {
+		ctx.Header("ETag", etag)
+		ctx.Status(http.StatusNotModified)
+		return
+	}
+	b, rc, err := h.svc.Open(ctx, hash)
+	if err == service.ErrBlobNotFound {
+		ctx.Status(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		h.l.Error("读取文件失败", logger.String("hash", hash), logger.Error(err))
+		return
+	}
+	defer rc.Close()
+	ctx.Header("ETag", etag)
+	ctx.Header("Cache-Control", "public, max-age=31536000, immutable")
+	ctx.Header("X-Content-Type-Options", "nosniff")
+	if !isImage(b) {
+		ctx.Header("Content-Disposition", `attachment; filename="`+hash+`"`)
+	}
+	ctx.DataFromReader(http.StatusOK, b.Size, b.MimeType, rc, map[string]string{
+		"Content-Length": strconv.FormatInt(b.Size, 10),
+	})
+}
+
+func isImage(b domain.Blob) bool {
+	return strings.HasPrefix(b.MimeType, "image/")
+}
+
+type BlobVO struct {
+	Hash string `json:"hash"`
+	// Path 在文章里面引用的路径
+	Path string `json:"path"`
+	Size int64  `json:"size"`
+	Mime string `json:"mime"`
+}
@@ -0,0 +1,127 @@
+package web
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"webook/internal/domain"
+	"webook/internal/service"
+	svcmocks "webook/internal/service/mocks"
+	"webook/pkg/logger"
+)
+
+func TestBlobHandler_Get(t *testing.T) {
+	const hash = "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+	testCases := []struct {
+		name        string
+		mock        func(ctrl *gomock.Controller) service.BlobService
+		hash        string
+		ifNoneMatch string
+
+		wantCode    int
+		wantHeaders map[string]string
+		wantBody    string
+	}{
+		{
+			name: "图片",
+			mock: func(ctrl *gomock.Controller) service.BlobService {
+				svc := svcmocks.NewMockBlobService(ctrl)
+				svc.EXPECT().Open(gomock.Any(), hash).Return(domain.Blob{
+					Hash:     hash,
+					Size:     4,
+					MimeType: "image/png",
+				}, io.NopCloser(strings.NewReader("test")), nil)
+				return svc
+			},
+			hash:     hash,
+			wantCode: http.StatusOK,
+			wantHeaders: map[string]string{
+				"Content-Type":           "image/png",
+				"ETag":                   `"` + hash + `"`,
+				"Cache-Control":          "public, max-age=31536000, immutable",
+				"X-Content-Type-Options": "nosniff",
+				"Content-Disposition":    "",
+			},
+			wantBody: "test",
+		},
+		{
+			name: "附件",
+			mock: func(ctrl *gomock.Controller) service.BlobService {
+				svc := svcmocks.NewMockBlobService(ctrl)
+				svc.EXPECT().Open(gomock.Any(), hash).Return(domain.Blob{
+					Hash:     hash,
+					Size:     4,
+					MimeType: "application/pdf",
+				}, io.NopCloser(strings.NewReader("test")), nil)
+				return svc
+			},
+			hash:     hash,
+			wantCode: http.StatusOK,
+			wantHeaders: map[string]string{
+				"Content-Type":        "application/pdf",
+				"Content-Disposition": `attachment; filename="` + hash + `"`,
+			},
+			wantBody: "test",
+		},
+		{
+			name: "没有修改",
+			mock: func(ctrl *gomock.Controller) service.BlobService {
+				return svcmocks.NewMockBlobService(ctrl)
+			},
+			hash:        hash,
+			ifNoneMatch: `"` + hash + `"`,
+			wantCode:    http.StatusNotModified,
+			wantHeaders: map[string]string{
+				"ETag": `"` + hash + `"`,
+			},
+		},
+		{
+			name: "hash 格式不对",
+			mock: func(ctrl *gomock.Controller) service.BlobService {
+				return svcmocks.NewMockBlobService(ctrl)
+			},
+			hash:     "ABC",
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name: "不存在",
+			mock: func(ctrl *gomock.Controller) service.BlobService {
+				svc := svcmocks.NewMockBlobService(ctrl)
+				svc.EXPECT().Open(gomock.Any(), hash).
+					Return(domain.Blob{}, nil, service.ErrBlobNotFound)
+				return svc
+			},
+			hash:     hash,
+			wantCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, "/blobs/"+tc.hash, nil)
+			assert.NoError(t, err)
+			if tc.ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", tc.ifNoneMatch)
+			}
+			recorder := httptest.NewRecorder()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			hdl := NewBlobHandler(tc.mock(ctrl), 1<<20, logger.NewNopLogger())
+			server := gin.Default()
+			hdl.RegisterRoutes(server)
+
+			server.ServeHTTP(recorder, req)
+
+			assert.Equal(t, tc.wantCode, recorder.Code)
+			for k, v := range tc.wantHeaders {
+				assert.Equal(t, v, recorder.Header().Get(k), k)
+			}
+			assert.Equal(t, tc.wantBody, recorder.Body.String())
+		})
+	}
+}
@@ -0,0 +1,261 @@
+package web
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/gotomicro/ekit/slice"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"net/http"
+	"time"
+	commentv1 "webook/api/proto/gen/comment/v1"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	"webook/internal/service"
+	"webook/internal/web/jwt"
+	"webook/pkg/logger"
+)
+
+type CommentHandler struct {
+	svc    commentv1.CommentServiceClient
+	artSvc service.ArticleService
+	l      logger.LoggerV1
+	biz    string
+}
+
+func NewCommentHandler(svc commentv1.CommentServiceClient, artSvc service.ArticleService, l logger.LoggerV1) *CommentHandler {
+	return &CommentHandler{
+		svc:    svc,
+		artSvc: artSvc,
+		l:      l,
+		biz:    "article",
+	}
+}
+
+func (h *CommentHandler) RegisterRoutes(server *gin.Engine) {
+	g := server.Group("/comments")
+	g.POST("/create", h.Create)
+	g.POST("/delete", h.Delete)
+	// 不需要登录
+	g.GET("/list", h.List)
+	g.GET("/replies", h.Replies)
+}
+
+func (h *CommentHandler) Create(ctx *gin.Context) {
+	type Req struct {
+		ArticleId int64 `json:"article_id"`
+		// ParentId 回复哪一条评论，不传表示直接评论文章
+		ParentId int64  `json:"parent_id"`
+		Content  string `json:"content"`
+	}
+	var req Req
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	uc := ctx.MustGet("user").(jwt.UserClaims)
+	// 看线上库，发表之后作者又在修改的文章草稿不是已发表状态，但是还可以评论
+	art, err := h.artSvc.GetPubById(ctx, uc.Uid, req.ArticleId, deviceId(ctx))
+	if err == repository.ErrArticleNotFound ||
+		(err == nil && (art.Status != domain.ArticleStatusPublished || !art.DeletedAt.IsZero())) {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "文章不存在",
+		})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.l.Error("查询文章失败", logger.Int64("aid", req.ArticleId), logger.Error(err))
+		return
+	}
+	res, err := h.svc.CreateComment(ctx, &commentv1.CreateCommentRequest{
+		Biz:      h.biz,
+		BizId:    req.ArticleId,
+		Uid:      uc.Uid,
+		ParentId: req.ParentId,
+		Content:  req.Content,
+	})
+	if err != nil {
+		h.handleError(ctx, "发表评论失败", uc.Uid, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Data: h.toVO(res.GetComment()),
+	})
+}
+
+// Delete 评论的人删除自己的评论，文章作者可以删除文章下面的任何评论
+func (h *CommentHandler) Delete(ctx *gin.Context) {
+	type Req struct {
+		Id        int64 `json:"id"`
+		ArticleId int64 `json:"article_id"`
+	}
+	var req Req
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	uc := ctx.MustGet("user").(jwt.UserClaims)
+	art, err := h.artSvc.GetById(ctx, req.ArticleId)
+	if err != nil && err != repository.ErrArticleNotFound {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.l.Error("查询文章失败", logger.Int64("aid", req.ArticleId), logger.Error(err))
+		return
+	}
+	bizOwner := err == nil && art.Author.Id == uc.Uid
+	_, err = h.svc.DeleteComment(ctx, &commentv1.DeleteCommentRequest{
+		Id:       req.Id,
+		Uid:      uc.Uid,
+		Biz:      h.biz,
+		BizId:    req.ArticleId,
+		BizOwner: bizOwner,
+	})
+	if err != nil {
+		h.handleError(ctx, "删除评论失败", uc.Uid, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Msg: "OK",
+	})
+}
+
+func (h *CommentHandler) List(ctx *gin.Context) {
+	type Req struct {
+		ArticleId int64 `form:"article_id"`
+		// MaxId 上一页最后一条根评论的 ID，第一页不传
+		MaxId int64 `form:"max_id"`
+		Limit int64 `form:"limit"`
+	}
+	var req Req
+	if err := ctx.BindQuery(&req); err != nil {
+		return
+	}
+	if req.Limit <= 0 || req.Limit > 50 || req.MaxId < 0 {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "分页参数错误",
+		})
+		return
+	}
+	res, err := h.svc.GetCommentList(ctx, &commentv1.CommentListRequest{
+		Biz:   h.biz,
+		BizId: req.ArticleId,
+		MaxId: req.MaxId,
+		Limit: req.Limit,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.l.Error("查询评论失败", logger.Int64("aid", req.ArticleId), logger.Error(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Data: h.toVOs(res.GetComments()),
+	})
+}
+
+func (h *CommentHandler) Replies(ctx *gin.Context) {
+	type Req struct {
+		RootId int64 `form:"root_id"`
+		// MinId 上一页最后一条回复的 ID，第一页不传
+		MinId int64 `form:"min_id"`
+		Limit int64 `form:"limit"`
+	}
+	var req Req
+	if err := ctx.BindQuery(&req); err != nil {
+		return
+	}
+	if req.Limit <= 0 || req.Limit > 50 || req.MinId < 0 {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "分页参数错误",
+		})
+		return
+	}
+	res, err := h.svc.GetMoreReplies(ctx, &commentv1.GetMoreRepliesRequest{
+		RootId: req.RootId,
+		MinId:  req.MinId,
+		Limit:  req.Limit,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.l.Error("查询回复失败", logger.Int64("rootId", req.RootId), logger.Error(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Data: h.toVOs(res.GetReplies()),
+	})
+}
+
+// handleError 评论服务用 grpc 的错误码区分业务错误
+func (h *CommentHandler) handleError(ctx *gin.Context, msg string, uid int64, err error) {
+	switch status.Code(err) {
+	case codes.InvalidArgument:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "评论内容不能为空，最多 1000 个字",
+		})
+	case codes.NotFound:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "评论不存在",
+		})
+	case codes.PermissionDenied:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "没有权限",
+		})
+	default:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.l.Error(msg, logger.Int64("uid", uid), logger.Error(err))
+	}
+}
+
+func (h *CommentHandler) toVOs(cs []*commentv1.Comment) []CommentVO {
+	return slice.Map(cs, func(idx int, src *commentv1.Comment) CommentVO {
+		return h.toVO(src)
+	})
+}
+
+func (h *CommentHandler) toVO(c *commentv1.Comment) CommentVO {
+	return CommentVO{
+		Id:         c.GetId(),
+		Uid:        c.GetUid(),
+		ArticleId:  c.GetBizId(),
+		Content:    c.GetContent(),
+		RootId:     c.GetRootId(),
+		ParentId:   c.GetParentId(),
+		ReplyToUid: c.GetReplyToUid(),
+		Deleted:    c.GetStatus() != 0,
+		ReplyCnt:   c.GetReplyCnt(),
+		Replies:    h.toVOs(c.GetReplies()),
+		Ctime:      time.UnixMilli(c.GetCtime()).Format(time.DateTime),
+	}
+}
+
+type CommentVO struct {
+	Id         int64  `json:"id"`
+	Uid        int64  `json:"uid"`
+	ArticleId  int64  `json:"article_id"`
+	Content    string `json:"content"`
+	RootId     int64  `json:"root_id"`
+	ParentId   int64  `json:"parent_id"`
+	ReplyToUid int64  `json:"reply_to_uid"`
+	// Deleted 已经删除但是还有回复的评论，展示成“评论已删除”
+	Deleted  bool        `json:"deleted"`
+	ReplyCnt int64       `json:"reply_cnt"`
+	Replies  []CommentVO `json:"replies,omitempty"`
+	Ctime    string      `json:"ctime"`
+}
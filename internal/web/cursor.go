@@ -0,0 +1,47 @@
+package web
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+	"webook/internal/domain"
+)
+
+var errInvalidCursor = errors.New("cursor 格式不对")
+
+// encodeArticleCursor 把游标编码成对前端不透明的字符串，零值编码成空字符串
+func encodeArticleCursor(c domain.ArticleCursor) string {
+	if c.IsZero() {
+		return ""
+	}
+	raw := strconv.FormatInt(c.Utime.UnixMilli(), 10) + ":" + strconv.FormatInt(c.Id, 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeArticleCursor(s string) (domain.ArticleCursor, error) {
+	if s == "" {
+		return domain.ArticleCursor{}, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return domain.ArticleCursor{}, errInvalidCursor
+	}
+	utimeStr, idStr, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return domain.ArticleCursor{}, errInvalidCursor
+	}
+	utime, err := strconv.ParseInt(utimeStr, 10, 64)
+	if err != nil || utime <= 0 {
+		return domain.ArticleCursor{}, errInvalidCursor
+	}
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		return domain.ArticleCursor{}, errInvalidCursor
+	}
+	return domain.ArticleCursor{
+		Utime: time.UnixMilli(utime),
+		Id:    id,
+	}, nil
+}
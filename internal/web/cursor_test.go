@@ -0,0 +1,54 @@
+package web
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+	"webook/internal/domain"
+)
+
+func TestArticleCursor(t *testing.T) {
+	testCases := []struct {
+		name    string
+		cursor  domain.ArticleCursor
+		wantStr string
+	}{
+		{
+			name: "零值",
+		},
+		{
+			name: "正常游标",
+			cursor: domain.ArticleCursor{
+				Utime: time.UnixMilli(1700000000123),
+				Id:    42,
+			},
+			wantStr: "MTcwMDAwMDAwMDEyMzo0Mg",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			str := encodeArticleCursor(tc.cursor)
+			assert.Equal(t, tc.wantStr, str)
+			c, err := decodeArticleCursor(str)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.cursor, c)
+		})
+	}
+}
+
+func TestDecodeArticleCursor_Invalid(t *testing.T) {
+	testCases := []struct {
+		name   string
+		cursor string
+	}{
+		{name: "不是 base64", cursor: "@@@"},
+		{name: "没有分隔符", cursor: "MTIz"},
+		{name: "utime 不是数字", cursor: "YWJjOjE"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := decodeArticleCursor(tc.cursor)
+			assert.Equal(t, errInvalidCursor, err)
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package web
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/service"
+	"webook/pkg/logger"
+)
+
+// FeedHandler Atom 订阅和 sitemap，不需要登录
+type FeedHandler struct {
+	svc service.FeedService
+	l   logger.LoggerV1
+}
+
+func NewFeedHandler(svc service.FeedService, l logger.LoggerV1) *FeedHandler {
+	return &FeedHandler{
+		svc: svc,
+		l:   l,
+	}
+}
+
+const (
+	contentTypeAtom = "application/atom+xml; charset=utf-8"
+	contentTypeXML  = "application/xml; charset=utf-8"
+)
+
+func (h *FeedHandler) RegisterRoutes(server *gin.Engine) {
+	server.GET("/feeds/articles.atom", h.SiteFeed)
+	server.GET("/feeds/authors/:id/articles.atom", h.AuthorFeed)
+	server.GET("/sitemap.xml", h.Sitemap)
+}
+
+func (h *FeedHandler) SiteFeed(ctx *gin.Context) {
+	feed, err := h.svc.SiteFeed(ctx)
+	if err != nil {
+		ctx.AbortWithStatus(http.StatusInternalServerError)
+		h.l.Error("生成全站订阅失败", logger.Error(err))
+		return
+	}
+	h.write(ctx, feed, contentTypeAtom)
+}
+
+func (h *FeedHandler) AuthorFeed(ctx *gin.Context) {
+	uid, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.AbortWithStatus(http.StatusNotFound)
+		return
+	}
+	feed, err := h.svc.AuthorFeed(ctx, uid)
+	switch err {
+	case nil:
+		h.write(ctx, feed, contentTypeAtom)
+	case service.ErrFeedAuthorNotFound:
+		ctx.AbortWithStatus(http.StatusNotFound)
+	default:
+		ctx.AbortWithStatus(http.StatusInternalServerError)
+		h.l.Error("生成作者订阅失败", logger.Int64("uid", uid), logger.Error(err))
+	}
+}
+
+func (h *FeedHandler) Sitemap(ctx *gin.Context) {
+	feed, err := h.svc.Sitemap(ctx)
+	if err != nil {
+		ctx.AbortWithStatus(http.StatusInternalServerError)
+		h.l.Error("生成 sitemap 失败", logger.Error(err))
+		return
+	}
+	h.write(ctx, feed, contentTypeXML)
+}
+
+// write 支持条件请求，If-None-Match 优先于 If-Modified-Since
+func (h *FeedHandler) write(ctx *gin.Context, feed domain.Feed, contentType string) {
+	ctx.Header("ETag", feed.ETag)
+	ctx.Header("Last-Modified", feed.Mtime.UTC().Format(http.TimeFormat))
+	ctx.Header("Cache-Control", "public, max-age=300")
+	if notModified(ctx.Request, feed) {
+		ctx.Status(http.StatusNotModified)
+		return
+	}
+	ctx.Data(http.StatusOK, contentType, feed.Content)
+}
+
+func notModified(req *http.Request, feed domain.Feed) bool {
+	if inm := req.Header.Get("If-None-Match"); inm != "" {
+		for _, tag := range strings.Split(inm, ",") {
+			tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+			if tag == "*" || tag == feed.ETag {
+				return true
+			}
+		}
+		return false
+	}
+	ims, err := time.Parse(http.TimeFormat, req.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+	return !feed.Mtime.After(ims)
+}
@@ -0,0 +1,117 @@
+package web
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/service"
+	svcmocks "webook/internal/service/mocks"
+	"webook/pkg/logger"
+)
+
+func TestFeedHandler_AuthorFeed(t *testing.T) {
+	mtime := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+	feed := domain.Feed{
+		Content: []byte("<feed/>"),
+		ETag:    `"abc"`,
+		Mtime:   mtime,
+	}
+	testCases := []struct {
+		name     string
+		mock     func(ctrl *gomock.Controller) service.FeedService
+		header   map[string]string
+		wantCode int
+		wantBody string
+	}{
+		{
+			name: "第一次请求",
+			mock: func(ctrl *gomock.Controller) service.FeedService {
+				svc := svcmocks.NewMockFeedService(ctrl)
+				svc.EXPECT().AuthorFeed(gomock.Any(), int64(2)).Return(feed, nil)
+				return svc
+			},
+			wantCode: http.StatusOK,
+			wantBody: "<feed/>",
+		},
+		{
+			name: "ETag 没变",
+			mock: func(ctrl *gomock.Controller) service.FeedService {
+				svc := svcmocks.NewMockFeedService(ctrl)
+				svc.EXPECT().AuthorFeed(gomock.Any(), int64(2)).Return(feed, nil)
+				return svc
+			},
+			header:   map[string]string{"If-None-Match": `"xyz", "abc"`},
+			wantCode: http.StatusNotModified,
+		},
+		{
+			name: "ETag 变了，忽略 If-Modified-Since",
+			mock: func(ctrl *gomock.Controller) service.FeedService {
+				svc := svcmocks.NewMockFeedService(ctrl)
+				svc.EXPECT().AuthorFeed(gomock.Any(), int64(2)).Return(feed, nil)
+				return svc
+			},
+			header: map[string]string{
+				"If-None-Match":     `"xyz"`,
+				"If-Modified-Since": mtime.Format(http.TimeFormat),
+			},
+			wantCode: http.StatusOK,
+			wantBody: "<feed/>",
+		},
+		{
+			name: "没有修改过",
+			mock: func(ctrl *gomock.Controller) service.FeedService {
+				svc := svcmocks.NewMockFeedService(ctrl)
+				svc.EXPECT().AuthorFeed(gomock.Any(), int64(2)).Return(feed, nil)
+				return svc
+			},
+			header:   map[string]string{"If-Modified-Since": mtime.Format(http.TimeFormat)},
+			wantCode: http.StatusNotModified,
+		},
+		{
+			name: "修改过",
+			mock: func(ctrl *gomock.Controller) service.FeedService {
+				svc := svcmocks.NewMockFeedService(ctrl)
+				svc.EXPECT().AuthorFeed(gomock.Any(), int64(2)).Return(feed, nil)
+				return svc
+			},
+			header:   map[string]string{"If-Modified-Since": mtime.Add(-time.Second).Format(http.TimeFormat)},
+			wantCode: http.StatusOK,
+			wantBody: "<feed/>",
+		},
+		{
+			name: "作者不存在",
+			mock: func(ctrl *gomock.Controller) service.FeedService {
+				svc := svcmocks.NewMockFeedService(ctrl)
+				svc.EXPECT().AuthorFeed(gomock.Any(), int64(2)).Return(domain.Feed{}, service.ErrFeedAuthorNotFound)
+				return svc
+			},
+			wantCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, "/feeds/authors/2/articles.atom", nil)
+			assert.NoError(t, err)
+			for k, v := range tc.header {
+				req.Header.Set(k, v)
+			}
+			recorder := httptest.NewRecorder()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			hdl := NewFeedHandler(tc.mock(ctrl), logger.NewNopLogger())
+			server := gin.Default()
+			hdl.RegisterRoutes(server)
+
+			server.ServeHTTP(recorder, req)
+
+			assert.Equal(t, tc.wantCode, recorder.Code)
+			assert.Equal(t, tc.wantBody, recorder.Body.String())
+		})
+	}
+}
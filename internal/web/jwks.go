@@ -0,0 +1,28 @@
+package web
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	ijwt "webook/internal/web/jwt"
+)
+
+// JWKSHandler 公开验证 access token 的公钥，其它服务不需要共享密钥就能验证 token
+type JWKSHandler struct {
+	hdl ijwt.Handler
+}
+
+func NewJWKSHandler(hdl ijwt.Handler) *JWKSHandler {
+	return &JWKSHandler{
+		hdl: hdl,
+	}
+}
+
+func (h *JWKSHandler) RegisterRoutes(server *gin.Engine) {
+	server.GET("/.well-known/jwks.json", h.JWKS)
+}
+
+func (h *JWKSHandler) JWKS(ctx *gin.Context) {
+	// 遇到不认识的 kid 验证方会重新拉取，这里缓存短一点就够了
+	ctx.Header("Cache-Control", "public, max-age=300")
+	ctx.JSON(http.StatusOK, h.hdl.JWKS())
+}
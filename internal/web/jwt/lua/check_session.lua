@@ -0,0 +1,28 @@
+-- KEYS[1] 会话，KEYS[2] 退出登录的标记，KEYS[3] 用户的会话集合，KEYS[4] 用户 token 的最早签发时间
+-- ARGV[1] 用户 ID，ARGV[2] 当前时间，ARGV[3] 更新最后活跃时间的最短间隔，
+-- ARGV[4] ssid，ARGV[5] 是否是升级之前签发的 token，ARGV[6] 会话的有效期（毫秒），
+-- ARGV[7] token 的签发时间（秒），升级之前签发的是 0
+if redis.call("EXISTS", KEYS[2]) == 1 then
+    return 0
+end
+local validAfter = redis.call("GET", KEYS[4])
+if validAfter and tonumber(ARGV[7]) < tonumber(validAfter) then
+    return 0
+end
+local vals = redis.call("HMGET", KEYS[1], "uid", "last_seen")
+if not vals[1] and ARGV[5] == "1" then
+    -- 升级之前登录的没有登记过会话，第一次用的时候补上，不然一升级所有人都被登出了
+    redis.call("HSET", KEYS[1], "uid", ARGV[1], "ctime", ARGV[2], "last_seen", ARGV[2])
+    redis.call("PEXPIRE", KEYS[1], ARGV[6])
+    redis.call("SADD", KEYS[3], ARGV[4])
+    redis.call("PEXPIRE", KEYS[3], ARGV[6])
+    return 1
+end
+if not vals[1] or vals[1] ~= ARGV[1] then
+    return 0
+end
+local now = tonumber(ARGV[2])
+if now - tonumber(vals[2]) >= tonumber(ARGV[3]) then
+    redis.call("HSET", KEYS[1], "last_seen", now)
+end
+return 1
@@ -0,0 +1,30 @@
+-- KEYS[1] 会话，KEYS[2] 退出登录的标记，KEYS[3] 用户的会话集合，KEYS[4] 用户 token 的最早签发时间
+-- ARGV[1] 用户 ID，ARGV[2] 出示的 refresh token 的 jti，ARGV[3] 新的 jti，ARGV[4] 当前时间，
+-- ARGV[5] ssid，ARGV[6] 是否是升级之前签发的 token，ARGV[7] 会话的有效期（毫秒），
+-- ARGV[8] token 的签发时间（秒），升级之前签发的是 0
+-- 返回 1 轮换成功，0 会话不存在，-1 出示的是已经被换掉的 refresh token
+if redis.call("EXISTS", KEYS[2]) == 1 then
+    return 0
+end
+local validAfter = redis.call("GET", KEYS[4])
+if validAfter and tonumber(ARGV[8]) < tonumber(validAfter) then
+    return 0
+end
+local vals = redis.call("HMGET", KEYS[1], "uid", "refresh_jti")
+if not vals[1] and ARGV[6] == "1" then
+    -- 升级之前登录的没有登记过会话，补登记
+    redis.call("HSET", KEYS[1], "uid", ARGV[1], "ctime", ARGV[4], "last_seen", ARGV[4], "refresh_jti", ARGV[3])
+    redis.call("PEXPIRE", KEYS[1], ARGV[7])
+    redis.call("SADD", KEYS[3], ARGV[5])
+    redis.call("PEXPIRE", KEYS[3], ARGV[7])
+    return 1
+end
+if not vals[1] or vals[1] ~= ARGV[1] then
+    return 0
+end
+-- 升级之前登录的会话没有记录 jti，第一次刷新直接放行
+if vals[2] and vals[2] ~= ARGV[2] then
+    return -1
+end
+redis.call("HSET", KEYS[1], "refresh_jti", ARGV[3], "last_seen", ARGV[4])
+return 1
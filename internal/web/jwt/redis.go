@@ -1,25 +1,23 @@
 package jwt
 
 import (
-	"errors"
-	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 	"strings"
 	"time"
+	"webook/pkg/jwtx"
 )
 
 type RedisJWTHandler struct {
-	client        redis.Cmdable
-	signingMethod jwt.SigningMethod
-	rcExpiration  time.Duration
+	client redis.Cmdable
+	// accessKeys 和 refreshKeys 不能是同一套密钥，不然 refresh token 可以当 access token 用
+	accessKeys   *jwtx.KeySet
+	refreshKeys  *jwtx.KeySet
+	rcExpiration time.Duration
 }
 
-var RefreshTokenKey = []byte("tD1vD9qI5bF9fX8fH5nJ6yH4FF2dD6uX")
-var JWTKey = []byte("tD1vD9qI5bF9fX8fH5nJ6yH4FF2dD6uX")
-
 type RefreshClaims struct {
 	jwt.RegisteredClaims
 	Uid  int64
@@ -33,25 +31,26 @@ type UserClaims struct {
 	Ssid      string
 }
 
-func NewRedisJWTHandler(client redis.Cmdable) Handler {
+func NewRedisJWTHandler(client redis.Cmdable, accessKeys *jwtx.KeySet, refreshKeys *jwtx.KeySet) Handler {
 	return &RedisJWTHandler{
-		signingMethod: jwt.SigningMethodHS512,
-		client:        client,
-		rcExpiration:  time.Hour * 24 * 7,
+		client:       client,
+		accessKeys:   accessKeys,
+		refreshKeys:  refreshKeys,
+		rcExpiration: time.Hour * 24 * 7,
 	}
 }
 
 func (h *RedisJWTHandler) SetJWTToken(ctx *gin.Context, uid int64, ssid string) error {
 	uc := UserClaims{
 		RegisteredClaims: jwt.RegisteredClaims{
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute * 30)),
 		},
 		Uid:       uid,
 		UserAgent: ctx.GetHeader("User-Agent"),
 		Ssid:      ssid,
 	}
-	token := jwt.NewWithClaims(h.signingMethod, uc)
-	tokenStr, err := token.SignedString(JWTKey)
+	tokenStr, err := h.accessKeys.Sign(uc)
 	if err != nil {
 		return err
 	}
@@ -59,16 +58,18 @@ func (h *RedisJWTHandler) SetJWTToken(ctx *gin.Context, uid int64, ssid string)
 	return nil
 }
 
-func (h *RedisJWTHandler) SetRefreshToken(ctx *gin.Context, uid int64, ssid string) error {
+// SetRefreshToken jti 用来识别同一个会话里面是不是最新的 refresh token
+func (h *RedisJWTHandler) SetRefreshToken(ctx *gin.Context, uid int64, ssid string, jti string, expiresAt time.Time) error {
 	rc := RefreshClaims{
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(h.rcExpiration)),
+			ID:        jti,
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
 		},
 		Uid:  uid,
 		Ssid: ssid,
 	}
-	token := jwt.NewWithClaims(h.signingMethod, rc)
-	tokenStr, err := token.SignedString(RefreshTokenKey)
+	tokenStr, err := h.refreshKeys.Sign(rc)
 	if err != nil {
 		return err
 	}
@@ -76,6 +77,22 @@ func (h *RedisJWTHandler) SetRefreshToken(ctx *gin.Context, uid int64, ssid stri
 	return nil
 }
 
+func (h *RedisJWTHandler) ParseAccessToken(tokenStr string) (UserClaims, error) {
+	var uc UserClaims
+	err := h.accessKeys.Parse(tokenStr, &uc)
+	return uc, err
+}
+
+func (h *RedisJWTHandler) ParseRefreshToken(tokenStr string) (RefreshClaims, error) {
+	var rc RefreshClaims
+	err := h.refreshKeys.Parse(tokenStr, &rc)
+	return rc, err
+}
+
+func (h *RedisJWTHandler) JWKS() jwtx.JWKS {
+	return h.accessKeys.JWKS()
+}
+
 // ExtractToken 根据约定token在Authorization头部
 func (h *RedisJWTHandler) ExtractToken(ctx *gin.Context) string {
 	authCode := ctx.GetHeader("Authorization")
@@ -90,29 +107,23 @@ func (h *RedisJWTHandler) ExtractToken(ctx *gin.Context) string {
 	return authSegs[1]
 }
 
-func (h *RedisJWTHandler) SetLoginToken(ctx *gin.Context, uid int64) error {
+func (h *RedisJWTHandler) SetLoginToken(ctx *gin.Context, uid int64, method string) error {
 	ssid := uuid.New().String()
-	err := h.SetRefreshToken(ctx, uid, ssid)
+	jti := uuid.New().String()
+	err := h.addSession(ctx, uid, ssid, method, jti)
 	if err != nil {
 		return err
 	}
-	return h.SetJWTToken(ctx, uid, ssid)
-}
-
-func (h *RedisJWTHandler) CheckSession(ctx *gin.Context, ssid string) error {
-	cnt, err := h.client.Exists(ctx, fmt.Sprintf("users:ssid:%s", ssid)).Result()
+	err = h.SetRefreshToken(ctx, uid, ssid, jti, time.Now().Add(h.rcExpiration))
 	if err != nil {
 		return err
 	}
-	if cnt > 0 {
-		return errors.New("用户已退出")
-	}
-	return nil
+	return h.SetJWTToken(ctx, uid, ssid)
 }
 
 func (h *RedisJWTHandler) ClearToken(ctx *gin.Context) error {
 	ctx.Header("x-refresh-token", "")
 	ctx.Header("x-jwt-token", "")
 	uc := ctx.MustGet("user").(UserClaims)
-	return h.client.Set(ctx, fmt.Sprintf("users:ssid:%s", uc.Ssid), "", h.rcExpiration).Err()
+	return h.revoke(ctx, uc.Uid, []string{uc.Ssid})
 }
@@ -0,0 +1,260 @@
+package jwt
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"sort"
+	"strconv"
+	"time"
+)
+
+//go:embed lua/check_session.lua
+var luaCheckSession string
+
+//go:embed lua/rotate_refresh.lua
+var luaRotateRefresh string
+
+var (
+	ErrSessionNotFound = errors.New("会话不存在")
+	// ErrRefreshTokenReused 已经轮换掉的 refresh token 又被拿来用了，整个会话已经注销
+	ErrRefreshTokenReused = errors.New("refresh token 被重复使用")
+	errSessionInvalid     = errors.New("用户已退出")
+)
+
+// 登录方式
+const (
+	LoginMethodPassword = "password"
+	// LoginMethodPasswordMFA 密码加两步验证
+	LoginMethodPasswordMFA = "password_mfa"
+	LoginMethodSMS         = "sms"
+	LoginMethodWechat      = "wechat"
+)
+
+// sessionTouchInterval 每次请求都更新最后活跃时间太浪费了
+const sessionTouchInterval = time.Minute
+
+// Session 一次登录，也就是一个 ssid
+type Session struct {
+	Ssid      string
+	Uid       int64
+	UserAgent string
+	IP        string
+	// Method 登录方式，LoginMethodPassword 之类的
+	Method   string
+	Ctime    time.Time
+	LastSeen time.Time
+}
+
+// addSession 会话和 refresh token 同时过期。
+// 用户的会话集合每次登录都续期，所以里面可能有已经过期的 ssid，列出来的时候顺便清理
+func (h *RedisJWTHandler) addSession(ctx *gin.Context, uid int64, ssid string, method string, jti string) error {
+	now := time.Now().UnixMilli()
+	key := h.sessionKey(ssid)
+	pipe := h.client.TxPipeline()
+	pipe.HSet(ctx, key, map[string]any{
+		"uid":         uid,
+		"user_agent":  ctx.GetHeader("User-Agent"),
+		"ip":          ctx.ClientIP(),
+		"method":      method,
+		"ctime":       now,
+		"last_seen":   now,
+		"refresh_jti": jti,
+	})
+	pipe.Expire(ctx, key, h.rcExpiration)
+	pipe.SAdd(ctx, h.sessionsKey(uid), ssid)
+	pipe.Expire(ctx, h.sessionsKey(uid), h.rcExpiration)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// CheckSession 会话必须还在登记表里面，并且属于这个用户
+func (h *RedisJWTHandler) CheckSession(ctx *gin.Context, uc UserClaims) error {
+	res, err := h.client.Eval(ctx, luaCheckSession,
+		[]string{h.sessionKey(uc.Ssid), h.revokedKey(uc.Ssid), h.sessionsKey(uc.Uid), h.validAfterKey(uc.Uid)},
+		uc.Uid, time.Now().UnixMilli(), sessionTouchInterval.Milliseconds(),
+		uc.Ssid, isLegacyToken(uc.IssuedAt), h.rcExpiration.Milliseconds(), issuedAt(uc.IssuedAt)).Int()
+	if err != nil {
+		return err
+	}
+	if res != 1 {
+		return errSessionInvalid
+	}
+	return nil
+}
+
+// RotateRefreshToken 每次刷新都换一个新的 refresh token。同一个会话的 refresh token 是一个家族，
+// 只有最新的那个能用，拿已经换掉的来刷新说明 token 可能被偷了，整个会话注销
+func (h *RedisJWTHandler) RotateRefreshToken(ctx *gin.Context, rc RefreshClaims) error {
+	jti := uuid.New().String()
+	res, err := h.client.Eval(ctx, luaRotateRefresh,
+		[]string{h.sessionKey(rc.Ssid), h.revokedKey(rc.Ssid), h.sessionsKey(rc.Uid), h.validAfterKey(rc.Uid)},
+		rc.Uid, rc.ID, jti, time.Now().UnixMilli(),
+		rc.Ssid, isLegacyToken(rc.IssuedAt), h.rcExpiration.Milliseconds(), issuedAt(rc.IssuedAt)).Int()
+	if err != nil {
+		return err
+	}
+	switch res {
+	case 1:
+	case -1:
+		err = h.revoke(ctx, rc.Uid, []string{rc.Ssid})
+		if err != nil {
+			return err
+		}
+		return ErrRefreshTokenReused
+	default:
+		return errSessionInvalid
+	}
+	// 有效期从登录的时候开始算，不会因为一直刷新就永远不过期
+	expiresAt := time.Now().Add(h.rcExpiration)
+	if rc.ExpiresAt != nil {
+		expiresAt = rc.ExpiresAt.Time
+	}
+	err = h.SetRefreshToken(ctx, rc.Uid, rc.Ssid, jti, expiresAt)
+	if err != nil {
+		return err
+	}
+	return h.SetJWTToken(ctx, rc.Uid, rc.Ssid)
+}
+
+// isLegacyToken 会话登记表上线之前签发的 token 没有 iat，这些 token 的会话在第一次使用的时候补登记。
+// refresh token 最多七天就过期了，之后就不会再有这种 token
+func isLegacyToken(issuedAt *jwt.NumericDate) bool {
+	return issuedAt == nil
+}
+
+// issuedAt 单位是秒，升级之前签发的 token 当成 0
+func issuedAt(iat *jwt.NumericDate) int64 {
+	if iat == nil {
+		return 0
+	}
+	return iat.Unix()
+}
+
+// ListSessions 最近活跃的在前面
+func (h *RedisJWTHandler) ListSessions(ctx *gin.Context, uid int64) ([]Session, error) {
+	ssids, err := h.client.SMembers(ctx, h.sessionsKey(uid)).Result()
+	if err != nil || len(ssids) == 0 {
+		return nil, err
+	}
+	pipe := h.client.Pipeline()
+	cmds := make([]*redis.MapStringStringCmd, len(ssids))
+	for i, ssid := range ssids {
+		cmds[i] = pipe.HGetAll(ctx, h.sessionKey(ssid))
+	}
+	_, err = pipe.Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]Session, 0, len(ssids))
+	var expired []any
+	for i, cmd := range cmds {
+		vals := cmd.Val()
+		if len(vals) == 0 {
+			expired = append(expired, ssids[i])
+			continue
+		}
+		res = append(res, h.toSession(ssids[i], vals))
+	}
+	if len(expired) > 0 {
+		// 清理失败也不影响结果
+		_ = h.client.SRem(ctx, h.sessionsKey(uid), expired...).Err()
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].LastSeen.After(res[j].LastSeen)
+	})
+	return res, nil
+}
+
+// RevokeSession 只能注销自己的会话，ssid 不存在或者不属于 uid 的时候返回 ErrSessionNotFound
+func (h *RedisJWTHandler) RevokeSession(ctx *gin.Context, uid int64, ssid string) error {
+	owner, err := h.client.HGet(ctx, h.sessionKey(ssid), "uid").Result()
+	if err == redis.Nil || (err == nil && owner != strconv.FormatInt(uid, 10)) {
+		return ErrSessionNotFound
+	}
+	if err != nil {
+		return err
+	}
+	return h.revoke(ctx, uid, []string{ssid})
+}
+
+// RevokeOtherSessions 注销除了 keep 之外的所有会话
+func (h *RedisJWTHandler) RevokeOtherSessions(ctx *gin.Context, uid int64, keep string) error {
+	ssids, err := h.client.SMembers(ctx, h.sessionsKey(uid)).Result()
+	if err != nil {
+		return err
+	}
+	others := make([]string, 0, len(ssids))
+	for _, ssid := range ssids {
+		if ssid != keep {
+			others = append(others, ssid)
+		}
+	}
+	return h.revoke(ctx, uid, others)
+}
+
+// ClearSessions 会话登记表上线之前登录的不在用户的会话集合里面，注销不掉，
+// 所以还要记下现在的时间，这之前签发的 token 全部作废。
+// token 的签发时间精确到秒，同一秒里面重新登录的不受影响
+func (h *RedisJWTHandler) ClearSessions(ctx *gin.Context, uid int64) error {
+	err := h.client.Set(ctx, h.validAfterKey(uid), time.Now().Unix(), h.rcExpiration).Err()
+	if err != nil {
+		return err
+	}
+	return h.RevokeOtherSessions(ctx, uid, "")
+}
+
+// revoke 删除会话的同时还是写退出登录的标记，兼容还没有升级的实例
+func (h *RedisJWTHandler) revoke(ctx context.Context, uid int64, ssids []string) error {
+	if len(ssids) == 0 {
+		return nil
+	}
+	pipe := h.client.Pipeline()
+	members := make([]any, 0, len(ssids))
+	for _, ssid := range ssids {
+		pipe.Del(ctx, h.sessionKey(ssid))
+		pipe.Set(ctx, h.revokedKey(ssid), "", h.rcExpiration)
+		members = append(members, ssid)
+	}
+	// 只删除拿到的这些，期间新登录的不受影响
+	pipe.SRem(ctx, h.sessionsKey(uid), members...)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (h *RedisJWTHandler) toSession(ssid string, vals map[string]string) Session {
+	uid, _ := strconv.ParseInt(vals["uid"], 10, 64)
+	ctime, _ := strconv.ParseInt(vals["ctime"], 10, 64)
+	lastSeen, _ := strconv.ParseInt(vals["last_seen"], 10, 64)
+	return Session{
+		Ssid:      ssid,
+		Uid:       uid,
+		UserAgent: vals["user_agent"],
+		IP:        vals["ip"],
+		Method:    vals["method"],
+		Ctime:     time.UnixMilli(ctime),
+		LastSeen:  time.UnixMilli(lastSeen),
+	}
+}
+
+func (h *RedisJWTHandler) sessionKey(ssid string) string {
+	return fmt.Sprintf("users:session:%s", ssid)
+}
+
+func (h *RedisJWTHandler) sessionsKey(uid int64) string {
+	return fmt.Sprintf("users:sessions:%d", uid)
+}
+
+func (h *RedisJWTHandler) revokedKey(ssid string) string {
+	return fmt.Sprintf("users:ssid:%s", ssid)
+}
+
+// validAfterKey 用户 token 的最早签发时间，早于这个时间的都作废
+func (h *RedisJWTHandler) validAfterKey(uid int64) string {
+	return fmt.Sprintf("users:token_valid_after:%d", uid)
+}
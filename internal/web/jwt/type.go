@@ -1,11 +1,30 @@
 package jwt
 
-import "github.com/gin-gonic/gin"
+import (
+	"github.com/gin-gonic/gin"
+	"webook/pkg/jwtx"
+)
 
+//go:generate mockgen -source=./type.go -package=jwtmocks -destination=./mocks/handler.mock.go Handler
 type Handler interface {
 	SetJWTToken(ctx *gin.Context, uid int64, ssid string) error
 	ExtractToken(ctx *gin.Context) string
-	SetLoginToken(ctx *gin.Context, uid int64) error
+	// ParseAccessToken 校验签名和过期时间，不检查会话
+	ParseAccessToken(tokenStr string) (UserClaims, error)
+	ParseRefreshToken(tokenStr string) (RefreshClaims, error)
+	// RotateRefreshToken 换新的 refresh token 和 access token，
+	// rc 已经被换掉过的时候注销整个会话并返回 ErrRefreshTokenReused
+	RotateRefreshToken(ctx *gin.Context, rc RefreshClaims) error
+	// JWKS 验证 access token 的公钥，给其它服务用
+	JWKS() jwtx.JWKS
+	// SetLoginToken method 是登录方式，记录在会话里面
+	SetLoginToken(ctx *gin.Context, uid int64, method string) error
 	ClearToken(ctx *gin.Context) error
-	CheckSession(ctx *gin.Context, ssid string) error
+	// CheckSession 会话被注销或者不属于 uc.Uid 的时候返回 error
+	CheckSession(ctx *gin.Context, uc UserClaims) error
+	// ClearSessions 让用户所有的登录态都失效，比如重置密码之后
+	ClearSessions(ctx *gin.Context, uid int64) error
+	ListSessions(ctx *gin.Context, uid int64) ([]Session, error)
+	RevokeSession(ctx *gin.Context, uid int64, ssid string) error
+	RevokeOtherSessions(ctx *gin.Context, uid int64, keep string) error
 }
@@ -0,0 +1,109 @@
+package web
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/gotomicro/ekit/slice"
+	"net/http"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/service"
+	"webook/internal/web/jwt"
+	"webook/pkg/logger"
+)
+
+type NotificationHandler struct {
+	svc service.NotificationService
+	l   logger.LoggerV1
+}
+
+func NewNotificationHandler(svc service.NotificationService, l logger.LoggerV1) *NotificationHandler {
+	return &NotificationHandler{
+		svc: svc,
+		l:   l,
+	}
+}
+
+func (h *NotificationHandler) RegisterRoutes(server *gin.Engine) {
+	g := server.Group("/notifications")
+	g.POST("/list", h.List)
+	g.GET("/unread", h.CountUnread)
+	g.POST("/read", h.MarkRead)
+}
+
+func (h *NotificationHandler) List(ctx *gin.Context) {
+	var page Page
+	if err := ctx.Bind(&page); err != nil {
+		return
+	}
+	uc := ctx.MustGet("user").(jwt.UserClaims)
+	ns, err := h.svc.List(ctx, uc.Uid, page.Offset, page.Limit)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.l.Error("查找通知失败", logger.Int64("uid", uc.Uid), logger.Error(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Data: slice.Map(ns, func(idx int, src domain.Notification) NotificationVO {
+			return NotificationVO{
+				Id:      src.Id,
+				Type:    src.Type,
+				BizId:   src.BizId,
+				Content: src.Content,
+				Read:    src.Read,
+				Ctime:   src.Ctime.Format(time.DateTime),
+			}
+		}),
+	})
+}
+
+func (h *NotificationHandler) CountUnread(ctx *gin.Context) {
+	uc := ctx.MustGet("user").(jwt.UserClaims)
+	cnt, err := h.svc.CountUnread(ctx, uc.Uid)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.l.Error("统计未读通知失败", logger.Int64("uid", uc.Uid), logger.Error(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Data: cnt,
+	})
+}
+
+// MarkRead Ids 为空表示全部标记为已读
+func (h *NotificationHandler) MarkRead(ctx *gin.Context) {
+	type Req struct {
+		Ids []int64 `json:"ids"`
+	}
+	req := Req{}
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	uc := ctx.MustGet("user").(jwt.UserClaims)
+	err := h.svc.MarkRead(ctx, uc.Uid, req.Ids)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.l.Error("标记通知已读失败", logger.Int64("uid", uc.Uid), logger.Error(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Msg: "OK",
+	})
+}
+
+type NotificationVO struct {
+	Id      int64  `json:"id"`
+	Type    string `json:"type"`
+	BizId   int64  `json:"biz_id"`
+	Content string `json:"content"`
+	Read    bool   `json:"read"`
+	Ctime   string `json:"ctime"`
+}
@@ -0,0 +1,81 @@
+package web
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/gotomicro/ekit/slice"
+	"net/http"
+	"strings"
+	"webook/internal/domain"
+	"webook/internal/service"
+	"webook/pkg/logger"
+)
+
+type SearchHandler struct {
+	svc service.SearchService
+	l   logger.LoggerV1
+}
+
+func NewSearchHandler(svc service.SearchService, l logger.LoggerV1) *SearchHandler {
+	return &SearchHandler{
+		svc: svc,
+		l:   l,
+	}
+}
+
+func (h *SearchHandler) RegisterRoutes(server *gin.Engine) {
+	g := server.Group("/search")
+	g.GET("/articles", h.SearchArticles)
+}
+
+func (h *SearchHandler) SearchArticles(ctx *gin.Context) {
+	type Req struct {
+		Query  string `form:"q"`
+		Offset int    `form:"offset"`
+		Limit  int    `form:"limit"`
+	}
+	var req Req
+	if err := ctx.BindQuery(&req); err != nil {
+		return
+	}
+	req.Query = strings.TrimSpace(req.Query)
+	if req.Query == "" {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "请输入搜索内容",
+		})
+		return
+	}
+	if req.Limit <= 0 || req.Limit > 100 || req.Offset < 0 {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "分页参数错误",
+		})
+		return
+	}
+	res, err := h.svc.Search(ctx, req.Query, req.Offset, req.Limit)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.l.Error("搜索文章失败",
+			logger.String("q", req.Query),
+			logger.Error(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Data: SearchResultVO{
+			Total: res.Total,
+			List: slice.Map(res.Articles, func(idx int, src domain.SearchArticle) SearchArticleVO {
+				return SearchArticleVO{
+					Id:         src.Id,
+					Title:      src.Title,
+					Snippet:    src.Snippet,
+					AuthorId:   src.Author.Id,
+					AuthorName: src.Author.Name,
+					Tags:       src.Tags,
+				}
+			}),
+		},
+	})
+}
@@ -0,0 +1,16 @@
+package web
+
+type SearchResultVO struct {
+	Total int               `json:"total"`
+	List  []SearchArticleVO `json:"list"`
+}
+
+// SearchArticleVO 里面的 Title 和 Snippet 已经转义过，命中的部分用 <em> 标签包起来
+type SearchArticleVO struct {
+	Id         int64    `json:"id"`
+	Title      string   `json:"title"`
+	Snippet    string   `json:"snippet"`
+	AuthorId   int64    `json:"authorId"`
+	AuthorName string   `json:"authorName,omitempty"`
+	Tags       []string `json:"tags,omitempty"`
+}
@@ -0,0 +1,328 @@
+package web
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/gotomicro/ekit/slice"
+	"net/http"
+	"strconv"
+	"time"
+	intrv1 "webook/api/proto/gen/intr/v1"
+	"webook/internal/domain"
+	"webook/internal/service"
+	"webook/internal/web/jwt"
+	"webook/pkg/logger"
+)
+
+type SeriesHandler struct {
+	svc     service.SeriesService
+	intrSvc intrv1.InteractiveServiceClient
+	l       logger.LoggerV1
+	biz     string
+}
+
+func NewSeriesHandler(svc service.SeriesService, intrSvc intrv1.InteractiveServiceClient, l logger.LoggerV1) *SeriesHandler {
+	return &SeriesHandler{
+		svc:     svc,
+		intrSvc: intrSvc,
+		l:       l,
+		biz:     "article",
+	}
+}
+
+func (h *SeriesHandler) RegisterRoutes(server *gin.Engine) {
+	g := server.Group("/series")
+	g.POST("/create", h.Create)
+	g.POST("/edit", h.Edit)
+	g.POST("/articles", h.SetArticles)
+	g.POST("/publish", h.Publish)
+	g.POST("/withdraw", h.Withdraw)
+	g.GET("/detail/:id", h.Detail)
+	g.POST("/list", h.List)
+	// 不需要登录
+	g.GET("/pub/:id", h.PubDetail)
+}
+
+func (h *SeriesHandler) Create(ctx *gin.Context) {
+	type Req struct {
+		Title       string `json:"title"`
+		Description string `json:"description"`
+	}
+	req := Req{}
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	uc := ctx.MustGet("user").(jwt.UserClaims)
+	id, err := h.svc.Create(ctx, domain.Series{
+		Title:       req.Title,
+		Description: req.Description,
+		Author: domain.Author{
+			Id: uc.Uid,
+		},
+	})
+	switch err {
+	case nil:
+		ctx.JSON(http.StatusOK, Result{
+			Data: id,
+		})
+	case service.ErrInvalidSeries:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "系列标题为空或者过长",
+		})
+	default:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.l.Error("创建系列失败", logger.Int64("uid", uc.Uid), logger.Error(err))
+	}
+}
+
+func (h *SeriesHandler) Edit(ctx *gin.Context) {
+	type Req struct {
+		Id          int64  `json:"id"`
+		Title       string `json:"title"`
+		Description string `json:"description"`
+	}
+	req := Req{}
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	uc := ctx.MustGet("user").(jwt.UserClaims)
+	err := h.svc.Update(ctx, domain.Series{
+		Id:          req.Id,
+		Title:       req.Title,
+		Description: req.Description,
+		Author: domain.Author{
+			Id: uc.Uid,
+		},
+	})
+	switch err {
+	case nil:
+		ctx.JSON(http.StatusOK, Result{
+			Msg: "OK",
+		})
+	case service.ErrInvalidSeries:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "系列标题为空或者过长",
+		})
+	default:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.l.Error("修改系列失败", logger.Int64("uid", uc.Uid), logger.Int64("id", req.Id), logger.Error(err))
+	}
+}
+
+// SetArticles 按照 ArticleIds 的顺序覆盖系列里面的文章
+func (h *SeriesHandler) SetArticles(ctx *gin.Context) {
+	type Req struct {
+		Id         int64   `json:"id"`
+		ArticleIds []int64 `json:"article_ids"`
+	}
+	req := Req{}
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	uc := ctx.MustGet("user").(jwt.UserClaims)
+	err := h.svc.SetArticles(ctx, req.Id, uc.Uid, req.ArticleIds)
+	switch err {
+	case nil:
+		ctx.JSON(http.StatusOK, Result{
+			Msg: "OK",
+		})
+	case service.ErrInvalidSeriesArticles:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "文章过多或者有重复",
+		})
+	case service.ErrSeriesArticleInvalid:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "文章不存在",
+		})
+	case service.ErrSeriesArticleConflict:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "文章已经在别的系列里面了",
+		})
+	default:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.l.Error("设置系列文章失败", logger.Int64("uid", uc.Uid), logger.Int64("id", req.Id), logger.Error(err))
+	}
+}
+
+func (h *SeriesHandler) Publish(ctx *gin.Context) {
+	h.updateStatus(ctx, h.svc.Publish, "发表系列失败")
+}
+
+func (h *SeriesHandler) Withdraw(ctx *gin.Context) {
+	h.updateStatus(ctx, h.svc.Withdraw, "撤回系列失败")
+}
+
+func (h *SeriesHandler) updateStatus(ctx *gin.Context,
+	fn func(ctx context.Context, id int64, uid int64) error, msg string) {
+	type Req struct {
+		Id int64 `json:"id"`
+	}
+	req := Req{}
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	uc := ctx.MustGet("user").(jwt.UserClaims)
+	err := fn(ctx, req.Id, uc.Uid)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.l.Error(msg, logger.Int64("uid", uc.Uid), logger.Int64("id", req.Id), logger.Error(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Msg: "OK",
+	})
+}
+
+func (h *SeriesHandler) Detail(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "id 参数错误",
+		})
+		return
+	}
+	uc := ctx.MustGet("user").(jwt.UserClaims)
+	series, err := h.svc.GetById(ctx, id, uc.Uid)
+	switch err {
+	case nil:
+		ctx.JSON(http.StatusOK, Result{
+			Data: h.toVO(series, nil),
+		})
+	case service.ErrSeriesNotFound:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "系列不存在",
+		})
+	default:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.l.Error("查询系列失败", logger.Int64("uid", uc.Uid), logger.Int64("id", id), logger.Error(err))
+	}
+}
+
+func (h *SeriesHandler) List(ctx *gin.Context) {
+	var req Page
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	if req.Limit <= 0 || req.Limit > 100 {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "limit 参数错误",
+		})
+		return
+	}
+	uc := ctx.MustGet("user").(jwt.UserClaims)
+	res, err := h.svc.ListByAuthor(ctx, uc.Uid, req.Offset, req.Limit)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.l.Error("查找系列列表失败",
+			logger.Error(err),
+			logger.Int("offset", req.Offset),
+			logger.Int("limit", req.Limit),
+			logger.Int64("uid", uc.Uid))
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Data: slice.Map(res, func(idx int, src domain.Series) SeriesVO {
+			return h.toVO(src, nil)
+		}),
+	})
+}
+
+// PubDetail 系列的公开页面，列出已经发表的文章和它们的阅读、点赞、收藏数
+func (h *SeriesHandler) PubDetail(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "id 参数错误",
+		})
+		return
+	}
+	series, err := h.svc.GetPub(ctx, id)
+	if err == service.ErrSeriesNotFound {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "系列不存在",
+		})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.l.Error("查询系列失败", logger.Int64("id", id), logger.Error(err))
+		return
+	}
+	var intrs map[int64]*intrv1.Interactive
+	if len(series.Articles) > 0 {
+		resp, er := h.intrSvc.GetByIds(ctx, &intrv1.GetByIdsRequest{
+			Biz: h.biz,
+			Ids: slice.Map(series.Articles, func(idx int, src domain.Article) int64 {
+				return src.Id
+			}),
+		})
+		if er != nil {
+			// 计数拿不到不影响看文章列表
+			h.l.Error("查询系列文章的互动数据失败", logger.Int64("id", id), logger.Error(er))
+		} else {
+			intrs = resp.GetIntrs()
+		}
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Data: h.toVO(series, intrs),
+	})
+}
+
+func (h *SeriesHandler) toVO(series domain.Series, intrs map[int64]*intrv1.Interactive) SeriesVO {
+	return SeriesVO{
+		Id:          series.Id,
+		Title:       series.Title,
+		Description: series.Description,
+		AuthorId:    series.Author.Id,
+		Status:      series.Status.ToUint8(),
+		Articles: slice.Map(series.Articles, func(idx int, src domain.Article) ArticleVO {
+			intr := intrs[src.Id]
+			return ArticleVO{
+				Id:         src.Id,
+				Title:      src.Title,
+				Abstract:   src.Abstract(),
+				AuthorId:   src.Author.Id,
+				Status:     src.Status.ToUint8(),
+				ReadCnt:    intr.GetReadCnt(),
+				LikeCnt:    intr.GetLikeCnt(),
+				CollectCnt: intr.GetCollectCnt(),
+				CommentCnt: intr.GetCommentCnt(),
+				Ctime:      src.Ctime.Format(time.DateTime),
+				Utime:      src.Utime.Format(time.DateTime),
+			}
+		}),
+		Ctime: series.Ctime.Format(time.DateTime),
+		Utime: series.Utime.Format(time.DateTime),
+	}
+}
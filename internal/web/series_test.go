@@ -0,0 +1,110 @@
+package web
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"webook/internal/service"
+	svcmocks "webook/internal/service/mocks"
+	ijwt "webook/internal/web/jwt"
+	"webook/pkg/logger"
+)
+
+func TestSeriesHandler_SetArticles(t *testing.T) {
+	testCases := []struct {
+		name    string
+		mock    func(ctrl *gomock.Controller) service.SeriesService
+		reqBody string
+		wantRes Result
+	}{
+		{
+			name: "设置成功",
+			mock: func(ctrl *gomock.Controller) service.SeriesService {
+				svc := svcmocks.NewMockSeriesService(ctrl)
+				svc.EXPECT().SetArticles(gomock.Any(), int64(2), int64(1), []int64{3, 5, 4}).Return(nil)
+				return svc
+			},
+			reqBody: `{"id":2,"article_ids":[3,5,4]}`,
+			wantRes: Result{
+				Msg: "OK",
+			},
+		},
+		{
+			name: "文章有重复",
+			mock: func(ctrl *gomock.Controller) service.SeriesService {
+				svc := svcmocks.NewMockSeriesService(ctrl)
+				svc.EXPECT().SetArticles(gomock.Any(), int64(2), int64(1), []int64{3, 3}).
+					Return(service.ErrInvalidSeriesArticles)
+				return svc
+			},
+			reqBody: `{"id":2,"article_ids":[3,3]}`,
+			wantRes: Result{
+				Code: 4,
+				Msg:  "文章过多或者有重复",
+			},
+		},
+		{
+			name: "文章在别的系列里面",
+			mock: func(ctrl *gomock.Controller) service.SeriesService {
+				svc := svcmocks.NewMockSeriesService(ctrl)
+				svc.EXPECT().SetArticles(gomock.Any(), int64(2), int64(1), []int64{3}).
+					Return(service.ErrSeriesArticleConflict)
+				return svc
+			},
+			reqBody: `{"id":2,"article_ids":[3]}`,
+			wantRes: Result{
+				Code: 4,
+				Msg:  "文章已经在别的系列里面了",
+			},
+		},
+		{
+			name: "系统错误",
+			mock: func(ctrl *gomock.Controller) service.SeriesService {
+				svc := svcmocks.NewMockSeriesService(ctrl)
+				svc.EXPECT().SetArticles(gomock.Any(), int64(2), int64(1), []int64{3}).
+					Return(errors.New("mock db error"))
+				return svc
+			},
+			reqBody: `{"id":2,"article_ids":[3]}`,
+			wantRes: Result{
+				Code: 5,
+				Msg:  "系统错误",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost,
+				"/series/articles",
+				bytes.NewBufferString(tc.reqBody))
+			req.Header.Set("Content-Type", "application/json")
+			assert.NoError(t, err)
+			recorder := httptest.NewRecorder()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			hdl := NewSeriesHandler(tc.mock(ctrl), nil, logger.NewNopLogger())
+			server := gin.Default()
+			server.Use(func(ctx *gin.Context) {
+				ctx.Set("user", ijwt.UserClaims{
+					Uid: 1,
+				})
+			})
+			hdl.RegisterRoutes(server)
+
+			server.ServeHTTP(recorder, req)
+
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			var res Result
+			err = json.NewDecoder(recorder.Body).Decode(&res)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantRes, res)
+		})
+	}
+}
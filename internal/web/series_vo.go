@@ -0,0 +1,29 @@
+package web
+
+type SeriesVO struct {
+	Id          int64  `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	AuthorId    int64  `json:"authorId"`
+	Status      uint8  `json:"status"`
+	// Articles 按照系列里面的顺序排列
+	Articles []ArticleVO `json:"articles,omitempty"`
+	Ctime    string      `json:"ctime,omitempty"`
+	Utime    string      `json:"utime,omitempty"`
+}
+
+// SeriesNavVO 文章详情页的系列导航，Prev 和 Next 为空表示已经是第一篇或者最后一篇
+type SeriesNavVO struct {
+	Id    int64  `json:"id"`
+	Title string `json:"title"`
+	// Index 从 0 开始
+	Index int           `json:"index"`
+	Total int           `json:"total"`
+	Prev  *SeriesPartVO `json:"prev,omitempty"`
+	Next  *SeriesPartVO `json:"next,omitempty"`
+}
+
+type SeriesPartVO struct {
+	Id    int64  `json:"id"`
+	Title string `json:"title"`
+}
@@ -0,0 +1,196 @@
+package web
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/gotomicro/ekit/slice"
+	"net/http"
+	"strconv"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/service"
+	"webook/internal/web/jwt"
+	"webook/pkg/logger"
+)
+
+const (
+	defaultShareTTL = 7 * 24 * time.Hour
+	maxShareTTL     = 30 * 24 * time.Hour
+)
+
+type ShareHandler struct {
+	svc service.ShareService
+	l   logger.LoggerV1
+}
+
+func NewShareHandler(svc service.ShareService, l logger.LoggerV1) *ShareHandler {
+	return &ShareHandler{
+		svc: svc,
+		l:   l,
+	}
+}
+
+func (h *ShareHandler) RegisterRoutes(server *gin.Engine) {
+	g := server.Group("/articles/share")
+	g.POST("/create", h.Create)
+	g.POST("/revoke", h.Revoke)
+	g.GET("/list/:id", h.List)
+	// 不需要登录
+	server.GET("/share/:token", h.Resolve)
+}
+
+func (h *ShareHandler) Create(ctx *gin.Context) {
+	type Req struct {
+		Id int64 `json:"id"`
+		// ExpireIn 多少秒之后过期，不传默认 7 天，最多 30 天
+		ExpireIn int64 `json:"expire_in"`
+		// MaxViews 最多可以访问多少次，不传表示不限制
+		MaxViews int64 `json:"max_views"`
+	}
+	var req Req
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	ttl := time.Duration(req.ExpireIn) * time.Second
+	if req.ExpireIn == 0 {
+		ttl = defaultShareTTL
+	}
+	if ttl <= 0 || ttl > maxShareTTL || req.MaxViews < 0 {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "有效期最长 30 天，访问次数不能小于 0",
+		})
+		return
+	}
+	uc := ctx.MustGet("user").(jwt.UserClaims)
+	link, err := h.svc.Create(ctx, uc.Uid, req.Id, ttl, req.MaxViews)
+	switch err {
+	case nil:
+		ctx.JSON(http.StatusOK, Result{
+			Data: h.toVO(link),
+		})
+	case service.ErrShareArticleNotFound:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "文章不存在",
+		})
+		h.l.Warn("分享别人的文章", logger.Int64("uid", uc.Uid), logger.Int64("id", req.Id))
+	default:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.l.Error("创建分享链接失败", logger.Int64("uid", uc.Uid), logger.Int64("id", req.Id), logger.Error(err))
+	}
+}
+
+func (h *ShareHandler) Revoke(ctx *gin.Context) {
+	type Req struct {
+		Id int64 `json:"id"`
+	}
+	var req Req
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	uc := ctx.MustGet("user").(jwt.UserClaims)
+	err := h.svc.Revoke(ctx, uc.Uid, req.Id)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.l.Error("撤销分享链接失败", logger.Int64("uid", uc.Uid), logger.Int64("id", req.Id), logger.Error(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Msg: "OK",
+	})
+}
+
+func (h *ShareHandler) List(ctx *gin.Context) {
+	idStr := ctx.Param("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "id 参数错误",
+		})
+		return
+	}
+	uc := ctx.MustGet("user").(jwt.UserClaims)
+	links, err := h.svc.List(ctx, uc.Uid, id)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.l.Error("查询分享链接失败", logger.Int64("uid", uc.Uid), logger.Int64("id", id), logger.Error(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Data: slice.Map(links, func(idx int, src domain.ShareLink) ShareLinkVO {
+			return h.toVO(src)
+		}),
+	})
+}
+
+func (h *ShareHandler) Resolve(ctx *gin.Context) {
+	token := ctx.Param("token")
+	link, art, err := h.svc.Resolve(ctx, token)
+	switch err {
+	case nil:
+		ctx.JSON(http.StatusOK, Result{
+			Data: SharedArticleVO{
+				Article: ArticleVO{
+					Id:       art.Id,
+					Title:    art.Title,
+					Html:     art.HTML,
+					Abstract: art.Abstract(),
+					AuthorId: art.Author.Id,
+					Tags:     art.Tags,
+					Status:   art.Status.ToUint8(),
+					Ctime:    art.Ctime.Format(time.DateTime),
+					Utime:    art.Utime.Format(time.DateTime),
+				},
+				ExpireAt: link.ExpireAt.Format(time.DateTime),
+				Views:    link.Views,
+				MaxViews: link.MaxViews,
+			},
+		})
+	case service.ErrShareLinkInvalid:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "分享链接无效",
+		})
+	case service.ErrShareLinkExpired:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "分享链接已经过期",
+		})
+	case service.ErrShareLinkExhausted:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "分享链接的访问次数已经用完",
+		})
+	default:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.l.Error("打开分享链接失败", logger.Error(err))
+	}
+}
+
+func (h *ShareHandler) toVO(link domain.ShareLink) ShareLinkVO {
+	return ShareLinkVO{
+		Id:        link.Id,
+		ArticleId: link.ArticleId,
+		Token:     link.Token,
+		Path:      "/share/" + link.Token,
+		ExpireAt:  link.ExpireAt.Format(time.DateTime),
+		MaxViews:  link.MaxViews,
+		Views:     link.Views,
+		Revoked:   link.Revoked,
+		Valid:     link.Valid(time.Now()),
+		Ctime:     link.Ctime.Format(time.DateTime),
+	}
+}
@@ -0,0 +1,98 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"webook/internal/domain"
+	"webook/internal/service"
+	svcmocks "webook/internal/service/mocks"
+	"webook/pkg/logger"
+)
+
+func TestShareHandler_Resolve(t *testing.T) {
+	testCases := []struct {
+		name    string
+		mock    func(ctrl *gomock.Controller) service.ShareService
+		wantRes Result
+	}{
+		{
+			name: "签名不对",
+			mock: func(ctrl *gomock.Controller) service.ShareService {
+				svc := svcmocks.NewMockShareService(ctrl)
+				svc.EXPECT().Resolve(gomock.Any(), "abc").
+					Return(domain.ShareLink{}, domain.Article{}, service.ErrShareLinkInvalid)
+				return svc
+			},
+			wantRes: Result{
+				Code: 4,
+				Msg:  "分享链接无效",
+			},
+		},
+		{
+			name: "已经过期",
+			mock: func(ctrl *gomock.Controller) service.ShareService {
+				svc := svcmocks.NewMockShareService(ctrl)
+				svc.EXPECT().Resolve(gomock.Any(), "abc").
+					Return(domain.ShareLink{}, domain.Article{}, service.ErrShareLinkExpired)
+				return svc
+			},
+			wantRes: Result{
+				Code: 4,
+				Msg:  "分享链接已经过期",
+			},
+		},
+		{
+			name: "次数用完",
+			mock: func(ctrl *gomock.Controller) service.ShareService {
+				svc := svcmocks.NewMockShareService(ctrl)
+				svc.EXPECT().Resolve(gomock.Any(), "abc").
+					Return(domain.ShareLink{}, domain.Article{}, service.ErrShareLinkExhausted)
+				return svc
+			},
+			wantRes: Result{
+				Code: 4,
+				Msg:  "分享链接的访问次数已经用完",
+			},
+		},
+		{
+			name: "系统错误",
+			mock: func(ctrl *gomock.Controller) service.ShareService {
+				svc := svcmocks.NewMockShareService(ctrl)
+				svc.EXPECT().Resolve(gomock.Any(), "abc").
+					Return(domain.ShareLink{}, domain.Article{}, errors.New("mock db error"))
+				return svc
+			},
+			wantRes: Result{
+				Code: 5,
+				Msg:  "系统错误",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, "/share/abc", nil)
+			assert.NoError(t, err)
+			recorder := httptest.NewRecorder()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			hdl := NewShareHandler(tc.mock(ctrl), logger.NewNopLogger())
+			server := gin.Default()
+			hdl.RegisterRoutes(server)
+
+			server.ServeHTTP(recorder, req)
+
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			var res Result
+			err = json.NewDecoder(recorder.Body).Decode(&res)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantRes, res)
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package web
+
+type ShareLinkVO struct {
+	Id        int64  `json:"id"`
+	ArticleId int64  `json:"articleId"`
+	Token     string `json:"token"`
+	// Path 打开分享链接的地址
+	Path     string `json:"path"`
+	ExpireAt string `json:"expireAt"`
+	MaxViews int64  `json:"maxViews"`
+	Views    int64  `json:"views"`
+	Revoked  bool   `json:"revoked"`
+	Valid    bool   `json:"valid"`
+	Ctime    string `json:"ctime"`
+}
+
+type SharedArticleVO struct {
+	Article  ArticleVO `json:"article"`
+	ExpireAt string    `json:"expireAt"`
+	Views    int64     `json:"views"`
+	MaxViews int64     `json:"maxViews"`
+}
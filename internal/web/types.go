@@ -1,6 +1,6 @@
 package web
 
 type Page struct {
-	Limit  int
-	Offset int
+	Limit  int `form:"limit"`
+	Offset int `form:"offset"`
 }
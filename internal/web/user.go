@@ -4,7 +4,7 @@ import (
 	regexp "github.com/dlclark/regexp2"
 	"github.com/gin-contrib/sessions"
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
+	"github.com/gotomicro/ekit/slice"
 	"net/http"
 	"time"
 	"webook/internal/domain"
@@ -19,6 +19,7 @@ const (
 	emailRegexPattern    = "^\\w+([-+.]\\w+)*@\\w+([-.]\\w+)*\\.\\w+([-.]\\w+)*$"
 	passwordRegexPattern = `^(?=.*[A-Za-z])(?=.*\d)(?=.*[$@$!%*#?&])[A-Za-z\d$@$!%*#?&]{8,}$`
 	bizLogin             = "login"
+	bizResetPassword     = "reset_password"
 )
 
 type UserHandler struct {
@@ -26,20 +27,24 @@ type UserHandler struct {
 	log         logger.LoggerV1
 	svc         service.UserService
 	codeSvc     service.CodeService
+	mfaSvc      service.UserMFAService
 	emailExp    *regexp.Regexp
 	passwordExp *regexp.Regexp
 }
 
-func NewUserHandler(svc service.UserService, codeSvc service.CodeService, handler ijwt.Handler) *UserHandler {
+func NewUserHandler(svc service.UserService, codeSvc service.CodeService, mfaSvc service.UserMFAService,
+	handler ijwt.Handler, l logger.LoggerV1) *UserHandler {
 	emailExp := regexp.MustCompile(emailRegexPattern, regexp.None)
 	passwordExp := regexp.MustCompile(passwordRegexPattern, regexp.None)
 
 	return &UserHandler{
 		svc:         svc,
 		codeSvc:     codeSvc,
+		mfaSvc:      mfaSvc,
 		emailExp:    emailExp,
 		passwordExp: passwordExp,
 		Handler:     handler,
+		log:         l,
 	}
 }
 
@@ -48,6 +53,8 @@ func (h *UserHandler) RegisterRoutes(server *gin.Engine) {
 	ug.POST("/signup", ginx.WrapBody(h.SignUp))
 	//ug.POST("/login", h.Login)
 	ug.POST("/login", ginx.WrapBody(h.LoginJWT))
+	// 开启了两步验证的用户，密码登录之后还要在这里提交验证码
+	ug.POST("/login/mfa", ginx.WrapBody(h.LoginMFA))
 	ug.POST("/refresh_token", h.RefreshToken)
 	ug.POST("/edit", ginx.WrapBodyAndClaims(h.Edit))
 	ug.GET("/profile", ginx.WrapClaims(h.Profile))
@@ -56,6 +63,21 @@ func (h *UserHandler) RegisterRoutes(server *gin.Engine) {
 	ug.POST("/login_sms/code/send", ginx.WrapBody(h.SendSMSLoginCode))
 	ug.POST("/login_sms", ginx.WrapBody(h.LoginSMS))
 	ug.POST("/logout", h.Logout)
+
+	// 修改密码要登录，找回密码不用
+	ug.POST("/password/change", ginx.WrapBodyAndClaims(h.ChangePassword))
+	ug.POST("/password/reset/code/send", ginx.WrapBody(h.SendResetPasswordCode))
+	ug.POST("/password/reset", ginx.WrapBody(h.ResetPassword))
+
+	// 登录的设备
+	ug.GET("/sessions", ginx.WrapClaims(h.ListSessions))
+	ug.POST("/sessions/revoke", ginx.WrapBodyAndClaims(h.RevokeSession))
+	ug.POST("/sessions/revoke_others", ginx.WrapClaims(h.RevokeOtherSessions))
+
+	mfa := ug.Group("/mfa")
+	mfa.POST("/enroll", ginx.WrapClaims(h.EnrollMFA))
+	mfa.POST("/confirm", ginx.WrapBodyAndClaims(h.ConfirmMFA))
+	mfa.POST("/disable", ginx.WrapBodyAndClaims(h.DisableMFA))
 }
 
 func (h *UserHandler) printLog(err *error) {
@@ -93,7 +115,7 @@ func (h *UserHandler) LoginSMS(ctx *gin.Context, req LoginSMSReq) (ginx.Result,
 			Msg:  "系统异常",
 		}, err
 	}
-	err = h.SetLoginToken(ctx, u.Id)
+	err = h.SetLoginToken(ctx, u.Id, ijwt.LoginMethodSMS)
 	if err != nil {
 		return ginx.Result{
 			Code: 5,
@@ -200,7 +222,17 @@ func (h *UserHandler) LoginJWT(ctx *gin.Context, req LoginJWTReq) (ginx.Result,
 	u, err := h.svc.Login(ctx.Request.Context(), req.Email, req.Password)
 	switch err {
 	case nil:
-		err := h.SetLoginToken(ctx, u.Id)
+		enabled, err := h.mfaSvc.Enabled(ctx.Request.Context(), u.Id)
+		if err != nil {
+			return ginx.Result{
+				Code: 5,
+				Msg:  "系统错误",
+			}, err
+		}
+		if enabled {
+			return h.beginMFALogin(ctx, u.Id)
+		}
+		err = h.SetLoginToken(ctx, u.Id, ijwt.LoginMethodPassword)
 		if err != nil {
 			return ginx.Result{
 				Code: 5,
@@ -304,24 +336,21 @@ func (h *UserHandler) Profile(ctx *gin.Context, uc ijwt.UserClaims) (ginx.Result
 
 func (h *UserHandler) RefreshToken(ctx *gin.Context) {
 	tokenStr := h.ExtractToken(ctx)
-	rc := ijwt.RefreshClaims{}
-	token, err := jwt.ParseWithClaims(tokenStr, &rc, func(token *jwt.Token) (interface{}, error) {
-		return ijwt.RefreshTokenKey, nil
-	})
+	rc, err := h.ParseRefreshToken(tokenStr)
 	if err != nil {
 		ctx.AbortWithStatus(http.StatusUnauthorized)
 		return
 	}
-	if token == nil || !token.Valid {
-		ctx.AbortWithStatus(http.StatusUnauthorized)
-		return
-	}
-	err = h.SetJWTToken(ctx, rc.Uid, rc.Ssid)
-	if err != nil {
-		ctx.String(http.StatusOK, "系统错误")
-		return
+	// 已经注销的会话不能再拿到新的 token
+	err = h.RotateRefreshToken(ctx, rc)
+	if err == ijwt.ErrRefreshTokenReused {
+		// 安全事件，后续告警在这里埋点
+		h.log.Warn("refresh token 被重复使用，已注销整个会话",
+			logger.Int64("uid", rc.Uid),
+			logger.String("ssid", rc.Ssid),
+			logger.String("ip", ctx.ClientIP()),
+			logger.String("userAgent", ctx.GetHeader("User-Agent")))
 	}
-	err = h.CheckSession(ctx, rc.Ssid)
 	if err != nil {
 		ctx.AbortWithStatus(http.StatusUnauthorized)
 		return
@@ -344,3 +373,230 @@ func (h *UserHandler) Logout(ctx *gin.Context) {
 		Msg: "退出登录成功",
 	})
 }
+
+type ChangePasswordReq struct {
+	OldPassword     string `json:"oldPassword"`
+	Password        string `json:"password"`
+	ConfirmPassword string `json:"confirmPassword"`
+}
+
+func (h *UserHandler) ChangePassword(ctx *gin.Context, req ChangePasswordReq, uc ijwt.UserClaims) (ginx.Result, error) {
+	res, err := h.checkNewPassword(req.Password, req.ConfirmPassword)
+	if err != nil || res.Code != 0 {
+		return res, err
+	}
+	err = h.svc.ChangePassword(ctx.Request.Context(), uc.Uid, req.OldPassword, req.Password)
+	switch err {
+	case nil:
+		return ginx.Result{
+			Msg: "OK",
+		}, nil
+	case service.ErrInvalidUserOrPassword:
+		return ginx.Result{
+			Code: errs.UserInvalidOrPassword,
+			Msg:  "旧密码不对",
+		}, nil
+	default:
+		return ginx.Result{
+			Code: errs.UserInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+}
+
+type SendResetPasswordCodeReq struct {
+	// Target 邮箱或者手机号
+	Target string `json:"target"`
+}
+
+// SendResetPasswordCode 不管用户存不存在都发送，不暴露哪些邮箱和手机号注册过
+func (h *UserHandler) SendResetPasswordCode(ctx *gin.Context, req SendResetPasswordCodeReq) (ginx.Result, error) {
+	if req.Target == "" {
+		return ginx.Result{
+			Code: errs.UserInvalidInput,
+			Msg:  "请输入邮箱或者手机号",
+		}, nil
+	}
+	isEmail, err := h.emailExp.MatchString(req.Target)
+	if err != nil {
+		return ginx.Result{
+			Code: errs.UserInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+	if isEmail {
+		err = h.codeSvc.SendEmail(ctx, bizResetPassword, req.Target)
+	} else {
+		err = h.codeSvc.Send(ctx, bizResetPassword, req.Target)
+	}
+	switch err {
+	case nil:
+		return ginx.Result{
+			Msg: "发送成功",
+		}, nil
+	case service.ErrCodeSendTooMany:
+		return ginx.Result{
+			Code: errs.UserInvalidInput,
+			Msg:  "验证码发送太频繁",
+		}, nil
+	default:
+		return ginx.Result{
+			Code: errs.UserInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+}
+
+type ResetPasswordReq struct {
+	Target          string `json:"target"`
+	Code            string `json:"code"`
+	Password        string `json:"password"`
+	ConfirmPassword string `json:"confirmPassword"`
+}
+
+func (h *UserHandler) ResetPassword(ctx *gin.Context, req ResetPasswordReq) (ginx.Result, error) {
+	res, err := h.checkNewPassword(req.Password, req.ConfirmPassword)
+	if err != nil || res.Code != 0 {
+		return res, err
+	}
+	ok, err := h.codeSvc.Verify(ctx, bizResetPassword, req.Target, req.Code)
+	if err != nil {
+		return ginx.Result{
+			Code: errs.UserInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+	if !ok {
+		return ginx.Result{
+			Code: errs.UserInvalidInput,
+			Msg:  "验证码不对，请重新输入",
+		}, nil
+	}
+	isEmail, err := h.emailExp.MatchString(req.Target)
+	if err != nil {
+		return ginx.Result{
+			Code: errs.UserInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+	uid, err := h.svc.ResetPassword(ctx.Request.Context(), req.Target, isEmail, req.Password)
+	switch err {
+	case nil:
+	case service.ErrUserNotFound:
+		return ginx.Result{
+			Code: errs.UserInvalidInput,
+			Msg:  "用户不存在",
+		}, nil
+	default:
+		return ginx.Result{
+			Code: errs.UserInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+	// 密码已经改了，登录态清理失败也要告诉用户
+	err = h.ClearSessions(ctx, uid)
+	if err != nil {
+		return ginx.Result{
+			Code: errs.UserInternalServerError,
+			Msg:  "密码已经重置，但是退出其它设备失败",
+		}, err
+	}
+	return ginx.Result{
+		Msg: "OK",
+	}, nil
+}
+
+func (h *UserHandler) checkNewPassword(password string, confirm string) (ginx.Result, error) {
+	if password != confirm {
+		return ginx.Result{
+			Code: errs.UserInvalidInput,
+			Msg:  "两次输入的密码不相等",
+		}, nil
+	}
+	ok, err := h.passwordExp.MatchString(password)
+	if err != nil {
+		return ginx.Result{
+			Code: errs.UserInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+	if !ok {
+		return ginx.Result{
+			Code: errs.UserInvalidInput,
+			Msg:  "密码必须包含字母、数字、特殊字符",
+		}, nil
+	}
+	return ginx.Result{}, nil
+}
+
+type SessionVO struct {
+	Ssid      string `json:"ssid"`
+	UserAgent string `json:"userAgent"`
+	IP        string `json:"ip"`
+	Method    string `json:"method"`
+	Ctime     string `json:"ctime"`
+	LastSeen  string `json:"lastSeen"`
+	// Current 是不是发起这次请求的会话
+	Current bool `json:"current"`
+}
+
+func (h *UserHandler) ListSessions(ctx *gin.Context, uc ijwt.UserClaims) (ginx.Result, error) {
+	sessions, err := h.Handler.ListSessions(ctx, uc.Uid)
+	if err != nil {
+		return ginx.Result{
+			Code: errs.UserInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+	return ginx.Result{
+		Data: slice.Map(sessions, func(idx int, src ijwt.Session) SessionVO {
+			return SessionVO{
+				Ssid:      src.Ssid,
+				UserAgent: src.UserAgent,
+				IP:        src.IP,
+				Method:    src.Method,
+				Ctime:     src.Ctime.Format(time.DateTime),
+				LastSeen:  src.LastSeen.Format(time.DateTime),
+				Current:   src.Ssid == uc.Ssid,
+			}
+		}),
+	}, nil
+}
+
+type RevokeSessionReq struct {
+	Ssid string `json:"ssid"`
+}
+
+// RevokeSession 注销当前会话相当于退出登录
+func (h *UserHandler) RevokeSession(ctx *gin.Context, req RevokeSessionReq, uc ijwt.UserClaims) (ginx.Result, error) {
+	err := h.Handler.RevokeSession(ctx, uc.Uid, req.Ssid)
+	switch err {
+	case nil:
+		return ginx.Result{
+			Msg: "OK",
+		}, nil
+	case ijwt.ErrSessionNotFound:
+		return ginx.Result{
+			Code: errs.UserInvalidInput,
+			Msg:  "会话不存在",
+		}, nil
+	default:
+		return ginx.Result{
+			Code: errs.UserInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+}
+
+func (h *UserHandler) RevokeOtherSessions(ctx *gin.Context, uc ijwt.UserClaims) (ginx.Result, error) {
+	err := h.Handler.RevokeOtherSessions(ctx, uc.Uid, uc.Ssid)
+	if err != nil {
+		return ginx.Result{
+			Code: errs.UserInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+	return ginx.Result{
+		Msg: "OK",
+	}, nil
+}
@@ -0,0 +1,176 @@
+package web
+
+import (
+	"github.com/gin-gonic/gin"
+	"webook/internal/errs"
+	"webook/internal/service"
+	ijwt "webook/internal/web/jwt"
+	"webook/pkg/ginx"
+)
+
+// MFALoginVO 密码正确但是还需要两步验证，拿 MFAToken 和验证码调用 /users/login/mfa
+type MFALoginVO struct {
+	MFARequired bool   `json:"mfaRequired"`
+	MFAToken    string `json:"mfaToken"`
+}
+
+func (h *UserHandler) beginMFALogin(ctx *gin.Context, uid int64) (ginx.Result, error) {
+	token, err := h.mfaSvc.BeginLogin(ctx.Request.Context(), uid)
+	if err != nil {
+		return ginx.Result{
+			Code: 5,
+			Msg:  "系统错误",
+		}, err
+	}
+	return ginx.Result{
+		Msg: "需要两步验证",
+		Data: MFALoginVO{
+			MFARequired: true,
+			MFAToken:    token,
+		},
+	}, nil
+}
+
+type LoginMFAReq struct {
+	MFAToken string `json:"mfaToken"`
+	// Code 验证器 App 上的验证码，或者恢复码
+	Code string `json:"code"`
+}
+
+func (h *UserHandler) LoginMFA(ctx *gin.Context, req LoginMFAReq) (ginx.Result, error) {
+	uid, err := h.mfaSvc.CompleteLogin(ctx.Request.Context(), req.MFAToken, req.Code)
+	switch err {
+	case nil:
+	case service.ErrInvalidMFACode:
+		return ginx.Result{
+			Code: errs.UserInvalidInput,
+			Msg:  "验证码不对",
+		}, nil
+	case service.ErrMFALoginExpired, service.ErrMFATooManyAttempts:
+		return ginx.Result{
+			Code: errs.UserInvalidInput,
+			Msg:  "登录已经失效，请重新输入密码",
+		}, nil
+	case service.ErrMFALocked:
+		return ginx.Result{
+			Code: errs.UserInvalidInput,
+			Msg:  "验证失败次数太多，请稍后再试",
+		}, nil
+	default:
+		return ginx.Result{
+			Code: errs.UserInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+	err = h.SetLoginToken(ctx, uid, ijwt.LoginMethodPasswordMFA)
+	if err != nil {
+		return ginx.Result{
+			Code: errs.UserInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+	return ginx.Result{
+		Msg: "OK",
+	}, nil
+}
+
+type MFAEnrollVO struct {
+	// Secret 没办法扫码的时候手动输入
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+func (h *UserHandler) EnrollMFA(ctx *gin.Context, uc ijwt.UserClaims) (ginx.Result, error) {
+	secret, uri, err := h.mfaSvc.Enroll(ctx.Request.Context(), uc.Uid)
+	switch err {
+	case nil:
+		return ginx.Result{
+			Data: MFAEnrollVO{Secret: secret, URI: uri},
+		}, nil
+	case service.ErrMFAAlreadyEnabled:
+		return ginx.Result{
+			Code: errs.UserInvalidInput,
+			Msg:  "已经开启了两步验证",
+		}, nil
+	default:
+		return ginx.Result{
+			Code: errs.UserInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+}
+
+type ConfirmMFAReq struct {
+	Code string `json:"code"`
+}
+
+// ConfirmMFA 返回的恢复码只有这一次能看到
+func (h *UserHandler) ConfirmMFA(ctx *gin.Context, req ConfirmMFAReq, uc ijwt.UserClaims) (ginx.Result, error) {
+	codes, err := h.mfaSvc.Confirm(ctx.Request.Context(), uc.Uid, req.Code)
+	switch err {
+	case nil:
+		return ginx.Result{
+			Data: codes,
+		}, nil
+	case service.ErrInvalidMFACode:
+		return ginx.Result{
+			Code: errs.UserInvalidInput,
+			Msg:  "验证码不对",
+		}, nil
+	case service.ErrMFANotEnabled:
+		return ginx.Result{
+			Code: errs.UserInvalidInput,
+			Msg:  "请先生成密钥",
+		}, nil
+	case service.ErrMFAAlreadyEnabled:
+		return ginx.Result{
+			Code: errs.UserInvalidInput,
+			Msg:  "已经开启了两步验证",
+		}, nil
+	default:
+		return ginx.Result{
+			Code: errs.UserInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+}
+
+type DisableMFAReq struct {
+	Password string `json:"password"`
+	Code     string `json:"code"`
+}
+
+func (h *UserHandler) DisableMFA(ctx *gin.Context, req DisableMFAReq, uc ijwt.UserClaims) (ginx.Result, error) {
+	err := h.mfaSvc.Disable(ctx.Request.Context(), uc.Uid, req.Password, req.Code)
+	switch err {
+	case nil:
+		return ginx.Result{
+			Msg: "OK",
+		}, nil
+	case service.ErrInvalidUserOrPassword:
+		return ginx.Result{
+			Code: errs.UserInvalidOrPassword,
+			Msg:  "密码不对",
+		}, nil
+	case service.ErrInvalidMFACode:
+		return ginx.Result{
+			Code: errs.UserInvalidInput,
+			Msg:  "验证码不对",
+		}, nil
+	case service.ErrMFANotEnabled:
+		return ginx.Result{
+			Code: errs.UserInvalidInput,
+			Msg:  "没有开启两步验证",
+		}, nil
+	case service.ErrMFALocked:
+		return ginx.Result{
+			Code: errs.UserInvalidInput,
+			Msg:  "验证失败次数太多，请稍后再试",
+		}, nil
+	default:
+		return ginx.Result{
+			Code: errs.UserInternalServerError,
+			Msg:  "系统错误",
+		}, err
+	}
+}
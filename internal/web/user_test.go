@@ -2,15 +2,30 @@ package web
 
 import (
 	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"github.com/alicebob/miniredis/v2"
 	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 	"webook/internal/domain"
+	"webook/internal/errs"
+	"webook/internal/middleware"
 	"webook/internal/service"
 	svcmocks "webook/internal/service/mocks"
+	ijwt "webook/internal/web/jwt"
+	jwtmocks "webook/internal/web/jwt/mocks"
+	"webook/pkg/ginx"
+	"webook/pkg/jwtx"
+	"webook/pkg/logger"
 )
 
 func TestUserHandler_SignUp(t *testing.T) {
@@ -69,7 +84,7 @@ func TestUserHandler_SignUp(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
 			userSvc, codeSvc := tc.mock(ctrl)
-			handler := NewUserHandler(userSvc, codeSvc, nil)
+			handler := NewUserHandler(userSvc, codeSvc, nil, nil, logger.NewNopLogger())
 			server := gin.Default()
 			handler.RegisterRoutes(server)
 			req := tc.reqBuilder(t)
@@ -80,3 +95,301 @@ func TestUserHandler_SignUp(t *testing.T) {
 		})
 	}
 }
+
+func TestUserHandler_ChangePassword(t *testing.T) {
+	testCases := []struct {
+		name    string
+		mock    func(ctrl *gomock.Controller) service.UserService
+		req     ChangePasswordReq
+		wantRes ginx.Result
+	}{
+		{
+			name: "修改成功",
+			mock: func(ctrl *gomock.Controller) service.UserService {
+				userSvc := svcmocks.NewMockUserService(ctrl)
+				userSvc.EXPECT().ChangePassword(gomock.Any(), int64(1), "old#123abc", "new#123abc").Return(nil)
+				return userSvc
+			},
+			req:     ChangePasswordReq{OldPassword: "old#123abc", Password: "new#123abc", ConfirmPassword: "new#123abc"},
+			wantRes: ginx.Result{Msg: "OK"},
+		},
+		{
+			name: "旧密码不对",
+			mock: func(ctrl *gomock.Controller) service.UserService {
+				userSvc := svcmocks.NewMockUserService(ctrl)
+				userSvc.EXPECT().ChangePassword(gomock.Any(), int64(1), "old#123abc", "new#123abc").
+					Return(service.ErrInvalidUserOrPassword)
+				return userSvc
+			},
+			req:     ChangePasswordReq{OldPassword: "old#123abc", Password: "new#123abc", ConfirmPassword: "new#123abc"},
+			wantRes: ginx.Result{Code: errs.UserInvalidOrPassword, Msg: "旧密码不对"},
+		},
+		{
+			name: "两次密码不一样",
+			mock: func(ctrl *gomock.Controller) service.UserService {
+				return svcmocks.NewMockUserService(ctrl)
+			},
+			req:     ChangePasswordReq{OldPassword: "old#123abc", Password: "new#123abc", ConfirmPassword: "new#123abd"},
+			wantRes: ginx.Result{Code: errs.UserInvalidInput, Msg: "两次输入的密码不相等"},
+		},
+		{
+			name: "新密码太简单",
+			mock: func(ctrl *gomock.Controller) service.UserService {
+				return svcmocks.NewMockUserService(ctrl)
+			},
+			req:     ChangePasswordReq{OldPassword: "old#123abc", Password: "12345678", ConfirmPassword: "12345678"},
+			wantRes: ginx.Result{Code: errs.UserInvalidInput, Msg: "密码必须包含字母、数字、特殊字符"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			handler := NewUserHandler(tc.mock(ctrl), nil, nil, nil, logger.NewNopLogger())
+			ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+			ctx.Request = httptest.NewRequest(http.MethodPost, "/users/password/change", nil)
+			res, err := handler.ChangePassword(ctx, tc.req, ijwt.UserClaims{Uid: 1})
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantRes, res)
+		})
+	}
+}
+
+// stubCodeService CodeService 有未导出的方法，mock 出来的实现不了，
+// 嵌入接口，用到的方法转给 mock
+type stubCodeService struct {
+	service.CodeService
+	mock *svcmocks.MockCodeService
+}
+
+func (s stubCodeService) Verify(ctx context.Context, biz, phone, inputCode string) (bool, error) {
+	return s.mock.Verify(ctx, biz, phone, inputCode)
+}
+
+func TestUserHandler_ResetPassword(t *testing.T) {
+	testCases := []struct {
+		name    string
+		mock    func(ctrl *gomock.Controller) (service.UserService, service.CodeService, ijwt.Handler)
+		req     ResetPasswordReq
+		wantRes ginx.Result
+	}{
+		{
+			name: "邮箱重置",
+			mock: func(ctrl *gomock.Controller) (service.UserService, service.CodeService, ijwt.Handler) {
+				userSvc := svcmocks.NewMockUserService(ctrl)
+				codeSvc := svcmocks.NewMockCodeService(ctrl)
+				jwtHdl := jwtmocks.NewMockHandler(ctrl)
+				codeSvc.EXPECT().Verify(gomock.Any(), "reset_password", "a@qq.com", "123456").Return(true, nil)
+				userSvc.EXPECT().ResetPassword(gomock.Any(), "a@qq.com", true, "new#123abc").Return(int64(1), nil)
+				jwtHdl.EXPECT().ClearSessions(gomock.Any(), int64(1)).Return(nil)
+				return userSvc, stubCodeService{mock: codeSvc}, jwtHdl
+			},
+			req:     ResetPasswordReq{Target: "a@qq.com", Code: "123456", Password: "new#123abc", ConfirmPassword: "new#123abc"},
+			wantRes: ginx.Result{Msg: "OK"},
+		},
+		{
+			name: "手机号重置",
+			mock: func(ctrl *gomock.Controller) (service.UserService, service.CodeService, ijwt.Handler) {
+				userSvc := svcmocks.NewMockUserService(ctrl)
+				codeSvc := svcmocks.NewMockCodeService(ctrl)
+				jwtHdl := jwtmocks.NewMockHandler(ctrl)
+				codeSvc.EXPECT().Verify(gomock.Any(), "reset_password", "13800138000", "123456").Return(true, nil)
+				userSvc.EXPECT().ResetPassword(gomock.Any(), "13800138000", false, "new#123abc").Return(int64(1), nil)
+				jwtHdl.EXPECT().ClearSessions(gomock.Any(), int64(1)).Return(nil)
+				return userSvc, stubCodeService{mock: codeSvc}, jwtHdl
+			},
+			req:     ResetPasswordReq{Target: "13800138000", Code: "123456", Password: "new#123abc", ConfirmPassword: "new#123abc"},
+			wantRes: ginx.Result{Msg: "OK"},
+		},
+		{
+			// 带 @ 但不是合法的邮箱，和发验证码的时候一样当成手机号
+			name: "不是邮箱",
+			mock: func(ctrl *gomock.Controller) (service.UserService, service.CodeService, ijwt.Handler) {
+				userSvc := svcmocks.NewMockUserService(ctrl)
+				codeSvc := svcmocks.NewMockCodeService(ctrl)
+				codeSvc.EXPECT().Verify(gomock.Any(), "reset_password", "a@b", "123456").Return(true, nil)
+				userSvc.EXPECT().ResetPassword(gomock.Any(), "a@b", false, "new#123abc").
+					Return(int64(0), service.ErrUserNotFound)
+				return userSvc, stubCodeService{mock: codeSvc}, nil
+			},
+			req:     ResetPasswordReq{Target: "a@b", Code: "123456", Password: "new#123abc", ConfirmPassword: "new#123abc"},
+			wantRes: ginx.Result{Code: errs.UserInvalidInput, Msg: "用户不存在"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			userSvc, codeSvc, jwtHdl := tc.mock(ctrl)
+			handler := NewUserHandler(userSvc, codeSvc, nil, jwtHdl, logger.NewNopLogger())
+			ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+			ctx.Request = httptest.NewRequest(http.MethodPost, "/users/password/reset", nil)
+			res, err := handler.ResetPassword(ctx, tc.req)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantRes, res)
+		})
+	}
+}
+
+func TestUserHandler_Sessions(t *testing.T) {
+	now := time.UnixMilli(1700000000000)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	jwtHdl := jwtmocks.NewMockHandler(ctrl)
+	handler := NewUserHandler(nil, nil, nil, jwtHdl, logger.NewNopLogger())
+	uc := ijwt.UserClaims{Uid: 1, Ssid: "b"}
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+
+	jwtHdl.EXPECT().ListSessions(gomock.Any(), int64(1)).Return([]ijwt.Session{
+		{Ssid: "a", Uid: 1, IP: "127.0.0.1", Method: ijwt.LoginMethodSMS, Ctime: now, LastSeen: now},
+		{Ssid: "b", Uid: 1, UserAgent: "curl", Method: ijwt.LoginMethodPassword, Ctime: now, LastSeen: now},
+	}, nil)
+	res, err := handler.ListSessions(ctx, uc)
+	assert.NoError(t, err)
+	assert.Equal(t, []SessionVO{
+		{Ssid: "a", IP: "127.0.0.1", Method: "sms",
+			Ctime: now.Format(time.DateTime), LastSeen: now.Format(time.DateTime)},
+		{Ssid: "b", UserAgent: "curl", Method: "password",
+			Ctime: now.Format(time.DateTime), LastSeen: now.Format(time.DateTime), Current: true},
+	}, res.Data)
+
+	jwtHdl.EXPECT().RevokeSession(gomock.Any(), int64(1), "c").Return(ijwt.ErrSessionNotFound)
+	res, err = handler.RevokeSession(ctx, RevokeSessionReq{Ssid: "c"}, uc)
+	assert.NoError(t, err)
+	assert.Equal(t, ginx.Result{Code: errs.UserInvalidInput, Msg: "会话不存在"}, res)
+
+	jwtHdl.EXPECT().RevokeOtherSessions(gomock.Any(), int64(1), "b").Return(nil)
+	res, err = handler.RevokeOtherSessions(ctx, uc)
+	assert.NoError(t, err)
+	assert.Equal(t, ginx.Result{Msg: "OK"}, res)
+}
+
+func TestUserHandler_LoginMFA(t *testing.T) {
+	testCases := []struct {
+		name    string
+		mock    func(ctrl *gomock.Controller) (service.UserMFAService, ijwt.Handler)
+		req     LoginMFAReq
+		wantRes ginx.Result
+		wantErr error
+	}{
+		{
+			name: "登录成功",
+			mock: func(ctrl *gomock.Controller) (service.UserMFAService, ijwt.Handler) {
+				mfaSvc := svcmocks.NewMockUserMFAService(ctrl)
+				mfaSvc.EXPECT().CompleteLogin(gomock.Any(), "token", "123456").Return(int64(1), nil)
+				jwtHdl := jwtmocks.NewMockHandler(ctrl)
+				jwtHdl.EXPECT().SetLoginToken(gomock.Any(), int64(1), ijwt.LoginMethodPasswordMFA).Return(nil)
+				return mfaSvc, jwtHdl
+			},
+			req:     LoginMFAReq{MFAToken: "token", Code: "123456"},
+			wantRes: ginx.Result{Msg: "OK"},
+		},
+		{
+			name: "验证码不对",
+			mock: func(ctrl *gomock.Controller) (service.UserMFAService, ijwt.Handler) {
+				mfaSvc := svcmocks.NewMockUserMFAService(ctrl)
+				mfaSvc.EXPECT().CompleteLogin(gomock.Any(), "token", "123456").
+					Return(int64(0), service.ErrInvalidMFACode)
+				return mfaSvc, jwtmocks.NewMockHandler(ctrl)
+			},
+			req:     LoginMFAReq{MFAToken: "token", Code: "123456"},
+			wantRes: ginx.Result{Code: errs.UserInvalidInput, Msg: "验证码不对"},
+		},
+		{
+			name: "尝试次数太多",
+			mock: func(ctrl *gomock.Controller) (service.UserMFAService, ijwt.Handler) {
+				mfaSvc := svcmocks.NewMockUserMFAService(ctrl)
+				mfaSvc.EXPECT().CompleteLogin(gomock.Any(), "token", "123456").
+					Return(int64(0), service.ErrMFATooManyAttempts)
+				return mfaSvc, jwtmocks.NewMockHandler(ctrl)
+			},
+			req:     LoginMFAReq{MFAToken: "token", Code: "123456"},
+			wantRes: ginx.Result{Code: errs.UserInvalidInput, Msg: "登录已经失效，请重新输入密码"},
+		},
+		{
+			name: "系统错误",
+			mock: func(ctrl *gomock.Controller) (service.UserMFAService, ijwt.Handler) {
+				mfaSvc := svcmocks.NewMockUserMFAService(ctrl)
+				mfaSvc.EXPECT().CompleteLogin(gomock.Any(), "token", "123456").
+					Return(int64(0), errors.New("mock error"))
+				return mfaSvc, jwtmocks.NewMockHandler(ctrl)
+			},
+			req:     LoginMFAReq{MFAToken: "token", Code: "123456"},
+			wantRes: ginx.Result{Code: errs.UserInternalServerError, Msg: "系统错误"},
+			wantErr: errors.New("mock error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mfaSvc, jwtHdl := tc.mock(ctrl)
+			handler := NewUserHandler(nil, nil, mfaSvc, jwtHdl, logger.NewNopLogger())
+			ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+			ctx.Request = httptest.NewRequest(http.MethodPost, "/users/login/mfa", nil)
+			res, err := handler.LoginMFA(ctx, tc.req)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantRes, res)
+		})
+	}
+}
+
+// TestUserHandler_RefreshToken 走真正的登录校验中间件，refresh token 轮换之后旧的再用一次整个会话注销
+func TestUserHandler_RefreshToken(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	hdl := ijwt.NewRedisJWTHandler(client, newTestKeySet(t, "access"), newTestKeySet(t, "refresh"))
+
+	server := gin.New()
+	server.Use(middleware.NewLoginJWTMiddlewareBuilder(hdl).CheckLogin())
+	NewUserHandler(nil, nil, nil, hdl, logger.NewNopLogger()).RegisterRoutes(server)
+	refresh := func(token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/users/refresh_token", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		recorder := httptest.NewRecorder()
+		server.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	// 登录时拿到的 refresh token
+	recorder := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(recorder)
+	ctx.Request = httptest.NewRequest(http.MethodPost, "/users/login", nil)
+	require.NoError(t, hdl.SetLoginToken(ctx, 1, ijwt.LoginMethodPassword))
+	oldToken := recorder.Header().Get("x-refresh-token")
+	oldRc, err := hdl.ParseRefreshToken(oldToken)
+	require.NoError(t, err)
+	ssid := oldRc.Ssid
+
+	// 第一次刷新，换成新的 refresh token
+	resp := refresh(oldToken)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.NotEmpty(t, resp.Header().Get("x-jwt-token"))
+	newToken := resp.Header().Get("x-refresh-token")
+	require.NotEqual(t, oldToken, newToken)
+	rc, err := hdl.ParseRefreshToken(newToken)
+	require.NoError(t, err)
+	assert.Equal(t, ssid, rc.Ssid)
+	assert.Equal(t, rc.ID, mr.HGet("users:session:"+ssid, "refresh_jti"))
+
+	// 旧的又被拿来用，整个会话注销
+	resp = refresh(oldToken)
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+	assert.Empty(t, resp.Header().Get("x-refresh-token"))
+	assert.False(t, mr.Exists("users:session:"+ssid))
+	assert.True(t, mr.Exists("users:ssid:"+ssid))
+
+	// 会话已经没了，新的那个也不能用了
+	resp = refresh(newToken)
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+}
+
+func newTestKeySet(t *testing.T, kid string) *jwtx.KeySet {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	key, err := jwtx.NewKey(kid, private)
+	require.NoError(t, err)
+	ks, err := jwtx.NewKeySet(kid, key)
+	require.NoError(t, err)
+	return ks
+}
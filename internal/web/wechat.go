@@ -84,7 +84,7 @@ func (h *OAuth2WechatHandler) Callback(ctx *gin.Context) {
 		})
 		return
 	}
-	err = h.SetLoginToken(ctx, u.Id)
+	err = h.SetLoginToken(ctx, u.Id, ijwt.LoginMethodWechat)
 	if err != nil {
 		ctx.JSON(http.StatusOK, Result{
 			Msg:  "系统错误",
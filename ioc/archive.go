@@ -0,0 +1,48 @@
+package ioc
+
+import (
+	"github.com/redis/go-redis/v9"
+	"github.com/spf13/viper"
+	"time"
+	"webook/internal/repository"
+	"webook/internal/service"
+	"webook/internal/web"
+	"webook/pkg/blobx"
+	"webook/pkg/limiter"
+	"webook/pkg/logger"
+)
+
+type archiveConfig struct {
+	// Key 下载链接的签名密钥，修改之后已经发出去的链接全部失效
+	Key string `yaml:"key"`
+	// MaxSize 导入的压缩包最大的字节数
+	MaxSize int64 `yaml:"maxSize"`
+	// ExportPerHour 每个用户每小时最多导出的次数
+	ExportPerHour int `yaml:"exportPerHour"`
+}
+
+func loadArchiveConfig() archiveConfig {
+	cfg := archiveConfig{
+		MaxSize:       50 << 20,
+		ExportPerHour: 3,
+	}
+	err := viper.UnmarshalKey("archive", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	return cfg
+}
+
+func InitArchiveService(repo repository.ArchiveTaskRepository, artRepo repository.ArticleRepository,
+	artSvc service.ArticleService, store blobx.BlobStore, client redis.Cmdable, l logger.LoggerV1) service.ArchiveService {
+	cfg := loadArchiveConfig()
+	if len(cfg.Key) < 32 {
+		panic("压缩包下载链接的签名密钥至少 32 个字符")
+	}
+	exportLimiter := limiter.NewRedisSlidingWindowLimiter(client, time.Hour, cfg.ExportPerHour)
+	return service.NewArchiveService(repo, artRepo, artSvc, store, []byte(cfg.Key), cfg.MaxSize, exportLimiter, l)
+}
+
+func InitArchiveHandler(svc service.ArchiveService, l logger.LoggerV1) *web.ArchiveHandler {
+	return web.NewArchiveHandler(svc, loadArchiveConfig().MaxSize, l)
+}
@@ -0,0 +1,79 @@
+package ioc
+
+import (
+	"context"
+	"github.com/redis/go-redis/v9"
+	"github.com/spf13/viper"
+	"time"
+	"webook/internal/domain"
+	"webook/internal/repository"
+	"webook/internal/repository/cache"
+	"webook/pkg/cachex"
+	"webook/pkg/logger"
+)
+
+type articleCacheConfig struct {
+	LocalSize   int           `yaml:"localSize"`
+	LocalTTL    time.Duration `yaml:"localTTL"`
+	TTL         time.Duration `yaml:"ttl"`
+	NotFoundTTL time.Duration `yaml:"notFoundTTL"`
+	Jitter      float64       `yaml:"jitter"`
+	Bloom       struct {
+		// Expected 预计的文章数量，超过之后误判率会上升
+		Expected uint64  `yaml:"expected"`
+		FPRate   float64 `yaml:"fpRate"`
+	} `yaml:"bloom"`
+}
+
+// InitArticlePubCache 线上库文章详情的多级缓存，启动的时候开始监听其它实例的删除通知
+func InitArticlePubCache(client redis.Cmdable, l logger.LoggerV1) cache.ArticlePubCache {
+	cfg := articleCacheConfig{
+		LocalSize:   10000,
+		LocalTTL:    time.Minute,
+		TTL:         10 * time.Minute,
+		NotFoundTTL: time.Minute,
+		Jitter:      0.1,
+	}
+	cfg.Bloom.Expected = 1000000
+	cfg.Bloom.FPRate = 0.01
+	err := viper.UnmarshalKey("articleCache", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	uc, ok := client.(redis.UniversalClient)
+	if !ok {
+		panic("文章缓存需要订阅 Redis 频道")
+	}
+	mlc := cachex.NewMultiLevelCache[domain.Article](uc, cachex.Config{
+		Prefix:      "article:pub:detail:",
+		Channel:     "article:pub:invalidate",
+		LocalSize:   cfg.LocalSize,
+		LocalTTL:    cfg.LocalTTL,
+		TTL:         cfg.TTL,
+		NotFoundTTL: cfg.NotFoundTTL,
+		Jitter:      cfg.Jitter,
+		ErrNotFound: repository.ErrArticleNotFound,
+	})
+	go subscribeArticleCache(mlc, l)
+	// hash tag 保证重建用的 key 和正式的 key 在同一个 slot
+	bloom := cachex.NewRedisBloomFilter(client, "{article:pub:bloom}", cfg.Bloom.Expected, cfg.Bloom.FPRate)
+	return cache.NewArticlePubCache(mlc, bloom)
+}
+
+// subscribeArticleCache 订阅断掉之后一直重试，不然这个实例的本地缓存就只能等过期了。
+// 重试间隔从一秒开始翻倍，最多一分钟，订阅正常跑过一段时间之后重新从一秒开始
+func subscribeArticleCache(mlc *cachex.MultiLevelCache[domain.Article], l logger.LoggerV1) {
+	const maxInterval = time.Minute
+	interval := time.Second
+	for {
+		start := time.Now()
+		err := mlc.Subscribe(context.Background())
+		if time.Since(start) > maxInterval {
+			interval = time.Second
+		}
+		l.Error("监听文章缓存失效通知失败，稍后重试", logger.Error(err),
+			logger.String("interval", interval.String()))
+		time.Sleep(interval)
+		interval = min(interval*2, maxInterval)
+	}
+}
@@ -0,0 +1,61 @@
+package ioc
+
+import (
+	"github.com/spf13/viper"
+	"net/http"
+	"time"
+	"webook/internal/repository"
+	"webook/internal/service"
+	"webook/internal/web"
+	"webook/pkg/blobx"
+	"webook/pkg/logger"
+)
+
+type blobConfig struct {
+	// Driver local 或者 s3
+	Driver string `yaml:"driver"`
+	// MaxSize 单个文件最大的字节数
+	MaxSize int64 `yaml:"maxSize"`
+	Local   struct {
+		Dir string `yaml:"dir"`
+	} `yaml:"local"`
+	S3 blobx.S3Config `yaml:"s3"`
+}
+
+func loadBlobConfig() blobConfig {
+	cfg := blobConfig{
+		Driver:  "local",
+		MaxSize: 10 << 20,
+	}
+	cfg.Local.Dir = "data/blobs"
+	err := viper.UnmarshalKey("blob", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	return cfg
+}
+
+func InitBlobStore() blobx.BlobStore {
+	cfg := loadBlobConfig()
+	switch cfg.Driver {
+	case "local":
+		store, err := blobx.NewLocalStore(cfg.Local.Dir)
+		if err != nil {
+			panic(err)
+		}
+		return store
+	case "s3":
+		return blobx.NewS3Store(cfg.S3, &http.Client{Timeout: time.Minute})
+	default:
+		panic("不支持的 blob driver " + cfg.Driver)
+	}
+}
+
+func InitBlobService(repo repository.BlobRepository, artRepo repository.ArticleRepository,
+	store blobx.BlobStore, l logger.LoggerV1) service.BlobService {
+	return service.NewBlobService(repo, artRepo, store, loadBlobConfig().MaxSize, l)
+}
+
+func InitBlobHandler(svc service.BlobService, l logger.LoggerV1) *web.BlobHandler {
+	return web.NewBlobHandler(svc, loadBlobConfig().MaxSize, l)
+}
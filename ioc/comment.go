@@ -0,0 +1,38 @@
+package ioc
+
+import (
+	"github.com/spf13/viper"
+	etcdv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/client/v3/naming/resolver"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	commentv1 "webook/api/proto/gen/comment/v1"
+	client2 "webook/internal/client"
+)
+
+// InitCommentClient 评论服务和 interactive 部署在一起
+func InitCommentClient(client *etcdv3.Client) commentv1.CommentServiceClient {
+	type Config struct {
+		Addr   string `yaml:"addr"`
+		Secure bool
+	}
+	var cfg Config
+	err := viper.UnmarshalKey("grpc.client.comment", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	etcdResolver, err := resolver.NewBuilder(client)
+	if err != nil {
+		panic(err)
+	}
+	opts := []grpc.DialOption{grpc.WithResolvers(etcdResolver),
+		grpc.WithUnaryInterceptor(client2.ForwardTokenInterceptor())}
+	if !cfg.Secure {
+		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	}
+	cc, err := grpc.Dial(cfg.Addr, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return commentv1.NewCommentServiceClient(cc)
+}
@@ -0,0 +1,10 @@
+package ioc
+
+import (
+	"webook/internal/service/email"
+	"webook/internal/service/email/local"
+)
+
+func InitEmailService() email.Service {
+	return local.NewLocalEmailService()
+}
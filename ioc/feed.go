@@ -0,0 +1,29 @@
+package ioc
+
+import (
+	"github.com/spf13/viper"
+	"strings"
+	"webook/internal/repository"
+	"webook/internal/service"
+	"webook/pkg/logger"
+)
+
+func InitFeedService(artRepo repository.ArticleRepository, userRepo repository.UserRepository,
+	repo repository.FeedRepository, l logger.LoggerV1) service.FeedService {
+	type Config struct {
+		// SiteURL 订阅和 sitemap 里面链接的前缀
+		SiteURL string `yaml:"siteURL"`
+		Title   string `yaml:"title"`
+	}
+	cfg := Config{
+		Title: "webook",
+	}
+	err := viper.UnmarshalKey("feed", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	if cfg.SiteURL == "" {
+		panic("没有配置 feed.siteURL")
+	}
+	return service.NewFeedService(artRepo, userRepo, repo, strings.TrimSuffix(cfg.SiteURL, "/"), cfg.Title, l)
+}
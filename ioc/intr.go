@@ -25,7 +25,8 @@ func InitIntrClientV1(client *etcdv3.Client) intrv1.InteractiveServiceClient {
 		panic(err)
 	}
 	etcdResolver, err := resolver.NewBuilder(client)
-	opts := []grpc.DialOption{grpc.WithResolvers(etcdResolver)}
+	opts := []grpc.DialOption{grpc.WithResolvers(etcdResolver),
+		grpc.WithUnaryInterceptor(client2.ForwardTokenInterceptor())}
 	if !cfg.Secure {
 		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
 	}
@@ -49,7 +50,7 @@ func InitIntrClient(svc service.InteractiveService) intrv1.InteractiveServiceCli
 	if err != nil {
 		panic(err)
 	}
-	var opts []grpc.DialOption
+	opts := []grpc.DialOption{grpc.WithUnaryInterceptor(client2.ForwardTokenInterceptor())}
 	if !cfg.Secure {
 		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
 	}
@@ -1,10 +1,13 @@
 package ioc
 
 import (
+	"context"
 	rlock "github.com/gotomicro/redis-lock"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/robfig/cron/v3"
 	"time"
+	service2 "webook/interactive/service"
+	"webook/internal/domain"
 	"webook/internal/job"
 	"webook/internal/service"
 	"webook/pkg/logger"
@@ -35,3 +38,96 @@ func InitJobs(l logger.LoggerV1, rjob *job.RankingJob) *cron.Cron {
 	}
 	return expr
 }
+
+func InitLocalExecutor(artSvc service.ArticleService, evtSvc service.ArticleEventService,
+	blobSvc service.BlobService, intrSvc service2.InteractiveService, archiveSvc service.ArchiveService,
+	relatedSvc service.ArticleRelatedService, l logger.LoggerV1) *job.LocalExecutor {
+	executor := job.NewLocalExecutor()
+	executor.RegisterFunc(job.ScheduledPublishJobName, job.NewScheduledPublishFunc(artSvc, l, 100))
+	executor.RegisterFunc(job.ArticleTrashPurgeJobName, job.NewArticleTrashPurgeFunc(artSvc, l, 100))
+	executor.RegisterFunc(job.ArticleEventRelayJobName,
+		job.NewArticleEventRelayFunc(evtSvc, l, 100, 7*24*time.Hour))
+	executor.RegisterFunc(job.BlobGCJobName, job.NewBlobGCFunc(blobSvc, l, 100))
+	executor.RegisterFunc(job.InteractiveRollupJobName, job.NewInteractiveRollupFunc(intrSvc, l, 500))
+	executor.RegisterFunc(job.ArticlePubFilterJobName, job.NewArticlePubFilterFunc(artSvc, l, 1000))
+	executor.RegisterFunc(job.ArchiveJobName, job.NewArchiveFunc(archiveSvc, l, 10))
+	executor.RegisterFunc(job.ArticleRelatedJobName, job.NewArticleRelatedFunc(relatedSvc, l))
+	return executor
+}
+
+func InitScheduler(svc service.CronJobService, local *job.LocalExecutor, l logger.LoggerV1) *job.Scheduler {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	err := svc.AddJob(ctx, domain.Job{
+		Name:       job.ScheduledPublishJobName,
+		Executor:   local.Name(),
+		Expression: "@every 1m",
+	})
+	if err != nil {
+		panic(err)
+	}
+	err = svc.AddJob(ctx, domain.Job{
+		Name:       job.ArticleTrashPurgeJobName,
+		Executor:   local.Name(),
+		Expression: "@every 1h",
+	})
+	if err != nil {
+		panic(err)
+	}
+	// 事件投递的延迟取决于这里的间隔
+	err = svc.AddJob(ctx, domain.Job{
+		Name:       job.ArticleEventRelayJobName,
+		Executor:   local.Name(),
+		Expression: "@every 3s",
+	})
+	if err != nil {
+		panic(err)
+	}
+	err = svc.AddJob(ctx, domain.Job{
+		Name:       job.BlobGCJobName,
+		Executor:   local.Name(),
+		Expression: "@every 1h",
+	})
+	if err != nil {
+		panic(err)
+	}
+	// 每天 00:10 汇总前一天的 UV 和 PV
+	err = svc.AddJob(ctx, domain.Job{
+		Name:       job.InteractiveRollupJobName,
+		Executor:   local.Name(),
+		Expression: "0 10 0 * * *",
+	})
+	if err != nil {
+		panic(err)
+	}
+	// 第一次重建之前布隆过滤器不生效
+	err = svc.AddJob(ctx, domain.Job{
+		Name:       job.ArticlePubFilterJobName,
+		Executor:   local.Name(),
+		Expression: "@every 1h",
+	})
+	if err != nil {
+		panic(err)
+	}
+	// 导出导入是用户触发的，间隔短一点
+	err = svc.AddJob(ctx, domain.Job{
+		Name:       job.ArchiveJobName,
+		Executor:   local.Name(),
+		Expression: "@every 10s",
+	})
+	if err != nil {
+		panic(err)
+	}
+	// 相关推荐的缓存 48 小时过期，失败几次也还有数据
+	err = svc.AddJob(ctx, domain.Job{
+		Name:       job.ArticleRelatedJobName,
+		Executor:   local.Name(),
+		Expression: "@every 6h",
+	})
+	if err != nil {
+		panic(err)
+	}
+	scheduler := job.NewScheduler(svc, l)
+	scheduler.RegisterExecutor(local)
+	return scheduler
+}
@@ -0,0 +1,90 @@
+package ioc
+
+import (
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"github.com/spf13/viper"
+	"os"
+	"path/filepath"
+	ijwt "webook/internal/web/jwt"
+	"webook/pkg/jwtx"
+)
+
+type jwtKeyConfig struct {
+	Kid string `yaml:"kid"`
+	// Env 环境变量的名字，值是 PEM 格式的私钥或者公钥
+	Env string `yaml:"env"`
+	// PrivateKeyFile 和 PublicKeyFile 是挂载进来的 secret，必须是绝对路径。
+	// 只有公钥的只能用来验证，一般是轮换下来的旧密钥
+	PrivateKeyFile string `yaml:"privateKeyFile"`
+	PublicKeyFile  string `yaml:"publicKeyFile"`
+}
+
+type jwtKeySetConfig struct {
+	// SigningKid 用来签名的密钥，其它的只用来验证
+	SigningKid string         `yaml:"signingKid"`
+	Keys       []jwtKeyConfig `yaml:"keys"`
+}
+
+// InitJWTHandler 密钥只从环境变量或者 secret 读，所有实例要用同一套，不然互相不认对方签发的 token
+func InitJWTHandler(client redis.Cmdable) ijwt.Handler {
+	type Config struct {
+		Access  jwtKeySetConfig `yaml:"access"`
+		Refresh jwtKeySetConfig `yaml:"refresh"`
+	}
+	var cfg Config
+	err := viper.UnmarshalKey("jwt", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	for _, a := range cfg.Access.Keys {
+		for _, r := range cfg.Refresh.Keys {
+			if a.Kid == r.Kid {
+				panic(fmt.Sprintf("access token 和 refresh token 不能用同一个密钥 %s", a.Kid))
+			}
+		}
+	}
+	return ijwt.NewRedisJWTHandler(client, loadJWTKeySet(cfg.Access), loadJWTKeySet(cfg.Refresh))
+}
+
+func loadJWTKeySet(cfg jwtKeySetConfig) *jwtx.KeySet {
+	if len(cfg.Keys) == 0 {
+		panic("没有配置 JWT 的密钥")
+	}
+	keys := make([]jwtx.Key, 0, len(cfg.Keys))
+	for _, kc := range cfg.Keys {
+		key, err := jwtx.ParsePEM(kc.Kid, readJWTKey(kc))
+		if err != nil {
+			panic(fmt.Sprintf("解析 JWT 密钥 %s 失败 %v", kc.Kid, err))
+		}
+		keys = append(keys, key)
+	}
+	ks, err := jwtx.NewKeySet(cfg.SigningKid, keys...)
+	if err != nil {
+		panic(err)
+	}
+	return ks
+}
+
+func readJWTKey(kc jwtKeyConfig) []byte {
+	if kc.Env != "" {
+		val := os.Getenv(kc.Env)
+		if val == "" {
+			panic(fmt.Sprintf("环境变量 %s 里面没有 JWT 密钥 %s", kc.Env, kc.Kid))
+		}
+		return []byte(val)
+	}
+	path := kc.PrivateKeyFile
+	if path == "" {
+		path = kc.PublicKeyFile
+	}
+	// 相对路径一般是仓库里面的文件，密钥不能放在仓库里面
+	if !filepath.IsAbs(path) {
+		panic(fmt.Sprintf("JWT 密钥 %s 只能从环境变量或者挂载的 secret 读取", kc.Kid))
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
@@ -5,9 +5,18 @@ import (
 	"github.com/spf13/viper"
 	events2 "webook/interactive/events"
 	"webook/internal/events"
+	"webook/internal/events/blob"
+	"webook/internal/events/feed"
+	"webook/internal/events/search"
 )
 
 func InitSaramaClient() sarama.Client {
+	scfg := sarama.NewConfig()
+	scfg.Producer.Return.Successes = true
+	return newSaramaClient(scfg)
+}
+
+func newSaramaClient(scfg *sarama.Config) sarama.Client {
 	type Config struct {
 		Addr []string `yaml:"addr"`
 	}
@@ -16,8 +25,6 @@ func InitSaramaClient() sarama.Client {
 	if err != nil {
 		panic(err)
 	}
-	scfg := sarama.NewConfig()
-	scfg.Producer.Return.Successes = true
 	client, err := sarama.NewClient(cfg.Addr, scfg)
 	if err != nil {
 		panic(err)
@@ -33,6 +40,7 @@ func InitSyncProducer(c sarama.Client) sarama.SyncProducer {
 	return producer
 }
 
-func InitConsumers(c1 *events2.InteractiveReadEventConsumer) []events.Consumer {
-	return []events.Consumer{c1}
+func InitConsumers(c1 *events2.InteractiveReadEventConsumer, c2 *search.ArticleEventConsumer,
+	c3 *blob.ArticleEventConsumer, c4 *feed.ArticleEventConsumer) []events.Consumer {
+	return []events.Consumer{c1, c2, c3, c4}
 }
@@ -0,0 +1,41 @@
+package ioc
+
+import (
+	"github.com/spf13/viper"
+	"webook/internal/service"
+	"webook/internal/web"
+	"webook/pkg/logger"
+	"webook/pkg/sensitive"
+)
+
+type moderationConfig struct {
+	// Dictionaries 敏感词词典文件，修改之后自动重新加载
+	Dictionaries []string `yaml:"dictionaries"`
+	// Admins 可以审核文章的用户
+	Admins []int64 `yaml:"admins"`
+}
+
+func loadModerationConfig() moderationConfig {
+	var cfg moderationConfig
+	err := viper.UnmarshalKey("moderation", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	return cfg
+}
+
+func InitSensitiveMatcher(l logger.LoggerV1) sensitive.Matcher {
+	f, err := sensitive.NewFileFilter(loadModerationConfig().Dictionaries, l)
+	if err != nil {
+		panic(err)
+	}
+	err = f.Watch()
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+func InitReviewHandler(svc service.ArticleReviewService, l logger.LoggerV1) *web.ReviewHandler {
+	return web.NewReviewHandler(svc, loadModerationConfig().Admins, l)
+}
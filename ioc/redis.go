@@ -3,7 +3,9 @@ package ioc
 import (
 	rlock "github.com/gotomicro/redis-lock"
 	"github.com/redis/go-redis/v9"
+	"time"
 	"webook/config"
+	"webook/internal/repository/cache"
 )
 
 func InitRedis() redis.Cmdable {
@@ -15,3 +17,8 @@ func InitRedis() redis.Cmdable {
 func InitRlockClient(client redis.Cmdable) *rlock.Client {
 	return rlock.NewClient(client)
 }
+
+// InitRankingCache 热榜每分钟计算一次，过期时间留一点余量
+func InitRankingCache(client redis.Cmdable) cache.RankingCache {
+	return cache.NewRankingRedisCache(client, "ranking:top_n", 3*time.Minute)
+}
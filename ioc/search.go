@@ -0,0 +1,45 @@
+package ioc
+
+import (
+	"github.com/IBM/sarama"
+	"github.com/spf13/viper"
+	"webook/internal/events/search"
+	"webook/internal/repository"
+	"webook/internal/service"
+	"webook/pkg/logger"
+)
+
+type searchConfig struct {
+	// SnapshotPath 索引快照的位置，每个实例各自一份
+	SnapshotPath string `yaml:"snapshotPath"`
+	// ConsumerGroup 每个实例一个固定的消费者组，不能和别的实例重复，
+	// 重启之后不能变，不然会从头开始消费
+	ConsumerGroup string `yaml:"consumerGroup"`
+}
+
+func loadSearchConfig() searchConfig {
+	cfg := searchConfig{
+		SnapshotPath: "data/search/articles.snapshot",
+	}
+	err := viper.UnmarshalKey("search", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	return cfg
+}
+
+func InitSearchService(repo repository.ArticleRepository, l logger.LoggerV1) service.SearchService {
+	return service.NewLocalSearchService(repo, loadSearchConfig().SnapshotPath, l)
+}
+
+// InitSearchArticleEventConsumer 单独用一个 client，新的消费者组从最早的消息开始消费，
+// 重复消费也没关系，不会漏掉启动之前的消息
+func InitSearchArticleEventConsumer(svc service.SearchService, l logger.LoggerV1) *search.ArticleEventConsumer {
+	cfg := loadSearchConfig()
+	if cfg.ConsumerGroup == "" {
+		panic("没有配置搜索的消费者组")
+	}
+	scfg := sarama.NewConfig()
+	scfg.Consumer.Offsets.Initial = sarama.OffsetOldest
+	return search.NewArticleEventConsumer(svc, newSaramaClient(scfg), cfg.ConsumerGroup, l)
+}
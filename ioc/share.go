@@ -0,0 +1,24 @@
+package ioc
+
+import (
+	"github.com/spf13/viper"
+	"webook/internal/repository"
+	"webook/internal/service"
+	"webook/pkg/logger"
+)
+
+func InitShareService(repo repository.ShareLinkRepository, artRepo repository.ArticleRepository, l logger.LoggerV1) service.ShareService {
+	type Config struct {
+		// Key 分享链接的签名密钥，修改之后已经发出去的链接全部失效
+		Key string `yaml:"key"`
+	}
+	var cfg Config
+	err := viper.UnmarshalKey("share", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	if len(cfg.Key) < 32 {
+		panic("分享链接的签名密钥至少 32 个字符")
+	}
+	return service.NewShareService(repo, artRepo, []byte(cfg.Key), l)
+}
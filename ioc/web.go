@@ -19,12 +19,34 @@ import (
 func InitWebServer(mdls []gin.HandlerFunc,
 	userHdl *web.UserHandler,
 	wechatHdl *web.OAuth2WechatHandler,
-	articleHdl *web.ArticleHandler) *gin.Engine {
+	articleHdl *web.ArticleHandler,
+	searchHdl *web.SearchHandler,
+	shareHdl *web.ShareHandler,
+	seriesHdl *web.SeriesHandler,
+	blobHdl *web.BlobHandler,
+	commentHdl *web.CommentHandler,
+	reviewHdl *web.ReviewHandler,
+	notificationHdl *web.NotificationHandler,
+	feedHdl *web.FeedHandler,
+	archiveHdl *web.ArchiveHandler,
+	relatedHdl *web.ArticleRelatedHandler,
+	jwksHdl *web.JWKSHandler) *gin.Engine {
 	server := gin.Default()
 	server.Use(mdls...)
 	articleHdl.RegisterRoutes(server)
+	relatedHdl.RegisterRoutes(server)
+	searchHdl.RegisterRoutes(server)
+	shareHdl.RegisterRoutes(server)
+	seriesHdl.RegisterRoutes(server)
+	blobHdl.RegisterRoutes(server)
+	commentHdl.RegisterRoutes(server)
+	reviewHdl.RegisterRoutes(server)
+	notificationHdl.RegisterRoutes(server)
+	feedHdl.RegisterRoutes(server)
+	archiveHdl.RegisterRoutes(server)
 	userHdl.RegisterRoutes(server)
 	wechatHdl.RegisterRoutes(server)
+	jwksHdl.RegisterRoutes(server)
 	return server
 }
 
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"github.com/fsnotify/fsnotify"
 	"github.com/gin-contrib/sessions"
 	"github.com/gin-contrib/sessions/memstore"
@@ -11,9 +13,14 @@ import (
 	"go.uber.org/zap"
 	"log"
 	"net/http"
+	"time"
 	"webook/internal/middleware"
+	"webook/internal/service"
+	"webook/pkg/logger"
 )
 
+var rebuildSearch = flag.Bool("rebuild-search", false, "从数据库重建搜索索引快照之后退出")
+
 func main() {
 	//tools.Mt.DeleteAll()
 	//for i := 0; i < 1000; i++ {
@@ -29,13 +36,25 @@ func main() {
 	//	defer cancel()
 	//	otelCtx(ctx)
 	//}()
+	flag.Parse()
 	app := InitApp()
+	if *rebuildSearch {
+		rebuildSearchIndex(app.searchSvc, app.l)
+		return
+	}
+	initSearchIndex(app.searchSvc, app.l)
 	for _, c := range app.consumers {
 		err := c.Start()
 		if err != nil {
 			panic(err)
 		}
 	}
+	go func() {
+		er := app.scheduler.Schedule(context.Background())
+		if er != nil {
+			app.l.Error("调度器退出", logger.Error(er))
+		}
+	}()
 	//app.cron.Start()
 	//defer func() {
 	//	<-app.cron.Stop().Done()
@@ -44,6 +63,41 @@ func main() {
 	server.Run(":8080")
 }
 
+// initSearchIndex 优先加载快照，没有快照就在后台从数据库重建，之后定时保存快照
+func initSearchIndex(svc service.SearchService, l logger.LoggerV1) {
+	err := svc.LoadSnapshot()
+	if err != nil {
+		l.Warn("加载搜索索引快照失败，从数据库重建", logger.Error(err))
+		go rebuildSearchIndex(svc, l)
+	}
+	go func() {
+		ticker := time.NewTicker(5 * time.Minute)
+		defer ticker.Stop()
+		for range ticker.C {
+			er := svc.SaveSnapshot()
+			if er != nil {
+				l.Error("保存搜索索引快照失败", logger.Error(er))
+			}
+		}
+	}()
+}
+
+func rebuildSearchIndex(svc service.SearchService, l logger.LoggerV1) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+	cnt, err := svc.Rebuild(ctx)
+	if err != nil {
+		l.Error("重建搜索索引失败", logger.Error(err))
+		return
+	}
+	err = svc.SaveSnapshot()
+	if err != nil {
+		l.Error("保存搜索索引快照失败", logger.Error(err))
+		return
+	}
+	l.Info("重建搜索索引完成", logger.Int("cnt", cnt))
+}
+
 func initPrometheus() {
 	go func() {
 		http.Handle("/metrics", promhttp.Handler())
@@ -0,0 +1,40 @@
+package blobx
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStore(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	data := []byte("hello blob")
+	require.NoError(t, store.Put(ctx, "abcdef", bytes.NewReader(data), int64(len(data)), ""))
+	rc, err := store.Get(ctx, "abcdef")
+	require.NoError(t, err)
+	got, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	require.NoError(t, rc.Close())
+	assert.Equal(t, data, got)
+
+	// 大小对不上的时候不能留下文件
+	err = store.Put(ctx, "abcdeg", bytes.NewReader(data), 100, "")
+	assert.Error(t, err)
+	_, err = store.Get(ctx, "abcdeg")
+	assert.Equal(t, ErrNotFound, err)
+
+	require.NoError(t, store.Delete(ctx, "abcdef"))
+	_, err = store.Get(ctx, "abcdef")
+	assert.Equal(t, ErrNotFound, err)
+	assert.NoError(t, store.Delete(ctx, "abcdef"))
+
+	_, err = store.Get(ctx, "../../etc/passwd")
+	assert.Error(t, err)
+}
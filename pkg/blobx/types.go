@@ -0,0 +1,19 @@
+// Package blobx 存放上传的文件，key 由调用方决定，这里不关心文件内容
+package blobx
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+var ErrNotFound = errors.New("blob 不存在")
+
+type BlobStore interface {
+	// Put 写入 size 字节，key 已经存在的时候覆盖
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get 返回的 ReadCloser 必须由调用方关闭，key 不存在返回 ErrNotFound
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete key 不存在的时候不报错
+	Delete(ctx context.Context, key string) error
+}
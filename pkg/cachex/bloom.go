@@ -0,0 +1,122 @@
+package cachex
+
+import (
+	"context"
+	_ "embed"
+	"github.com/redis/go-redis/v9"
+	"hash/fnv"
+	"math"
+)
+
+var (
+	//go:embed lua/bloom_add.lua
+	luaBloomAdd string
+	//go:embed lua/bloom_check.lua
+	luaBloomCheck string
+)
+
+// RedisBloomFilter 用 Redis 的位图实现的布隆过滤器，所有实例共享。
+// 位图需要先 Rebuild 一次才会生效，在那之前 MightContain 总是返回 true
+type RedisBloomFilter struct {
+	client redis.Cmdable
+	key    string
+	// tmpKey 重建的时候先写到这里，写完再替换。
+	// Redis Cluster 下 key 要用 hash tag，保证两个 key 在同一个 slot
+	tmpKey string
+	// m 位图的位数，k 哈希函数的个数
+	m uint64
+	k int
+}
+
+// NewRedisBloomFilter expected 是预计的元素数量，fpRate 是期望的误判率
+func NewRedisBloomFilter(client redis.Cmdable, key string, expected uint64, fpRate float64) *RedisBloomFilter {
+	m, k := bloomParams(expected, fpRate)
+	return &RedisBloomFilter{
+		client: client,
+		key:    key,
+		tmpKey: key + ":rebuild",
+		m:      m,
+		k:      k,
+	}
+}
+
+// bloomParams m = -n*ln(p)/ln(2)^2，k = m/n*ln(2)
+func bloomParams(n uint64, p float64) (uint64, int) {
+	if n == 0 {
+		n = 1
+	}
+	m := uint64(math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2)))
+	k := int(math.Round(float64(m) / float64(n) * math.Ln2))
+	if k < 1 {
+		k = 1
+	}
+	return m, k
+}
+
+func (f *RedisBloomFilter) Add(ctx context.Context, items ...string) error {
+	if len(items) == 0 {
+		return nil
+	}
+	args := make([]any, 0, len(items)*f.k)
+	for _, item := range items {
+		for _, offset := range f.offsets(item) {
+			args = append(args, offset)
+		}
+	}
+	return f.client.Eval(ctx, luaBloomAdd, []string{f.key, f.tmpKey}, args...).Err()
+}
+
+func (f *RedisBloomFilter) MightContain(ctx context.Context, item string) (bool, error) {
+	offsets := f.offsets(item)
+	args := make([]any, 0, len(offsets))
+	for _, offset := range offsets {
+		args = append(args, offset)
+	}
+	res, err := f.client.Eval(ctx, luaBloomCheck, []string{f.key}, args...).Int()
+	if err != nil {
+		return false, err
+	}
+	return res != 0, nil
+}
+
+// Rebuild 布隆过滤器不支持删除，定期重建把已经删除的元素清理掉。
+// fill 通过 add 把全部元素写进去，期间调用 Add 新增的元素也会写到新的位图里面
+func (f *RedisBloomFilter) Rebuild(ctx context.Context, fill func(add func(items ...string) error) error) error {
+	err := f.client.Del(ctx, f.tmpKey).Err()
+	if err != nil {
+		return err
+	}
+	// 先把整个位图分配好，Add 看到 key 存在就会同时写进来
+	err = f.client.SetBit(ctx, f.tmpKey, int64(f.m-1), 0).Err()
+	if err != nil {
+		return err
+	}
+	err = fill(func(items ...string) error {
+		pipe := f.client.Pipeline()
+		for _, item := range items {
+			for _, offset := range f.offsets(item) {
+				pipe.SetBit(ctx, f.tmpKey, int64(offset), 1)
+			}
+		}
+		_, er := pipe.Exec(ctx)
+		return er
+	})
+	if err != nil {
+		f.client.Del(ctx, f.tmpKey)
+		return err
+	}
+	return f.client.Rename(ctx, f.tmpKey, f.key).Err()
+}
+
+// offsets 用两个哈希值模拟 k 个哈希函数
+func (f *RedisBloomFilter) offsets(item string) []uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(item))
+	sum := h.Sum64()
+	h1, h2 := sum&math.MaxUint32, sum>>32
+	res := make([]uint64, f.k)
+	for i := 0; i < f.k; i++ {
+		res[i] = (h1 + uint64(i)*h2) % f.m
+	}
+	return res
+}
@@ -0,0 +1,54 @@
+package cachex
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestLRU(t *testing.T) {
+	c := NewLRU[string, int](2)
+	c.Set("a", 1, time.Minute)
+	c.Set("b", 2, time.Minute)
+	// 访问过 a 之后，最久没有访问的是 b
+	val, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, val)
+	c.Set("c", 3, time.Minute)
+	_, ok = c.Get("b")
+	assert.False(t, ok)
+	assert.Equal(t, 2, c.Len())
+
+	// 更新已有的 key 不淘汰别的元素
+	c.Set("a", 10, time.Minute)
+	val, ok = c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 10, val)
+	_, ok = c.Get("c")
+	assert.True(t, ok)
+
+	c.Delete("a")
+	_, ok = c.Get("a")
+	assert.False(t, ok)
+
+	// 过期之后当成不存在
+	c.Set("d", 4, time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	_, ok = c.Get("d")
+	assert.False(t, ok)
+	assert.Equal(t, 1, c.Len())
+}
+
+func TestBloomParams(t *testing.T) {
+	m, k := bloomParams(1000000, 0.01)
+	assert.Equal(t, uint64(9585059), m)
+	assert.Equal(t, 7, k)
+
+	f := &RedisBloomFilter{m: m, k: k}
+	offsets := f.offsets("123")
+	assert.Len(t, offsets, k)
+	assert.Equal(t, offsets, f.offsets("123"))
+	for _, offset := range offsets {
+		assert.Less(t, offset, m)
+	}
+}
@@ -0,0 +1,9 @@
+-- KEYS 是正式的位图和正在重建的位图，只写已经存在的，重建期间新增的元素两边都要有
+for i = 1, #KEYS do
+    if redis.call('EXISTS', KEYS[i]) == 1 then
+        for j = 1, #ARGV do
+            redis.call('SETBIT', KEYS[i], ARGV[j], 1)
+        end
+    end
+end
+return 0
@@ -0,0 +1,6 @@
+-- 占位还在说明回源期间没有人删除过这个 key，可以写入，否则回源的结果可能已经是旧的了
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+    redis.call('SET', KEYS[1], ARGV[2], 'PX', ARGV[3])
+    return 1
+end
+return 0
@@ -0,0 +1,226 @@
+package cachex
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/sync/singleflight"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+)
+
+//go:embed lua/set_if_lease.lua
+var luaSetIfLease string
+
+type Config struct {
+	// Prefix Redis 里面 key 的前缀
+	Prefix string
+	// Channel 通知其它实例删除本地缓存的频道
+	Channel   string
+	LocalSize int
+	LocalTTL  time.Duration
+	TTL       time.Duration
+	// NotFoundTTL 数据不存在的时候缓存空值的时间，应该比 TTL 短很多
+	NotFoundTTL time.Duration
+	// Jitter 过期时间随机增加的比例，避免同一批缓存同时过期
+	Jitter float64
+	// ErrNotFound 加载函数返回这个错误的时候缓存空值，命中空值的时候也返回这个错误
+	ErrNotFound error
+}
+
+// MultiLevelCache 先查本地 LRU，再查 Redis，最后调用加载函数。
+// 同一个实例上同一个 key 只会有一个请求回源
+type MultiLevelCache[V any] struct {
+	client redis.UniversalClient
+	local  *LRU[string, localValue[V]]
+	group  singleflight.Group
+	cfg    Config
+
+	// mu 保护 deletes，删除本地缓存的时候加一。
+	// 读的过程中变过的话，读到的可能是删除之前的值，不写本地缓存
+	mu      sync.Mutex
+	deletes uint64
+}
+
+type localValue[V any] struct {
+	val   V
+	found bool
+}
+
+// notFoundValue Redis 里面的空值，任何值序列化之后都不会是空字符串
+const notFoundValue = ""
+
+// leasePrefix 回源期间在 Redis 里面占位，JSON 不会以这个开头。
+// 占位被删掉说明回源期间数据变了，回源的结果不写回 Redis
+const (
+	leasePrefix = "lease:"
+	leaseTTL    = 10 * time.Second
+)
+
+func NewMultiLevelCache[V any](client redis.UniversalClient, cfg Config) *MultiLevelCache[V] {
+	return &MultiLevelCache[V]{
+		client: client,
+		local:  NewLRU[string, localValue[V]](cfg.LocalSize),
+		cfg:    cfg,
+	}
+}
+
+func (c *MultiLevelCache[V]) Get(ctx context.Context, key string, load func(ctx context.Context) (V, error)) (V, error) {
+	if lv, ok := c.local.Get(key); ok {
+		return c.unwrap(lv)
+	}
+	val, err, _ := c.group.Do(key, func() (any, error) {
+		// 共享结果的请求不应该因为第一个请求被取消而失败
+		ctx := context.WithoutCancel(ctx)
+		deletes := c.deleteCount()
+		lv, err := c.getRemote(ctx, key)
+		if err == nil {
+			c.setLocal(key, lv, deletes)
+			return lv, nil
+		}
+		// 别的实例正在回源或者 Redis 出问题了都占不到位，结果只是不写回 Redis
+		lease, _ := c.lease(ctx, key)
+		v, err := load(ctx)
+		switch {
+		case err == nil:
+			lv = localValue[V]{val: v, found: true}
+		case c.cfg.ErrNotFound != nil && errors.Is(err, c.cfg.ErrNotFound):
+			lv = localValue[V]{}
+		default:
+			// 占位等它自己过期
+			return nil, err
+		}
+		if lease != "" {
+			// 写缓存失败不影响这一次的结果
+			_ = c.setRemote(ctx, key, lease, lv)
+		}
+		c.setLocal(key, lv, deletes)
+		return lv, nil
+	})
+	if err != nil {
+		var zero V
+		return zero, err
+	}
+	return c.unwrap(val.(localValue[V]))
+}
+
+// Delete 删除 Redis 和本地的缓存，并且通知其它实例删除本地缓存。
+// 同时也删掉了回源的占位，正在回源的请求不会把旧的值写回去
+func (c *MultiLevelCache[V]) Delete(ctx context.Context, key string) error {
+	c.deleteLocal(key)
+	err := c.client.Del(ctx, c.cfg.Prefix+key).Err()
+	if err != nil {
+		return err
+	}
+	return c.client.Publish(ctx, c.cfg.Channel, key).Err()
+}
+
+// Subscribe 监听其它实例的删除通知，一直阻塞到 ctx 结束。
+// 断线期间错过的通知只能等本地缓存过期，所以 LocalTTL 不能太长
+func (c *MultiLevelCache[V]) Subscribe(ctx context.Context) error {
+	ps := c.client.Subscribe(ctx, c.cfg.Channel)
+	defer ps.Close()
+	ch := ps.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case msg, ok := <-ch:
+			if !ok {
+				return nil
+			}
+			c.deleteLocal(msg.Payload)
+		}
+	}
+}
+
+func (c *MultiLevelCache[V]) getRemote(ctx context.Context, key string) (localValue[V], error) {
+	data, err := c.client.Get(ctx, c.cfg.Prefix+key).Bytes()
+	if err != nil {
+		return localValue[V]{}, err
+	}
+	if strings.HasPrefix(string(data), leasePrefix) {
+		return localValue[V]{}, redis.Nil
+	}
+	if string(data) == notFoundValue {
+		return localValue[V]{}, nil
+	}
+	var v V
+	err = json.Unmarshal(data, &v)
+	if err != nil {
+		return localValue[V]{}, err
+	}
+	return localValue[V]{val: v, found: true}, nil
+}
+
+// lease 占位成功的时候返回占位的值，key 已经有值或者别人在占位的时候返回空字符串
+func (c *MultiLevelCache[V]) lease(ctx context.Context, key string) (string, error) {
+	lease := leasePrefix + uuid.New().String()
+	ok, err := c.client.SetNX(ctx, c.cfg.Prefix+key, lease, leaseTTL).Result()
+	if err != nil || !ok {
+		return "", err
+	}
+	return lease, nil
+}
+
+// setRemote 只有占位还在的时候才写
+func (c *MultiLevelCache[V]) setRemote(ctx context.Context, key string, lease string, lv localValue[V]) error {
+	data, ttl := []byte(notFoundValue), c.cfg.NotFoundTTL
+	if lv.found {
+		var err error
+		data, err = json.Marshal(lv.val)
+		if err != nil {
+			return err
+		}
+		ttl = c.cfg.TTL
+	}
+	return c.client.Eval(ctx, luaSetIfLease, []string{c.cfg.Prefix + key},
+		lease, data, c.jitter(ttl).Milliseconds()).Err()
+}
+
+// setLocal deletes 是读之前的删除次数，期间有删除的话不写
+func (c *MultiLevelCache[V]) setLocal(key string, lv localValue[V], deletes uint64) {
+	ttl := c.cfg.LocalTTL
+	if !lv.found && c.cfg.NotFoundTTL < ttl {
+		ttl = c.cfg.NotFoundTTL
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.deletes != deletes {
+		return
+	}
+	c.local.Set(key, lv, c.jitter(ttl))
+}
+
+func (c *MultiLevelCache[V]) deleteLocal(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.deletes++
+	c.local.Delete(key)
+}
+
+func (c *MultiLevelCache[V]) deleteCount() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.deletes
+}
+
+func (c *MultiLevelCache[V]) unwrap(lv localValue[V]) (V, error) {
+	if !lv.found {
+		return lv.val, c.cfg.ErrNotFound
+	}
+	return lv.val, nil
+}
+
+func (c *MultiLevelCache[V]) jitter(ttl time.Duration) time.Duration {
+	delta := int64(float64(ttl) * c.cfg.Jitter)
+	if delta <= 0 {
+		return ttl
+	}
+	return ttl + time.Duration(rand.Int63n(delta))
+}
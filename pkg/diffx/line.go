@@ -0,0 +1,174 @@
+package diffx
+
+import (
+	"errors"
+	"strings"
+)
+
+type Op int8
+
+const (
+	OpEqual Op = iota
+	OpInsert
+	OpDelete
+)
+
+func (o Op) String() string {
+	switch o {
+	case OpInsert:
+		return "+"
+	case OpDelete:
+		return "-"
+	default:
+		return " "
+	}
+}
+
+type Line struct {
+	Op   Op
+	Text string
+}
+
+// MaxLines 每一边最多的行数，超过了就不比较，避免比较太大的文本占用太多 CPU
+const MaxLines = 10000
+
+var ErrTooManyLines = errors.New("diffx: 行数太多")
+
+// Lines 按行比较 a 和 b，返回把 a 变成 b 的最短编辑脚本。
+// 用的是线性空间的 Myers 算法，每次找到中间的 snake 再递归处理两边
+func Lines(a, b string) ([]Line, error) {
+	la, lb := splitLines(a), splitLines(b)
+	if len(la) > MaxLines || len(lb) > MaxLines {
+		return nil, ErrTooManyLines
+	}
+	if len(la)+len(lb) == 0 {
+		return nil, nil
+	}
+	res := make([]Line, 0, len(la)+len(lb))
+	return diff(res, la, lb), nil
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// diff 把 a 变成 b 的编辑脚本追加到 res 后面
+func diff(res []Line, a, b []string) []Line {
+	// 先去掉相同的前缀和后缀
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		res = append(res, Line{Op: OpEqual, Text: a[prefix]})
+		prefix++
+	}
+	a, b = a[prefix:], b[prefix:]
+	suffix := 0
+	for suffix < len(a) && suffix < len(b) && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	common := a[len(a)-suffix:]
+	a, b = a[:len(a)-suffix], b[:len(b)-suffix]
+	switch {
+	case len(a) == 0:
+		for _, l := range b {
+			res = append(res, Line{Op: OpInsert, Text: l})
+		}
+	case len(b) == 0:
+		for _, l := range a {
+			res = append(res, Line{Op: OpDelete, Text: l})
+		}
+	default:
+		x, y, ok := middleSnake(a, b)
+		if ok {
+			res = diff(res, a[:x], b[:y])
+			res = diff(res, a[x:], b[y:])
+		} else {
+			for _, l := range a {
+				res = append(res, Line{Op: OpDelete, Text: l})
+			}
+			for _, l := range b {
+				res = append(res, Line{Op: OpInsert, Text: l})
+			}
+		}
+	}
+	for _, l := range common {
+		res = append(res, Line{Op: OpEqual, Text: l})
+	}
+	return res
+}
+
+// middleSnake 从两头同时往中间找，返回最短路径上的一个点，用来把问题切成两半。
+// 只需要两个 O(n+m) 的数组，找不到公共的行返回 false
+func middleSnake(a, b []string) (int, int, bool) {
+	n, m := len(a), len(b)
+	maxD := (n + m + 1) / 2
+	offset := maxD
+	// vf 正向每条对角线走到的最远的 x，vb 反向的，-1 表示还没走到
+	vf := make([]int, 2*maxD+2)
+	vb := make([]int, 2*maxD+2)
+	for i := range vf {
+		vf[i], vb[i] = -1, -1
+	}
+	vf[offset+1], vb[offset+1] = 0, 0
+	delta := n - m
+	// delta 是奇数的时候正向的路径先和反向的重叠
+	front := delta%2 != 0
+	// 走出边界的对角线不用再算
+	var fStart, fEnd, bStart, bEnd int
+	for d := 0; d < maxD; d++ {
+		for k := -d + fStart; k <= d-fEnd; k += 2 {
+			var x int
+			if k == -d || (k != d && vf[offset+k-1] < vf[offset+k+1]) {
+				x = vf[offset+k+1]
+			} else {
+				x = vf[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			vf[offset+k] = x
+			switch {
+			case x > n:
+				fEnd += 2
+			case y > m:
+				fStart += 2
+			case front:
+				bk := offset + delta - k
+				if bk >= 0 && bk < len(vb) && vb[bk] != -1 && x >= n-vb[bk] {
+					return x, y, true
+				}
+			}
+		}
+		for k := -d + bStart; k <= d-bEnd; k += 2 {
+			var x int
+			if k == -d || (k != d && vb[offset+k-1] < vb[offset+k+1]) {
+				x = vb[offset+k+1]
+			} else {
+				x = vb[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[n-1-x] == b[m-1-y] {
+				x++
+				y++
+			}
+			vb[offset+k] = x
+			switch {
+			case x > n:
+				bEnd += 2
+			case y > m:
+				bStart += 2
+			case !front:
+				fk := offset + delta - k
+				if fk >= 0 && fk < len(vf) && vf[fk] != -1 && vf[fk] >= n-x {
+					fx := vf[fk]
+					return fx, fx - (fk - offset), true
+				}
+			}
+		}
+	}
+	return 0, 0, false
+}
@@ -0,0 +1,106 @@
+package diffx
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestLines(t *testing.T) {
+	testCases := []struct {
+		name string
+		a    string
+		b    string
+		want []Line
+	}{
+		{
+			name: "都为空",
+		},
+		{
+			name: "完全相同",
+			a:    "a\nb",
+			b:    "a\nb\n",
+			want: []Line{{Op: OpEqual, Text: "a"}, {Op: OpEqual, Text: "b"}},
+		},
+		{
+			name: "新增全部",
+			b:    "a\nb",
+			want: []Line{{Op: OpInsert, Text: "a"}, {Op: OpInsert, Text: "b"}},
+		},
+		{
+			name: "删除全部",
+			a:    "a\nb",
+			want: []Line{{Op: OpDelete, Text: "a"}, {Op: OpDelete, Text: "b"}},
+		},
+		{
+			name: "修改中间一行",
+			a:    "a\nb\nc",
+			b:    "a\nx\nc",
+			want: []Line{
+				{Op: OpEqual, Text: "a"},
+				{Op: OpDelete, Text: "b"},
+				{Op: OpInsert, Text: "x"},
+				{Op: OpEqual, Text: "c"},
+			},
+		},
+		{
+			name: "插入和删除混合",
+			a:    "a\nb\nc\nd",
+			b:    "b\nc\ne\nd\nf",
+			want: []Line{
+				{Op: OpDelete, Text: "a"},
+				{Op: OpEqual, Text: "b"},
+				{Op: OpEqual, Text: "c"},
+				{Op: OpInsert, Text: "e"},
+				{Op: OpEqual, Text: "d"},
+				{Op: OpInsert, Text: "f"},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := Lines(tc.a, tc.b)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, res)
+		})
+	}
+}
+
+func TestLines_Long(t *testing.T) {
+	var a, b []string
+	for i := 0; i < 3000; i++ {
+		a = append(a, strconv.Itoa(i))
+		switch i % 10 {
+		case 3:
+			// 删除
+		case 7:
+			b = append(b, "x"+strconv.Itoa(i))
+		default:
+			b = append(b, strconv.Itoa(i))
+		}
+	}
+	res, err := Lines(strings.Join(a, "\n"), strings.Join(b, "\n"))
+	require.NoError(t, err)
+	var gotA, gotB []string
+	changes := 0
+	for _, l := range res {
+		if l.Op != OpInsert {
+			gotA = append(gotA, l.Text)
+		}
+		if l.Op != OpDelete {
+			gotB = append(gotB, l.Text)
+		}
+		if l.Op != OpEqual {
+			changes++
+		}
+	}
+	assert.Equal(t, a, gotA)
+	assert.Equal(t, b, gotB)
+	// 每 10 行删掉一行，改掉一行
+	assert.Equal(t, 300*3, changes)
+
+	_, err = Lines(strings.Repeat("a\n", MaxLines+1), "a")
+	assert.Equal(t, ErrTooManyLines, err)
+}
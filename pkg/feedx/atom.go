@@ -0,0 +1,62 @@
+// Package feedx 生成 Atom 订阅和 sitemap
+package feedx
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// Feed Atom 1.0，字段只保留了用得上的，见 RFC 4287
+type Feed struct {
+	XMLName xml.Name `xml:"http://www.w3.org/2005/Atom feed"`
+	Id      string   `xml:"id"`
+	Title   string   `xml:"title"`
+	Updated string   `xml:"updated"`
+	Links   []Link   `xml:"link"`
+	Entries []Entry  `xml:"entry"`
+}
+
+type Link struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type Person struct {
+	Name string `xml:"name"`
+	URI  string `xml:"uri,omitempty"`
+}
+
+type Text struct {
+	// Type text 或者 html
+	Type string `xml:"type,attr,omitempty"`
+	Body string `xml:",chardata"`
+}
+
+type Entry struct {
+	Id        string  `xml:"id"`
+	Title     string  `xml:"title"`
+	Updated   string  `xml:"updated"`
+	Published string  `xml:"published,omitempty"`
+	Links     []Link  `xml:"link"`
+	Author    *Person `xml:"author,omitempty"`
+	Summary   *Text   `xml:"summary,omitempty"`
+	Content   *Text   `xml:"content,omitempty"`
+}
+
+// Time 按照 RFC 3339 格式化时间
+func Time(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+func (f *Feed) Marshal() ([]byte, error) {
+	return marshal(f)
+}
+
+func marshal(v any) ([]byte, error) {
+	data, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), data...), nil
+}
@@ -0,0 +1,28 @@
+package feedx
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// MaxSitemapURLs 一个 sitemap 文件最多的 URL 数量
+const MaxSitemapURLs = 50000
+
+type URLSet struct {
+	XMLName xml.Name `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 urlset"`
+	URLs    []URL    `xml:"url"`
+}
+
+type URL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// Date sitemap 里面的修改时间只需要精确到天
+func Date(t time.Time) string {
+	return t.UTC().Format(time.DateOnly)
+}
+
+func (s *URLSet) Marshal() ([]byte, error) {
+	return marshal(s)
+}
@@ -0,0 +1,93 @@
+package jwtx
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+)
+
+// JWKS RFC 7517 的格式，只放验证用的公钥
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use,omitempty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	// N 和 E 是 RSA 的
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// Crv 和 X 是 Ed25519 的，RFC 8037
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+func (k Key) JWK() JWK {
+	res := JWK{
+		Use: "sig",
+		Kid: k.Kid,
+		Alg: k.Method.Alg(),
+	}
+	switch pub := k.public.(type) {
+	case *rsa.PublicKey:
+		res.Kty = "RSA"
+		res.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+		res.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+	case ed25519.PublicKey:
+		res.Kty = "OKP"
+		res.Crv = "Ed25519"
+		res.X = base64.RawURLEncoding.EncodeToString(pub)
+	}
+	return res
+}
+
+// Key 转成只能验证的密钥，alg 和密钥类型对不上的不接受
+func (j JWK) Key() (Key, error) {
+	k, err := j.publicKey()
+	if err != nil {
+		return Key{}, err
+	}
+	if j.Alg != "" && j.Alg != k.Method.Alg() {
+		return Key{}, ErrAlgMismatch
+	}
+	return k, nil
+}
+
+func (j JWK) publicKey() (Key, error) {
+	switch j.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(j.N)
+		if err != nil {
+			return Key{}, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(j.E)
+		if err != nil {
+			return Key{}, err
+		}
+		if len(e) == 0 || len(e) > 4 {
+			return Key{}, fmt.Errorf("jwtx: kid %s 的 e 不对", j.Kid)
+		}
+		return NewKey(j.Kid, &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		})
+	case "OKP":
+		if j.Crv != "Ed25519" {
+			return Key{}, ErrUnsupportedKey
+		}
+		x, err := base64.RawURLEncoding.DecodeString(j.X)
+		if err != nil {
+			return Key{}, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return Key{}, fmt.Errorf("jwtx: kid %s 的 x 长度不对", j.Kid)
+		}
+		return NewKey(j.Kid, ed25519.PublicKey(x))
+	default:
+		return Key{}, ErrUnsupportedKey
+	}
+}
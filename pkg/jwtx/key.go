@@ -0,0 +1,102 @@
+package jwtx
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrUnsupportedKey = errors.New("jwtx: 只支持 RSA 和 Ed25519 密钥")
+	ErrKeyNotFound    = errors.New("jwtx: 未知的 kid")
+	ErrAlgMismatch    = errors.New("jwtx: 签名算法和密钥不匹配")
+)
+
+// minRSABits RSA 密钥至少 2048 位
+const minRSABits = 2048
+
+// validMethods 只接受非对称的算法，防止拿公钥当 HMAC 密钥伪造 token
+var validMethods = []string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg()}
+
+// Key 一个带 kid 的密钥，没有私钥的只能用来验证，一般是轮换下来的旧密钥
+type Key struct {
+	Kid     string
+	Method  jwt.SigningMethod
+	private crypto.PrivateKey
+	public  crypto.PublicKey
+}
+
+// NewKey key 可以是 RSA 或者 Ed25519 的私钥或者公钥，RSA 用 RS256，Ed25519 用 EdDSA
+func NewKey(kid string, key any) (Key, error) {
+	if kid == "" {
+		return Key{}, errors.New("jwtx: kid 不能为空")
+	}
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		if k.N.BitLen() < minRSABits {
+			return Key{}, fmt.Errorf("jwtx: RSA 密钥至少 %d 位", minRSABits)
+		}
+		return Key{Kid: kid, Method: jwt.SigningMethodRS256, private: k, public: &k.PublicKey}, nil
+	case *rsa.PublicKey:
+		if k.N.BitLen() < minRSABits {
+			return Key{}, fmt.Errorf("jwtx: RSA 密钥至少 %d 位", minRSABits)
+		}
+		return Key{Kid: kid, Method: jwt.SigningMethodRS256, public: k}, nil
+	case ed25519.PrivateKey:
+		return Key{Kid: kid, Method: jwt.SigningMethodEdDSA, private: k, public: k.Public()}, nil
+	case ed25519.PublicKey:
+		return Key{Kid: kid, Method: jwt.SigningMethodEdDSA, public: k}, nil
+	default:
+		return Key{}, ErrUnsupportedKey
+	}
+}
+
+// ParsePEM 支持 PKCS8、PKCS1 的私钥和 PKIX、PKCS1 的公钥
+func ParsePEM(kid string, data []byte) (Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return Key{}, errors.New("jwtx: 不是 PEM 格式")
+	}
+	var (
+		key any
+		err error
+	)
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		return Key{}, fmt.Errorf("jwtx: 不支持的 PEM 类型 %s", block.Type)
+	}
+	if err != nil {
+		return Key{}, err
+	}
+	return NewKey(kid, key)
+}
+
+func (k Key) CanSign() bool {
+	return k.private != nil
+}
+
+// verifyKey 按照 token 头部的 kid 找密钥，并且算法要和密钥对得上
+func verifyKey(keys map[string]Key, token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := keys[kid]
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, ErrAlgMismatch
+	}
+	return key.public, nil
+}
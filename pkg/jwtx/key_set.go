@@ -0,0 +1,76 @@
+package jwtx
+
+import (
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"sort"
+)
+
+// KeySet 用一个密钥签名，用所有的密钥验证。
+// 轮换的时候先把新密钥加进来，等所有实例都认识之后再切换签名的密钥，
+// 旧密钥留到用它签发的 token 全部过期再删掉，这样用户不会被登出
+type KeySet struct {
+	signing Key
+	keys    map[string]Key
+}
+
+func NewKeySet(signingKid string, keys ...Key) (*KeySet, error) {
+	s := &KeySet{
+		keys: make(map[string]Key, len(keys)),
+	}
+	for _, k := range keys {
+		if _, ok := s.keys[k.Kid]; ok {
+			return nil, fmt.Errorf("jwtx: kid %s 重复", k.Kid)
+		}
+		s.keys[k.Kid] = k
+	}
+	signing, ok := s.keys[signingKid]
+	if !ok {
+		return nil, fmt.Errorf("jwtx: 签名的密钥 %s 不存在", signingKid)
+	}
+	if !signing.CanSign() {
+		return nil, errors.New("jwtx: 签名的密钥没有私钥")
+	}
+	s.signing = signing
+	return s, nil
+}
+
+// Sign 头部带上 kid，验证的时候按照 kid 找密钥
+func (s *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(s.signing.Method, claims)
+	token.Header["kid"] = s.signing.Kid
+	return token.SignedString(s.signing.private)
+}
+
+func (s *KeySet) Keyfunc(token *jwt.Token) (any, error) {
+	return verifyKey(s.keys, token)
+}
+
+// Parse 签名、过期时间都校验过才返回 nil
+func (s *KeySet) Parse(tokenStr string, claims jwt.Claims) error {
+	_, err := jwt.ParseWithClaims(tokenStr, claims, s.Keyfunc, jwt.WithValidMethods(validMethods))
+	return err
+}
+
+// Kids 签名的密钥在最前面
+func (s *KeySet) Kids() []string {
+	res := make([]string, 0, len(s.keys))
+	for kid := range s.keys {
+		if kid != s.signing.Kid {
+			res = append(res, kid)
+		}
+	}
+	sort.Strings(res)
+	return append([]string{s.signing.Kid}, res...)
+}
+
+// JWKS 只有公钥
+func (s *KeySet) JWKS() JWKS {
+	kids := s.Kids()
+	res := JWKS{Keys: make([]JWK, 0, len(kids))}
+	for _, kid := range kids {
+		res.Keys = append(res.Keys, s.keys[kid].JWK())
+	}
+	return res
+}
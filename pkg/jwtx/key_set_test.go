@@ -0,0 +1,134 @@
+package jwtx
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type testClaims struct {
+	jwt.RegisteredClaims
+	Uid int64
+}
+
+func newClaims(uid int64) testClaims {
+	return testClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		},
+		Uid: uid,
+	}
+}
+
+func TestKeySet_Rotate(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	oldKey, err := NewKey("old", rsaKey)
+	require.NoError(t, err)
+	newKey, err := NewKey("new", edKey)
+	require.NoError(t, err)
+
+	before, err := NewKeySet("old", oldKey)
+	require.NoError(t, err)
+	oldToken, err := before.Sign(newClaims(1))
+	require.NoError(t, err)
+
+	// 切换到新密钥之后，旧密钥签发的 token 还能用
+	after, err := NewKeySet("new", newKey, oldKey)
+	require.NoError(t, err)
+	newToken, err := after.Sign(newClaims(2))
+	require.NoError(t, err)
+
+	var c testClaims
+	require.NoError(t, after.Parse(oldToken, &c))
+	assert.Equal(t, int64(1), c.Uid)
+	require.NoError(t, after.Parse(newToken, &c))
+	assert.Equal(t, int64(2), c.Uid)
+	assert.ErrorIs(t, before.Parse(newToken, &c), ErrKeyNotFound)
+
+	// 旧密钥删掉之后就不认了
+	retired, err := NewKeySet("new", newKey)
+	require.NoError(t, err)
+	assert.ErrorIs(t, retired.Parse(oldToken, &c), ErrKeyNotFound)
+
+	// 只有公钥的不能用来签名
+	pubOnly, err := NewKey("pub", rsaKey.Public())
+	require.NoError(t, err)
+	_, err = NewKeySet("pub", pubOnly)
+	assert.Error(t, err)
+}
+
+func TestKeySet_AlgMismatch(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	key, err := NewKey("k1", rsaKey)
+	require.NoError(t, err)
+	ks, err := NewKeySet("k1", key)
+	require.NoError(t, err)
+
+	// 用 HS256 签名，拿公钥当密钥
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, newClaims(1))
+	token.Header["kid"] = "k1"
+	tokenStr, err := token.SignedString(rsaKey.N.Bytes())
+	require.NoError(t, err)
+	var c testClaims
+	assert.Error(t, ks.Parse(tokenStr, &c))
+
+	// kid 对得上但是算法不对
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	token = jwt.NewWithClaims(jwt.SigningMethodEdDSA, newClaims(1))
+	token.Header["kid"] = "k1"
+	tokenStr, err = token.SignedString(edKey)
+	require.NoError(t, err)
+	assert.ErrorIs(t, ks.Parse(tokenStr, &c), ErrAlgMismatch)
+}
+
+func TestRemoteKeySet(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	k1, err := NewKey("k1", rsaKey)
+	require.NoError(t, err)
+	k2, err := NewKey("k2", edKey)
+	require.NoError(t, err)
+
+	ks, err := NewKeySet("k1", k1)
+	require.NoError(t, err)
+	fetchCnt := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetchCnt++
+		_ = json.NewEncoder(w).Encode(ks.JWKS())
+	}))
+	defer server.Close()
+
+	remote := NewRemoteKeySet(server.URL, server.Client())
+	remote.minInterval = 0
+	tokenStr, err := ks.Sign(newClaims(1))
+	require.NoError(t, err)
+	var c testClaims
+	require.NoError(t, remote.Parse(tokenStr, &c))
+	assert.Equal(t, int64(1), c.Uid)
+	require.NoError(t, remote.Parse(tokenStr, &c))
+	assert.Equal(t, 1, fetchCnt)
+
+	// 签发方轮换了密钥，遇到新的 kid 重新拉取
+	ks, err = NewKeySet("k2", k2, k1)
+	require.NoError(t, err)
+	tokenStr, err = ks.Sign(newClaims(2))
+	require.NoError(t, err)
+	require.NoError(t, remote.Parse(tokenStr, &c))
+	assert.Equal(t, int64(2), c.Uid)
+	assert.Equal(t, 2, fetchCnt)
+}
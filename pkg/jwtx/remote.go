@@ -0,0 +1,98 @@
+package jwtx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// RemoteKeySet 其它服务用来验证 token，从签发方的 /.well-known/jwks.json 拉取公钥。
+// 遇到不认识的 kid 说明签发方轮换了密钥，重新拉一次
+type RemoteKeySet struct {
+	url    string
+	client *http.Client
+	// minInterval 两次拉取的最小间隔，防止伪造的 kid 把签发方打爆
+	minInterval time.Duration
+
+	mu        sync.RWMutex
+	keys      map[string]Key
+	fetchedAt time.Time
+}
+
+func NewRemoteKeySet(url string, client *http.Client) *RemoteKeySet {
+	return &RemoteKeySet{
+		url:         url,
+		client:      client,
+		minInterval: time.Minute,
+	}
+}
+
+func (s *RemoteKeySet) Keyfunc(token *jwt.Token) (any, error) {
+	s.mu.RLock()
+	keys := s.keys
+	s.mu.RUnlock()
+	kid, _ := token.Header["kid"].(string)
+	if _, ok := keys[kid]; !ok {
+		var err error
+		keys, err = s.refresh(context.Background(), kid)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return verifyKey(keys, token)
+}
+
+func (s *RemoteKeySet) Parse(tokenStr string, claims jwt.Claims) error {
+	_, err := jwt.ParseWithClaims(tokenStr, claims, s.Keyfunc, jwt.WithValidMethods(validMethods))
+	return err
+}
+
+// refresh 别的请求已经拉到了 kid，或者刚拉过，就直接用现在的
+func (s *RemoteKeySet) refresh(ctx context.Context, kid string) (map[string]Key, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.keys[kid]; ok || time.Since(s.fetchedAt) < s.minInterval {
+		return s.keys, nil
+	}
+	keys, err := s.fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.keys = keys
+	s.fetchedAt = time.Now()
+	return keys, nil
+}
+
+func (s *RemoteKeySet) fetch(ctx context.Context) (map[string]Key, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("jwtx: 拉取 JWKS 失败，状态码 %d", resp.StatusCode)
+	}
+	var jwks JWKS
+	err = json.NewDecoder(resp.Body).Decode(&jwks)
+	if err != nil {
+		return nil, err
+	}
+	keys := make(map[string]Key, len(jwks.Keys))
+	for _, j := range jwks.Keys {
+		k, er := j.Key()
+		if er != nil {
+			// 不认识的密钥类型跳过，不影响其它的
+			continue
+		}
+		keys[k.Kid] = k
+	}
+	return keys, nil
+}
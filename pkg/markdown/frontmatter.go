@@ -0,0 +1,77 @@
+package markdown
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+)
+
+const frontMatterDelim = "---"
+
+var ErrInvalidFrontMatter = errors.New("front matter 格式不对")
+
+// Meta 一个 front matter 字段，值写成 JSON，字符串和数组同时也是合法的 YAML
+type Meta struct {
+	Key   string
+	Value any
+}
+
+// WriteFrontMatter 按照 metas 的顺序写入 front matter 和正文
+func WriteFrontMatter(w io.Writer, metas []Meta, body string) error {
+	var buf bytes.Buffer
+	buf.WriteString(frontMatterDelim + "\n")
+	for _, m := range metas {
+		val, err := json.Marshal(m.Value)
+		if err != nil {
+			return err
+		}
+		buf.WriteString(m.Key)
+		buf.WriteString(": ")
+		buf.Write(val)
+		buf.WriteByte('\n')
+	}
+	buf.WriteString(frontMatterDelim + "\n")
+	buf.WriteString(body)
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// ParseFrontMatter 没有 front matter 的时候整个文件都是正文。
+// 值是 JSON 的时候按照 JSON 解析，否则当成普通字符串，手写的简单 YAML 也能识别
+func ParseFrontMatter(src []byte) (map[string]json.RawMessage, string, error) {
+	text := strings.ReplaceAll(string(src), "\r\n", "\n")
+	if !strings.HasPrefix(text, frontMatterDelim+"\n") {
+		return map[string]json.RawMessage{}, text, nil
+	}
+	rest := text[len(frontMatterDelim)+1:]
+	head, body, ok := strings.Cut(rest, "\n"+frontMatterDelim+"\n")
+	if !ok {
+		// 正文为空的时候结尾可能没有换行
+		head, ok = strings.CutSuffix(rest, "\n"+frontMatterDelim)
+		if !ok {
+			return nil, "", ErrInvalidFrontMatter
+		}
+	}
+	res := make(map[string]json.RawMessage)
+	scanner := bufio.NewScanner(strings.NewReader(head))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, val, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, "", ErrInvalidFrontMatter
+		}
+		key, val = strings.TrimSpace(key), strings.TrimSpace(val)
+		if !json.Valid([]byte(val)) {
+			quoted, _ := json.Marshal(val)
+			val = string(quoted)
+		}
+		res[key] = json.RawMessage(val)
+	}
+	return res, body, scanner.Err()
+}
@@ -0,0 +1,78 @@
+package markdown
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestFrontMatter_RoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteFrontMatter(&buf, []Meta{
+		{Key: "id", Value: 12},
+		{Key: "title", Value: "标题: 带冒号"},
+		{Key: "tags", Value: []string{"Go", "MySQL"}},
+	}, "# 正文\n---\n分隔线后面还是正文")
+	require.NoError(t, err)
+
+	metas, body, err := ParseFrontMatter(buf.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, "# 正文\n---\n分隔线后面还是正文", body)
+	var title string
+	require.NoError(t, json.Unmarshal(metas["title"], &title))
+	assert.Equal(t, "标题: 带冒号", title)
+	var tags []string
+	require.NoError(t, json.Unmarshal(metas["tags"], &tags))
+	assert.Equal(t, []string{"Go", "MySQL"}, tags)
+	assert.Equal(t, json.RawMessage("12"), metas["id"])
+}
+
+func TestParseFrontMatter(t *testing.T) {
+	testCases := []struct {
+		name     string
+		src      string
+		wantMeta map[string]json.RawMessage
+		wantBody string
+		wantErr  error
+	}{
+		{
+			name:     "没有 front matter",
+			src:      "# 标题\n正文",
+			wantMeta: map[string]json.RawMessage{},
+			wantBody: "# 标题\n正文",
+		},
+		{
+			name: "手写的 YAML",
+			src:  "---\r\ntitle: 我的文章\r\n# 注释\r\n\r\n---\r\n正文",
+			wantMeta: map[string]json.RawMessage{
+				"title": json.RawMessage(`"我的文章"`),
+			},
+			wantBody: "正文",
+		},
+		{
+			name: "正文为空",
+			src:  "---\ntitle: \"空\"\n---",
+			wantMeta: map[string]json.RawMessage{
+				"title": json.RawMessage(`"空"`),
+			},
+		},
+		{
+			name:    "没有结束",
+			src:     "---\ntitle: a\n正文",
+			wantErr: ErrInvalidFrontMatter,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			metas, body, err := ParseFrontMatter([]byte(tc.src))
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tc.wantMeta, metas)
+			assert.Equal(t, tc.wantBody, body)
+		})
+	}
+}
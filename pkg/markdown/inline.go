@@ -0,0 +1,223 @@
+package markdown
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	inlineTagRegexp = regexp.MustCompile(`^</?[a-zA-Z][a-zA-Z0-9]*(\s+[a-zA-Z_:][-a-zA-Z0-9_:.]*(\s*=\s*("[^"]*"|'[^']*'|[^\s"'=<>` + "`" + `]+))?)*\s*/?>`)
+	autoLinkRegexp  = regexp.MustCompile(`^<((https?|mailto):[^\s<>]+)>`)
+)
+
+// maxLinkDestLen 链接地址最长的字节数，找不到 ) 的时候最多往后看这么多
+const maxLinkDestLen = 2048
+
+// renderInline 处理行内的语法，text 里面可以有换行
+func renderInline(text string) string {
+	var sb strings.Builder
+	brackets := matchBrackets(text)
+	for i := 0; i < len(text); {
+		c := text[i]
+		switch c {
+		case '\\':
+			if i+1 < len(text) && strings.IndexByte("\\`*_{}[]()#+-.!~<>|", text[i+1]) >= 0 {
+				sb.WriteString(html.EscapeString(text[i+1 : i+2]))
+				i += 2
+				continue
+			}
+			if i+1 < len(text) && text[i+1] == '\n' {
+				sb.WriteString("<br>\n")
+				i += 2
+				continue
+			}
+		case '`':
+			if n, ok := codeSpan(&sb, text[i:]); ok {
+				i += n
+				continue
+			}
+		case '!':
+			if i+1 < len(text) && text[i+1] == '[' {
+				if n, ok := link(&sb, text[i+1:], brackets[i+1]-i-1, true); ok {
+					i += n + 1
+					continue
+				}
+			}
+		case '[':
+			if n, ok := link(&sb, text[i:], brackets[i]-i, false); ok {
+				i += n
+				continue
+			}
+		case '<':
+			if m := autoLinkRegexp.FindStringSubmatch(text[i:]); m != nil {
+				sb.WriteString(`<a href="` + html.EscapeString(m[1]) + `">` + html.EscapeString(m[1]) + "</a>")
+				i += len(m[0])
+				continue
+			}
+			// 原始的 HTML 标签留给 Sanitize 处理
+			if loc := inlineTagRegexp.FindStringIndex(text[i:]); loc != nil {
+				sb.WriteString(text[i : i+loc[1]])
+				i += loc[1]
+				continue
+			}
+		case '*', '_', '~':
+			if n, ok := emphasis(&sb, text, i); ok {
+				i += n
+				continue
+			}
+		case '\n':
+			// 行尾两个空格是硬换行
+			if strings.HasSuffix(sb.String(), "  ") {
+				res := strings.TrimRight(sb.String(), " ")
+				sb.Reset()
+				sb.WriteString(res)
+				sb.WriteString("<br>\n")
+				i++
+				continue
+			}
+		}
+		sb.WriteString(html.EscapeString(text[i : i+1]))
+		i++
+	}
+	return sb.String()
+}
+
+func codeSpan(sb *strings.Builder, text string) (int, bool) {
+	n := 0
+	for n < len(text) && text[n] == '`' {
+		n++
+	}
+	fence := text[:n]
+	end := strings.Index(text[n:], fence)
+	if end < 0 {
+		return 0, false
+	}
+	code := strings.ReplaceAll(text[n:n+end], "\n", " ")
+	if len(code) > 2 && code[0] == ' ' && code[len(code)-1] == ' ' {
+		code = code[1 : len(code)-1]
+	}
+	sb.WriteString("<code>")
+	sb.WriteString(html.EscapeString(code))
+	sb.WriteString("</code>")
+	return n + end + n, true
+}
+
+// matchBrackets 用栈一次找出每个 [ 对应的 ] 的位置。
+// 不能每个 [ 都往后扫到结尾，不然一长串的 [ 就是 O(n²)
+func matchBrackets(text string) map[int]int {
+	res := make(map[int]int)
+	var stack []int
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '\\':
+			i++
+		case '[':
+			stack = append(stack, i)
+		case ']':
+			if len(stack) > 0 {
+				res[stack[len(stack)-1]] = i
+				stack = stack[:len(stack)-1]
+			}
+		}
+	}
+	return res
+}
+
+// link 处理 [text](url "title")，closeIdx 是对应的 ] 的位置，image 为 true 的时候处理的是图片
+func link(sb *strings.Builder, text string, closeIdx int, image bool) (int, bool) {
+	if closeIdx <= 0 || closeIdx+1 >= len(text) || text[closeIdx+1] != '(' {
+		return 0, false
+	}
+	rest := text[closeIdx+2:]
+	if len(rest) > maxLinkDestLen {
+		rest = rest[:maxLinkDestLen]
+	}
+	end := strings.IndexByte(rest, ')')
+	if end < 0 {
+		return 0, false
+	}
+	label := text[1:closeIdx]
+	dest := strings.TrimSpace(text[closeIdx+2 : closeIdx+2+end])
+	var title string
+	if idx := strings.IndexAny(dest, " \n"); idx >= 0 {
+		title = strings.Trim(strings.TrimSpace(dest[idx:]), `"'`)
+		dest = dest[:idx]
+	}
+	dest = strings.TrimSuffix(strings.TrimPrefix(dest, "<"), ">")
+	if image {
+		sb.WriteString(`<img src="` + html.EscapeString(dest) + `" alt="` + html.EscapeString(label) + `"`)
+		if title != "" {
+			sb.WriteString(` title="` + html.EscapeString(title) + `"`)
+		}
+		sb.WriteString(">")
+	} else {
+		sb.WriteString(`<a href="` + html.EscapeString(dest) + `"`)
+		if title != "" {
+			sb.WriteString(` title="` + html.EscapeString(title) + `"`)
+		}
+		sb.WriteString(">" + renderInline(label) + "</a>")
+	}
+	return closeIdx + 2 + end + 1, true
+}
+
+// emphasis 处理 **strong**、*em*、_em_ 和 ~~del~~，找不到结束的标记就当成普通字符
+func emphasis(sb *strings.Builder, text string, i int) (int, bool) {
+	c := text[i]
+	n := 1
+	if i+1 < len(text) && text[i+1] == c {
+		n = 2
+	}
+	if c == '~' && n != 2 {
+		return 0, false
+	}
+	delim := text[i : i+n]
+	start := i + n
+	// 开始标记后面不能是空白
+	if start >= len(text) || text[start] == ' ' || text[start] == '\n' {
+		return 0, false
+	}
+	// 单词中间的下划线不算强调，比如 snake_case
+	if c == '_' && i > 0 && isWordByte(text[i-1]) {
+		return 0, false
+	}
+	for j := start + 1; j+n <= len(text); j++ {
+		if text[j] == '\\' {
+			j++
+			continue
+		}
+		if text[j] == '`' {
+			// 跳过代码，代码里面的标记不算
+			if end := strings.IndexByte(text[j+1:], '`'); end >= 0 {
+				j += end + 1
+			}
+			continue
+		}
+		if text[j:j+n] != delim || text[j-1] == ' ' || text[j-1] == '\n' {
+			continue
+		}
+		// ** 里面的 * 不能被当成 *
+		if n == 1 && j+1 < len(text) && text[j+1] == c {
+			j++
+			continue
+		}
+		if c == '_' && j+n < len(text) && isWordByte(text[j+n]) {
+			continue
+		}
+		tag := "em"
+		if c == '~' {
+			tag = "del"
+		} else if n == 2 {
+			tag = "strong"
+		}
+		sb.WriteString("<" + tag + ">")
+		sb.WriteString(renderInline(text[start:j]))
+		sb.WriteString("</" + tag + ">")
+		return j + n - i, true
+	}
+	return 0, false
+}
+
+func isWordByte(b byte) bool {
+	return b == '_' || b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= 0x80
+}
@@ -0,0 +1,136 @@
+package markdown
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRender(t *testing.T) {
+	testCases := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			name: "标题和段落",
+			src:  "# 标题\n\n第一行\n第二行",
+			want: "<h1>标题</h1>\n<p>第一行\n第二行</p>\n",
+		},
+		{
+			name: "强调和行内代码",
+			src:  "**粗体** 和 *斜体* 还有 `a < b` 以及 snake_case_name ~~删除~~",
+			want: "<p><strong>粗体</strong> 和 <em>斜体</em> 还有 <code>a &lt; b</code> 以及 snake_case_name <del>删除</del></p>\n",
+		},
+		{
+			name: "代码块带语言",
+			src:  "```Go\nfmt.Println(\"<hi>\")\n```",
+			want: "<pre><code class=\"language-go\">fmt.Println(&#34;&lt;hi&gt;&#34;)\n</code></pre>\n",
+		},
+		{
+			name: "链接和图片",
+			src:  "[官网](https://example.com \"标题\") ![图](/a.png)",
+			want: "<p><a href=\"https://example.com\" title=\"标题\">官网</a> <img src=\"/a.png\" alt=\"图\"></p>\n",
+		},
+		{
+			name: "列表",
+			src:  "- 一\n- 二\n\n3. 三\n4. 四",
+			want: "<ul>\n<li>一</li>\n<li>二</li>\n</ul>\n<ol start=\"3\">\n<li>三</li>\n<li>四</li>\n</ol>\n",
+		},
+		{
+			name: "引用和分割线",
+			src:  "> 引用\n\n---",
+			want: "<blockquote>\n<p>引用</p>\n</blockquote>\n<hr>\n",
+		},
+		{
+			name: "未闭合的标记当成普通字符",
+			src:  "2 * 3 = 6 和 [不是链接",
+			want: "<p>2 * 3 = 6 和 [不是链接</p>\n",
+		},
+		{
+			name: "链接文字里面有方括号",
+			src:  "[[1]](/a) [a [b](/b)",
+			want: "<p><a href=\"/a\">[1]</a> [a <a href=\"/b\">b</a></p>\n",
+		},
+		{
+			name: "转义的方括号不算",
+			src:  "[a\\]](/a)",
+			want: "<p><a href=\"/a\">a]</a></p>\n",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, Render(tc.src))
+		})
+	}
+}
+
+func TestSanitize(t *testing.T) {
+	testCases := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			name: "去掉脚本",
+			src:  "<p>你好<script>alert(1)</script></p>",
+			want: "<p>你好</p>",
+		},
+		{
+			name: "去掉事件属性和不在白名单里面的标签",
+			src:  `<div onclick="x()"><img src="/a.png" onerror="x()">文字</div>`,
+			want: `<img src="/a.png">文字`,
+		},
+		{
+			name: "危险的链接",
+			src:  `<a href="javascript:alert(1)">点我</a><a href="//evil.com">远程</a>`,
+			want: `<a rel="nofollow noopener noreferrer">点我</a><a rel="nofollow noopener noreferrer">远程</a>`,
+		},
+		{
+			name: "反斜杠开头的链接",
+			src:  `<a href="/\evil.com">远程</a><img src=" \\evil.com/a.png"><a href="/a\b">本站</a>`,
+			want: `<a rel="nofollow noopener noreferrer">远程</a><img><a href="/a\b" rel="nofollow noopener noreferrer">本站</a>`,
+		},
+		{
+			name: "只保留代码块的语言",
+			src:  `<pre><code class="language-go">x</code></pre><code class="evil">y</code>`,
+			want: `<pre><code class="language-go">x</code></pre><code>y</code>`,
+		},
+		{
+			name: "补齐没有闭合的标签",
+			src:  "<blockquote><p>引用",
+			want: "<blockquote><p>引用</p></blockquote>",
+		},
+		{
+			name: "转义文字",
+			src:  "a &lt; b",
+			want: "a &lt; b",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, Sanitize(tc.src))
+		})
+	}
+}
+
+func TestRender_UnclosedBrackets(t *testing.T) {
+	// 每个 [ 都往后扫到结尾的话，这里要跑很久
+	src := strings.Repeat("[a](", 100000)
+	start := time.Now()
+	res := Render(src)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, "<p>"+src+"</p>\n", res)
+}
+
+func TestToHTML_RawHTML(t *testing.T) {
+	src := "正文 <span style=\"color:red\">红色</span>\n\n<iframe src=\"https://evil.com\"></iframe>"
+	assert.Equal(t, "<p>正文 红色</p>\n\n", ToHTML(src))
+}
+
+func TestPlainText(t *testing.T) {
+	src := ToHTML("# 标题\n\n**第一段** 内容\n\n```go\ncode()\n```\n\n- 列表")
+	assert.Equal(t, "标题 第一段 内容 列表", PlainText(src, 100))
+	assert.Equal(t, "标题 第一", PlainText(src, 5))
+}
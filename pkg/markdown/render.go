@@ -0,0 +1,247 @@
+// Package markdown 把 markdown 渲染成 HTML。
+// 只支持常用的语法，原始的 HTML 会原样输出，所以渲染结果一定要经过 Sanitize 才能展示
+package markdown
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	headingRegexp   = regexp.MustCompile(`^(#{1,6})[ \t]+(.*?)[ \t#]*$`)
+	hrRegexp        = regexp.MustCompile(`^ {0,3}((\*[ \t]*){3,}|(-[ \t]*){3,}|(_[ \t]*){3,})$`)
+	bulletRegexp    = regexp.MustCompile(`^ {0,3}([-*+])[ \t]+`)
+	orderedRegexp   = regexp.MustCompile(`^ {0,3}(\d{1,9})[.)][ \t]+`)
+	htmlBlockRegexp = regexp.MustCompile(`^ {0,3}</?([a-zA-Z][a-zA-Z0-9]*)[\s/>]`)
+	langRegexp      = regexp.MustCompile(`^[A-Za-z0-9_+#-]+$`)
+)
+
+// ToHTML 渲染并且清洗，发表的时候用这个
+func ToHTML(src string) string {
+	return Sanitize(Render(src))
+}
+
+// Render 把 markdown 渲染成 HTML，没有做任何清洗
+func Render(src string) string {
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+	src = strings.ReplaceAll(src, "\t", "    ")
+	var sb strings.Builder
+	renderBlocks(&sb, strings.Split(src, "\n"))
+	return sb.String()
+}
+
+func renderBlocks(sb *strings.Builder, lines []string) {
+	var para []string
+	flush := func() {
+		if len(para) == 0 {
+			return
+		}
+		sb.WriteString("<p>")
+		sb.WriteString(renderInline(strings.Join(para, "\n")))
+		sb.WriteString("</p>\n")
+		para = nil
+	}
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			flush()
+			i++
+		case isFence(trimmed):
+			flush()
+			i = renderFence(sb, lines, i)
+		case len(para) == 0 && strings.HasPrefix(line, "    "):
+			i = renderIndentedCode(sb, lines, i)
+		case headingRegexp.MatchString(trimmed):
+			flush()
+			m := headingRegexp.FindStringSubmatch(trimmed)
+			level := string(rune('0' + len(m[1])))
+			sb.WriteString("<h" + level + ">")
+			sb.WriteString(renderInline(m[2]))
+			sb.WriteString("</h" + level + ">\n")
+			i++
+		case hrRegexp.MatchString(line):
+			flush()
+			sb.WriteString("<hr>\n")
+			i++
+		case strings.HasPrefix(trimmed, ">"):
+			flush()
+			i = renderQuote(sb, lines, i)
+		case bulletRegexp.MatchString(line) || orderedRegexp.MatchString(line):
+			flush()
+			i = renderList(sb, lines, i)
+		case len(para) == 0 && htmlBlockRegexp.MatchString(line):
+			i = renderHTMLBlock(sb, lines, i)
+		default:
+			para = append(para, trimmed)
+			i++
+		}
+	}
+	flush()
+}
+
+func isFence(trimmed string) bool {
+	return strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~")
+}
+
+func renderFence(sb *strings.Builder, lines []string, i int) int {
+	open := strings.TrimSpace(lines[i])
+	fence := open[:3]
+	info := strings.Fields(strings.TrimLeft(open, fence[:1]))
+	var code []string
+	i++
+	for ; i < len(lines); i++ {
+		if strings.HasPrefix(strings.TrimSpace(lines[i]), fence) {
+			i++
+			break
+		}
+		code = append(code, lines[i])
+	}
+	sb.WriteString("<pre><code")
+	if len(info) > 0 && langRegexp.MatchString(info[0]) {
+		sb.WriteString(` class="language-`)
+		sb.WriteString(html.EscapeString(strings.ToLower(info[0])))
+		sb.WriteString(`"`)
+	}
+	sb.WriteString(">")
+	for _, l := range code {
+		sb.WriteString(html.EscapeString(l))
+		sb.WriteString("\n")
+	}
+	sb.WriteString("</code></pre>\n")
+	return i
+}
+
+func renderIndentedCode(sb *strings.Builder, lines []string, i int) int {
+	var code []string
+	for ; i < len(lines); i++ {
+		if strings.HasPrefix(lines[i], "    ") {
+			code = append(code, lines[i][4:])
+			continue
+		}
+		if strings.TrimSpace(lines[i]) != "" {
+			break
+		}
+		code = append(code, "")
+	}
+	// 代码块后面的空行不算
+	for len(code) > 0 && code[len(code)-1] == "" {
+		code = code[:len(code)-1]
+	}
+	sb.WriteString("<pre><code>")
+	for _, l := range code {
+		sb.WriteString(html.EscapeString(l))
+		sb.WriteString("\n")
+	}
+	sb.WriteString("</code></pre>\n")
+	return i
+}
+
+func renderQuote(sb *strings.Builder, lines []string, i int) int {
+	var inner []string
+	for ; i < len(lines); i++ {
+		trimmed := strings.TrimLeft(lines[i], " ")
+		if !strings.HasPrefix(trimmed, ">") {
+			break
+		}
+		trimmed = strings.TrimPrefix(trimmed, ">")
+		inner = append(inner, strings.TrimPrefix(trimmed, " "))
+	}
+	sb.WriteString("<blockquote>\n")
+	renderBlocks(sb, inner)
+	sb.WriteString("</blockquote>\n")
+	return i
+}
+
+func renderList(sb *strings.Builder, lines []string, i int) int {
+	ordered := !bulletRegexp.MatchString(lines[i])
+	marker := bulletRegexp
+	tag := "ul"
+	if ordered {
+		marker = orderedRegexp
+		tag = "ol"
+	}
+	sb.WriteString("<" + tag)
+	if ordered {
+		start := strings.TrimLeft(orderedRegexp.FindStringSubmatch(lines[i])[1], "0")
+		if start != "" && start != "1" {
+			sb.WriteString(` start="` + start + `"`)
+		}
+	}
+	sb.WriteString(">\n")
+	var (
+		item  []string
+		loose bool
+	)
+	flushItem := func() {
+		if item == nil {
+			return
+		}
+		var inner strings.Builder
+		renderBlocks(&inner, item)
+		content := strings.TrimSuffix(inner.String(), "\n")
+		// 紧凑的列表不要 <p>
+		if !loose && strings.HasPrefix(content, "<p>") && strings.Count(content, "<p>") == 1 {
+			content = strings.Replace(content, "<p>", "", 1)
+			content = strings.Replace(content, "</p>", "", 1)
+		}
+		sb.WriteString("<li>")
+		sb.WriteString(content)
+		sb.WriteString("</li>\n")
+		item = nil
+	}
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		if loc := marker.FindStringIndex(line); loc != nil {
+			flushItem()
+			item = []string{line[loc[1]:]}
+			continue
+		}
+		if strings.TrimSpace(line) == "" {
+			// 空行之后还是缩进的内容或者下一个列表项，才算同一个列表
+			if i+1 < len(lines) && (strings.HasPrefix(lines[i+1], "  ") || marker.MatchString(lines[i+1])) {
+				loose = true
+				item = append(item, "")
+				continue
+			}
+			break
+		}
+		if strings.HasPrefix(line, "  ") {
+			item = append(item, dedent(line))
+			continue
+		}
+		// 懒惰的续行，只有在上一行是普通文本的时候成立
+		if len(item) > 0 && item[len(item)-1] != "" && !isBlockStart(line) {
+			item = append(item, line)
+			continue
+		}
+		break
+	}
+	flushItem()
+	sb.WriteString("</" + tag + ">\n")
+	return i
+}
+
+func dedent(line string) string {
+	n := 0
+	for n < len(line) && n < 4 && line[n] == ' ' {
+		n++
+	}
+	return line[n:]
+}
+
+func isBlockStart(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return isFence(trimmed) || headingRegexp.MatchString(trimmed) || hrRegexp.MatchString(line) ||
+		strings.HasPrefix(trimmed, ">") || bulletRegexp.MatchString(line) || orderedRegexp.MatchString(line)
+}
+
+func renderHTMLBlock(sb *strings.Builder, lines []string, i int) int {
+	for ; i < len(lines) && strings.TrimSpace(lines[i]) != ""; i++ {
+		sb.WriteString(lines[i])
+		sb.WriteString("\n")
+	}
+	return i
+}
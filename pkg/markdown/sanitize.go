@@ -0,0 +1,213 @@
+package markdown
+
+import (
+	"golang.org/x/net/html"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// allowedTags 允许的标签和每个标签允许的属性，其余的标签去掉但是保留里面的文字
+var allowedTags = map[string]map[string]bool{
+	"p": {}, "br": {}, "hr": {},
+	"h1": {}, "h2": {}, "h3": {}, "h4": {}, "h5": {}, "h6": {},
+	"strong": {}, "b": {}, "em": {}, "i": {}, "del": {}, "s": {}, "sub": {}, "sup": {},
+	"blockquote": {}, "pre": {}, "code": {"class": true},
+	"ul": {}, "ol": {"start": true}, "li": {},
+	"a":     {"href": true, "title": true},
+	"img":   {"src": true, "alt": true, "title": true},
+	"table": {}, "thead": {}, "tbody": {}, "tr": {},
+	"th": {"align": true}, "td": {"align": true},
+}
+
+// droppedTags 连同里面的内容一起去掉
+var droppedTags = map[string]bool{
+	"script": true, "style": true, "iframe": true, "object": true, "embed": true,
+	"noscript": true, "textarea": true, "select": true, "template": true, "svg": true, "math": true,
+}
+
+var voidTags = map[string]bool{"br": true, "hr": true, "img": true}
+
+// blockTags 提取文字的时候块级标签之间要有空格
+var blockTags = map[string]bool{
+	"p": true, "br": true, "hr": true, "div": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"blockquote": true, "pre": true, "ul": true, "ol": true, "li": true,
+	"table": true, "tr": true, "th": true, "td": true,
+}
+
+var (
+	codeClassRegexp = regexp.MustCompile(`^language-[a-z0-9_+#-]+$`)
+	alignRegexp     = regexp.MustCompile(`^(left|center|right)$`)
+	digitsRegexp    = regexp.MustCompile(`^\d{1,9}$`)
+)
+
+// Sanitize 按照白名单清洗 HTML，同时保证标签是闭合的
+func Sanitize(src string) string {
+	var (
+		sb    strings.Builder
+		stack []string
+		// skip 大于 0 说明在被丢弃的标签里面
+		skip int
+	)
+	z := html.NewTokenizer(strings.NewReader(src))
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			break
+		}
+		tok := z.Token()
+		switch tt {
+		case html.TextToken:
+			if skip == 0 {
+				sb.WriteString(html.EscapeString(tok.Data))
+			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			if droppedTags[tok.Data] {
+				if tt == html.StartTagToken {
+					skip++
+				}
+				continue
+			}
+			attrs, ok := allowedTags[tok.Data]
+			if skip > 0 || !ok {
+				continue
+			}
+			writeStartTag(&sb, tok, attrs)
+			if !voidTags[tok.Data] {
+				stack = append(stack, tok.Data)
+			}
+		case html.EndTagToken:
+			if droppedTags[tok.Data] {
+				if skip > 0 {
+					skip--
+				}
+				continue
+			}
+			if skip > 0 {
+				continue
+			}
+			// 只闭合打开过的标签，中间没有闭合的一起闭合掉
+			for i := len(stack) - 1; i >= 0; i-- {
+				if stack[i] != tok.Data {
+					continue
+				}
+				for j := len(stack) - 1; j >= i; j-- {
+					sb.WriteString("</" + stack[j] + ">")
+				}
+				stack = stack[:i]
+				break
+			}
+		}
+	}
+	for i := len(stack) - 1; i >= 0; i-- {
+		sb.WriteString("</" + stack[i] + ">")
+	}
+	return sb.String()
+}
+
+func writeStartTag(sb *strings.Builder, tok html.Token, allowed map[string]bool) {
+	sb.WriteString("<" + tok.Data)
+	for _, attr := range tok.Attr {
+		if !allowed[attr.Key] || !validAttr(tok.Data, attr.Key, attr.Val) {
+			continue
+		}
+		sb.WriteString(" " + attr.Key + `="` + html.EscapeString(attr.Val) + `"`)
+	}
+	if tok.Data == "a" {
+		sb.WriteString(` rel="nofollow noopener noreferrer"`)
+	}
+	sb.WriteString(">")
+}
+
+func validAttr(tag, key, val string) bool {
+	switch key {
+	case "href", "src":
+		return safeURL(val, tag == "a")
+	case "class":
+		// 只允许代码块的语言，用来做语法高亮
+		return codeClassRegexp.MatchString(val)
+	case "align":
+		return alignRegexp.MatchString(val)
+	case "start":
+		return digitsRegexp.MatchString(val)
+	default:
+		return true
+	}
+}
+
+// safeURL 只允许 http、https 和相对路径，链接还允许 mailto
+func safeURL(val string, isLink bool) bool {
+	val = strings.TrimSpace(val)
+	u, err := url.Parse(val)
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "":
+		// //evil.com 这种没有 scheme 的也不要，浏览器会把 /\evil.com 里面的 \ 当成 /
+		return u.Host == "" && !strings.HasPrefix(strings.ReplaceAll(val, `\`, "/"), "//")
+	case "http", "https":
+		return true
+	case "mailto":
+		return isLink
+	default:
+		return false
+	}
+}
+
+// PlainText 提取 HTML 里面的文字作为摘要，去掉代码块，合并空白，最多 maxLen 个字符
+func PlainText(src string, maxLen int) string {
+	var (
+		sb   strings.Builder
+		skip int
+		cnt  int
+	)
+	z := html.NewTokenizer(strings.NewReader(src))
+	space := false
+	for cnt < maxLen {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			break
+		}
+		tok := z.Token()
+		switch tt {
+		case html.StartTagToken:
+			if tok.Data == "pre" || droppedTags[tok.Data] {
+				skip++
+			}
+			space = space || blockTags[tok.Data]
+		case html.EndTagToken:
+			if (tok.Data == "pre" || droppedTags[tok.Data]) && skip > 0 {
+				skip--
+			}
+			space = space || blockTags[tok.Data]
+		case html.SelfClosingTagToken:
+			space = space || blockTags[tok.Data]
+		case html.TextToken:
+			if skip > 0 {
+				continue
+			}
+			for _, r := range tok.Data {
+				if cnt >= maxLen {
+					break
+				}
+				if r == ' ' || r == '\n' || r == '\t' || r == '\r' {
+					space = true
+					continue
+				}
+				if space && sb.Len() > 0 {
+					sb.WriteByte(' ')
+					cnt++
+					if cnt >= maxLen {
+						break
+					}
+				}
+				space = false
+				sb.WriteRune(r)
+				cnt++
+			}
+		}
+	}
+	return strings.TrimSpace(sb.String())
+}
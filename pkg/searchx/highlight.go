@@ -0,0 +1,82 @@
+package searchx
+
+import (
+	"html"
+	"strings"
+)
+
+// Highlight 从 text 里面截取命中查询词最多的一段，最多 maxLen 个字符，maxLen 不大于 0 表示不截取。
+// 命中的部分用 pre 和 post 包起来，其余部分会做 HTML 转义
+func Highlight(text string, query string, maxLen int, pre string, post string) string {
+	runes := []rune(text)
+	spans := matchSpans(text, query)
+	start, end := 0, len(runes)
+	if maxLen > 0 && len(runes) > maxLen {
+		start = bestWindow(spans, maxLen, len(runes))
+		end = start + maxLen
+	}
+	var sb strings.Builder
+	if start > 0 {
+		sb.WriteString("...")
+	}
+	cur := start
+	for _, s := range spans {
+		if s[1] <= start || s[0] >= end {
+			continue
+		}
+		from, to := max(s[0], start), min(s[1], end)
+		sb.WriteString(html.EscapeString(string(runes[cur:from])))
+		sb.WriteString(pre)
+		sb.WriteString(html.EscapeString(string(runes[from:to])))
+		sb.WriteString(post)
+		cur = to
+	}
+	sb.WriteString(html.EscapeString(string(runes[cur:end])))
+	if end < len(runes) {
+		sb.WriteString("...")
+	}
+	return sb.String()
+}
+
+// matchSpans 返回合并之后的命中区间，按照起始位置排序
+func matchSpans(text string, query string) [][2]int {
+	terms := TokenizeQuery(query)
+	if len(terms) == 0 {
+		return nil
+	}
+	set := make(map[string]struct{}, len(terms))
+	for _, t := range terms {
+		set[t] = struct{}{}
+	}
+	var spans [][2]int
+	for _, t := range Tokenize(text) {
+		if _, ok := set[t.Term]; !ok {
+			continue
+		}
+		// Tokenize 输出的 Start 是递增的，所以只需要和最后一个区间合并
+		if n := len(spans); n > 0 && t.Start <= spans[n-1][1] {
+			spans[n-1][1] = max(spans[n-1][1], t.End)
+			continue
+		}
+		spans = append(spans, [2]int{t.Start, t.End})
+	}
+	return spans
+}
+
+// bestWindow 返回包含命中区间最多的窗口起点，命中的地方前面留一点上下文
+func bestWindow(spans [][2]int, size int, total int) int {
+	best, bestCnt := 0, 0
+	for _, s := range spans {
+		start := max(0, min(s[0]-size/4, total-size))
+		cnt := 0
+		for _, o := range spans {
+			if o[0] >= start && o[1] <= start+size {
+				cnt++
+			}
+		}
+		if cnt > bestCnt {
+			best, bestCnt = start, cnt
+		}
+	}
+	return best
+}
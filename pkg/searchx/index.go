@@ -0,0 +1,202 @@
+package searchx
+
+import (
+	"encoding/gob"
+	"io"
+	"math"
+	"sort"
+	"sync"
+)
+
+// Doc 是被索引的文档，Fields 里面只有在 boosts 中配置了权重的字段才会被索引，
+// 其余字段只是原样保存下来
+type Doc struct {
+	Id     int64
+	Fields map[string]string
+}
+
+type Hit struct {
+	Id    int64
+	Score float64
+	Doc   Doc
+}
+
+// Index 是一个完全在内存中的倒排索引，使用 BM25 打分。
+// 不同字段的词频按照权重累加，也就是简化版的 BM25F
+type Index struct {
+	mu       sync.RWMutex
+	boosts   map[string]float64
+	docs     map[int64]*entry
+	postings map[string]map[int64]float64
+	totalLen float64
+	k1       float64
+	b        float64
+}
+
+type entry struct {
+	doc    Doc
+	terms  map[string]float64
+	length float64
+}
+
+func NewIndex(boosts map[string]float64) *Index {
+	return &Index{
+		boosts:   boosts,
+		docs:     make(map[int64]*entry),
+		postings: make(map[string]map[int64]float64),
+		k1:       1.2,
+		b:        0.75,
+	}
+}
+
+// Put 新增或者覆盖文档
+func (i *Index) Put(doc Doc) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.remove(doc.Id)
+	i.add(doc)
+}
+
+func (i *Index) Delete(id int64) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.remove(id)
+}
+
+// Replace 用 docs 整体替换索引里面的数据，用于重建索引
+func (i *Index) Replace(docs []Doc) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.docs = make(map[int64]*entry, len(docs))
+	i.postings = make(map[string]map[int64]float64)
+	i.totalLen = 0
+	for _, doc := range docs {
+		i.remove(doc.Id)
+		i.add(doc)
+	}
+}
+
+func (i *Index) Len() int {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return len(i.docs)
+}
+
+// Search 返回按照分数倒序排列的一页结果，以及命中的文档总数
+func (i *Index) Search(query string, offset int, limit int) ([]Hit, int) {
+	terms := TokenizeQuery(query)
+	if len(terms) == 0 {
+		return nil, 0
+	}
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	n := float64(len(i.docs))
+	if n == 0 {
+		return nil, 0
+	}
+	avgLen := i.totalLen / n
+	scores := make(map[int64]float64)
+	for _, term := range terms {
+		posting := i.postings[term]
+		if len(posting) == 0 {
+			continue
+		}
+		df := float64(len(posting))
+		idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+		for id, tf := range posting {
+			norm := i.k1 * (1 - i.b + i.b*i.docs[id].length/avgLen)
+			scores[id] += idf * tf * (i.k1 + 1) / (tf + norm)
+		}
+	}
+	hits := make([]Hit, 0, len(scores))
+	for id, score := range scores {
+		hits = append(hits, Hit{Id: id, Score: score})
+	}
+	sort.Slice(hits, func(a, b int) bool {
+		if hits[a].Score != hits[b].Score {
+			return hits[a].Score > hits[b].Score
+		}
+		// 分数相同的时候新的文档在前面
+		return hits[a].Id > hits[b].Id
+	})
+	total := len(hits)
+	if offset >= total {
+		return nil, total
+	}
+	hits = hits[offset:min(offset+limit, total)]
+	for k := range hits {
+		hits[k].Doc = i.docs[hits[k].Id].doc
+	}
+	return hits, total
+}
+
+type snapshot struct {
+	Docs []Doc
+}
+
+// Snapshot 只保存原始文档，恢复的时候重新分词，这样分词规则变了也不影响快照
+func (i *Index) Snapshot(w io.Writer) error {
+	i.mu.RLock()
+	docs := make([]Doc, 0, len(i.docs))
+	for _, e := range i.docs {
+		docs = append(docs, e.doc)
+	}
+	i.mu.RUnlock()
+	sort.Slice(docs, func(a, b int) bool {
+		return docs[a].Id < docs[b].Id
+	})
+	return gob.NewEncoder(w).Encode(snapshot{Docs: docs})
+}
+
+func (i *Index) Restore(r io.Reader) error {
+	var s snapshot
+	err := gob.NewDecoder(r).Decode(&s)
+	if err != nil {
+		return err
+	}
+	i.Replace(s.Docs)
+	return nil
+}
+
+func (i *Index) add(doc Doc) {
+	e := &entry{
+		doc:   doc,
+		terms: make(map[string]float64),
+	}
+	for field, text := range doc.Fields {
+		boost := i.boosts[field]
+		if boost <= 0 {
+			continue
+		}
+		for _, t := range Tokenize(text) {
+			e.terms[t.Term] += boost
+			e.length += boost
+		}
+	}
+	for term, tf := range e.terms {
+		posting, ok := i.postings[term]
+		if !ok {
+			posting = make(map[int64]float64)
+			i.postings[term] = posting
+		}
+		posting[doc.Id] = tf
+	}
+	i.docs[doc.Id] = e
+	i.totalLen += e.length
+}
+
+func (i *Index) remove(id int64) {
+	e, ok := i.docs[id]
+	if !ok {
+		return
+	}
+	for term := range e.terms {
+		posting := i.postings[term]
+		delete(posting, id)
+		if len(posting) == 0 {
+			delete(i.postings, term)
+		}
+	}
+	delete(i.docs, id)
+	i.totalLen -= e.length
+}
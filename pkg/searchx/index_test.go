@@ -0,0 +1,153 @@
+package searchx
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func newTestIndex() *Index {
+	idx := NewIndex(map[string]float64{
+		"title":   3,
+		"content": 1,
+	})
+	idx.Put(Doc{Id: 1, Fields: map[string]string{
+		"title":   "Go 语言入门",
+		"content": "介绍 Go 的基本语法",
+	}})
+	idx.Put(Doc{Id: 2, Fields: map[string]string{
+		"title":   "数据库索引",
+		"content": "MySQL 的索引和 Go 没有关系",
+		"author":  "不参与索引",
+	}})
+	idx.Put(Doc{Id: 3, Fields: map[string]string{
+		"title":   "搜索引擎",
+		"content": "倒排索引是搜索引擎的核心",
+	}})
+	return idx
+}
+
+func TestIndex_Search(t *testing.T) {
+	testCases := []struct {
+		name      string
+		query     string
+		offset    int
+		limit     int
+		wantIds   []int64
+		wantTotal int
+	}{
+		{
+			name:      "标题命中的分数更高",
+			query:     "go",
+			limit:     10,
+			wantIds:   []int64{1, 2},
+			wantTotal: 2,
+		},
+		{
+			name:      "中文二元组，全部命中的排在前面",
+			query:     "搜索引擎",
+			limit:     10,
+			wantIds:   []int64{3, 2},
+			wantTotal: 2,
+		},
+		{
+			name:      "分页",
+			query:     "索引",
+			offset:    1,
+			limit:     1,
+			wantIds:   []int64{2},
+			wantTotal: 2,
+		},
+		{
+			name:      "没有权重的字段不索引",
+			query:     "参与",
+			limit:     10,
+			wantTotal: 0,
+		},
+	}
+	idx := newTestIndex()
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			hits, total := idx.Search(tc.query, tc.offset, tc.limit)
+			assert.Equal(t, tc.wantTotal, total)
+			ids := make([]int64, 0, len(hits))
+			for _, h := range hits {
+				ids = append(ids, h.Id)
+			}
+			if tc.wantIds == nil {
+				tc.wantIds = []int64{}
+			}
+			assert.Equal(t, tc.wantIds, ids)
+		})
+	}
+}
+
+func TestIndex_PutDelete(t *testing.T) {
+	idx := newTestIndex()
+	idx.Put(Doc{Id: 1, Fields: map[string]string{"title": "Rust 入门"}})
+	hits, _ := idx.Search("go", 0, 10)
+	assert.Len(t, hits, 1)
+	assert.Equal(t, int64(2), hits[0].Id)
+
+	idx.Delete(2)
+	hits, total := idx.Search("go", 0, 10)
+	assert.Empty(t, hits)
+	assert.Equal(t, 0, total)
+	assert.Equal(t, 2, idx.Len())
+}
+
+func TestIndex_Snapshot(t *testing.T) {
+	idx := newTestIndex()
+	var buf bytes.Buffer
+	require.NoError(t, idx.Snapshot(&buf))
+
+	restored := NewIndex(map[string]float64{"title": 3, "content": 1})
+	require.NoError(t, restored.Restore(&buf))
+	assert.Equal(t, idx.Len(), restored.Len())
+	want, _ := idx.Search("索引", 0, 10)
+	got, _ := restored.Search("索引", 0, 10)
+	assert.Equal(t, want, got)
+}
+
+func TestHighlight(t *testing.T) {
+	testCases := []struct {
+		name   string
+		text   string
+		query  string
+		maxLen int
+		want   string
+	}{
+		{
+			name:  "合并相邻的二元组",
+			text:  "倒排索引是搜索引擎的核心",
+			query: "搜索引擎",
+			want:  "倒排<em>索引</em>是<em>搜索引擎</em>的核心",
+		},
+		{
+			name:  "转义 HTML",
+			text:  "<b>Go</b> & go",
+			query: "go",
+			want:  "&lt;b&gt;<em>Go</em>&lt;/b&gt; &amp; <em>go</em>",
+		},
+		{
+			name:   "截取命中的片段",
+			text:   "这是一段很长很长的开头然后才提到搜索",
+			query:  "搜索",
+			maxLen: 8,
+			want:   "...头然后才提到<em>搜索</em>",
+		},
+		{
+			name:   "没有命中就从头截取",
+			text:   "abcdefghij",
+			query:  "xyz",
+			maxLen: 4,
+			want:   "abcd...",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, Highlight(tc.text, tc.query, tc.maxLen, "<em>", "</em>"))
+		})
+	}
+}
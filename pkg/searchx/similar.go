@@ -0,0 +1,148 @@
+package searchx
+
+import (
+	"math"
+	"sort"
+)
+
+// Similar 相似的文档，Score 是两个 TF-IDF 向量的余弦相似度
+type Similar struct {
+	Id    int64
+	Score float64
+}
+
+// TFIDF 计算一批文档两两之间的相似度，用来做离线的相关推荐。
+// 每个文档只保留权重最高的 maxTerms 个词，既去掉了噪声，也控制了计算量
+type TFIDF struct {
+	boosts   map[string]float64
+	maxTerms int
+	docs     []tfidfDoc
+	df       map[string]int
+}
+
+type tfidfDoc struct {
+	id  int64
+	tf  map[string]float64
+	vec []termWeight
+}
+
+type termWeight struct {
+	term   string
+	weight float64
+}
+
+// maxPostings 出现在太多文档里面的词区分度很低，
+// 不用它们找候选文档，避免退化成两两比较
+const maxPostings = 2000
+
+func NewTFIDF(boosts map[string]float64, maxTerms int) *TFIDF {
+	return &TFIDF{
+		boosts:   boosts,
+		maxTerms: maxTerms,
+		df:       make(map[string]int),
+	}
+}
+
+func (t *TFIDF) Add(doc Doc) {
+	tf := make(map[string]float64)
+	for field, text := range doc.Fields {
+		boost, ok := t.boosts[field]
+		if !ok {
+			continue
+		}
+		for _, token := range Tokenize(text) {
+			tf[token.Term] += boost
+		}
+	}
+	for term := range tf {
+		t.df[term]++
+	}
+	t.docs = append(t.docs, tfidfDoc{id: doc.Id, tf: tf})
+}
+
+func (t *TFIDF) Len() int {
+	return len(t.docs)
+}
+
+// Similar 返回每个文档最相似的 topN 个文档，相似度为 0 的不返回
+func (t *TFIDF) Similar(topN int) map[int64][]Similar {
+	n := float64(len(t.docs))
+	postings := make(map[string][]int)
+	for i := range t.docs {
+		d := &t.docs[i]
+		vec := make([]termWeight, 0, len(d.tf))
+		for term, tf := range d.tf {
+			idf := math.Log(n / float64(t.df[term]))
+			if idf <= 0 {
+				continue
+			}
+			vec = append(vec, termWeight{term: term, weight: (1 + math.Log(tf)) * idf})
+		}
+		sort.Slice(vec, func(i, j int) bool {
+			if vec[i].weight != vec[j].weight {
+				return vec[i].weight > vec[j].weight
+			}
+			return vec[i].term < vec[j].term
+		})
+		if len(vec) > t.maxTerms {
+			vec = vec[:t.maxTerms]
+		}
+		var norm float64
+		for _, w := range vec {
+			norm += w.weight * w.weight
+		}
+		norm = math.Sqrt(norm)
+		for j := range vec {
+			vec[j].weight /= norm
+			postings[vec[j].term] = append(postings[vec[j].term], i)
+		}
+		d.vec = vec
+		// 后面用不到了，释放内存
+		d.tf = nil
+	}
+
+	res := make(map[int64][]Similar, len(t.docs))
+	for i, d := range t.docs {
+		weights := make(map[string]float64, len(d.vec))
+		for _, w := range d.vec {
+			weights[w.term] = w.weight
+		}
+		scores := make(map[int]float64)
+		for _, w := range d.vec {
+			ps := postings[w.term]
+			if len(ps) > maxPostings {
+				continue
+			}
+			for _, j := range ps {
+				if j != i {
+					scores[j] = 0
+				}
+			}
+		}
+		// 候选文档再完整地算一次点积，避免被跳过的词影响分数
+		for j := range scores {
+			var dot float64
+			for _, w := range t.docs[j].vec {
+				dot += weights[w.term] * w.weight
+			}
+			scores[j] = dot
+		}
+		sims := make([]Similar, 0, len(scores))
+		for j, score := range scores {
+			if score > 0 {
+				sims = append(sims, Similar{Id: t.docs[j].id, Score: score})
+			}
+		}
+		sort.Slice(sims, func(i, j int) bool {
+			if sims[i].Score != sims[j].Score {
+				return sims[i].Score > sims[j].Score
+			}
+			return sims[i].Id > sims[j].Id
+		})
+		if len(sims) > topN {
+			sims = sims[:topN]
+		}
+		res[d.id] = sims
+	}
+	return res
+}
@@ -0,0 +1,50 @@
+package searchx
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestTFIDF_Similar(t *testing.T) {
+	tfidf := NewTFIDF(map[string]float64{
+		"title":   3,
+		"content": 1,
+	}, 20)
+	tfidf.Add(Doc{Id: 1, Fields: map[string]string{
+		"title":   "Go 并发编程",
+		"content": "goroutine 和 channel 是 Go 并发的基础",
+	}})
+	tfidf.Add(Doc{Id: 2, Fields: map[string]string{
+		"title":   "Go channel 详解",
+		"content": "channel 的底层实现",
+	}})
+	tfidf.Add(Doc{Id: 3, Fields: map[string]string{
+		"title":   "MySQL 索引",
+		"content": "B+ 树索引",
+	}})
+	tfidf.Add(Doc{Id: 4, Fields: map[string]string{
+		"title":   "索引下推",
+		"content": "MySQL 的优化",
+	}})
+	require.Equal(t, 4, tfidf.Len())
+
+	res := tfidf.Similar(1)
+	testCases := []struct {
+		id      int64
+		wantIds []int64
+	}{
+		{id: 1, wantIds: []int64{2}},
+		{id: 2, wantIds: []int64{1}},
+		{id: 3, wantIds: []int64{4}},
+		{id: 4, wantIds: []int64{3}},
+	}
+	for _, tc := range testCases {
+		var ids []int64
+		for _, s := range res[tc.id] {
+			assert.True(t, s.Score > 0 && s.Score <= 1+1e-9)
+			ids = append(ids, s.Id)
+		}
+		assert.Equal(t, tc.wantIds, ids, "id %d", tc.id)
+	}
+}
@@ -0,0 +1,87 @@
+package searchx
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Token 是分词的结果，Start 和 End 是 rune 下标，左闭右开
+type Token struct {
+	Term  string
+	Start int
+	End   int
+}
+
+// Tokenize 用于建立索引。
+// 拉丁字母和数字按照单词切分并且转小写，
+// 中日韩文字没有空格，同时输出单字和相邻两个字组成的二元组
+func Tokenize(text string) []Token {
+	return tokenize([]rune(text), true)
+}
+
+// TokenizeQuery 用于切分查询语句，去掉了重复的词。
+// 中日韩文字只有一个字的时候用单字，否则只用二元组，这样结果更精确
+func TokenizeQuery(query string) []string {
+	tokens := tokenize([]rune(query), false)
+	res := make([]string, 0, len(tokens))
+	seen := make(map[string]struct{}, len(tokens))
+	for _, t := range tokens {
+		if _, ok := seen[t.Term]; ok {
+			continue
+		}
+		seen[t.Term] = struct{}{}
+		res = append(res, t.Term)
+	}
+	return res
+}
+
+func tokenize(runes []rune, unigram bool) []Token {
+	var res []Token
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case isCJK(r):
+			j := i
+			for j < len(runes) && isCJK(runes[j]) {
+				j++
+			}
+			res = append(res, cjkTokens(runes, i, j, unigram)...)
+			i = j
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			j := i
+			for j < len(runes) && !isCJK(runes[j]) &&
+				(unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j])) {
+				j++
+			}
+			res = append(res, Token{
+				Term:  strings.ToLower(string(runes[i:j])),
+				Start: i,
+				End:   j,
+			})
+			i = j
+		default:
+			i++
+		}
+	}
+	return res
+}
+
+func cjkTokens(runes []rune, start, end int, unigram bool) []Token {
+	if end-start == 1 {
+		return []Token{{Term: string(runes[start]), Start: start, End: end}}
+	}
+	res := make([]Token, 0, 2*(end-start))
+	for k := start; k < end; k++ {
+		if unigram {
+			res = append(res, Token{Term: string(runes[k]), Start: k, End: k + 1})
+		}
+		if k+1 < end {
+			res = append(res, Token{Term: string(runes[k : k+2]), Start: k, End: k + 2})
+		}
+	}
+	return res
+}
+
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
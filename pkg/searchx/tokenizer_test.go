@@ -0,0 +1,84 @@
+package searchx
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	testCases := []struct {
+		name string
+		text string
+		want []Token
+	}{
+		{
+			name: "英文转小写",
+			text: "Hello, Go!",
+			want: []Token{
+				{Term: "hello", Start: 0, End: 5},
+				{Term: "go", Start: 7, End: 9},
+			},
+		},
+		{
+			name: "中文单字和二元组",
+			text: "搜索引擎",
+			want: []Token{
+				{Term: "搜", Start: 0, End: 1},
+				{Term: "搜索", Start: 0, End: 2},
+				{Term: "索", Start: 1, End: 2},
+				{Term: "索引", Start: 1, End: 3},
+				{Term: "引", Start: 2, End: 3},
+				{Term: "引擎", Start: 2, End: 4},
+				{Term: "擎", Start: 3, End: 4},
+			},
+		},
+		{
+			name: "中英文混合",
+			text: "用Go写",
+			want: []Token{
+				{Term: "用", Start: 0, End: 1},
+				{Term: "go", Start: 1, End: 3},
+				{Term: "写", Start: 3, End: 4},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, Tokenize(tc.text))
+		})
+	}
+}
+
+func TestTokenizeQuery(t *testing.T) {
+	testCases := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{
+			name:  "中文只用二元组",
+			query: "搜索引擎",
+			want:  []string{"搜索", "索引", "引擎"},
+		},
+		{
+			name:  "单个汉字",
+			query: "搜",
+			want:  []string{"搜"},
+		},
+		{
+			name:  "去重",
+			query: "go Go GO",
+			want:  []string{"go"},
+		},
+		{
+			name:  "只有标点",
+			query: "，。!",
+			want:  []string{},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, TokenizeQuery(tc.query))
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package sensitive
+
+import (
+	"bufio"
+	"github.com/fsnotify/fsnotify"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"webook/pkg/logger"
+)
+
+// FileFilter 从词典文件加载敏感词，文件修改之后自动重新加载。
+// 词典一行一个词，# 开头的是注释
+type FileFilter struct {
+	paths   []string
+	trie    atomic.Pointer[Trie]
+	watcher *fsnotify.Watcher
+	l       logger.LoggerV1
+}
+
+// NewFileFilter 第一次加载失败直接返回错误，之后重新加载失败会继续用旧的词典
+func NewFileFilter(paths []string, l logger.LoggerV1) (*FileFilter, error) {
+	f := &FileFilter{
+		paths: paths,
+		l:     l,
+	}
+	err := f.Reload()
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *FileFilter) Match(text string) []string {
+	return f.trie.Load().Match(text)
+}
+
+func (f *FileFilter) Reload() error {
+	var words []string
+	for _, p := range f.paths {
+		ws, err := readWords(p)
+		if err != nil {
+			return err
+		}
+		words = append(words, ws...)
+	}
+	f.trie.Store(NewTrie(words))
+	return nil
+}
+
+// Watch 监听词典文件所在的目录，编辑器保存文件的时候经常是写临时文件再改名，
+// 只监听文件本身的话改名之后就收不到通知了
+func (f *FileFilter) Watch() error {
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	files := make(map[string]bool, len(f.paths))
+	dirs := make(map[string]bool, len(f.paths))
+	for _, p := range f.paths {
+		abs, err := filepath.Abs(p)
+		if err != nil {
+			_ = w.Close()
+			return err
+		}
+		files[abs] = true
+		dirs[filepath.Dir(abs)] = true
+	}
+	for dir := range dirs {
+		err = w.Add(dir)
+		if err != nil {
+			_ = w.Close()
+			return err
+		}
+	}
+	f.watcher = w
+	go func() {
+		for {
+			select {
+			case evt, ok := <-w.Events:
+				if !ok {
+					return
+				}
+				abs, _ := filepath.Abs(evt.Name)
+				if !files[abs] || !evt.Has(fsnotify.Write|fsnotify.Create|fsnotify.Rename) {
+					continue
+				}
+				er := f.Reload()
+				if er != nil {
+					f.l.Error("重新加载敏感词失败", logger.String("file", evt.Name), logger.Error(er))
+					continue
+				}
+				f.l.Info("重新加载敏感词", logger.String("file", evt.Name))
+			case er, ok := <-w.Errors:
+				if !ok {
+					return
+				}
+				f.l.Error("监听敏感词文件失败", logger.Error(er))
+			}
+		}
+	}()
+	return nil
+}
+
+func (f *FileFilter) Close() error {
+	if f.watcher == nil {
+		return nil
+	}
+	return f.watcher.Close()
+}
+
+func readWords(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	var res []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		res = append(res, line)
+	}
+	return res, scanner.Err()
+}
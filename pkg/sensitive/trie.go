@@ -0,0 +1,98 @@
+// Package sensitive 敏感词匹配
+package sensitive
+
+import (
+	"strings"
+	"unicode"
+)
+
+type Matcher interface {
+	// Match 返回 text 里面出现的敏感词，去重，按照出现的顺序
+	Match(text string) []string
+}
+
+// Trie 前缀树，大小写不敏感。敏感词中间夹了空格或者标点符号，
+// 比如“敏 感-词”，也能匹配上
+type Trie struct {
+	root *node
+}
+
+type node struct {
+	children map[rune]*node
+	// word 不为空说明到这里是一个完整的敏感词
+	word string
+}
+
+func NewTrie(words []string) *Trie {
+	t := &Trie{root: &node{}}
+	for _, w := range words {
+		t.add(w)
+	}
+	return t
+}
+
+func (t *Trie) add(word string) {
+	word = strings.TrimSpace(word)
+	cur := t.root
+	for _, r := range word {
+		if skippable(r) {
+			continue
+		}
+		r = unicode.ToLower(r)
+		next, ok := cur.children[r]
+		if !ok {
+			if cur.children == nil {
+				cur.children = make(map[rune]*node)
+			}
+			next = &node{}
+			cur.children[r] = next
+		}
+		cur = next
+	}
+	if cur != t.root {
+		cur.word = word
+	}
+}
+
+func (t *Trie) Match(text string) []string {
+	var (
+		res  []string
+		seen = make(map[string]bool)
+	)
+	runes := []rune(text)
+	for i := 0; i < len(runes); i++ {
+		if skippable(runes[i]) {
+			continue
+		}
+		// 从 i 开始找最长的敏感词
+		cur := t.root
+		matched, end := "", i
+		for j := i; j < len(runes); j++ {
+			if skippable(runes[j]) {
+				continue
+			}
+			next, ok := cur.children[unicode.ToLower(runes[j])]
+			if !ok {
+				break
+			}
+			cur = next
+			if cur.word != "" {
+				matched, end = cur.word, j
+			}
+		}
+		if matched == "" {
+			continue
+		}
+		if !seen[matched] {
+			seen[matched] = true
+			res = append(res, matched)
+		}
+		i = end
+	}
+	return res
+}
+
+// skippable 匹配的时候忽略空白和标点符号
+func skippable(r rune) bool {
+	return unicode.IsSpace(r) || unicode.IsPunct(r) || unicode.IsSymbol(r)
+}
@@ -0,0 +1,71 @@
+package sensitive
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+	"webook/pkg/logger"
+)
+
+func TestTrie_Match(t *testing.T) {
+	trie := NewTrie([]string{"赌博", "网络赌博", "Casino", "代开发票", ""})
+	testCases := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{
+			name: "没有命中",
+			text: "今天天气不错",
+		},
+		{
+			name: "最长匹配",
+			text: "严厉打击网络赌博",
+			want: []string{"网络赌博"},
+		},
+		{
+			name: "大小写不敏感",
+			text: "online CASINO here",
+			want: []string{"Casino"},
+		},
+		{
+			name: "中间夹了符号",
+			text: "代 开-发*票，联系我",
+			want: []string{"代开发票"},
+		},
+		{
+			name: "去重",
+			text: "赌博，赌博，还是赌博",
+			want: []string{"赌博"},
+		},
+		{
+			name: "多个",
+			text: "代开发票，赌博",
+			want: []string{"代开发票", "赌博"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, trie.Match(tc.text))
+		})
+	}
+}
+
+func TestFileFilter_Watch(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "words.txt")
+	require.NoError(t, os.WriteFile(path, []byte("# 注释\n赌博\n"), 0o644))
+	f, err := NewFileFilter([]string{path}, logger.NewNopLogger())
+	require.NoError(t, err)
+	require.NoError(t, f.Watch())
+	defer f.Close()
+	assert.Equal(t, []string{"赌博"}, f.Match("赌博和发票"))
+
+	require.NoError(t, os.WriteFile(path, []byte("赌博\n发票\n"), 0o644))
+	assert.Eventually(t, func() bool {
+		return len(f.Match("赌博和发票")) == 2
+	}, 3*time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,75 @@
+package signx
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalid = errors.New("签名无效")
+	ErrExpired = errors.New("已经过期")
+)
+
+// Signer 用 HMAC-SHA256 给放在链接里面的凭证签名，
+// 凭证的格式是 payload.过期时间.签名，payload 里面不能有 '.'
+type Signer struct {
+	key []byte
+}
+
+func NewSigner(key []byte) *Signer {
+	return &Signer{key: key}
+}
+
+// Sign 过期时间只保留到毫秒
+func (s *Signer) Sign(payload string, expireAt time.Time) string {
+	res := payload + "." + strconv.FormatInt(expireAt.UnixMilli(), 36)
+	return res + "." + s.mac(res)
+}
+
+// Verify 先校验签名再校验过期时间，伪造的和过期的凭证不需要再查数据库。
+// 过期的时候也会返回 payload 和过期时间
+func (s *Signer) Verify(token string) (string, time.Time, error) {
+	idx := strings.LastIndexByte(token, '.')
+	if idx < 0 {
+		return "", time.Time{}, ErrInvalid
+	}
+	signed, sig := token[:idx], token[idx+1:]
+	if !hmac.Equal([]byte(sig), []byte(s.mac(signed))) {
+		return "", time.Time{}, ErrInvalid
+	}
+	payload, expStr, ok := strings.Cut(signed, ".")
+	if !ok {
+		return "", time.Time{}, ErrInvalid
+	}
+	exp, err := strconv.ParseInt(expStr, 36, 64)
+	if err != nil {
+		return "", time.Time{}, ErrInvalid
+	}
+	expireAt := time.UnixMilli(exp)
+	if !time.Now().Before(expireAt) {
+		return payload, expireAt, ErrExpired
+	}
+	return payload, expireAt, nil
+}
+
+func (s *Signer) mac(payload string) string {
+	h := hmac.New(sha256.New, s.key)
+	h.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil))
+}
+
+// NewNonce 16 个字节的随机数，可以直接放在链接里面
+func NewNonce() (string, error) {
+	b := make([]byte, 16)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
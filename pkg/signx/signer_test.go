@@ -0,0 +1,57 @@
+package signx
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestSigner(t *testing.T) {
+	s := NewSigner([]byte("test-key-0123456789abcdef0123456"))
+	exp := time.Now().Add(time.Hour).Truncate(time.Millisecond)
+	token := s.Sign("abc", exp)
+	testCases := []struct {
+		name        string
+		token       string
+		wantPayload string
+		wantExpire  time.Time
+		wantErr     error
+	}{
+		{
+			name:        "正常",
+			token:       token,
+			wantPayload: "abc",
+			wantExpire:  exp,
+		},
+		{
+			name:        "过期",
+			token:       s.Sign("abc", exp.Add(-2*time.Hour)),
+			wantPayload: "abc",
+			wantExpire:  exp.Add(-2 * time.Hour),
+			wantErr:     ErrExpired,
+		},
+		{
+			name:    "改了 payload",
+			token:   "abd" + token[3:],
+			wantErr: ErrInvalid,
+		},
+		{
+			name:    "别的密钥签的",
+			token:   NewSigner([]byte("another-key-0123456789abcdef0123")).Sign("abc", exp),
+			wantErr: ErrInvalid,
+		},
+		{
+			name:    "格式不对",
+			token:   "abc",
+			wantErr: ErrInvalid,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			payload, expireAt, err := s.Verify(tc.token)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantPayload, payload)
+			assert.True(t, tc.wantExpire.Equal(expireAt))
+		})
+	}
+}
@@ -10,12 +10,13 @@ import (
 	dao2 "webook/interactive/repository/dao"
 	service2 "webook/interactive/service"
 	"webook/internal/events/article"
+	"webook/internal/events/blob"
+	"webook/internal/events/feed"
 	"webook/internal/repository"
 	"webook/internal/repository/cache"
 	"webook/internal/repository/dao"
 	"webook/internal/service"
 	"webook/internal/web"
-	"webook/internal/web/jwt"
 	"webook/ioc"
 )
 
@@ -27,7 +28,7 @@ var interactiveSvcSet = wire.NewSet(
 )
 
 var rankingSvcSet = wire.NewSet(
-	cache.NewRankingRedisCache,
+	ioc.InitRankingCache,
 	repository.NewCachedRankingRepository,
 	service.NewBatchRankingService,
 )
@@ -41,23 +42,80 @@ func InitApp() *App {
 		ioc.InitSyncProducer,
 		article.NewSaramaSyncProducer,
 		events.NewInteractiveReadEventConsumer,
+		ioc.InitSearchArticleEventConsumer,
+		blob.NewArticleEventConsumer,
+		feed.NewArticleEventConsumer,
 		ioc.InitConsumers,
 		ioc.InitRlockClient,
-		dao.NewArticleGORMDAO,
+		ioc.InitArticleDAO,
+		ioc.InitArticleEventDAO,
+		repository.NewArticleEventRepository,
+		service.NewArticleEventService,
+		dao.NewGORMTagDAO,
+		dao.NewGORMArticleCollaboratorDAO,
+		repository.NewArticleCollaboratorRepository,
 		dao.NewGORMUserDAO, cache.NewRedisUserCache, cache.NewLocalCodeCache, cache.NewArticleRedisCache,
 		repository.NewCacheArticleRepository,
+		ioc.InitArticlePubCache,
 		repository.NewCacheUserRepository, repository.NewCacheCodeRepository,
 		ioc.InitSMSService,
+		ioc.InitEmailService,
 		ioc.InitWechatService,
 		service.NewArticleService,
 		service.NewCacheUserService, service.NewCacheCodeService,
+		dao.NewGORMUserMFADAO, cache.NewUserMFARedisCache,
+		repository.NewCachedUserMFARepository, service.NewUserMFAService,
 		interactiveSvcSet,
 		ioc.InitIntrClientV1,
+		ioc.InitCommentClient,
+		web.NewCommentHandler,
 		rankingSvcSet,
 		ioc.InitRankingJob,
 		ioc.InitJobs,
+		dao.NewGORMJobDAO,
+		repository.NewPreemptJobRepository,
+		service.NewCronJobService,
+		ioc.InitLocalExecutor,
+		ioc.InitScheduler,
+		ioc.InitSearchService,
+		web.NewSearchHandler,
+		dao.NewGORMShareLinkDAO,
+		repository.NewShareLinkRepository,
+		ioc.InitShareService,
+		web.NewShareHandler,
+		dao.NewGORMSeriesDAO,
+		repository.NewSeriesRepository,
+		service.NewSeriesService,
+		web.NewSeriesHandler,
+		dao.NewGORMBlobDAO,
+		repository.NewBlobRepository,
+		ioc.InitBlobStore,
+		ioc.InitBlobService,
+		ioc.InitBlobHandler,
+		ioc.InitSensitiveMatcher,
+		dao.NewGORMArticleReviewDAO,
+		repository.NewArticleReviewRepository,
+		dao.NewGORMNotificationDAO,
+		repository.NewNotificationRepository,
+		service.NewNotificationService,
+		service.NewArticleReviewService,
+		ioc.InitReviewHandler,
+		web.NewNotificationHandler,
+		cache.NewFeedRedisCache,
+		repository.NewCachedFeedRepository,
+		ioc.InitFeedService,
+		web.NewFeedHandler,
+		dao.NewGORMArchiveTaskDAO,
+		repository.NewArchiveTaskRepository,
+		ioc.InitArchiveService,
+		ioc.InitArchiveHandler,
+		cache.NewArticleRelatedRedisCache,
+		repository.NewCachedArticleRelatedRepository,
+		service.NewArticleRelatedService,
+		web.NewArticleRelatedHandler,
 		web.NewArticleHandler,
-		jwt.NewRedisJWTHandler,
+		ioc.InitJWTHandler,
+		web.NewJWKSHandler,
 		web.NewUserHandler,
 		web.NewOAuth2WechatHandler,
 		ioc.InitGinMiddleWares,
@@ -14,12 +14,13 @@ import (
 	dao2 "webook/interactive/repository/dao"
 	service2 "webook/interactive/service"
 	"webook/internal/events/article"
+	"webook/internal/events/blob"
+	"webook/internal/events/feed"
 	"webook/internal/repository"
 	"webook/internal/repository/cache"
 	"webook/internal/repository/dao"
 	"webook/internal/service"
 	"webook/internal/web"
-	"webook/internal/web/jwt"
 	"webook/ioc"
 )
 
@@ -31,7 +32,7 @@ import (
 
 func InitApp() *App {
 	cmdable := ioc.InitRedis()
-	handler := jwt.NewRedisJWTHandler(cmdable)
+	handler := ioc.InitJWTHandler(cmdable)
 	loggerV1 := ioc.InitLogger()
 	v := ioc.InitGinMiddleWares(cmdable, handler, loggerV1)
 	db := ioc.InitDB(loggerV1)
@@ -43,34 +44,99 @@ func InitApp() *App {
 	codeCache := cache.NewLocalCodeCache(freecacheCache)
 	codeRepository := repository.NewCacheCodeRepository(codeCache)
 	smsService := ioc.InitSMSService()
-	codeService := service.NewCacheCodeService(codeRepository, smsService)
-	userHandler := web.NewUserHandler(userService, codeService, handler)
+	emailService := ioc.InitEmailService()
+	codeService := service.NewCacheCodeService(codeRepository, smsService, emailService)
+	userMFADAO := dao.NewGORMUserMFADAO(db)
+	userMFACache := cache.NewUserMFARedisCache(cmdable)
+	userMFARepository := repository.NewCachedUserMFARepository(userMFADAO, userMFACache)
+	userMFAService := service.NewUserMFAService(userMFARepository, userRepository)
+	userHandler := web.NewUserHandler(userService, codeService, userMFAService, handler, loggerV1)
 	wechatService := ioc.InitWechatService()
 	oAuth2WechatHandler := web.NewOAuth2WechatHandler(wechatService, userService, handler)
-	articleDAO := dao.NewArticleGORMDAO(db)
+	articleDAO := ioc.InitArticleDAO(db)
+	tagDAO := dao.NewGORMTagDAO(db)
 	articleCache := cache.NewArticleRedisCache(cmdable)
-	articleRepository := repository.NewCacheArticleRepository(articleDAO, userRepository, articleCache)
+	articlePubCache := ioc.InitArticlePubCache(cmdable, loggerV1)
+	articleRepository := repository.NewCacheArticleRepository(articleDAO, tagDAO, userRepository, articleCache, articlePubCache)
 	client := ioc.InitSaramaClient()
 	syncProducer := ioc.InitSyncProducer(client)
 	producer := article.NewSaramaSyncProducer(syncProducer)
-	articleService := service.NewArticleService(articleRepository, producer)
+	rankingCache := ioc.InitRankingCache(cmdable)
+	rankingRepository := repository.NewCachedRankingRepository(rankingCache)
+	articleCollaboratorDAO := dao.NewGORMArticleCollaboratorDAO(db)
+	articleCollaboratorRepository := repository.NewArticleCollaboratorRepository(articleCollaboratorDAO, articleDAO, userRepository)
+	matcher := ioc.InitSensitiveMatcher(loggerV1)
+	articleReviewDAO := dao.NewGORMArticleReviewDAO(db)
+	articleReviewRepository := repository.NewArticleReviewRepository(articleReviewDAO)
+	articleService := service.NewArticleService(articleRepository, articleCollaboratorRepository, rankingRepository, producer, matcher, articleReviewRepository, loggerV1)
 	clientv3Client := ioc.InitEtcd()
 	interactiveServiceClient := ioc.InitIntrClientV1(clientv3Client)
-	articleHandler := web.NewArticleHandler(articleService, interactiveServiceClient, loggerV1)
-	engine := ioc.InitWebServer(v, userHandler, oAuth2WechatHandler, articleHandler)
+	seriesDAO := dao.NewGORMSeriesDAO(db)
+	seriesRepository := repository.NewSeriesRepository(seriesDAO, articleDAO)
+	seriesService := service.NewSeriesService(seriesRepository)
+	articleHandler := web.NewArticleHandler(articleService, seriesService, interactiveServiceClient, loggerV1)
+	searchService := ioc.InitSearchService(articleRepository, loggerV1)
+	searchHandler := web.NewSearchHandler(searchService, loggerV1)
+	shareLinkDAO := dao.NewGORMShareLinkDAO(db)
+	shareLinkRepository := repository.NewShareLinkRepository(shareLinkDAO)
+	shareService := ioc.InitShareService(shareLinkRepository, articleRepository, loggerV1)
+	shareHandler := web.NewShareHandler(shareService, loggerV1)
+	seriesHandler := web.NewSeriesHandler(seriesService, interactiveServiceClient, loggerV1)
+	blobDAO := dao.NewGORMBlobDAO(db)
+	blobRepository := repository.NewBlobRepository(blobDAO)
+	blobStore := ioc.InitBlobStore()
+	blobService := ioc.InitBlobService(blobRepository, articleRepository, blobStore, loggerV1)
+	blobHandler := ioc.InitBlobHandler(blobService, loggerV1)
+	commentServiceClient := ioc.InitCommentClient(clientv3Client)
+	commentHandler := web.NewCommentHandler(commentServiceClient, articleService, loggerV1)
+	notificationDAO := dao.NewGORMNotificationDAO(db)
+	notificationRepository := repository.NewNotificationRepository(notificationDAO)
+	notificationService := service.NewNotificationService(notificationRepository)
+	articleReviewService := service.NewArticleReviewService(articleReviewRepository, articleRepository, articleCollaboratorRepository, notificationService, loggerV1)
+	reviewHandler := ioc.InitReviewHandler(articleReviewService, loggerV1)
+	notificationHandler := web.NewNotificationHandler(notificationService, loggerV1)
+	feedCache := cache.NewFeedRedisCache(cmdable)
+	feedRepository := repository.NewCachedFeedRepository(feedCache)
+	feedService := ioc.InitFeedService(articleRepository, userRepository, feedRepository, loggerV1)
+	feedHandler := web.NewFeedHandler(feedService, loggerV1)
+	archiveTaskDAO := dao.NewGORMArchiveTaskDAO(db)
+	archiveTaskRepository := repository.NewArchiveTaskRepository(archiveTaskDAO)
+	archiveService := ioc.InitArchiveService(archiveTaskRepository, articleRepository, articleService, blobStore, cmdable, loggerV1)
+	archiveHandler := ioc.InitArchiveHandler(archiveService, loggerV1)
+	articleRelatedCache := cache.NewArticleRelatedRedisCache(cmdable)
+	articleRelatedRepository := repository.NewCachedArticleRelatedRepository(articleRelatedCache)
+	articleRelatedService := service.NewArticleRelatedService(articleRelatedRepository, articleRepository, interactiveServiceClient, loggerV1)
+	articleRelatedHandler := web.NewArticleRelatedHandler(articleRelatedService, interactiveServiceClient, loggerV1)
+	jwksHandler := web.NewJWKSHandler(handler)
+	engine := ioc.InitWebServer(v, userHandler, oAuth2WechatHandler, articleHandler, searchHandler, shareHandler, seriesHandler, blobHandler, commentHandler, reviewHandler, notificationHandler, feedHandler, archiveHandler, articleRelatedHandler, jwksHandler)
 	interactiveDAO := dao2.NewGORMInteractiveDAO(db)
 	interactiveCache := cache2.NewInteractiveRedisCache(cmdable)
 	interactiveRepository := repository2.NewCachedInteractiveRepository(interactiveDAO, interactiveCache)
 	interactiveReadEventConsumer := events.NewInteractiveReadEventConsumer(interactiveRepository, client, loggerV1)
-	v2 := ioc.InitConsumers(interactiveReadEventConsumer)
+	articleEventConsumer := ioc.InitSearchArticleEventConsumer(searchService, loggerV1)
+	blobArticleEventConsumer := blob.NewArticleEventConsumer(blobService, client, loggerV1)
+	feedArticleEventConsumer := feed.NewArticleEventConsumer(feedService, client, loggerV1)
+	v2 := ioc.InitConsumers(interactiveReadEventConsumer, articleEventConsumer, blobArticleEventConsumer, feedArticleEventConsumer)
 	rankingService := service.NewBatchRankingService(interactiveServiceClient, articleService)
 	rlockClient := ioc.InitRlockClient(cmdable)
 	rankingJob := ioc.InitRankingJob(rankingService, loggerV1, rlockClient)
 	cron := ioc.InitJobs(loggerV1, rankingJob)
+	jobDAO := dao.NewGORMJobDAO(db)
+	cronJobRepository := repository.NewPreemptJobRepository(jobDAO)
+	cronJobService := service.NewCronJobService(cronJobRepository, loggerV1)
+	articleEventDAO := ioc.InitArticleEventDAO(db)
+	articleEventRepository := repository.NewArticleEventRepository(articleEventDAO)
+	articleEventService := service.NewArticleEventService(articleEventRepository, producer, loggerV1)
+	interactiveService := service2.NewInteractiveService(interactiveRepository)
+	localExecutor := ioc.InitLocalExecutor(articleService, articleEventService, blobService, interactiveService, archiveService, articleRelatedService, loggerV1)
+	scheduler := ioc.InitScheduler(cronJobService, localExecutor, loggerV1)
 	app := &App{
 		server:    engine,
 		consumers: v2,
 		cron:      cron,
+		scheduler: scheduler,
+		searchSvc: searchService,
+		l:         loggerV1,
 	}
 	return app
 }
@@ -79,4 +145,4 @@ func InitApp() *App {
 
 var interactiveSvcSet = wire.NewSet(service2.NewInteractiveService, repository2.NewCachedInteractiveRepository, cache2.NewInteractiveRedisCache, dao2.NewGORMInteractiveDAO)
 
-var rankingSvcSet = wire.NewSet(cache.NewRankingRedisCache, repository.NewCachedRankingRepository, service.NewBatchRankingService)
+var rankingSvcSet = wire.NewSet(ioc.InitRankingCache, repository.NewCachedRankingRepository, service.NewBatchRankingService)